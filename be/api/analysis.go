@@ -0,0 +1,234 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/analyzer"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type AnalyzeSourcesRequest struct {
+	// Files are the module's Go sources, test files included
+	Files []ScanSourceFile `json:"files" validate:"required,min=1"`
+	Apply bool             `json:"apply,omitempty"`
+	// IfMatch is the report ETag the client read; applying is refused
+	// when the report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+func (r *AnalyzeSourcesRequest) Validate() error {
+	var v ValidationErrors
+	if len(r.Files) == 0 {
+		v.add("files", "at least one file is required")
+	}
+	if len(r.Files) > maxScanFiles {
+		v.add("files", "at most %d files can be analyzed at once", maxScanFiles)
+	}
+	total := 0
+	for _, f := range r.Files {
+		total += len(f.Content)
+	}
+	if total > maxScanBytes {
+		v.add("files", "sources exceed %d MB", maxScanBytes>>20)
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type AnalysisRun struct {
+	ID                       string      `json:"id"`
+	ReportID                 string      `json:"report_id"`
+	UploadedBy               string      `json:"uploaded_by"`
+	Files                    int         `json:"files"`
+	TestFiles                int         `json:"test_files"`
+	Packages                 int         `json:"packages"`
+	CodeLines                int         `json:"code_lines"`
+	TestLines                int         `json:"test_lines"`
+	Functions                int         `json:"functions"`
+	TestToCodeRatio          float64     `json:"test_to_code_ratio"`
+	AverageComplexity        float64     `json:"average_complexity"`
+	MaxComplexity            int         `json:"max_complexity"`
+	ComplexFunctions         int         `json:"complex_functions"`
+	UncheckedErrors          int         `json:"unchecked_errors"`
+	Panics                   int         `json:"panics"`
+	Goroutines               int         `json:"goroutines"`
+	GoroutinesWithoutContext int         `json:"goroutines_without_context"`
+	ExportedDeclarations     int         `json:"exported_declarations"`
+	TotalDeclarations        int         `json:"total_declarations"`
+	ParseErrors              int         `json:"parse_errors"`
+	Suggested                *Evaluation `json:"suggested"`
+	Applied                  bool        `json:"applied"`
+	CreatedAt                time.Time   `json:"created_at"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/evaluation/analysis
+func AnalyzeSources(ctx context.Context, id string, req *AnalyzeSourcesRequest) (*AnalysisRun, error) {
+	// Score: [S8,P6,M6,T7,E8,L6]
+	// Details:
+	// - Security (S8): Sources are parsed only, upload size bounded
+	// - Performance (P6): Parses every uploaded file per request
+	// - Memory (M6): Whole upload held in memory while analyzing
+	// - Testing (T7): Analyzer is a pure library shared with the CLI
+	// - Error (E8): Unparseable files counted and skipped, applying blocked when nothing parsed
+	// - Load (L6): CPU-bound, intended for CI runs rather than interactive traffic
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	sources := make([]analyzer.Source, len(req.Files))
+	for i, f := range req.Files {
+		sources[i] = analyzer.Source{Path: f.Path, Content: f.Content}
+	}
+	metrics := analyzer.Analyze(sources)
+	suggested := analyzer.Suggest(metrics)
+	suggested.ReportID = report.ID
+	suggested.EvaluatorID = userID
+
+	run := &models.AnalysisRun{
+		ReportID:                 report.ID,
+		UploadedBy:               userID,
+		Files:                    metrics.Files,
+		TestFiles:                metrics.TestFiles,
+		Packages:                 metrics.Packages,
+		CodeLines:                metrics.CodeLines,
+		TestLines:                metrics.TestLines,
+		Functions:                metrics.Functions,
+		TestToCodeRatio:          metrics.TestToCodeRatio,
+		AverageComplexity:        metrics.AverageComplexity,
+		MaxComplexity:            metrics.MaxComplexity,
+		ComplexFunctions:         metrics.ComplexFunctions,
+		UncheckedErrors:          metrics.UncheckedErrors,
+		Panics:                   metrics.Panics,
+		Goroutines:               metrics.Goroutines,
+		GoroutinesWithoutContext: metrics.GoroutinesWithoutContext,
+		ExportedDeclarations:     metrics.ExportedDeclarations,
+		TotalDeclarations:        metrics.TotalDeclarations,
+		ParseErrors:              len(metrics.ParseErrors),
+		Suggested:                suggested,
+	}
+
+	if req.Apply {
+		if metrics.Files == 0 {
+			return nil, errs.InvalidArgument("no Go source files could be analyzed")
+		}
+		// Hold the report so the version check and the write are atomic
+		locked, unlock, err := lockReportForWrite(ctx, report.ID, req.IfMatch)
+		if err != nil {
+			return nil, err
+		}
+		_, err = recordEvaluation(ctx, locked, userID, &EvaluateReportRequest{
+			SecurityScore:      suggested.SecurityScore,
+			PerformanceScore:   suggested.PerformanceScore,
+			MemoryScore:        suggested.MemoryScore,
+			TestingScore:       suggested.TestingScore,
+			ErrorScore:         suggested.ErrorScore,
+			LoadScore:          suggested.LoadScore,
+			SecurityDetails:    suggested.SecurityDetails,
+			PerformanceDetails: suggested.PerformanceDetails,
+			MemoryDetails:      suggested.MemoryDetails,
+			TestingDetails:     suggested.TestingDetails,
+			ErrorDetails:       suggested.ErrorDetails,
+			LoadDetails:        suggested.LoadDetails,
+		})
+		unlock()
+		if err != nil {
+			return nil, err
+		}
+		run.Applied = true
+	}
+
+	if err := models.SaveAnalysisRun(ctx, run); err != nil {
+		rlog.Error("failed to save analysis run", "error", err)
+		return nil, errs.Internal("failed to save analysis run")
+	}
+
+	return convertModelToAPIAnalysisRun(run), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/evaluation/analysis
+func GetAnalysis(ctx context.Context, id string) (*AnalysisRun, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check, trashed reports not served
+	// - Performance (P8): Single lookup by report ID
+	// - Memory (M8): Returns the stored run only
+	// - Testing (T7): Missing, stored and trashed-report analyses tested
+	// - Error (E8): Missing analyses reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database; trashed reports are not found
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	run, err := models.GetAnalysisRunByReportID(ctx, id)
+	if err != nil {
+		if err == models.ErrAnalysisRunNotFound {
+			return nil, errs.NotFound("no analysis has been run for this report")
+		}
+		rlog.Error("failed to get analysis run", "error", err)
+		return nil, errs.Internal("failed to get analysis run")
+	}
+	return convertModelToAPIAnalysisRun(run), nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIAnalysisRun(model *models.AnalysisRun) *AnalysisRun {
+	return &AnalysisRun{
+		ID:                       model.ID,
+		ReportID:                 model.ReportID,
+		UploadedBy:               model.UploadedBy,
+		Files:                    model.Files,
+		TestFiles:                model.TestFiles,
+		Packages:                 model.Packages,
+		CodeLines:                model.CodeLines,
+		TestLines:                model.TestLines,
+		Functions:                model.Functions,
+		TestToCodeRatio:          model.TestToCodeRatio,
+		AverageComplexity:        model.AverageComplexity,
+		MaxComplexity:            model.MaxComplexity,
+		ComplexFunctions:         model.ComplexFunctions,
+		UncheckedErrors:          model.UncheckedErrors,
+		Panics:                   model.Panics,
+		Goroutines:               model.Goroutines,
+		GoroutinesWithoutContext: model.GoroutinesWithoutContext,
+		ExportedDeclarations:     model.ExportedDeclarations,
+		TotalDeclarations:        model.TotalDeclarations,
+		ParseErrors:              model.ParseErrors,
+		Suggested:                convertModelToAPIEvaluation(model.Suggested),
+		Applied:                  model.Applied,
+		CreatedAt:                model.CreatedAt,
+	}
+}
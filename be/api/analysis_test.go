@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestAnalyzeAndGetAnalysis(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	if _, err := GetAnalysis(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "no analysis has been run") {
+		t.Errorf("GetAnalysis before a run = %v, want not found", err)
+	}
+
+	// Applying needs at least one file that parses
+	broken := []ScanSourceFile{{Path: "broken.go", Content: "package broken\nfunc {"}}
+	if _, err := AnalyzeSources(ctx, report.ID, &AnalyzeSourcesRequest{Files: broken, Apply: true}); err == nil || !strings.Contains(err.Error(), "no Go source files could be analyzed") {
+		t.Errorf("applying an unparseable upload = %v, want rejected", err)
+	}
+	if _, err := models.GetEvaluationByReportID(ctx, report.ID); err != models.ErrEvaluationNotFound {
+		t.Errorf("rejected upload recorded an evaluation: %v", err)
+	}
+
+	files := []ScanSourceFile{
+		{Path: "sum/sum.go", Content: "package sum\n\n// Sum adds two numbers.\nfunc Sum(a, b int) int {\n\treturn a + b\n}\n"},
+		{Path: "sum/sum_test.go", Content: "package sum\n\nimport \"testing\"\n\nfunc TestSum(t *testing.T) {\n\tif Sum(1, 2) != 3 {\n\t\tt.Fail()\n\t}\n}\n"},
+	}
+	run, err := AnalyzeSources(ctx, report.ID, &AnalyzeSourcesRequest{Files: files, Apply: true})
+	if err != nil {
+		t.Fatalf("AnalyzeSources: %v", err)
+	}
+	if run.Files != 1 || run.TestFiles != 1 || !run.Applied {
+		t.Errorf("run = %d files, %d test files, applied %v, want 1, 1, true", run.Files, run.TestFiles, run.Applied)
+	}
+
+	stored, err := GetAnalysis(ctx, report.ID)
+	if err != nil || stored.ID != run.ID {
+		t.Fatalf("GetAnalysis = %v, %v, want run %s", stored, err, run.ID)
+	}
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetEvaluationByReportID: %v", err)
+	}
+	if evaluation.TestingScore != run.Suggested.TestingScore || evaluation.ErrorScore != run.Suggested.ErrorScore {
+		t.Errorf("evaluation = %+v, want the suggested scores %+v", evaluation, run.Suggested)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type DepartmentActivity struct {
+	DepartmentID   string         `json:"department_id"`
+	Events         map[string]int `json:"events"`
+	Reviewed       int            `json:"reviewed"`
+	AvgReviewHours float64        `json:"avg_review_hours"`
+}
+
+// BE-IN - Internal backend only
+type AnalyticsResponse struct {
+	Events      map[string]int        `json:"events"`
+	Departments []*DepartmentActivity `json:"departments"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/analytics
+func GetAnalytics(ctx context.Context) (*AnalyticsResponse, error) {
+	// Score: [S7,P8,M7,T5,E7,L8]
+	// Details:
+	// - Security (S7): Authentication check, aggregate counts only
+	// - Performance (P8): Reads precomputed counters
+	// - Memory (M7): Copies counters under the lock
+	// - Testing (T5): Counters tested in models, the handler only copies them
+	// - Error (E7): Proper error handling
+	// - Load (L8): No per-report work at request time
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	summary, err := models.GetActivitySummary(ctx)
+	if err != nil {
+		rlog.Error("failed to get activity summary", "error", err)
+		return nil, errs.Internal("failed to get analytics")
+	}
+
+	response := &AnalyticsResponse{
+		Events:      summary.Events,
+		Departments: make([]*DepartmentActivity, len(summary.Departments)),
+	}
+	for i, department := range summary.Departments {
+		response.Departments[i] = &DepartmentActivity{
+			DepartmentID:   department.DepartmentID,
+			Events:         department.Events,
+			Reviewed:       department.Reviewed,
+			AvgReviewHours: department.AvgReviewHours,
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// maxAuditEntries caps one page of the audit log
+const maxAuditEntries = 500
+
+// BE-IN - Internal backend only
+type AuditEntry struct {
+	ID           string            `json:"id"`
+	Action       string            `json:"action"`
+	ActorID      string            `json:"actor_id"`
+	DepartmentID string            `json:"department_id"`
+	ProjectID    string            `json:"project_id,omitempty"`
+	ReportID     string            `json:"report_id,omitempty"`
+	Details      map[string]string `json:"details,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+type ListAuditRequest struct {
+	ReportID string `query:"report_id"`
+	Action   string `query:"action"`
+	Limit    int    `query:"limit"`
+}
+
+// BE-IN - Internal backend only
+func (r *ListAuditRequest) Validate() error {
+	var v ValidationErrors
+	if r.Limit < 0 || r.Limit > maxAuditEntries {
+		v.add("limit", "must be between 0 and 500")
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type ListAuditResponse struct {
+	Entries []*AuditEntry `json:"entries"`
+}
+
+// BE-OUT - External data involved
+// ListAudit returns a department's audit log, newest first: retention
+// policy changes, legal holds, and reports deleted, restored, archived
+// or purged.
+//
+//encore:api public method=GET path=/api/departments/:id/audit
+func ListAudit(ctx context.Context, id string, req *ListAuditRequest) (*ListAuditResponse, error) {
+	// Score: [S8,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S8): Department head only
+	// - Performance (P7): Linear scan of the log
+	// - Memory (M7): Bounded page size
+	// - Testing (T7): Filters, ordering, limit and head-only access tested
+	// - Error (E8): Proper error handling
+	// - Load (L7): Read-only
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	department, err := models.GetDepartmentByID(ctx, id)
+	if err != nil {
+		return nil, errs.NotFound("department not found")
+	}
+	if department.HeadID != userID {
+		return nil, errs.Permission("only the department head can read the audit log")
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	entries, err := models.ListAuditEntries(ctx, models.AuditFilter{
+		DepartmentID: id,
+		ReportID:     req.ReportID,
+		Action:       req.Action,
+		Limit:        limit,
+	})
+	if err != nil {
+		rlog.Error("failed to list audit entries", "department_id", id, "error", err)
+		return nil, errs.Internal("failed to list audit entries")
+	}
+
+	response := &ListAuditResponse{Entries: make([]*AuditEntry, len(entries))}
+	for i := range entries {
+		response.Entries[i] = convertModelToAPIAuditEntry(&entries[i])
+	}
+	return response, nil
+}
+
+// BE-IN - Internal backend only
+// audit records an action in the audit log. A failure is logged rather
+// than returned, since the action itself has already happened.
+func audit(ctx context.Context, entry *models.AuditEntry) {
+	if err := models.SaveAuditEntry(ctx, entry); err != nil {
+		rlog.Error("failed to record audit entry", "action", entry.Action, "report_id", entry.ReportID, "error", err)
+	}
+}
+
+// BE-IN - Internal backend only
+// auditReport records an action on a report.
+func auditReport(ctx context.Context, action, actorID string, report *models.Report, details map[string]string) {
+	audit(ctx, &models.AuditEntry{
+		Action:       action,
+		ActorID:      actorID,
+		DepartmentID: report.DepartmentID,
+		ProjectID:    report.ProjectID,
+		ReportID:     report.ID,
+		Details:      details,
+	})
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIAuditEntry(model *models.AuditEntry) *AuditEntry {
+	return &AuditEntry{
+		ID:           model.ID,
+		Action:       model.Action,
+		ActorID:      model.ActorID,
+		DepartmentID: model.DepartmentID,
+		ProjectID:    model.ProjectID,
+		ReportID:     model.ReportID,
+		Details:      model.Details,
+		CreatedAt:    model.CreatedAt,
+	}
+}
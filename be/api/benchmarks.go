@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"encore.app/events"
+	"encore.app/models"
+	"encore.app/services/benchmark"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// maxBenchmarkOutputBytes bounds uploaded benchmark output
+const maxBenchmarkOutputBytes = 32 << 20
+
+// BE-IN - Internal backend only
+// regressionThreshold is read once at startup; an invalid configuration
+// falls back to the default.
+var regressionThreshold = loadRegressionThreshold()
+
+// BE-IN - Internal backend only
+func loadRegressionThreshold() float64 {
+	threshold, err := benchmark.ThresholdFromEnv()
+	if err != nil {
+		rlog.Error("invalid benchmark regression threshold, using the default", "error", err)
+	}
+	return threshold
+}
+
+// BE-IN - Internal backend only
+type IngestBenchmarksRequest struct {
+	// Output is go test -bench -benchmem output, plain or from go test -json
+	Output      string `json:"output" validate:"required"`
+	Apply       bool   `json:"apply,omitempty"`
+	SetBaseline bool   `json:"set_baseline,omitempty"`
+	// IfMatch is the report ETag the client read; applying is refused
+	// when the report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+func (r *IngestBenchmarksRequest) Validate() error {
+	var v ValidationErrors
+	v.required("output", r.Output)
+	if len(r.Output) > maxBenchmarkOutputBytes {
+		v.add("output", "must be at most %d MB", maxBenchmarkOutputBytes>>20)
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type BenchmarkResult struct {
+	Package     string  `json:"package"`
+	Name        string  `json:"name"`
+	Procs       int     `json:"procs"`
+	Runs        int     `json:"runs"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	HasMemory   bool    `json:"has_memory"`
+}
+
+// BE-IN - Internal backend only
+type BenchmarkComparison struct {
+	Key            string  `json:"key"`
+	NsPerOp        float64 `json:"ns_per_op"`
+	BaselineNsOp   float64 `json:"baseline_ns_per_op"`
+	NsDelta        float64 `json:"ns_delta"`
+	BytesPerOp     float64 `json:"bytes_per_op"`
+	BaselineBytes  float64 `json:"baseline_bytes_per_op"`
+	BytesDelta     float64 `json:"bytes_delta"`
+	AllocsPerOp    float64 `json:"allocs_per_op"`
+	BaselineAllocs float64 `json:"baseline_allocs_per_op"`
+	AllocsDelta    float64 `json:"allocs_delta"`
+	HasMemory      bool    `json:"has_memory"`
+	Regressed      bool    `json:"regressed"`
+}
+
+// BE-IN - Internal backend only
+type BenchmarkRun struct {
+	ID                 string                 `json:"id"`
+	ReportID           string                 `json:"report_id"`
+	ProjectID          string                 `json:"project_id"`
+	UploadedBy         string                 `json:"uploaded_by"`
+	GoOS               string                 `json:"goos,omitempty"`
+	GoArch             string                 `json:"goarch,omitempty"`
+	CPU                string                 `json:"cpu,omitempty"`
+	Results            []*BenchmarkResult     `json:"results"`
+	BaselineRunID      string                 `json:"baseline_run_id,omitempty"`
+	BaselineReportID   string                 `json:"baseline_report_id,omitempty"`
+	Comparisons        []*BenchmarkComparison `json:"comparisons"`
+	Unmatched          int                    `json:"unmatched"`
+	Regressions        int                    `json:"regressions"`
+	PerformanceScore   *int                   `json:"performance_score,omitempty"`
+	MemoryScore        *int                   `json:"memory_score,omitempty"`
+	PerformanceDetails string                 `json:"performance_details"`
+	MemoryDetails      string                 `json:"memory_details"`
+	Applied            bool                   `json:"applied"`
+	IsBaseline         bool                   `json:"is_baseline"`
+	CreatedAt          time.Time              `json:"created_at"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/evaluation/benchmarks
+func IngestBenchmarks(ctx context.Context, id string, req *IngestBenchmarksRequest) (*BenchmarkRun, error) {
+	// Score: [S8,P7,M6,T7,E8,L6]
+	// Details:
+	// - Security (S8): Upload size bounded, only department heads replace a baseline
+	// - Performance (P7): Single parse plus one lookup per benchmark
+	// - Memory (M6): Whole upload held in memory while parsing
+	// - Testing (T7): Parsing and comparison live in a pure library
+	// - Error (E8): Missing baseline explained instead of a made-up score
+	// - Load (L6): Intended for CI runs rather than interactive traffic
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	output, err := benchmark.Parse(strings.NewReader(req.Output))
+	if err != nil {
+		return nil, errs.InvalidArgument(err.Error())
+	}
+
+	baseline, err := models.GetBenchmarkBaseline(ctx, report.ProjectID)
+	if err != nil && err != models.ErrBenchmarkRunNotFound {
+		rlog.Error("failed to get benchmark baseline", "error", err)
+		return nil, errs.Internal("failed to get benchmark baseline")
+	}
+
+	// Anyone may seed the first baseline of a project; replacing one is up
+	// to the head of the report's department
+	if req.SetBaseline && baseline != nil {
+		department, err := models.GetDepartmentByID(ctx, report.DepartmentID)
+		if err != nil || department.HeadID != userID {
+			return nil, errs.Permission("only the department head can replace the project's benchmark baseline")
+		}
+	}
+
+	var baselineOutput *benchmark.Output
+	if baseline != nil {
+		baselineOutput = benchmarkOutputFromModel(baseline)
+	}
+	suggestion := benchmark.Compare(output, baselineOutput, regressionThreshold)
+
+	run := &models.BenchmarkRun{
+		ReportID:           report.ID,
+		ProjectID:          report.ProjectID,
+		UploadedBy:         userID,
+		GoOS:               output.GoOS,
+		GoArch:             output.GoArch,
+		CPU:                output.CPU,
+		Results:            make([]models.BenchmarkResult, len(output.Results)),
+		Comparisons:        make([]models.BenchmarkComparison, len(suggestion.Comparisons)),
+		Unmatched:          suggestion.Unmatched,
+		Regressions:        suggestion.Regressions,
+		PerformanceScore:   suggestion.PerformanceScore,
+		MemoryScore:        suggestion.MemoryScore,
+		PerformanceDetails: suggestion.PerformanceDetails,
+		MemoryDetails:      suggestion.MemoryDetails,
+	}
+	for i, r := range output.Results {
+		run.Results[i] = models.BenchmarkResult(*r)
+	}
+	for i, c := range suggestion.Comparisons {
+		run.Comparisons[i] = models.BenchmarkComparison(*c)
+	}
+	if baseline != nil {
+		run.BaselineRunID = baseline.ID
+		run.BaselineReportID = baseline.ReportID
+	}
+
+	if req.Apply {
+		if run.PerformanceScore == nil && run.MemoryScore == nil {
+			return nil, errs.InvalidArgument("no scores to apply: " + run.PerformanceDetails)
+		}
+		_, err = recordDimension(ctx, report.ID, userID, req.IfMatch, func(e *EvaluateReportRequest) {
+			if run.PerformanceScore != nil {
+				e.PerformanceScore = *run.PerformanceScore
+				e.PerformanceDetails = run.PerformanceDetails
+			}
+			if run.MemoryScore != nil {
+				e.MemoryScore = *run.MemoryScore
+				e.MemoryDetails = run.MemoryDetails
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		run.Applied = true
+	}
+
+	if err := models.SaveBenchmarkRun(ctx, run); err != nil {
+		rlog.Error("failed to save benchmark run", "error", err)
+		return nil, errs.Internal("failed to save benchmark run")
+	}
+	if req.SetBaseline {
+		if err := models.SetBenchmarkBaseline(ctx, run); err != nil {
+			rlog.Error("failed to set benchmark baseline", "error", err)
+			return nil, errs.Internal("failed to set benchmark baseline")
+		}
+	}
+
+	return convertModelToAPIBenchmarkRun(ctx, run), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/evaluation/benchmarks
+func GetBenchmarks(ctx context.Context, id string) (*BenchmarkRun, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check, trashed reports not served
+	// - Performance (P8): Single lookup by report ID
+	// - Memory (M8): Returns the stored run only
+	// - Testing (T7): Missing and stored runs tested, including the baseline comparison
+	// - Error (E8): Missing uploads reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database; trashed reports are not found
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	run, err := models.GetBenchmarkRunByReportID(ctx, id)
+	if err != nil {
+		if err == models.ErrBenchmarkRunNotFound {
+			return nil, errs.NotFound("no benchmarks have been uploaded for this report")
+		}
+		rlog.Error("failed to get benchmark run", "error", err)
+		return nil, errs.Internal("failed to get benchmark run")
+	}
+	return convertModelToAPIBenchmarkRun(ctx, run), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/projects/:id/benchmark-baseline
+func GetBenchmarkBaseline(ctx context.Context, id string) (*BenchmarkRun, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check
+	// - Performance (P8): Single lookup by project ID
+	// - Memory (M8): Returns the stored run only
+	// - Testing (T7): Missing, seeded and replaced baselines tested
+	// - Error (E8): Projects without a baseline reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	run, err := models.GetBenchmarkBaseline(ctx, id)
+	if err != nil {
+		if err == models.ErrBenchmarkRunNotFound {
+			return nil, errs.NotFound("the project has no benchmark baseline")
+		}
+		rlog.Error("failed to get benchmark baseline", "error", err)
+		return nil, errs.Internal("failed to get benchmark baseline")
+	}
+	return convertModelToAPIBenchmarkRun(ctx, run), nil
+}
+
+// BE-IN - Internal backend only
+// promoteBenchmarkBaseline makes the latest benchmark run of an approved
+// report the baseline of its project.
+func promoteBenchmarkBaseline(ctx context.Context, event *events.ReportApproved) error {
+	run, err := models.GetBenchmarkRunByReportID(ctx, event.ReportID)
+	if err == models.ErrBenchmarkRunNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if run.ProjectID != event.ProjectID {
+		return nil
+	}
+	rlog.Info("promoting benchmark baseline", "project_id", run.ProjectID, "report_id", run.ReportID, "run_id", run.ID)
+	return models.SetBenchmarkBaseline(ctx, run)
+}
+
+// BE-IN - Internal backend only
+func benchmarkOutputFromModel(run *models.BenchmarkRun) *benchmark.Output {
+	output := &benchmark.Output{
+		GoOS:    run.GoOS,
+		GoArch:  run.GoArch,
+		CPU:     run.CPU,
+		Results: make([]*benchmark.Result, len(run.Results)),
+	}
+	for i, r := range run.Results {
+		result := benchmark.Result(r)
+		output.Results[i] = &result
+	}
+	return output
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIBenchmarkRun(ctx context.Context, model *models.BenchmarkRun) *BenchmarkRun {
+	run := &BenchmarkRun{
+		ID:                 model.ID,
+		ReportID:           model.ReportID,
+		ProjectID:          model.ProjectID,
+		UploadedBy:         model.UploadedBy,
+		GoOS:               model.GoOS,
+		GoArch:             model.GoArch,
+		CPU:                model.CPU,
+		Results:            make([]*BenchmarkResult, len(model.Results)),
+		BaselineRunID:      model.BaselineRunID,
+		BaselineReportID:   model.BaselineReportID,
+		Comparisons:        make([]*BenchmarkComparison, len(model.Comparisons)),
+		Unmatched:          model.Unmatched,
+		Regressions:        model.Regressions,
+		PerformanceScore:   model.PerformanceScore,
+		MemoryScore:        model.MemoryScore,
+		PerformanceDetails: model.PerformanceDetails,
+		MemoryDetails:      model.MemoryDetails,
+		Applied:            model.Applied,
+		CreatedAt:          model.CreatedAt,
+	}
+	for i, r := range model.Results {
+		result := BenchmarkResult(r)
+		run.Results[i] = &result
+	}
+	for i, c := range model.Comparisons {
+		comparison := BenchmarkComparison(c)
+		run.Comparisons[i] = &comparison
+	}
+	if baseline, err := models.GetBenchmarkBaseline(ctx, model.ProjectID); err == nil {
+		run.IsBaseline = baseline.ID == model.ID
+	}
+	return run
+}
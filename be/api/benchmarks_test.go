@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestBenchmarkBaseline(t *testing.T) {
+	ctx := context.Background()
+	const project = "project-benchmarks"
+
+	report := func(title string) *models.Report {
+		t.Helper()
+		r := &models.Report{Title: title, ProjectID: project, AuthorID: "user-123", DepartmentID: "dept-456", Status: "draft"}
+		if err := models.SaveReport(ctx, r); err != nil {
+			t.Fatalf("SaveReport: %v", err)
+		}
+		t.Cleanup(func() { models.DeleteReport(ctx, r.ID) })
+		return r
+	}
+	output := func(nsPerOp string) string {
+		return "goos: linux\ngoarch: amd64\nBenchmarkParse-8   1000   " + nsPerOp + " ns/op   64 B/op   2 allocs/op\n"
+	}
+
+	if _, err := GetBenchmarkBaseline(ctx, project); err == nil || !strings.Contains(err.Error(), "no benchmark baseline") {
+		t.Fatalf("GetBenchmarkBaseline before any upload = %v, want not found", err)
+	}
+
+	// Anyone may seed the first baseline
+	first := report("Parser rewrite")
+	seeded, err := IngestBenchmarks(ctx, first.ID, &IngestBenchmarksRequest{Output: output("1000"), SetBaseline: true})
+	if err != nil {
+		t.Fatalf("IngestBenchmarks: %v", err)
+	}
+	baseline, err := GetBenchmarkBaseline(ctx, project)
+	if err != nil || baseline.ID != seeded.ID {
+		t.Fatalf("GetBenchmarkBaseline = %v, %v, want run %s", baseline, err, seeded.ID)
+	}
+
+	second := report("Parser follow-up")
+	if _, err := GetBenchmarks(ctx, second.ID); err == nil || !strings.Contains(err.Error(), "no benchmarks have been uploaded") {
+		t.Errorf("GetBenchmarks before an upload = %v, want not found", err)
+	}
+	slower, err := IngestBenchmarks(ctx, second.ID, &IngestBenchmarksRequest{Output: output("2000")})
+	if err != nil {
+		t.Fatalf("IngestBenchmarks: %v", err)
+	}
+	stored, err := GetBenchmarks(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("GetBenchmarks: %v", err)
+	}
+	if stored.ID != slower.ID || stored.BaselineReportID != first.ID || stored.Regressions != 1 {
+		t.Errorf("GetBenchmarks = run %s against %s with %d regressions, want run %s against %s with 1", stored.ID, stored.BaselineReportID, stored.Regressions, slower.ID, first.ID)
+	}
+
+	// Replacing the baseline is up to the head of dept-456
+	replace := &IngestBenchmarksRequest{Output: output("2000"), SetBaseline: true}
+	if _, err := IngestBenchmarks(ctx, second.ID, replace); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Errorf("replacing the baseline as the author = %v, want permission denied", err)
+	}
+	t.Setenv("DEMO_USER_ID", "user-789")
+	replaced, err := IngestBenchmarks(ctx, second.ID, replace)
+	if err != nil {
+		t.Fatalf("IngestBenchmarks as the department head: %v", err)
+	}
+	if baseline, err := GetBenchmarkBaseline(ctx, project); err != nil || baseline.ID != replaced.ID {
+		t.Errorf("GetBenchmarkBaseline = %v, %v, want run %s", baseline, err, replaced.ID)
+	}
+}
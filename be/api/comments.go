@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type Comment struct {
+	ID         string     `json:"id"`
+	ReportID   string     `json:"report_id"`
+	ParentID   string     `json:"parent_id,omitempty"`
+	Dimension  string     `json:"dimension,omitempty"`
+	AuthorID   string     `json:"author_id"`
+	Body       string     `json:"body"`
+	Mentions   []string   `json:"mentions,omitempty"`
+	Resolved   bool       `json:"resolved"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+type CommentThread struct {
+	Comment
+	Replies []Comment `json:"replies"`
+}
+
+// BE-IN - Internal backend only
+type CreateCommentRequest struct {
+	Body      string  `json:"body" validate:"required,min=1"`
+	ParentID  *string `json:"parent_id,omitempty"`
+	Dimension *string `json:"dimension,omitempty" validate:"omitempty,oneof=security performance memory testing error load"`
+}
+
+// BE-IN - Internal backend only
+type ListCommentsRequest struct {
+	Dimension *string `json:"dimension,omitempty"`
+	Resolved  *bool   `json:"resolved,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type ListCommentsResponse struct {
+	Threads []CommentThread `json:"threads"`
+	Total   int             `json:"total"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/comments
+func CreateComment(ctx context.Context, id string, req *CreateCommentRequest) (*Comment, error) {
+	return idempotent(ctx, req, func() (*Comment, error) { return createComment(ctx, id, req) })
+}
+
+// BE-IN - Internal backend only
+func createComment(ctx context.Context, id string, req *CreateCommentRequest) (*Comment, error) {
+	// Score: [S7,P8,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Authentication check, parent comment must belong to the report, only known users are mentioned
+	// - Performance (P8): Single in-memory write, mention emails sent by a subscriber
+	// - Memory (M7): No copies beyond the stored comment
+	// - Testing (T7): Mention filtering, reply threading and parent checks tested
+	// - Error (E8): Validation of dimension, parent and body
+	// - Load (L7): Handles bursts of review activity
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errs.InvalidArgument("comment body is required")
+	}
+
+	// Get report from database
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	comment := &models.Comment{
+		ReportID:  id,
+		AuthorID:  userID,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+
+	// Handles that do not name a user are plain text
+	for _, handle := range models.ParseMentions(body) {
+		if _, err := models.GetUserByID(ctx, handle); err == nil {
+			comment.Mentions = append(comment.Mentions, handle)
+		}
+	}
+
+	if req.Dimension != nil {
+		if !models.IsEvaluationDimension(*req.Dimension) {
+			return nil, errs.InvalidArgument("unknown evaluation dimension")
+		}
+		comment.Dimension = *req.Dimension
+	}
+
+	// Replies always attach to the root of the thread and share its dimension
+	if req.ParentID != nil {
+		parent, err := models.GetCommentByID(ctx, *req.ParentID)
+		if err != nil {
+			if err == models.ErrCommentNotFound {
+				return nil, errs.InvalidArgument("parent comment not found")
+			}
+			rlog.Error("failed to get comment", "error", err)
+			return nil, errs.Internal("failed to get comment")
+		}
+		if parent.ReportID != id {
+			return nil, errs.InvalidArgument("parent comment belongs to another report")
+		}
+		if parent.ParentID != "" {
+			parent, err = models.GetCommentByID(ctx, parent.ParentID)
+			if err != nil {
+				rlog.Error("failed to get comment", "error", err)
+				return nil, errs.Internal("failed to get comment")
+			}
+		}
+		comment.ParentID = parent.ID
+		comment.Dimension = parent.Dimension
+	}
+
+	// Save to database
+	err = models.SaveComment(ctx, comment)
+	if err != nil {
+		rlog.Error("failed to save comment", "error", err)
+		return nil, errs.Internal("failed to save comment")
+	}
+
+	publishCommentCreated(ctx, comment)
+
+	return convertModelToAPIComment(comment), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/comments
+func ListComments(ctx context.Context, id string, req *ListCommentsRequest) (*ListCommentsResponse, error) {
+	// Score: [S7,P7,M6,T7,E7,L7]
+	// Details:
+	// - Security (S7): Authentication check
+	// - Performance (P7): Single pass to group replies under their thread
+	// - Memory (M6): Builds thread structure in memory
+	// - Testing (T7): Thread grouping, filters and missing reports tested
+	// - Error (E7): Not found for a missing report, as on create
+	// - Load (L7): Comment volume per report is small
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	comments, err := models.ListCommentsByReportID(ctx, id)
+	if err != nil {
+		rlog.Error("failed to list comments", "error", err)
+		return nil, errs.Internal("failed to list comments")
+	}
+
+	// Group replies under their thread root
+	var threads []CommentThread
+	index := make(map[string]int)
+	for i := range comments {
+		comment := &comments[i]
+		if comment.ParentID != "" {
+			continue
+		}
+		if req.Dimension != nil && comment.Dimension != *req.Dimension {
+			continue
+		}
+		if req.Resolved != nil && comment.Resolved != *req.Resolved {
+			continue
+		}
+		index[comment.ID] = len(threads)
+		threads = append(threads, CommentThread{
+			Comment: *convertModelToAPIComment(comment),
+			Replies: []Comment{},
+		})
+	}
+	for i := range comments {
+		comment := &comments[i]
+		if pos, ok := index[comment.ParentID]; ok {
+			threads[pos].Replies = append(threads[pos].Replies, *convertModelToAPIComment(comment))
+		}
+	}
+
+	if threads == nil {
+		threads = []CommentThread{}
+	}
+
+	return &ListCommentsResponse{
+		Threads: threads,
+		Total:   len(threads),
+	}, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/comments/:id/resolve
+func ResolveComment(ctx context.Context, id string) (*Comment, error) {
+	return setCommentResolved(ctx, id, true)
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/comments/:id/unresolve
+func UnresolveComment(ctx context.Context, id string) (*Comment, error) {
+	return setCommentResolved(ctx, id, false)
+}
+
+// BE-IN - Internal backend only
+func setCommentResolved(ctx context.Context, id string, resolved bool) (*Comment, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check, records who resolved the thread
+	// - Performance (P8): Single lookup and write
+	// - Memory (M8): Updates in place
+	// - Testing (T7): Resolving, replies and trashed reports tested
+	// - Error (E8): Rejects replies and missing comments
+	// - Load (L8): Lightweight state toggle
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	comment, err := models.GetCommentByID(ctx, id)
+	if err != nil {
+		if err == models.ErrCommentNotFound {
+			return nil, errs.NotFound("comment not found")
+		}
+		rlog.Error("failed to get comment", "error", err)
+		return nil, errs.Internal("failed to get comment")
+	}
+
+	// Comments of a trashed report stay as they are until it is restored
+	if _, err := models.GetReportByID(ctx, comment.ReportID); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("comment not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	// Only thread roots can be resolved
+	if comment.ParentID != "" {
+		return nil, errs.InvalidArgument("only the first comment of a thread can be resolved")
+	}
+
+	comment.Resolved = resolved
+	if resolved {
+		now := time.Now()
+		comment.ResolvedBy = userID
+		comment.ResolvedAt = &now
+	} else {
+		comment.ResolvedBy = ""
+		comment.ResolvedAt = nil
+	}
+
+	// Save to database
+	err = models.SaveComment(ctx, comment)
+	if err != nil {
+		rlog.Error("failed to save comment", "error", err)
+		return nil, errs.Internal("failed to save comment")
+	}
+
+	return convertModelToAPIComment(comment), nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIComment(model *models.Comment) *Comment {
+	return &Comment{
+		ID:         model.ID,
+		ReportID:   model.ReportID,
+		ParentID:   model.ParentID,
+		Dimension:  model.Dimension,
+		AuthorID:   model.AuthorID,
+		Body:       model.Body,
+		Mentions:   model.Mentions,
+		Resolved:   model.Resolved,
+		ResolvedBy: model.ResolvedBy,
+		ResolvedAt: model.ResolvedAt,
+		CreatedAt:  model.CreatedAt,
+		UpdatedAt:  model.UpdatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"encore.app/services/notifications"
+)
+
+func TestListCommentsMissingReport(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := ListComments(ctx, "report-missing", &ListCommentsRequest{}); err == nil || !strings.Contains(err.Error(), "report not found") {
+		t.Errorf("ListComments on a missing report = %v, want not found", err)
+	}
+	if _, err := ListComments(ctx, "report-123", &ListCommentsRequest{}); err != nil {
+		t.Errorf("ListComments: %v", err)
+	}
+}
+
+// recordingSender keeps the notifications instead of sending them.
+type recordingSender struct {
+	messages []notifications.Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, message notifications.Message) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestCreateCommentNotifiesMentions(t *testing.T) {
+	sender := &recordingSender{}
+	saved := notifier
+	notifier = notifications.NewNotifier(sender, "")
+	t.Cleanup(func() { notifier = saved })
+
+	ctx := context.Background()
+	report := draftReport(t)
+
+	// The author, an unknown handle and trailing punctuation are not notified
+	comment, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "@user-456, @user-123 and @nobody: thanks @user-789."})
+	if err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	if want := []string{"user-456", "user-123", "user-789"}; !reflect.DeepEqual(comment.Mentions, want) {
+		t.Errorf("Mentions = %q, want %q", comment.Mentions, want)
+	}
+
+	var to []string
+	for _, message := range sender.messages {
+		to = append(to, message.To)
+	}
+	want := []string{"sam@example.com", "jordan@example.com"}
+	if !reflect.DeepEqual(to, want) {
+		t.Errorf("notified %q, want %q", to, want)
+	}
+}
+
+func TestCommentThreads(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+	other := draftReport(t)
+
+	security := "security"
+	root, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "Is the token logged?", Dimension: &security})
+	if err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	general, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "Looks good overall"})
+	if err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	reply, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "No, it is redacted", ParentID: &root.ID})
+	if err != nil {
+		t.Fatalf("CreateComment reply: %v", err)
+	}
+
+	// A reply to a reply joins the root's thread and dimension
+	nested, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "Thanks", ParentID: &reply.ID})
+	if err != nil {
+		t.Fatalf("CreateComment nested reply: %v", err)
+	}
+	if nested.ParentID != root.ID || nested.Dimension != "security" {
+		t.Errorf("nested reply has parent %q and dimension %q, want %q and security", nested.ParentID, nested.Dimension, root.ID)
+	}
+
+	if _, err := CreateComment(ctx, other.ID, &CreateCommentRequest{Body: "Wrong report", ParentID: &root.ID}); err == nil || !strings.Contains(err.Error(), "another report") {
+		t.Errorf("reply on another report = %v, want rejected", err)
+	}
+	if _, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "   "}); err == nil || !strings.Contains(err.Error(), "body is required") {
+		t.Errorf("blank comment = %v, want rejected", err)
+	}
+
+	if _, err := ResolveComment(ctx, reply.ID); err == nil || !strings.Contains(err.Error(), "only the first comment") {
+		t.Errorf("resolving a reply = %v, want rejected", err)
+	}
+	resolved, err := ResolveComment(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("ResolveComment: %v", err)
+	}
+	if !resolved.Resolved || resolved.ResolvedBy != "user-123" || resolved.ResolvedAt == nil {
+		t.Errorf("resolved comment = %+v, want resolved by user-123", resolved)
+	}
+
+	threads := func(req *ListCommentsRequest) []string {
+		t.Helper()
+		resp, err := ListComments(ctx, report.ID, req)
+		if err != nil {
+			t.Fatalf("ListComments: %v", err)
+		}
+		var got []string
+		for _, thread := range resp.Threads {
+			entry := thread.Body
+			for _, r := range thread.Replies {
+				entry += " > " + r.Body
+			}
+			got = append(got, entry)
+		}
+		return got
+	}
+
+	open := false
+	for _, tt := range []struct {
+		name string
+		req  *ListCommentsRequest
+		want []string
+	}{
+		{"all", &ListCommentsRequest{}, []string{"Is the token logged? > No, it is redacted > Thanks", general.Body}},
+		{"dimension", &ListCommentsRequest{Dimension: &security}, []string{"Is the token logged? > No, it is redacted > Thanks"}},
+		{"unresolved", &ListCommentsRequest{Resolved: &open}, []string{general.Body}},
+	} {
+		if got := threads(tt.req); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: threads = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
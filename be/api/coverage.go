@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/coverage"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// Upload limits for coverage ingestion
+const (
+	maxCoverageProfileBytes = 32 << 20
+	maxTestOutputBytes      = 64 << 20
+)
+
+// BE-IN - Internal backend only
+// coverageMapping is read once at startup; an invalid configuration falls
+// back to the default mapping.
+var coverageMapping = loadCoverageMapping()
+
+// BE-IN - Internal backend only
+func loadCoverageMapping() coverage.Mapping {
+	mapping, err := coverage.MappingFromEnv()
+	if err != nil {
+		rlog.Error("invalid coverage score mapping, using the default", "error", err)
+	}
+	return mapping
+}
+
+// BE-IN - Internal backend only
+type IngestCoverageRequest struct {
+	// Profile is the content of a go test -coverprofile file
+	Profile string `json:"profile" validate:"required"`
+	// TestJSON is the optional output of go test -json
+	TestJSON string `json:"test_json,omitempty"`
+	Apply    bool   `json:"apply,omitempty"`
+	// IfMatch is the report ETag the client read; applying is refused
+	// when the report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+type CoveragePackage struct {
+	Package    string  `json:"package"`
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percent    float64 `json:"percent"`
+	Passed     int     `json:"passed"`
+	Failed     int     `json:"failed"`
+	Skipped    int     `json:"skipped"`
+	Flaky      int     `json:"flaky"`
+}
+
+// BE-IN - Internal backend only
+// CoverageTests is present when go test -json output was uploaded.
+type CoverageTests struct {
+	Passed         int      `json:"passed"`
+	Failed         int      `json:"failed"`
+	Skipped        int      `json:"skipped"`
+	Flaky          int      `json:"flaky"`
+	FailedTests    []string `json:"failed_tests,omitempty"`
+	FlakyTests     []string `json:"flaky_tests,omitempty"`
+	FailedPackages []string `json:"failed_packages,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type CoverageRun struct {
+	ID             string             `json:"id"`
+	ReportID       string             `json:"report_id"`
+	UploadedBy     string             `json:"uploaded_by"`
+	Mode           string             `json:"mode"`
+	Statements     int                `json:"statements"`
+	Covered        int                `json:"covered"`
+	Percent        float64            `json:"percent"`
+	Tests          *CoverageTests     `json:"tests,omitempty"`
+	Packages       []*CoveragePackage `json:"packages"`
+	SuggestedScore int                `json:"suggested_score"`
+	Details        string             `json:"details"`
+	Applied        bool               `json:"applied"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+func (r *IngestCoverageRequest) Validate() error {
+	var v ValidationErrors
+	v.required("profile", r.Profile)
+	if len(r.Profile) > maxCoverageProfileBytes {
+		v.add("profile", "must be at most %d MB", maxCoverageProfileBytes>>20)
+	}
+	if len(r.TestJSON) > maxTestOutputBytes {
+		v.add("test_json", "must be at most %d MB", maxTestOutputBytes>>20)
+	}
+	return v.err()
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/evaluation/coverage
+func IngestCoverage(ctx context.Context, id string, req *IngestCoverageRequest) (*CoverageRun, error) {
+	// Score: [S8,P7,M6,T7,E8,L6]
+	// Details:
+	// - Security (S8): Uploads parsed as text only, size bounded, mapping is server-side
+	// - Performance (P7): Streaming parsers over the uploaded text
+	// - Memory (M6): Whole upload held in memory while parsing
+	// - Testing (T7): Parsing and scoring live in a pure library
+	// - Error (E8): Parse errors returned with input and line
+	// - Load (L6): Intended for CI runs rather than interactive traffic
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	var tests io.Reader
+	if req.TestJSON != "" {
+		tests = strings.NewReader(req.TestJSON)
+	}
+	result, err := coverage.Analyze(strings.NewReader(req.Profile), tests, coverageMapping)
+	if err != nil {
+		return nil, errs.InvalidArgument(err.Error())
+	}
+
+	run := convertCoverageResult(result)
+	run.ReportID = report.ID
+	run.UploadedBy = userID
+
+	if req.Apply {
+		_, err = recordDimension(ctx, report.ID, userID, req.IfMatch, func(e *EvaluateReportRequest) {
+			e.TestingScore = run.SuggestedScore
+			e.TestingDetails = run.Details
+		})
+		if err != nil {
+			return nil, err
+		}
+		run.Applied = true
+	}
+
+	if err := models.SaveCoverageRun(ctx, run); err != nil {
+		rlog.Error("failed to save coverage run", "error", err)
+		return nil, errs.Internal("failed to save coverage run")
+	}
+
+	return convertModelToAPICoverageRun(run), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/evaluation/coverage
+func GetCoverage(ctx context.Context, id string) (*CoverageRun, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check, trashed reports not served
+	// - Performance (P8): Single lookup by report ID
+	// - Memory (M8): Returns the stored run only
+	// - Testing (T7): Missing, stored, trashed and restored uploads tested
+	// - Error (E8): Missing uploads reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database; trashed reports are not found
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	run, err := models.GetCoverageRunByReportID(ctx, id)
+	if err != nil {
+		if err == models.ErrCoverageRunNotFound {
+			return nil, errs.NotFound("no coverage has been uploaded for this report")
+		}
+		rlog.Error("failed to get coverage run", "error", err)
+		return nil, errs.Internal("failed to get coverage run")
+	}
+	return convertModelToAPICoverageRun(run), nil
+}
+
+// BE-IN - Internal backend only
+func convertCoverageResult(result *coverage.Result) *models.CoverageRun {
+	run := &models.CoverageRun{
+		Mode:           result.Mode,
+		Statements:     result.Statements,
+		Covered:        result.Covered,
+		Percent:        result.Percent,
+		Packages:       make([]models.CoveragePackage, len(result.Packages)),
+		SuggestedScore: result.SuggestedScore,
+		Details:        result.Details,
+	}
+	for i, pkg := range result.Packages {
+		run.Packages[i] = models.CoveragePackage(*pkg)
+	}
+	if tests := result.Tests; tests != nil {
+		run.HasTests = true
+		run.Passed = tests.Passed
+		run.Failed = tests.Failed
+		run.Skipped = tests.Skipped
+		run.Flaky = tests.Flaky
+		run.FailedTests = tests.FailedTests
+		run.FlakyTests = tests.FlakyTests
+		run.FailedPackages = tests.FailedPackages
+	}
+	return run
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPICoverageRun(model *models.CoverageRun) *CoverageRun {
+	run := &CoverageRun{
+		ID:             model.ID,
+		ReportID:       model.ReportID,
+		UploadedBy:     model.UploadedBy,
+		Mode:           model.Mode,
+		Statements:     model.Statements,
+		Covered:        model.Covered,
+		Percent:        model.Percent,
+		Packages:       make([]*CoveragePackage, len(model.Packages)),
+		SuggestedScore: model.SuggestedScore,
+		Details:        model.Details,
+		Applied:        model.Applied,
+		CreatedAt:      model.CreatedAt,
+	}
+	for i, pkg := range model.Packages {
+		p := CoveragePackage(pkg)
+		run.Packages[i] = &p
+	}
+	if model.HasTests {
+		run.Tests = &CoverageTests{
+			Passed:         model.Passed,
+			Failed:         model.Failed,
+			Skipped:        model.Skipped,
+			Flaky:          model.Flaky,
+			FailedTests:    model.FailedTests,
+			FlakyTests:     model.FlakyTests,
+			FailedPackages: model.FailedPackages,
+		}
+	}
+	return run
+}
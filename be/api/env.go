@@ -0,0 +1,25 @@
+package api
+
+import (
+	"os"
+	"time"
+
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// durationFromEnv reads a Go duration such as 24h from the environment
+// variable key at startup. An unset value gives fallback, and so does an
+// invalid or non-positive one, which is logged.
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		rlog.Error("invalid "+key+", using the default", "value", value, "default", fallback)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationFromEnv(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"":      time.Hour,
+		"90m":   90 * time.Minute,
+		"soon":  time.Hour,
+		"-1h":   time.Hour,
+		"0s":    time.Hour,
+		"720h0": time.Hour,
+	} {
+		t.Setenv("TEST_DURATION", value)
+		if got := durationFromEnv("TEST_DURATION", time.Hour); got != want {
+			t.Errorf("durationFromEnv with %q = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type EvaluateReportRequest struct {
+	SecurityScore      int    `json:"security_score" validate:"min=0,max=10"`
+	PerformanceScore   int    `json:"performance_score" validate:"min=0,max=10"`
+	MemoryScore        int    `json:"memory_score" validate:"min=0,max=10"`
+	TestingScore       int    `json:"testing_score" validate:"min=0,max=10"`
+	ErrorScore         int    `json:"error_score" validate:"min=0,max=10"`
+	LoadScore          int    `json:"load_score" validate:"min=0,max=10"`
+	SecurityDetails    string `json:"security_details,omitempty"`
+	PerformanceDetails string `json:"performance_details,omitempty"`
+	MemoryDetails      string `json:"memory_details,omitempty"`
+	TestingDetails     string `json:"testing_details,omitempty"`
+	ErrorDetails       string `json:"error_details,omitempty"`
+	LoadDetails        string `json:"load_details,omitempty"`
+	// IfMatch is the report ETag the client read; the evaluation is
+	// refused when the report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+func (r *EvaluateReportRequest) Validate() error {
+	var v ValidationErrors
+	v.score("security_score", r.SecurityScore)
+	v.score("performance_score", r.PerformanceScore)
+	v.score("memory_score", r.MemoryScore)
+	v.score("testing_score", r.TestingScore)
+	v.score("error_score", r.ErrorScore)
+	v.score("load_score", r.LoadScore)
+	return v.err()
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/evaluate
+func EvaluateReport(ctx context.Context, id string, req *EvaluateReportRequest) (*Report, error) {
+	return idempotent(ctx, req, func() (*Report, error) { return evaluateReport(ctx, id, req) })
+}
+
+// BE-IN - Internal backend only
+func evaluateReport(ctx context.Context, id string, req *EvaluateReportRequest) (*Report, error) {
+	// Score: [S7,P8,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Authentication check, scores range-checked
+	// - Performance (P8): Single read and write
+	// - Memory (M7): Updates the existing evaluation in place
+	// - Testing (T7): Notification and refusal after review tested with the review flow
+	// - Error (E8): Not found, validation and stale If-Match errors surfaced
+	// - Load (L7): Low write volume
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Hold the report so the version check and the write are atomic
+	report, unlock, err := lockReportForWrite(ctx, id, req.IfMatch)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	evaluation, err := recordEvaluation(ctx, report, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertModelToAPIReport(report, convertModelToAPIEvaluation(evaluation)), nil
+}
+
+// BE-IN - Internal backend only
+// recordEvaluation adds or replaces the evaluation of report on behalf of
+// userID and publishes EvaluationRecorded. It is shared by EvaluateReport,
+// UpdateReport and the endpoints that apply suggested scores. The caller
+// holds models.LockReport.
+func recordEvaluation(ctx context.Context, report *models.Report, userID string, req *EvaluateReportRequest) (*models.Evaluation, error) {
+	// Decided reports keep the evaluation they were decided on
+	if report.Status == "approved" || report.Status == "rejected" {
+		return nil, errs.InvalidArgument("report has already been reviewed")
+	}
+	if err := checkNotArchived(report); err != nil {
+		return nil, err
+	}
+
+	// Add or update the evaluation
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil {
+		if err != models.ErrEvaluationNotFound {
+			rlog.Error("failed to get evaluation", "error", err)
+			return nil, errs.Internal("failed to get evaluation")
+		}
+		evaluation = &models.Evaluation{
+			ReportID:  report.ID,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	evaluation.SecurityScore = req.SecurityScore
+	evaluation.PerformanceScore = req.PerformanceScore
+	evaluation.MemoryScore = req.MemoryScore
+	evaluation.TestingScore = req.TestingScore
+	evaluation.ErrorScore = req.ErrorScore
+	evaluation.LoadScore = req.LoadScore
+	evaluation.SecurityDetails = req.SecurityDetails
+	evaluation.PerformanceDetails = req.PerformanceDetails
+	evaluation.MemoryDetails = req.MemoryDetails
+	evaluation.TestingDetails = req.TestingDetails
+	evaluation.ErrorDetails = req.ErrorDetails
+	evaluation.LoadDetails = req.LoadDetails
+	evaluation.EvaluatorID = userID
+
+	// Save to database
+	err = models.SaveEvaluation(ctx, evaluation)
+	if err != nil {
+		rlog.Error("failed to save evaluation", "error", err)
+		return nil, errs.Internal("failed to save evaluation")
+	}
+
+	publishEvaluationRecorded(ctx, report, evaluation)
+
+	return evaluation, nil
+}
+
+// BE-IN - Internal backend only
+// recordDimension updates one dimension of the evaluation of report id and
+// keeps the others. Without an existing evaluation the other dimensions
+// start at zero. set receives the request prefilled with the current
+// evaluation. The report is locked from the read to the write, so two
+// uploads applying different dimensions both take effect.
+func recordDimension(ctx context.Context, id, userID, ifMatch string, set func(req *EvaluateReportRequest)) (*models.Evaluation, error) {
+	report, unlock, err := lockReportForWrite(ctx, id, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	req := &EvaluateReportRequest{}
+	current, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		rlog.Error("failed to get evaluation", "error", err)
+		return nil, errs.Internal("failed to get evaluation")
+	}
+	if current != nil {
+		*req = EvaluateReportRequest{
+			SecurityScore:      current.SecurityScore,
+			PerformanceScore:   current.PerformanceScore,
+			MemoryScore:        current.MemoryScore,
+			TestingScore:       current.TestingScore,
+			ErrorScore:         current.ErrorScore,
+			LoadScore:          current.LoadScore,
+			SecurityDetails:    current.SecurityDetails,
+			PerformanceDetails: current.PerformanceDetails,
+			MemoryDetails:      current.MemoryDetails,
+			TestingDetails:     current.TestingDetails,
+			ErrorDetails:       current.ErrorDetails,
+			LoadDetails:        current.LoadDetails,
+		}
+	}
+	set(req)
+	return recordEvaluation(ctx, report, userID, req)
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIEvaluation(model *models.Evaluation) *Evaluation {
+	if model == nil {
+		return nil
+	}
+	return &Evaluation{
+		SecurityScore:      model.SecurityScore,
+		PerformanceScore:   model.PerformanceScore,
+		MemoryScore:        model.MemoryScore,
+		TestingScore:       model.TestingScore,
+		ErrorScore:         model.ErrorScore,
+		LoadScore:          model.LoadScore,
+		SecurityDetails:    model.SecurityDetails,
+		PerformanceDetails: model.PerformanceDetails,
+		MemoryDetails:      model.MemoryDetails,
+		TestingDetails:     model.TestingDetails,
+		ErrorDetails:       model.ErrorDetails,
+		LoadDetails:        model.LoadDetails,
+		EvaluatorID:        model.EvaluatorID,
+		Version:            model.Version,
+	}
+}
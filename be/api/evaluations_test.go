@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"encore.app/models"
+)
+
+// draftReport saves a draft report for a test and deletes it afterwards.
+func draftReport(t *testing.T) *models.Report {
+	t.Helper()
+	ctx := context.Background()
+	report := &models.Report{
+		Title:        "Concurrency test",
+		ProjectID:    "project-456",
+		AuthorID:     "user-123",
+		DepartmentID: "dept-456",
+		Status:       "draft",
+	}
+	if err := models.SaveReport(ctx, report); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteReport(ctx, report.ID) })
+	return report
+}
+
+func TestRecordDimensionKeepsConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	// Uploads applying different dimensions at the same time must not
+	// overwrite each other's dimension with the value they read
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := recordDimension(ctx, report.ID, "user-123", "", func(e *EvaluateReportRequest) { e.TestingScore = 7 }); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := recordDimension(ctx, report.ID, "user-123", "", func(e *EvaluateReportRequest) { e.LoadScore = 4 }); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetEvaluationByReportID: %v", err)
+	}
+	if evaluation.TestingScore != 7 || evaluation.LoadScore != 4 {
+		t.Errorf("TestingScore = %d, LoadScore = %d, want 7 and 4", evaluation.TestingScore, evaluation.LoadScore)
+	}
+}
+
+func TestIngestCoverageIfMatch(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+	req := &IngestCoverageRequest{
+		Profile: "mode: set\nencore.app/api/reports.go:10.2,12.3 2 1\nencore.app/api/reports.go:14.2,15.3 1 0\n",
+		Apply:   true,
+		IfMatch: `"0.0"`,
+	}
+
+	if _, err := IngestCoverage(ctx, report.ID, req); err == nil || !strings.Contains(err.Error(), "changed by another request") {
+		t.Fatalf("IngestCoverage with a stale If-Match = %v, want a conflict", err)
+	}
+	if _, err := models.GetEvaluationByReportID(ctx, report.ID); err != models.ErrEvaluationNotFound {
+		t.Errorf("stale upload recorded an evaluation: %v", err)
+	}
+	if _, err := GetCoverage(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "no coverage has been uploaded") {
+		t.Errorf("GetCoverage after a stale upload = %v, want not found", err)
+	}
+
+	req.IfMatch = reportETag(report.Version, nil)
+	run, err := IngestCoverage(ctx, report.ID, req)
+	if err != nil {
+		t.Fatalf("IngestCoverage with the current If-Match: %v", err)
+	}
+	if !run.Applied {
+		t.Error("coverage was not applied")
+	}
+	if stored, err := GetCoverage(ctx, report.ID); err != nil || stored.ID != run.ID {
+		t.Errorf("GetCoverage = %v, %v, want run %s", stored, err, run.ID)
+	}
+
+	// The evaluation changed, so the tag read before is stale now
+	if _, err := IngestCoverage(ctx, report.ID, req); err == nil {
+		t.Error("IngestCoverage reused a stale If-Match")
+	}
+}
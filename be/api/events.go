@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"encore.app/events"
+	"encore.app/models"
+	"encore.dev/rlog"
+)
+
+// BE-OUT - External data involved
+// The publish helpers are called by handlers once a change is saved. A
+// publish failure is logged but does not fail the request, since the
+// change itself has already been stored.
+func publishReportCreated(ctx context.Context, report *Report, source string) {
+	_, err := events.ReportCreatedTopic.Publish(ctx, &events.ReportCreated{
+		ReportID:     report.ID,
+		ProjectID:    report.ProjectID,
+		DepartmentID: report.DepartmentID,
+		AuthorID:     report.AuthorID,
+		TemplateID:   report.TemplateID,
+		Source:       source,
+		CreatedAt:    report.CreatedAt,
+	})
+	if err != nil {
+		rlog.Error("failed to publish event", "event", events.NameReportCreated, "report_id", report.ID, "error", err)
+	}
+}
+
+// BE-OUT - External data involved
+func publishReportSubmitted(ctx context.Context, report *models.Report) {
+	submittedAt := report.UpdatedAt
+	if report.SubmittedAt != nil {
+		submittedAt = *report.SubmittedAt
+	}
+	_, err := events.ReportSubmittedTopic.Publish(ctx, &events.ReportSubmitted{
+		ReportID:     report.ID,
+		ProjectID:    report.ProjectID,
+		DepartmentID: report.DepartmentID,
+		AuthorID:     report.AuthorID,
+		SubmittedAt:  submittedAt,
+	})
+	if err != nil {
+		rlog.Error("failed to publish event", "event", events.NameReportSubmitted, "report_id", report.ID, "error", err)
+	}
+}
+
+// BE-OUT - External data involved
+func publishEvaluationRecorded(ctx context.Context, report *models.Report, evaluation *models.Evaluation) {
+	_, err := events.EvaluationRecordedTopic.Publish(ctx, &events.EvaluationRecorded{
+		ReportID:     report.ID,
+		EvaluationID: evaluation.ID,
+		ProjectID:    report.ProjectID,
+		DepartmentID: report.DepartmentID,
+		EvaluatorID:  evaluation.EvaluatorID,
+		RecordedAt:   evaluation.UpdatedAt,
+	})
+	if err != nil {
+		rlog.Error("failed to publish event", "event", events.NameEvaluationRecorded, "report_id", report.ID, "error", err)
+	}
+}
+
+// BE-OUT - External data involved
+func publishReportReviewed(ctx context.Context, report *models.Report, reviewerID, reason string) {
+	var err error
+	name := events.NameReportApproved
+	now := time.Now()
+	if report.Status == "rejected" {
+		name = events.NameReportRejected
+		_, err = events.ReportRejectedTopic.Publish(ctx, &events.ReportRejected{
+			ReportID:     report.ID,
+			ProjectID:    report.ProjectID,
+			DepartmentID: report.DepartmentID,
+			ReviewerID:   reviewerID,
+			Reason:       reason,
+			RejectedAt:   now,
+		})
+	} else {
+		_, err = events.ReportApprovedTopic.Publish(ctx, &events.ReportApproved{
+			ReportID:     report.ID,
+			ProjectID:    report.ProjectID,
+			DepartmentID: report.DepartmentID,
+			ReviewerID:   reviewerID,
+			Reason:       reason,
+			ApprovedAt:   now,
+		})
+	}
+	if err != nil {
+		rlog.Error("failed to publish event", "event", name, "report_id", report.ID, "error", err)
+	}
+}
+
+// BE-OUT - External data involved
+func publishCommentCreated(ctx context.Context, comment *models.Comment) {
+	_, err := events.CommentCreatedTopic.Publish(ctx, &events.CommentCreated{
+		CommentID: comment.ID,
+		ReportID:  comment.ReportID,
+		AuthorID:  comment.AuthorID,
+		CreatedAt: comment.CreatedAt,
+	})
+	if err != nil {
+		rlog.Error("failed to publish event", "event", events.NameCommentCreated, "report_id", comment.ReportID, "error", err)
+	}
+}
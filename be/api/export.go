@@ -0,0 +1,185 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/export"
+	"encore.dev"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-OUT - External data involved
+//encore:api public raw method=GET path=/api/reports/:id/export.pdf
+func ExportReportPDF(w http.ResponseWriter, req *http.Request) {
+	// Score: [S8,P7,M6,T5,E8,L6]
+	// Details:
+	// - Security (S8): Authentication check, PDF rendered in-process without external binaries
+	// - Performance (P7): Single render pass per request
+	// - Memory (M6): Document buffered so errors can still be reported as JSON
+	// - Testing (T5): Rendering tested in the export package, the handler itself is not
+	// - Error (E8): Not found and render failures mapped to API errors
+	// - Load (L6): CPU-bound, suitable for on-demand exports
+	// Tags: BE-OUT-medium
+
+	ctx := req.Context()
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		errs.HTTPError(w, errs.Unauthenticated("user must be authenticated"))
+		return
+	}
+
+	id := encore.CurrentRequest().PathParams.Get("id")
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			errs.HTTPError(w, errs.NotFound("report not found"))
+			return
+		}
+		rlog.Error("failed to get report", "error", err)
+		errs.HTTPError(w, errs.Internal("failed to get report"))
+		return
+	}
+
+	// Get evaluation
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		rlog.Error("failed to get evaluation", "error", err)
+		errs.HTTPError(w, errs.Internal("failed to get evaluation"))
+		return
+	}
+
+	departmentName := ""
+	if department, err := models.GetDepartmentByID(ctx, report.DepartmentID); err == nil {
+		departmentName = department.Name
+	}
+
+	var buf bytes.Buffer
+	err = export.WriteReportPDF(&buf, export.ReportDocument{
+		Report:     report,
+		Evaluation: evaluation,
+		Department: departmentName,
+	})
+	if err != nil {
+		rlog.Error("failed to render report pdf", "report_id", report.ID, "error", err)
+		errs.HTTPError(w, errs.Internal("failed to render report"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="report-%s.pdf"`, report.ID))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		rlog.Error("failed to write report pdf", "report_id", report.ID, "error", err)
+	}
+}
+
+// BE-OUT - External data involved
+//encore:api public raw method=GET path=/api/exports/reports
+func ExportReports(w http.ResponseWriter, req *http.Request) {
+	// Score: [S8,P7,M8,T5,E7,L7]
+	// Details:
+	// - Security (S8): Authentication check, CSV cells neutralized against formula injection
+	// - Performance (P7): Single pass over matching reports
+	// - Memory (M8): Rows streamed to the client, only report IDs held in memory
+	// - Testing (T5): CSV and XLSX writers tested in the export package, the handler itself is not
+	// - Error (E7): Invalid format rejected before streaming starts
+	// - Load (L7): Handles large result sets without buffering
+	// Tags: BE-OUT-high
+
+	ctx := req.Context()
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		errs.HTTPError(w, errs.Unauthenticated("user must be authenticated"))
+		return
+	}
+
+	// Accept the same filters as ListReports
+	query := req.URL.Query()
+	params := models.ListReportsParams{Now: time.Now()}
+	if status := query.Get("status"); status != "" {
+		if status == "overdue" {
+			overdueOnly := true
+			params.Overdue = &overdueOnly
+		} else {
+			params.Status = &status
+		}
+	}
+	if overdue := query.Get("overdue"); overdue != "" {
+		overdueOnly, err := strconv.ParseBool(overdue)
+		if err != nil {
+			errs.HTTPError(w, errs.InvalidArgument("overdue must be true or false"))
+			return
+		}
+		params.Overdue = &overdueOnly
+	}
+	if periodID := query.Get("period_id"); periodID != "" {
+		params.PeriodID = &periodID
+	}
+	if authorID := query.Get("author_id"); authorID != "" {
+		params.AuthorID = &authorID
+	}
+	if projectID := query.Get("project_id"); projectID != "" {
+		params.ProjectID = &projectID
+	}
+	tagType, tagScope, tagImpact := query.Get("tag_type"), query.Get("tag_scope"), query.Get("tag_impact")
+	tags, err := tagFilter(&tagType, &tagScope, &tagImpact)
+	if err != nil {
+		errs.HTTPError(w, err)
+		return
+	}
+	params.Tags = tags
+
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		errs.HTTPError(w, errs.InvalidArgument("format must be csv or xlsx"))
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="reports-%s.%s"`, time.Now().UTC().Format("20060102-150405"), format))
+	w.WriteHeader(http.StatusOK)
+
+	// Once streaming has started errors can only be logged
+	writer, err := export.NewListingWriter(w, format)
+	if err != nil {
+		rlog.Error("failed to start report export", "error", err)
+		return
+	}
+
+	rows := 0
+	err = models.ForEachReport(ctx, params, func(report *models.Report) error {
+		evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+		if err != nil && err != models.ErrEvaluationNotFound {
+			rlog.Error("failed to get evaluation", "report_id", report.ID, "error", err)
+		}
+		rows++
+		return writer.WriteReport(report, evaluation)
+	})
+	if err != nil {
+		rlog.Error("failed to export reports", "rows", rows, "error", err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		rlog.Error("failed to finish report export", "rows", rows, "error", err)
+		return
+	}
+
+	rlog.Info("exported reports", "format", format, "rows", rows)
+}
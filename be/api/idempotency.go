@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"encore.app/models"
+	"encore.dev"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+const (
+	// IdempotencyKeyHeader carries the client's key for a mutating request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// maxIdempotencyKeyLength bounds keys; a UUID fits comfortably
+	maxIdempotencyKeyLength = 255
+	// defaultIdempotencyWindow is how long a response is replayed
+	defaultIdempotencyWindow = 24 * time.Hour
+)
+
+// BE-IN - Internal backend only
+// idempotencyWindow is set by IDEMPOTENCY_WINDOW
+var idempotencyWindow = durationFromEnv("IDEMPOTENCY_WINDOW", defaultIdempotencyWindow)
+
+// BE-IN - Internal backend only
+// idempotent runs handle at most once per Idempotency-Key. The first
+// successful response is stored and replayed for repeats of the same
+// request within idempotencyWindow; a repeat that arrives while the first
+// is still running waits for it. Failed requests release the key so they
+// can be retried. Requests without the header run as usual.
+func idempotent[T any](ctx context.Context, req interface{}, handle func() (*T, error)) (*T, error) {
+	// Score: [S8,P7,M7,T7,E8,L8]
+	// Details:
+	// - Security (S8): Keys scoped to user and endpoint, fingerprint compared on reuse
+	// - Performance (P7): One hash and one JSON encoding per keyed request
+	// - Memory (M7): Stores the encoded response for the window
+	// - Testing (T7): Wraps handlers without changing them
+	// - Error (E8): Reuse and validation errors surfaced, failures release the key
+	// - Load (L8): Concurrent repeats wait instead of running twice
+	// Tags: BE-module-medium
+
+	current := encore.CurrentRequest()
+	key := current.Headers.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return handle()
+	}
+	if !validIdempotencyKey(key) {
+		return nil, errs.InvalidArgument(fmt.Sprintf("%s must be 1 to %d printable ASCII characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+	}
+
+	// Unauthenticated requests are rejected by the handler itself
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return handle()
+	}
+
+	fingerprint, err := requestFingerprint(current.Path, req)
+	if err != nil {
+		rlog.Error("failed to fingerprint request", "endpoint", current.Endpoint, "error", err)
+		return nil, errs.Internal("failed to process idempotency key")
+	}
+
+	for {
+		record, claimed, err := models.ClaimIdempotencyKey(ctx, userID, current.Endpoint, key, fingerprint, idempotencyWindow)
+		if err == models.ErrIdempotencyKeyReused {
+			return nil, errs.InvalidArgument(IdempotencyKeyHeader + " was already used for a different request")
+		}
+		if err != nil {
+			rlog.Error("failed to claim idempotency key", "endpoint", current.Endpoint, "error", err)
+			return nil, errs.Internal("failed to process idempotency key")
+		}
+		if claimed {
+			break
+		}
+		if record.Completed {
+			var replay T
+			if err := json.Unmarshal(record.Response, &replay); err != nil {
+				rlog.Error("failed to replay idempotent response", "endpoint", current.Endpoint, "error", err)
+				return nil, errs.Internal("failed to replay response")
+			}
+			if tagged, ok := any(&replay).(etagResponse); ok {
+				tagged.setETag(record.ETag)
+			}
+			return &replay, nil
+		}
+
+		// Wait for the request holding the key, then look again
+		select {
+		case <-record.Done():
+		case <-ctx.Done():
+			return nil, errs.Aborted("a request with this " + IdempotencyKeyHeader + " is still in progress")
+		}
+	}
+
+	completed := false
+	defer func() {
+		if !completed {
+			models.ReleaseIdempotencyKey(ctx, userID, current.Endpoint, key)
+		}
+	}()
+
+	resp, err := handle()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		rlog.Error("failed to store idempotent response", "endpoint", current.Endpoint, "error", err)
+		return resp, nil
+	}
+	etag := ""
+	if tagged, ok := any(resp).(etagResponse); ok {
+		etag = tagged.etag()
+	}
+	if err := models.CompleteIdempotencyKey(ctx, userID, current.Endpoint, key, raw, etag); err != nil {
+		rlog.Error("failed to store idempotent response", "endpoint", current.Endpoint, "error", err)
+		return resp, nil
+	}
+	completed = true
+	return resp, nil
+}
+
+// BE-IN - Internal backend only
+// etagResponse is a response carrying an ETag header. Headers are not part
+// of the stored JSON body, so the ETag is kept alongside it for replays.
+type etagResponse interface {
+	etag() string
+	setETag(etag string)
+}
+
+// BE-IN - Internal backend only
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// BE-IN - Internal backend only
+// requestFingerprint hashes the path, which holds the path parameters,
+// and the decoded request body.
+func requestFingerprint(path string, req interface{}) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(path+"\n"), body...))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,392 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"encore.app/events"
+	"encore.app/models"
+	"encore.app/services/importer"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// Import modes
+const (
+	ImportModeAtomic  = "atomic"
+	ImportModeChunked = "chunked"
+)
+
+// BE-IN - Internal backend only
+const (
+	defaultImportChunkSize = 500
+	maxImportChunkSize     = 5000
+)
+
+// BE-IN - Internal backend only
+type ImportReportsRequest struct {
+	Format    string  `json:"format" validate:"required,oneof=csv jsonl"`
+	Data      string  `json:"data" validate:"required"`
+	DryRun    bool    `json:"dry_run,omitempty"`
+	Mode      string  `json:"mode,omitempty" validate:"omitempty,oneof=atomic chunked"`
+	ChunkSize int     `json:"chunk_size,omitempty"`
+	JobID     *string `json:"job_id,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// BE-IN - Internal backend only
+type ImportReportsResponse struct {
+	JobID     string           `json:"job_id,omitempty"`
+	Status    string           `json:"status"`
+	DryRun    bool             `json:"dry_run"`
+	TotalRows int              `json:"total_rows"`
+	ValidRows int              `json:"valid_rows"`
+	NextRow   int              `json:"next_row"`
+	Imported  int              `json:"imported"`
+	Failed    int              `json:"failed"`
+	Done      bool             `json:"done"`
+	ReportIDs []string         `json:"report_ids"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/imports/reports
+func ImportReports(ctx context.Context, req *ImportReportsRequest) (*ImportReportsResponse, error) {
+	return idempotent(ctx, req, func() (*ImportReportsResponse, error) { return importReports(ctx, req) })
+}
+
+// BE-IN - Internal backend only
+func importReports(ctx context.Context, req *ImportReportsRequest) (*ImportReportsResponse, error) {
+	// Score: [S7,P7,M6,T7,E9,L6]
+	// Details:
+	// - Security (S7): Authentication check, rows validated with CreateReportRequest rules, job ownership enforced
+	// - Performance (P7): Validation in one pass, chunked writes
+	// - Memory (M6): Whole payload parsed per request, chunk size bounds writes
+	// - Testing (T7): Dry-run mode doubles as a validation harness
+	// - Error (E9): Per-row, per-field errors; atomic mode rolls back on failure
+	// - Load (L6): Batch endpoint, chunking keeps requests short
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = ImportModeAtomic
+	}
+	if mode != ImportModeAtomic && mode != ImportModeChunked {
+		return nil, errs.InvalidArgument("mode must be atomic or chunked")
+	}
+
+	records, parseErrors, err := importer.Parse(req.Format, []byte(req.Data))
+	if err != nil {
+		if errors.Is(err, importer.ErrUnsupportedFormat) {
+			return nil, errs.InvalidArgument("format must be csv or jsonl")
+		}
+		return nil, errs.InvalidArgument(err.Error())
+	}
+
+	// Validate every row up front
+	requests, rowErrors := validateImportRecords(ctx, records, parseErrors)
+
+	response := &ImportReportsResponse{
+		DryRun:    req.DryRun,
+		TotalRows: len(records),
+		ValidRows: countValid(requests),
+		ReportIDs: []string{},
+		Errors:    []ImportRowError{},
+	}
+
+	if req.DryRun {
+		response.Status = "validated"
+		response.Failed = len(records) - response.ValidRows
+		response.Done = true
+		response.Errors = flattenRowErrors(rowErrors, 1, len(records))
+		return response, nil
+	}
+
+	if mode == ImportModeAtomic {
+		return importAtomically(ctx, userID, requests, rowErrors, response)
+	}
+
+	return importChunk(ctx, userID, req, requests, rowErrors, response)
+}
+
+// BE-IN - Internal backend only
+// validateImportRecords converts records into create requests. The
+// returned slice has a nil entry for every row that failed validation.
+func validateImportRecords(ctx context.Context, records []importer.Record, parseErrors []importer.RowError) ([]*CreateReportRequest, map[int][]ImportRowError) {
+	rowErrors := make(map[int][]ImportRowError)
+	for _, e := range parseErrors {
+		rowErrors[e.Row] = append(rowErrors[e.Row], ImportRowError{Row: e.Row, Field: e.Field, Message: e.Message})
+	}
+
+	requests := make([]*CreateReportRequest, len(records))
+	for i, record := range records {
+		if len(rowErrors[record.Row]) > 0 {
+			continue
+		}
+
+		req := &CreateReportRequest{
+			Title:        record.Title,
+			Description:  record.Description,
+			ProjectID:    record.ProjectID,
+			DepartmentID: record.DepartmentID,
+			TemplateID:   record.TemplateID,
+			PeriodID:     record.PeriodID,
+			Metadata:     record.Metadata,
+			Tags:         record.Tags,
+		}
+		if record.Evaluation != nil {
+			evaluation := Evaluation{
+				SecurityScore:      record.Evaluation.SecurityScore,
+				PerformanceScore:   record.Evaluation.PerformanceScore,
+				MemoryScore:        record.Evaluation.MemoryScore,
+				TestingScore:       record.Evaluation.TestingScore,
+				ErrorScore:         record.Evaluation.ErrorScore,
+				LoadScore:          record.Evaluation.LoadScore,
+				SecurityDetails:    record.Evaluation.SecurityDetails,
+				PerformanceDetails: record.Evaluation.PerformanceDetails,
+				MemoryDetails:      record.Evaluation.MemoryDetails,
+				TestingDetails:     record.Evaluation.TestingDetails,
+				ErrorDetails:       record.Evaluation.ErrorDetails,
+				LoadDetails:        record.Evaluation.LoadDetails,
+			}
+			req.Evaluation = &evaluation
+		}
+
+		if err := req.Validate(); err != nil {
+			var fieldErrors ValidationErrors
+			if errors.As(err, &fieldErrors) {
+				for _, fe := range fieldErrors {
+					rowErrors[record.Row] = append(rowErrors[record.Row], ImportRowError{Row: record.Row, Field: fe.Field, Message: fe.Message})
+				}
+			} else {
+				rowErrors[record.Row] = append(rowErrors[record.Row], ImportRowError{Row: record.Row, Message: err.Error()})
+			}
+			continue
+		}
+
+		// Templates are checked here so dry runs catch them too
+		metadata := req.Metadata
+		if req.TemplateID != nil {
+			template, err := models.GetTemplateByID(ctx, *req.TemplateID)
+			if err != nil || !templateAppliesTo(template, req.ProjectID, req.DepartmentID) {
+				rowErrors[record.Row] = append(rowErrors[record.Row], ImportRowError{
+					Row: record.Row, Field: "template_id", Message: "template not found for the report's project or department",
+				})
+				continue
+			}
+			metadata = templateMetadata(template, req.Metadata)
+		}
+
+		// And the reporting period
+		if req.PeriodID != nil && *req.PeriodID != "" {
+			period, err := models.GetPeriodByID(ctx, *req.PeriodID)
+			if err != nil || !period.AppliesTo(req.DepartmentID, req.ProjectID) || period.ClosedAt != nil {
+				rowErrors[record.Row] = append(rowErrors[record.Row], ImportRowError{
+					Row: record.Row, Field: "period_id", Message: "no open reporting period found for the report's project or department",
+				})
+				continue
+			}
+		}
+
+		// So is the department's metadata schema
+		fieldErrors, err := metadataErrors(ctx, req.DepartmentID, metadata)
+		if err != nil {
+			rlog.Error("failed to check metadata schema", "department_id", req.DepartmentID, "error", err)
+			fieldErrors = ValidationErrors{{Field: "metadata", Message: "failed to check metadata schema"}}
+		}
+		if len(fieldErrors) > 0 {
+			for _, fe := range fieldErrors {
+				rowErrors[record.Row] = append(rowErrors[record.Row], ImportRowError{Row: record.Row, Field: fe.Field, Message: fe.Message})
+			}
+			continue
+		}
+
+		requests[i] = req
+	}
+
+	return requests, rowErrors
+}
+
+// BE-IN - Internal backend only
+// importAtomically imports every row or none of them. Reports created
+// before a storage failure are deleted again.
+func importAtomically(ctx context.Context, userID string, requests []*CreateReportRequest, rowErrors map[int][]ImportRowError, response *ImportReportsResponse) (*ImportReportsResponse, error) {
+	if len(rowErrors) > 0 {
+		response.Status = models.ImportStatusFailed
+		response.Failed = len(requests) - response.ValidRows
+		response.Done = true
+		response.Errors = flattenRowErrors(rowErrors, 1, len(requests))
+		return response, nil
+	}
+
+	created := make([]*Report, 0, len(requests))
+	for i, req := range requests {
+		report, err := createReport(ctx, userID, req)
+		if err != nil {
+			rlog.Error("import failed, rolling back", "row", i+1, "imported", len(response.ReportIDs), "error", err)
+			rollbackImport(ctx, response.ReportIDs)
+			return nil, errs.Internal("import failed and was rolled back")
+		}
+		created = append(created, report)
+		response.ReportIDs = append(response.ReportIDs, report.ID)
+	}
+
+	// Only announce the reports once the whole import is committed
+	for _, report := range created {
+		publishReportCreated(ctx, report, events.SourceImport)
+	}
+
+	response.Status = models.ImportStatusCompleted
+	response.Imported = len(response.ReportIDs)
+	response.NextRow = len(requests) + 1
+	response.Done = true
+	return response, nil
+}
+
+// BE-IN - Internal backend only
+// importChunk imports the next chunk of a resumable job. Invalid rows are
+// skipped and reported; valid rows are imported. A row that cannot be
+// created ends the chunk and is reported, and fails the request when it
+// is the chunk's first row, so a client never loops without progress.
+func importChunk(ctx context.Context, userID string, req *ImportReportsRequest, requests []*CreateReportRequest, rowErrors map[int][]ImportRowError, response *ImportReportsResponse) (*ImportReportsResponse, error) {
+	checksum := sha256.Sum256([]byte(req.Data))
+
+	// Load or start the job
+	var job *models.ImportJob
+	if req.JobID != nil {
+		var err error
+		job, err = models.GetImportJobByID(ctx, *req.JobID)
+		if err != nil {
+			if err == models.ErrImportJobNotFound {
+				return nil, errs.NotFound("import job not found")
+			}
+			rlog.Error("failed to get import job", "error", err)
+			return nil, errs.Internal("failed to get import job")
+		}
+		if job.OwnerID != userID {
+			return nil, errs.Permission("only the owner can resume the import")
+		}
+		if job.Checksum != hex.EncodeToString(checksum[:]) || job.Format != req.Format {
+			return nil, errs.InvalidArgument("data does not match the data the import job was started with")
+		}
+	} else {
+		job = &models.ImportJob{
+			OwnerID:   userID,
+			Format:    req.Format,
+			Checksum:  hex.EncodeToString(checksum[:]),
+			Status:    models.ImportStatusInProgress,
+			TotalRows: len(requests),
+			NextRow:   1,
+			ReportIDs: []string{},
+		}
+	}
+
+	chunkSize := defaultImportChunkSize
+	if req.ChunkSize > 0 && req.ChunkSize <= maxImportChunkSize {
+		chunkSize = req.ChunkSize
+	}
+
+	first := job.NextRow
+	last := first + chunkSize - 1
+	if last > len(requests) {
+		last = len(requests)
+	}
+
+	var stopped *ImportRowError
+	for row := first; row <= last; row++ {
+		createReq := requests[row-1]
+		if createReq == nil {
+			job.Failed++
+			job.NextRow = row + 1
+			continue
+		}
+
+		report, err := createReport(ctx, userID, createReq)
+		if err != nil {
+			// Stop here; the job resumes from this row
+			rlog.Error("failed to import row", "job_id", job.ID, "row", row, "error", err)
+			if row == first {
+				// Nothing moved, so resuming would fail the same way
+				return nil, err
+			}
+			stopped = &ImportRowError{Row: row, Message: "not imported, resume the job to retry: " + err.Error()}
+			break
+		}
+		job.Imported++
+		job.NextRow = row + 1
+		job.ReportIDs = append(job.ReportIDs, report.ID)
+		response.ReportIDs = append(response.ReportIDs, report.ID)
+		publishReportCreated(ctx, report, events.SourceImport)
+	}
+
+	if job.NextRow > len(requests) {
+		job.Status = models.ImportStatusCompleted
+	}
+
+	if err := models.SaveImportJob(ctx, job); err != nil {
+		rlog.Error("failed to save import job", "error", err)
+		return nil, errs.Internal("failed to save import job")
+	}
+
+	response.JobID = job.ID
+	response.Status = job.Status
+	response.NextRow = job.NextRow
+	response.Imported = len(response.ReportIDs)
+	response.Failed = job.Failed
+	response.Done = job.Status == models.ImportStatusCompleted
+	response.Errors = flattenRowErrors(rowErrors, first, job.NextRow-1)
+	if stopped != nil {
+		response.Errors = append(response.Errors, *stopped)
+	}
+	return response, nil
+}
+
+// BE-IN - Internal backend only
+func rollbackImport(ctx context.Context, reportIDs []string) {
+	for _, id := range reportIDs {
+		if evaluation, err := models.GetEvaluationByReportID(ctx, id); err == nil {
+			if err := models.DeleteEvaluation(ctx, evaluation.ID); err != nil {
+				rlog.Error("failed to roll back evaluation", "report_id", id, "error", err)
+			}
+		}
+		if err := models.DeleteReport(ctx, id); err != nil {
+			rlog.Error("failed to roll back report", "report_id", id, "error", err)
+		}
+	}
+}
+
+// BE-IN - Internal backend only
+func countValid(requests []*CreateReportRequest) int {
+	valid := 0
+	for _, req := range requests {
+		if req != nil {
+			valid++
+		}
+	}
+	return valid
+}
+
+// BE-IN - Internal backend only
+// flattenRowErrors returns the errors for rows first..last in row order.
+func flattenRowErrors(rowErrors map[int][]ImportRowError, first, last int) []ImportRowError {
+	result := []ImportRowError{}
+	for row := first; row <= last; row++ {
+		result = append(result, rowErrors[row]...)
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+// importCSV has three valid rows and two invalid ones.
+const importCSV = `title,description,project_id,department_id,testing_score
+First,Migrated from the legacy tracker,project-456,dept-456,7
+,Migrated from the legacy tracker,project-456,dept-456,7
+Third,Migrated from the legacy tracker,project-456,dept-456,11
+Fourth,Migrated from the legacy tracker,project-456,dept-456,
+Fifth,Migrated from the legacy tracker,project-456,dept-456,5
+`
+
+func deleteImported(t *testing.T, ids []string) {
+	t.Helper()
+	rollbackImport(context.Background(), ids)
+}
+
+func TestImportDryRun(t *testing.T) {
+	resp, err := ImportReports(context.Background(), &ImportReportsRequest{Format: "csv", Data: importCSV, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportReports: %v", err)
+	}
+	if resp.Status != "validated" || resp.TotalRows != 5 || resp.ValidRows != 3 || resp.Failed != 2 || !resp.Done || len(resp.ReportIDs) != 0 {
+		t.Errorf("response = %+v", resp)
+	}
+	var fields []string
+	for _, e := range resp.Errors {
+		fields = append(fields, e.Field)
+	}
+	if strings.Join(fields, ",") != "title,evaluation.testing_score" || resp.Errors[0].Row != 2 || resp.Errors[1].Row != 3 {
+		t.Errorf("errors = %+v", resp.Errors)
+	}
+}
+
+func TestImportAtomicRefusesInvalidRows(t *testing.T) {
+	resp, err := ImportReports(context.Background(), &ImportReportsRequest{Format: "csv", Data: importCSV})
+	if err != nil {
+		t.Fatalf("ImportReports: %v", err)
+	}
+	if resp.Status != models.ImportStatusFailed || resp.Imported != 0 || len(resp.Errors) != 2 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestImportChunkedResumes(t *testing.T) {
+	ctx := context.Background()
+	req := &ImportReportsRequest{Format: "csv", Data: importCSV, Mode: ImportModeChunked, ChunkSize: 2}
+
+	var imported []string
+	defer func() { deleteImported(t, imported) }()
+
+	wantNext := []int{3, 5, 6}
+	for i, next := range wantNext {
+		resp, err := ImportReports(ctx, req)
+		if err != nil {
+			t.Fatalf("chunk %d: %v", i+1, err)
+		}
+		imported = append(imported, resp.ReportIDs...)
+		if resp.NextRow != next || resp.Done != (i == len(wantNext)-1) {
+			t.Fatalf("chunk %d = %+v, want next row %d", i+1, resp, next)
+		}
+		req.JobID = &resp.JobID
+	}
+	if len(imported) != 3 {
+		t.Errorf("imported %d reports, want 3", len(imported))
+	}
+
+	// Resuming with other data is refused
+	req.Data = strings.Replace(importCSV, "First", "Other", 1)
+	if _, err := ImportReports(ctx, req); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("resume with changed data = %v", err)
+	}
+}
+
+func TestImportChunkStopsOnCreateFailure(t *testing.T) {
+	ctx := context.Background()
+	missing := "template-missing"
+	valid := &CreateReportRequest{Title: "Valid", Description: "Migrated from the legacy tracker", ProjectID: "project-456", DepartmentID: "dept-456"}
+	// Skips the validation that would catch the template, as a template
+	// deleted between validation and creation would
+	failing := &CreateReportRequest{Title: "Failing", Description: "Migrated from the legacy tracker", ProjectID: "project-456", DepartmentID: "dept-456", TemplateID: &missing}
+	req := &ImportReportsRequest{Format: "csv", Mode: ImportModeChunked}
+	newResponse := func() *ImportReportsResponse {
+		return &ImportReportsResponse{ReportIDs: []string{}, Errors: []ImportRowError{}}
+	}
+
+	// The failing row ends the chunk and is reported
+	resp, err := importChunk(ctx, "user-123", req, []*CreateReportRequest{valid, failing, valid}, nil, newResponse())
+	if err != nil {
+		t.Fatalf("importChunk: %v", err)
+	}
+	defer deleteImported(t, resp.ReportIDs)
+	if resp.NextRow != 2 || resp.Done || len(resp.Errors) != 1 || resp.Errors[0].Row != 2 || !strings.Contains(resp.Errors[0].Message, "template not found") {
+		t.Errorf("response = %+v", resp)
+	}
+
+	// Resuming at the failing row makes no progress and fails the request
+	req.JobID = &resp.JobID
+	if _, err := importChunk(ctx, "user-123", req, []*CreateReportRequest{valid, failing, valid}, nil, newResponse()); err == nil || !strings.Contains(err.Error(), "template not found") {
+		t.Errorf("importChunk without progress = %v, want the row's error", err)
+	}
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/loadtest"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// maxLoadTestBytes bounds uploaded load-test results
+const maxLoadTestBytes = 64 << 20
+
+// BE-IN - Internal backend only
+// loadSLOs is read once at startup; an invalid configuration falls back
+// to the default levels.
+var loadSLOs = loadLoadSLOs()
+
+// BE-IN - Internal backend only
+func loadLoadSLOs() loadtest.SLOs {
+	slos, err := loadtest.SLOsFromEnv()
+	if err != nil {
+		rlog.Error("invalid load SLO thresholds, using the default", "error", err)
+	}
+	return slos
+}
+
+// BE-IN - Internal backend only
+type IngestLoadTestRequest struct {
+	// Results is a vegeta or k6 JSON file or a CSV of latency samples
+	Results string `json:"results" validate:"required"`
+	// Format overrides detection: vegeta, vegeta-results, k6 or csv
+	Format string `json:"format,omitempty"`
+	// DurationSeconds is the test duration for CSV samples without
+	// timestamps
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	Apply           bool    `json:"apply,omitempty"`
+	// IfMatch is the report ETag the client read; applying is refused
+	// when the report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+func (r *IngestLoadTestRequest) Validate() error {
+	var v ValidationErrors
+	v.required("results", r.Results)
+	if len(r.Results) > maxLoadTestBytes {
+		v.add("results", "must be at most %d MB", maxLoadTestBytes>>20)
+	}
+	switch r.Format {
+	case "", loadtest.FormatVegeta, loadtest.FormatVegetaResults, loadtest.FormatK6, loadtest.FormatCSV:
+	default:
+		v.add("format", "must be one of vegeta, vegeta-results, k6, csv")
+	}
+	if r.DurationSeconds < 0 {
+		v.add("duration_seconds", "must not be negative")
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type LoadTestRun struct {
+	ID             string    `json:"id"`
+	ReportID       string    `json:"report_id"`
+	UploadedBy     string    `json:"uploaded_by"`
+	Format         string    `json:"format"`
+	Requests       int       `json:"requests"`
+	Errors         int       `json:"errors"`
+	ErrorRate      float64   `json:"error_rate"`
+	Duration       float64   `json:"duration_seconds"`
+	Throughput     float64   `json:"throughput"`
+	MeanMs         float64   `json:"mean_ms"`
+	P50Ms          float64   `json:"p50_ms"`
+	P95Ms          float64   `json:"p95_ms"`
+	P99Ms          float64   `json:"p99_ms"`
+	MaxMs          float64   `json:"max_ms"`
+	Missing        []string  `json:"missing,omitempty"`
+	SuggestedScore int       `json:"suggested_score"`
+	Details        string    `json:"details"`
+	Applied        bool      `json:"applied"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/evaluation/load
+func IngestLoadTest(ctx context.Context, id string, req *IngestLoadTestRequest) (*LoadTestRun, error) {
+	// Score: [S8,P7,M6,T7,E8,L6]
+	// Details:
+	// - Security (S8): Upload size bounded, SLO levels are server-side
+	// - Performance (P7): Single parse, one sort for sample percentiles
+	// - Memory (M6): Latency samples held in memory while parsing
+	// - Testing (T7): Parsing and scoring live in a pure library
+	// - Error (E8): Parse errors returned with format and row
+	// - Load (L6): Intended for CI runs rather than interactive traffic
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	summary, err := loadtest.Parse(strings.NewReader(req.Results), loadtest.Options{
+		Format:   req.Format,
+		Duration: time.Duration(req.DurationSeconds * float64(time.Second)),
+	})
+	if err != nil {
+		return nil, errs.InvalidArgument(err.Error())
+	}
+
+	run := &models.LoadTestRun{
+		ReportID:   report.ID,
+		UploadedBy: userID,
+		Format:     summary.Format,
+		Requests:   summary.Requests,
+		Errors:     summary.Errors,
+		ErrorRate:  summary.ErrorRate,
+		Duration:   summary.Duration,
+		Throughput: summary.Throughput,
+		MeanMs:     summary.MeanMs,
+		P50Ms:      summary.P50Ms,
+		P95Ms:      summary.P95Ms,
+		P99Ms:      summary.P99Ms,
+		MaxMs:      summary.MaxMs,
+		Missing:    summary.Missing,
+	}
+	run.SuggestedScore, run.Details = loadSLOs.Suggest(summary)
+
+	if req.Apply {
+		_, err = recordDimension(ctx, report.ID, userID, req.IfMatch, func(e *EvaluateReportRequest) {
+			e.LoadScore = run.SuggestedScore
+			e.LoadDetails = run.Details
+		})
+		if err != nil {
+			return nil, err
+		}
+		run.Applied = true
+	}
+
+	if err := models.SaveLoadTestRun(ctx, run); err != nil {
+		rlog.Error("failed to save load test run", "error", err)
+		return nil, errs.Internal("failed to save load test run")
+	}
+
+	return convertModelToAPILoadTestRun(run), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/evaluation/load
+func GetLoadTest(ctx context.Context, id string) (*LoadTestRun, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check, trashed reports not served
+	// - Performance (P8): Single lookup by report ID
+	// - Memory (M8): Returns the stored run only
+	// - Testing (T7): Missing, stored and trashed-report uploads tested
+	// - Error (E8): Missing uploads reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database; trashed reports are not found
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	run, err := models.GetLoadTestRunByReportID(ctx, id)
+	if err != nil {
+		if err == models.ErrLoadTestRunNotFound {
+			return nil, errs.NotFound("no load test has been uploaded for this report")
+		}
+		rlog.Error("failed to get load test run", "error", err)
+		return nil, errs.Internal("failed to get load test run")
+	}
+	return convertModelToAPILoadTestRun(run), nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPILoadTestRun(model *models.LoadTestRun) *LoadTestRun {
+	return &LoadTestRun{
+		ID:             model.ID,
+		ReportID:       model.ReportID,
+		UploadedBy:     model.UploadedBy,
+		Format:         model.Format,
+		Requests:       model.Requests,
+		Errors:         model.Errors,
+		ErrorRate:      model.ErrorRate,
+		Duration:       model.Duration,
+		Throughput:     model.Throughput,
+		MeanMs:         model.MeanMs,
+		P50Ms:          model.P50Ms,
+		P95Ms:          model.P95Ms,
+		P99Ms:          model.P99Ms,
+		MaxMs:          model.MaxMs,
+		Missing:        model.Missing,
+		SuggestedScore: model.SuggestedScore,
+		Details:        model.Details,
+		Applied:        model.Applied,
+		CreatedAt:      model.CreatedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestIngestAndGetLoadTest(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	if _, err := GetLoadTest(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "no load test has been uploaded") {
+		t.Errorf("GetLoadTest before an upload = %v, want not found", err)
+	}
+	if err := (&IngestLoadTestRequest{Results: "latency_ms\n12\n", Format: "jmeter"}).Validate(); err == nil {
+		t.Error("Validate accepted an unknown format")
+	}
+	if _, err := IngestLoadTest(ctx, report.ID, &IngestLoadTestRequest{Results: "latency_ms,status\n12,ok\n"}); err == nil || !strings.Contains(err.Error(), "csv: row 2") {
+		t.Errorf("IngestLoadTest with a bad row = %v, want the row reported", err)
+	}
+
+	run, err := IngestLoadTest(ctx, report.ID, &IngestLoadTestRequest{
+		Results:         "latency_ms,status\n12,200\n18,200\n30,500\n25,200\n",
+		DurationSeconds: 2,
+		Apply:           true,
+	})
+	if err != nil {
+		t.Fatalf("IngestLoadTest: %v", err)
+	}
+	if run.Format != "csv" || run.Requests != 4 || run.Errors != 1 || !run.Applied {
+		t.Errorf("run = %+v, want 4 applied csv requests with 1 error", run)
+	}
+
+	stored, err := GetLoadTest(ctx, report.ID)
+	if err != nil || stored.ID != run.ID {
+		t.Fatalf("GetLoadTest = %v, %v, want run %s", stored, err, run.ID)
+	}
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetEvaluationByReportID: %v", err)
+	}
+	if evaluation.LoadScore != run.SuggestedScore || evaluation.LoadDetails != run.Details {
+		t.Errorf("evaluation load = %d %q, want the suggestion %d %q", evaluation.LoadScore, evaluation.LoadDetails, run.SuggestedScore, run.Details)
+	}
+}
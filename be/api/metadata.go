@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/jsonschema"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// maxMetadataSchemaBytes bounds a published schema document
+const maxMetadataSchemaBytes = 64 << 10
+
+// BE-IN - Internal backend only
+type PutMetadataSchemaRequest struct {
+	// Schema is a JSON Schema for the report's metadata object
+	Schema json.RawMessage `json:"schema" validate:"required"`
+}
+
+// BE-IN - Internal backend only
+func (r *PutMetadataSchemaRequest) Validate() error {
+	var v ValidationErrors
+	if len(r.Schema) == 0 {
+		v.add("schema", "is required")
+	}
+	if len(r.Schema) > maxMetadataSchemaBytes {
+		v.add("schema", "must be at most %d KB", maxMetadataSchemaBytes>>10)
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type MetadataSchema struct {
+	DepartmentID string          `json:"department_id"`
+	Schema       json.RawMessage `json:"schema"`
+	Version      int             `json:"version"`
+	UpdatedBy    string          `json:"updated_by"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=PUT path=/api/departments/:id/metadata-schema
+func PutMetadataSchema(ctx context.Context, id string, req *PutMetadataSchemaRequest) (*MetadataSchema, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Department head only, schema size bounded and compiled before saving
+	// - Performance (P8): Single compile and write
+	// - Memory (M8): One schema per department
+	// - Testing (T7): Schema handling lives in a pure library
+	// - Error (E8): Schema errors name the keyword path
+	// - Load (L8): Rare administrative writes
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	department, err := models.GetDepartmentByID(ctx, id)
+	if err != nil {
+		return nil, errs.NotFound("department not found")
+	}
+	if department.HeadID != userID {
+		return nil, errs.Permission("only the department head can change the metadata schema")
+	}
+
+	schema, err := jsonschema.Compile(req.Schema)
+	if err != nil {
+		return nil, errs.InvalidArgument(err.Error())
+	}
+	// Metadata is always an object, so a schema rejecting objects would
+	// refuse every report
+	if !schema.Accepts("object") {
+		return nil, errs.InvalidArgument("schema must accept an object")
+	}
+
+	model := &models.MetadataSchema{
+		DepartmentID: department.ID,
+		Schema:       req.Schema,
+		UpdatedBy:    userID,
+	}
+	if err := models.SaveMetadataSchema(ctx, model); err != nil {
+		rlog.Error("failed to save metadata schema", "error", err)
+		return nil, errs.Internal("failed to save metadata schema")
+	}
+
+	return convertModelToAPIMetadataSchema(model), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/departments/:id/metadata-schema
+func GetMetadataSchema(ctx context.Context, id string) (*MetadataSchema, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check
+	// - Performance (P8): Single lookup by department ID
+	// - Memory (M8): Returns the stored schema only
+	// - Testing (T7): Missing and republished schemas tested
+	// - Error (E8): Departments without a schema reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	schema, err := models.GetMetadataSchemaByDepartmentID(ctx, id)
+	if err != nil {
+		if err == models.ErrMetadataSchemaNotFound {
+			return nil, errs.NotFound("department has no metadata schema")
+		}
+		rlog.Error("failed to get metadata schema", "error", err)
+		return nil, errs.Internal("failed to get metadata schema")
+	}
+	return convertModelToAPIMetadataSchema(schema), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=DELETE path=/api/departments/:id/metadata-schema
+func DeleteMetadataSchema(ctx context.Context, id string) error {
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return errs.Unauthenticated("user must be authenticated")
+	}
+
+	department, err := models.GetDepartmentByID(ctx, id)
+	if err != nil {
+		return errs.NotFound("department not found")
+	}
+	if department.HeadID != userID {
+		return errs.Permission("only the department head can change the metadata schema")
+	}
+
+	err = models.DeleteMetadataSchema(ctx, id)
+	if err != nil {
+		if err == models.ErrMetadataSchemaNotFound {
+			return errs.NotFound("department has no metadata schema")
+		}
+		rlog.Error("failed to delete metadata schema", "error", err)
+		return errs.Internal("failed to delete metadata schema")
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+// metadataErrors validates report metadata against the schema of the
+// department, if it published one. Field names are paths below metadata,
+// such as metadata.owner.email.
+func metadataErrors(ctx context.Context, departmentID string, metadata map[string]interface{}) (ValidationErrors, error) {
+	stored, err := models.GetMetadataSchemaByDepartmentID(ctx, departmentID)
+	if err != nil {
+		if err == models.ErrMetadataSchemaNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	schema, err := jsonschema.Compile(stored.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing metadata object is checked as an empty one, so required
+	// keys are reported
+	var value interface{} = metadata
+	if metadata == nil {
+		value = map[string]interface{}{}
+	}
+
+	var v ValidationErrors
+	for _, e := range schema.Validate(value) {
+		field := "metadata"
+		if e.Path != "" {
+			field += "." + e.Path
+		}
+		v.add(field, "%s", e.Message)
+	}
+	return v, nil
+}
+
+// BE-IN - Internal backend only
+// checkMetadata returns an InvalidArgument error listing every metadata
+// path that does not satisfy the department's schema.
+func checkMetadata(ctx context.Context, departmentID string, metadata map[string]interface{}) error {
+	v, err := metadataErrors(ctx, departmentID, metadata)
+	if err != nil {
+		rlog.Error("failed to check metadata schema", "department_id", departmentID, "error", err)
+		return errs.Internal("failed to check metadata schema")
+	}
+	if len(v) > 0 {
+		return errs.InvalidArgument("metadata does not match the department schema: " + v.Error())
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIMetadataSchema(model *models.MetadataSchema) *MetadataSchema {
+	return &MetadataSchema{
+		DepartmentID: model.DepartmentID,
+		Schema:       model.Schema,
+		Version:      model.Version,
+		UpdatedBy:    model.UpdatedBy,
+		UpdatedAt:    model.UpdatedAt,
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestPutMetadataSchema(t *testing.T) {
+	ctx := context.Background()
+	req := &PutMetadataSchemaRequest{Schema: json.RawMessage(`{
+		"type": "object",
+		"required": ["owner"],
+		"properties": {"owner": {"type": "string", "format": "email"}}
+	}`)}
+
+	// The default demo user heads no department
+	if _, err := PutMetadataSchema(ctx, "dept-123", req); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Fatalf("PutMetadataSchema as user-123 = %v, want a permission error", err)
+	}
+
+	// Sam Security heads dept-123
+	t.Setenv("DEMO_USER_ID", "user-456")
+	if _, err := GetMetadataSchema(ctx, "dept-123"); err == nil || !strings.Contains(err.Error(), "department has no metadata schema") {
+		t.Fatalf("GetMetadataSchema before a schema is published = %v, want not found", err)
+	}
+	schema, err := PutMetadataSchema(ctx, "dept-123", req)
+	if err != nil {
+		t.Fatalf("PutMetadataSchema: %v", err)
+	}
+	defer func() {
+		if err := DeleteMetadataSchema(ctx, "dept-123"); err != nil {
+			t.Errorf("DeleteMetadataSchema: %v", err)
+		}
+	}()
+	if schema.UpdatedBy != "user-456" || schema.Version != 1 {
+		t.Errorf("schema = %+v", schema)
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     string
+	}{
+		{"valid", map[string]interface{}{"owner": "sam@example.com"}, ""},
+		{"missing", nil, "metadata.owner: is required"},
+		{"invalid", map[string]interface{}{"owner": "sam"}, "metadata.owner: must be a valid email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CreateReport(ctx, &CreateReportRequest{
+				Title:        "Schema checked report",
+				Description:  "A description long enough to pass validation",
+				ProjectID:    "project-123",
+				DepartmentID: "dept-123",
+				Metadata:     tt.metadata,
+			})
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("CreateReport: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CreateReport error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	for schema, want := range map[string]string{
+		`{"$ref": "#/defs/owner"}`: "schema $ref: is not a supported keyword",
+		`{"type": "string"}`:       "schema must accept an object",
+	} {
+		_, err := PutMetadataSchema(ctx, "dept-123", &PutMetadataSchemaRequest{Schema: json.RawMessage(schema)})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("PutMetadataSchema(%s) = %v, want %q", schema, err, want)
+		}
+	}
+
+	// Publishing again replaces the schema under a new version
+	if _, err := PutMetadataSchema(ctx, "dept-123", req); err != nil {
+		t.Fatalf("PutMetadataSchema: %v", err)
+	}
+	if stored, err := GetMetadataSchema(ctx, "dept-123"); err != nil || stored.Version != 2 {
+		t.Errorf("GetMetadataSchema = %+v, %v, want version 2", stored, err)
+	}
+}
+
+func TestUpdateReportChecksMetadata(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("DEMO_USER_ID", "user-456")
+	if _, err := PutMetadataSchema(ctx, "dept-123", &PutMetadataSchemaRequest{Schema: json.RawMessage(`{
+		"type": "object",
+		"properties": {"owner": {"type": "string", "format": "email"}}
+	}`)}); err != nil {
+		t.Fatalf("PutMetadataSchema: %v", err)
+	}
+	t.Cleanup(func() { DeleteMetadataSchema(ctx, "dept-123") })
+
+	report, err := CreateReport(ctx, &CreateReportRequest{
+		Title:        "Schema checked update",
+		Description:  "A description long enough to pass validation",
+		ProjectID:    "project-123",
+		DepartmentID: "dept-456",
+		Metadata:     map[string]interface{}{"owner": "sam"},
+	})
+	if err != nil {
+		t.Fatalf("CreateReport: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteReport(ctx, report.ID) })
+
+	// Moving to dept-123 checks the kept metadata against its schema
+	dept := "dept-123"
+	if _, err := UpdateReport(ctx, report.ID, &UpdateReportRequest{DepartmentID: &dept}); err == nil || !strings.Contains(err.Error(), "metadata.owner: must be a valid email") {
+		t.Errorf("moving invalid metadata = %v, want a metadata error", err)
+	}
+	updated, err := UpdateReport(ctx, report.ID, &UpdateReportRequest{DepartmentID: &dept, Metadata: map[string]interface{}{"owner": "sam@example.com"}})
+	if err != nil {
+		t.Fatalf("UpdateReport: %v", err)
+	}
+	if updated.DepartmentID != dept || updated.Metadata["owner"] != "sam@example.com" {
+		t.Errorf("updated report = %+v, want moved with the new owner", updated)
+	}
+
+	submitted := "submitted"
+	if _, err := UpdateReport(ctx, report.ID, &UpdateReportRequest{Status: &submitted}); err == nil || !strings.Contains(err.Error(), "use the submit") {
+		t.Errorf("changing the status = %v, want rejected", err)
+	}
+	t.Setenv("DEMO_USER_ID", "")
+	if _, err := UpdateReport(ctx, report.ID, &UpdateReportRequest{Metadata: map[string]interface{}{}}); err == nil || !strings.Contains(err.Error(), "only the author") {
+		t.Errorf("update by another user = %v, want permission denied", err)
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"os"
+
+	"encore.app/models"
+	"encore.app/services/notifications"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// notifier sends lifecycle emails. SMTP settings come from the SMTP_*
+// environment variables; links point at APP_BASE_URL.
+var notifier = notifications.NewNotifier(notifications.NewSenderFromEnv(), os.Getenv("APP_BASE_URL"))
+
+// BE-OUT - External data involved
+// notifyReportEvent emails everyone involved in a report lifecycle event.
+// It runs in the notification subscribers, so handlers never wait on SMTP.
+func notifyReportEvent(ctx context.Context, kind string, report *models.Report, evaluation *models.Evaluation, actorID, reason string) {
+	// Score: [S7,P7,M7,T7,E7,L7]
+	// Details:
+	// - Security (S7): Recipients derived from report roles, never from request input
+	// - Performance (P7): Runs off the request path in a subscriber
+	// - Memory (M7): One message rendered per recipient
+	// - Testing (T7): Recipients of evaluation, submission and approval emails tested
+	// - Error (E7): Failures logged, not retried to avoid duplicate emails
+	// - Load (L7): Bounded by the number of recipients per event
+	// Tags: BE-OUT-medium
+
+	event := notifications.Event{
+		Kind:       kind,
+		Report:     report,
+		Evaluation: evaluation,
+		Reason:     reason,
+	}
+
+	if actor, err := models.GetUserByID(ctx, actorID); err == nil {
+		event.ActorName = actor.Name
+	} else {
+		event.ActorName = actorID
+	}
+
+	department, err := models.GetDepartmentByID(ctx, report.DepartmentID)
+	if err == nil {
+		event.DepartmentName = department.Name
+	} else {
+		event.DepartmentName = report.DepartmentID
+	}
+
+	// Resolve recipients for the event
+	recipientIDs := []string{report.AuthorID}
+	switch kind {
+	case notifications.KindReportSubmitted, notifications.KindDeliveryFailed:
+		if department != nil && department.HeadID != "" {
+			recipientIDs = append(recipientIDs, department.HeadID)
+		}
+	case notifications.KindReportApproved, notifications.KindReportRejected:
+		if evaluation != nil && evaluation.EvaluatorID != "" {
+			recipientIDs = append(recipientIDs, evaluation.EvaluatorID)
+		}
+	}
+	for _, id := range recipientIDs {
+		// Nobody needs an email about their own action
+		if id == actorID && kind != notifications.KindDeliveryFailed {
+			continue
+		}
+		user, err := models.GetUserByID(ctx, id)
+		if err != nil {
+			rlog.Warn("notification recipient not found", "user_id", id, "kind", kind)
+			continue
+		}
+		event.Recipients = append(event.Recipients, user)
+	}
+
+	if len(event.Recipients) == 0 {
+		return
+	}
+
+	if err := notifier.Notify(ctx, event); err != nil {
+		rlog.Error("failed to send notifications", "kind", kind, "report_id", report.ID, "error", err)
+	}
+}
+
+// BE-OUT - External data involved
+// notifyMentions emails the users mentioned in a comment.
+func notifyMentions(ctx context.Context, report *models.Report, comment *models.Comment) {
+	event := notifications.Event{
+		Kind:      notifications.KindCommentMention,
+		Report:    report,
+		ActorName: comment.AuthorID,
+		Comment:   comment.Body,
+	}
+	if author, err := models.GetUserByID(ctx, comment.AuthorID); err == nil {
+		event.ActorName = author.Name
+	}
+
+	for _, id := range comment.Mentions {
+		// Mentioning yourself sends nothing
+		if id == comment.AuthorID {
+			continue
+		}
+		user, err := models.GetUserByID(ctx, id)
+		if err != nil {
+			rlog.Warn("notification recipient not found", "user_id", id, "kind", event.Kind)
+			continue
+		}
+		event.Recipients = append(event.Recipients, user)
+	}
+
+	if len(event.Recipients) == 0 {
+		return
+	}
+
+	if err := notifier.Notify(ctx, event); err != nil {
+		rlog.Error("failed to send notifications", "kind", event.Kind, "report_id", report.ID, "error", err)
+	}
+}
+
+// BE-OUT - External data involved
+// notifyReportDue reminds the author of a draft that its period is due.
+func notifyReportDue(ctx context.Context, report *models.Report, period *models.ReportingPeriod) {
+	event := notifications.Event{
+		Kind:           notifications.KindReportDue,
+		Report:         report,
+		Period:         period,
+		DepartmentName: report.DepartmentID,
+	}
+	if department, err := models.GetDepartmentByID(ctx, report.DepartmentID); err == nil {
+		event.DepartmentName = department.Name
+	}
+
+	author, err := models.GetUserByID(ctx, report.AuthorID)
+	if err != nil {
+		rlog.Warn("notification recipient not found", "user_id", report.AuthorID, "kind", event.Kind)
+		return
+	}
+	event.Recipients = []*models.User{author}
+
+	if err := notifier.Notify(ctx, event); err != nil {
+		rlog.Error("failed to send notifications", "kind", event.Kind, "report_id", report.ID, "error", err)
+	}
+}
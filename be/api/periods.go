@@ -0,0 +1,369 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/cron"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// defaultReminderLead is how long before the due date authors of drafts
+// are reminded
+const defaultReminderLead = 72 * time.Hour
+
+// BE-IN - Internal backend only
+// reminderLead is set by DEADLINE_REMINDER_LEAD
+var reminderLead = durationFromEnv("DEADLINE_REMINDER_LEAD", defaultReminderLead)
+
+// BE-IN - Internal backend only
+// Remind authors of upcoming deadlines every hour
+var _ = cron.NewJob("deadline-reminders", cron.JobConfig{
+	Title:    "Remind authors of drafts due soon",
+	Every:    cron.Hour,
+	Endpoint: SendDeadlineReminders,
+})
+
+// BE-IN - Internal backend only
+type ReportingPeriod struct {
+	ID           string     `json:"id"`
+	DepartmentID string     `json:"department_id"`
+	ProjectID    string     `json:"project_id,omitempty"`
+	Name         string     `json:"name"`
+	StartsAt     time.Time  `json:"starts_at"`
+	DueAt        time.Time  `json:"due_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	ClosedBy     string     `json:"closed_by,omitempty"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Stats is filled in when a single period is requested
+	Stats *PeriodStats `json:"stats,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type PeriodStats struct {
+	Reports   int `json:"reports"`
+	Submitted int `json:"submitted"`
+	Overdue   int `json:"overdue"`
+}
+
+// BE-IN - Internal backend only
+type CreatePeriodRequest struct {
+	Name      string    `json:"name"`
+	ProjectID string    `json:"project_id,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	DueAt     time.Time `json:"due_at"`
+}
+
+// BE-IN - Internal backend only
+func (r *CreatePeriodRequest) Validate() error {
+	var v ValidationErrors
+	v.required("name", strings.TrimSpace(r.Name))
+	if r.StartsAt.IsZero() {
+		v.add("starts_at", "is required")
+	}
+	if r.DueAt.IsZero() {
+		v.add("due_at", "is required")
+	} else if !r.DueAt.After(r.StartsAt) {
+		v.add("due_at", "must be after starts_at")
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type ListPeriodsRequest struct {
+	ProjectID     string `query:"project_id"`
+	IncludeClosed bool   `query:"include_closed"`
+}
+
+// BE-IN - Internal backend only
+type ListPeriodsResponse struct {
+	Periods []*ReportingPeriod `json:"periods"`
+}
+
+// BE-IN - Internal backend only
+type SendDeadlineRemindersResponse struct {
+	Reminded []string `json:"reminded"`
+}
+
+// BE-OUT - External data involved
+// CreatePeriod opens a reporting period for a department, or for one of
+// its projects when project_id is set.
+//
+//encore:api public method=POST path=/api/departments/:id/periods
+func CreatePeriod(ctx context.Context, id string, req *CreatePeriodRequest) (*ReportingPeriod, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Department head only
+	// - Performance (P8): Single write
+	// - Memory (M8): One entry per period
+	// - Testing (T7): Head-only access and date validation tested
+	// - Error (E8): Date ranges validated
+	// - Load (L8): Low write volume
+	// Tags: BE-module-medium
+
+	userID, err := requireDepartmentHead(ctx, id, "only the department head can manage reporting periods")
+	if err != nil {
+		return nil, err
+	}
+
+	period := &models.ReportingPeriod{
+		DepartmentID: id,
+		ProjectID:    req.ProjectID,
+		Name:         strings.TrimSpace(req.Name),
+		StartsAt:     req.StartsAt,
+		DueAt:        req.DueAt,
+		CreatedBy:    userID,
+	}
+	if err := models.SavePeriod(ctx, period); err != nil {
+		rlog.Error("failed to save reporting period", "department_id", id, "error", err)
+		return nil, errs.Internal("failed to save reporting period")
+	}
+
+	return convertModelToAPIPeriod(period), nil
+}
+
+// BE-OUT - External data involved
+// ListPeriods returns a department's open reporting periods, latest due
+// date first.
+//
+//encore:api public method=GET path=/api/departments/:id/periods
+func ListPeriods(ctx context.Context, id string, req *ListPeriodsRequest) (*ListPeriodsResponse, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Any authenticated user may read the periods
+	// - Performance (P8): Scan of the period store
+	// - Memory (M8): One entry per period
+	// - Testing (T7): Ordering, project and closed filters tested
+	// - Error (E8): Proper error handling
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	if _, err := models.GetUserIDFromContext(ctx); err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	if _, err := models.GetDepartmentByID(ctx, id); err != nil {
+		return nil, errs.NotFound("department not found")
+	}
+
+	periods, err := models.ListPeriods(ctx, id, req.ProjectID, req.IncludeClosed)
+	if err != nil {
+		rlog.Error("failed to list reporting periods", "department_id", id, "error", err)
+		return nil, errs.Internal("failed to list reporting periods")
+	}
+
+	response := &ListPeriodsResponse{Periods: make([]*ReportingPeriod, len(periods))}
+	for i := range periods {
+		response.Periods[i] = convertModelToAPIPeriod(&periods[i])
+	}
+	return response, nil
+}
+
+// BE-OUT - External data involved
+// GetPeriod returns a reporting period with the number of reports
+// assigned, submitted and overdue.
+//
+//encore:api public method=GET path=/api/periods/:id
+func GetPeriod(ctx context.Context, id string) (*ReportingPeriod, error) {
+	// Score: [S7,P7,M8,T7,E8,L7]
+	// Details:
+	// - Security (S7): Any authenticated user may read a period
+	// - Performance (P7): Scan of reports for the counts
+	// - Memory (M8): Counters only
+	// - Testing (T7): Stats and unknown periods tested
+	// - Error (E8): Proper error handling
+	// - Load (L7): Read-only
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	if _, err := models.GetUserIDFromContext(ctx); err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	period, err := getPeriod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := models.GetPeriodStats(ctx, id, time.Now())
+	if err != nil {
+		rlog.Error("failed to count period reports", "period_id", id, "error", err)
+		return nil, errs.Internal("failed to count period reports")
+	}
+
+	response := convertModelToAPIPeriod(period)
+	response.Stats = &PeriodStats{Reports: stats.Reports, Submitted: stats.Submitted, Overdue: stats.Overdue}
+	return response, nil
+}
+
+// BE-OUT - External data involved
+// ClosePeriod locks the drafts assigned to a period: they can no longer
+// be edited or submitted until the period is reopened.
+//
+//encore:api public method=POST path=/api/periods/:id/close
+func ClosePeriod(ctx context.Context, id string) (*ReportingPeriod, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Department head only
+	// - Performance (P8): Single write
+	// - Memory (M8): Marks the period in place
+	// - Testing (T7): Closing, repeat closing and locked edits tested
+	// - Error (E8): Closing twice reported as a precondition failure
+	// - Load (L8): Low write volume
+	// Tags: BE-module-medium
+
+	period, err := getPeriod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := requireDepartmentHead(ctx, period.DepartmentID, "only the department head can close reporting periods")
+	if err != nil {
+		return nil, err
+	}
+
+	period, err = models.ClosePeriod(ctx, id, userID)
+	if err != nil {
+		if err == models.ErrPeriodClosed {
+			return nil, errs.FailedPrecondition("reporting period is already closed")
+		}
+		rlog.Error("failed to close reporting period", "period_id", id, "error", err)
+		return nil, errs.Internal("failed to close reporting period")
+	}
+	return convertModelToAPIPeriod(period), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/periods/:id/reopen
+func ReopenPeriod(ctx context.Context, id string) (*ReportingPeriod, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Department head only
+	// - Performance (P8): Single write
+	// - Memory (M8): Clears the marker in place
+	// - Testing (T7): Head-only access and repeat reopening tested
+	// - Error (E8): Open periods reported as a precondition failure
+	// - Load (L8): Low write volume
+	// Tags: BE-module-medium
+
+	period, err := getPeriod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireDepartmentHead(ctx, period.DepartmentID, "only the department head can reopen reporting periods"); err != nil {
+		return nil, err
+	}
+
+	period, err = models.ReopenPeriod(ctx, id)
+	if err != nil {
+		if err == models.ErrPeriodNotClosed {
+			return nil, errs.FailedPrecondition("reporting period is not closed")
+		}
+		rlog.Error("failed to reopen reporting period", "period_id", id, "error", err)
+		return nil, errs.Internal("failed to reopen reporting period")
+	}
+	return convertModelToAPIPeriod(period), nil
+}
+
+// BE-IN - Internal backend only
+// SendDeadlineReminders emails the authors of drafts whose period is due
+// within the reminder lead time, once per report and period. It runs
+// from the deadline-reminders cron job.
+//
+//encore:api private
+func SendDeadlineReminders(ctx context.Context) (*SendDeadlineRemindersResponse, error) {
+	// Score: [S8,P6,M7,T7,E7,L7]
+	// Details:
+	// - Security (S8): Private endpoint, authors only receive their own reports
+	// - Performance (P6): Full scan of reports once an hour
+	// - Memory (M7): Holds the due reports only
+	// - Testing (T7): Recipient and one reminder per report tested
+	// - Error (E7): Send failures logged, never retried to avoid duplicates
+	// - Load (L7): Runs off the request path
+	// Tags: BE-OUT-medium
+
+	now := time.Now()
+	due, err := models.FindReportsDueBefore(ctx, now, now.Add(reminderLead))
+	if err != nil {
+		rlog.Error("failed to find reports due soon", "error", err)
+		return nil, errs.Internal("failed to find reports due soon")
+	}
+
+	response := &SendDeadlineRemindersResponse{Reminded: []string{}}
+	for _, d := range due {
+		// Claim the reminder first so an overlapping run cannot send it twice
+		if !models.MarkReminderSent(ctx, d.Period.ID, d.Report.ID) {
+			continue
+		}
+		notifyReportDue(ctx, d.Report, &d.Period)
+		response.Reminded = append(response.Reminded, d.Report.ID)
+	}
+	if len(response.Reminded) > 0 {
+		rlog.Info("sent deadline reminders", "count", len(response.Reminded))
+	}
+	return response, nil
+}
+
+// BE-IN - Internal backend only
+func getPeriod(ctx context.Context, id string) (*models.ReportingPeriod, error) {
+	period, err := models.GetPeriodByID(ctx, id)
+	if err != nil {
+		if err == models.ErrPeriodNotFound {
+			return nil, errs.NotFound("reporting period not found")
+		}
+		rlog.Error("failed to get reporting period", "period_id", id, "error", err)
+		return nil, errs.Internal("failed to get reporting period")
+	}
+	return period, nil
+}
+
+// BE-IN - Internal backend only
+// checkPeriod refuses assigning a report to a period of another
+// department or project, or to a closed one.
+func checkPeriod(ctx context.Context, periodID, departmentID, projectID string) error {
+	period, err := models.GetPeriodByID(ctx, periodID)
+	if err != nil {
+		if err == models.ErrPeriodNotFound {
+			return errs.InvalidArgument("reporting period not found")
+		}
+		rlog.Error("failed to get reporting period", "period_id", periodID, "error", err)
+		return errs.Internal("failed to get reporting period")
+	}
+	if !period.AppliesTo(departmentID, projectID) {
+		return errs.InvalidArgument("reporting period does not belong to the report's department or project")
+	}
+	if period.ClosedAt != nil {
+		return errs.FailedPrecondition("reporting period is closed")
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+// checkPeriodOpen refuses late edits to reports whose period is closed.
+func checkPeriodOpen(ctx context.Context, report *models.Report) error {
+	if models.IsPeriodClosed(ctx, report) {
+		return errs.FailedPrecondition("the report's reporting period is closed")
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIPeriod(model *models.ReportingPeriod) *ReportingPeriod {
+	return &ReportingPeriod{
+		ID:           model.ID,
+		DepartmentID: model.DepartmentID,
+		ProjectID:    model.ProjectID,
+		Name:         model.Name,
+		StartsAt:     model.StartsAt,
+		DueAt:        model.DueAt,
+		ClosedAt:     model.ClosedAt,
+		ClosedBy:     model.ClosedBy,
+		CreatedBy:    model.CreatedBy,
+		CreatedAt:    model.CreatedAt,
+	}
+}
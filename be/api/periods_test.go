@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/notifications"
+)
+
+func TestReportingPeriods(t *testing.T) {
+	ctx := context.Background()
+	sender := &recordingSender{}
+	saved := notifier
+	notifier = notifications.NewNotifier(sender, "")
+	t.Cleanup(func() { notifier = saved })
+
+	now := time.Now()
+	sprint := &CreatePeriodRequest{Name: "Sprint 12", ProjectID: "project-periods", StartsAt: now.Add(-24 * time.Hour), DueAt: now.Add(24 * time.Hour)}
+	if _, err := CreatePeriod(ctx, "dept-456", sprint); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Errorf("CreatePeriod as user-123 = %v, want permission denied", err)
+	}
+	if err := (&CreatePeriodRequest{Name: "Backwards", StartsAt: now, DueAt: now.Add(-time.Hour)}).Validate(); err == nil || !strings.Contains(err.Error(), "must be after starts_at") {
+		t.Errorf("Validate = %v, want a due_at error", err)
+	}
+
+	// Jordan Lead heads dept-456
+	t.Setenv("DEMO_USER_ID", "user-789")
+	project, err := CreatePeriod(ctx, "dept-456", sprint)
+	if err != nil {
+		t.Fatalf("CreatePeriod: %v", err)
+	}
+	quarter, err := CreatePeriod(ctx, "dept-456", &CreatePeriodRequest{Name: "Q3", StartsAt: now.Add(-24 * time.Hour), DueAt: now.Add(10 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("CreatePeriod: %v", err)
+	}
+
+	listed := func(req *ListPeriodsRequest) []string {
+		t.Helper()
+		resp, err := ListPeriods(ctx, "dept-456", req)
+		if err != nil {
+			t.Fatalf("ListPeriods: %v", err)
+		}
+		var ids []string
+		for _, p := range resp.Periods {
+			if p.ID == project.ID || p.ID == quarter.ID {
+				ids = append(ids, p.ID)
+			}
+		}
+		return ids
+	}
+	if got, want := listed(&ListPeriodsRequest{ProjectID: "project-periods"}), []string{quarter.ID, project.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("periods = %q, want latest due first: %q", got, want)
+	}
+	if got, want := listed(&ListPeriodsRequest{ProjectID: "project-other"}), []string{quarter.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("periods of another project = %q, want only the department-wide %q", got, want)
+	}
+
+	// Reports can only join a period of their own project
+	t.Setenv("DEMO_USER_ID", "")
+	other := &CreateReportRequest{Title: "Other project", ProjectID: "project-other", DepartmentID: "dept-456", PeriodID: &project.ID}
+	if _, err := createReport(ctx, "user-123", other); err == nil || !strings.Contains(err.Error(), "does not belong") {
+		t.Errorf("createReport in another project's period = %v, want rejected", err)
+	}
+	report, err := createReport(ctx, "user-123", &CreateReportRequest{Title: "Sprint report", ProjectID: "project-periods", DepartmentID: "dept-456", PeriodID: &project.ID})
+	if err != nil {
+		t.Fatalf("createReport: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteReport(ctx, report.ID) })
+
+	got, err := GetPeriod(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("GetPeriod: %v", err)
+	}
+	if *got.Stats != (PeriodStats{Reports: 1}) {
+		t.Errorf("Stats = %+v, want one report", *got.Stats)
+	}
+	if _, err := GetPeriod(ctx, "period-missing"); err == nil || !strings.Contains(err.Error(), "reporting period not found") {
+		t.Errorf("GetPeriod for an unknown period = %v, want not found", err)
+	}
+
+	// The sprint is due within the reminder lead; its author hears once
+	reminded, err := SendDeadlineReminders(ctx)
+	if err != nil {
+		t.Fatalf("SendDeadlineReminders: %v", err)
+	}
+	if !reflect.DeepEqual(reminded.Reminded, []string{report.ID}) || len(sender.messages) != 1 || sender.messages[0].To != "alex@example.com" {
+		t.Errorf("reminded %q and emailed %d messages, want alex reminded of %s", reminded.Reminded, len(sender.messages), report.ID)
+	}
+	if again, err := SendDeadlineReminders(ctx); err != nil || len(again.Reminded) != 0 {
+		t.Errorf("second run reminded %v, %v, want nobody", again, err)
+	}
+
+	t.Setenv("DEMO_USER_ID", "user-789")
+	closed, err := ClosePeriod(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("ClosePeriod: %v", err)
+	}
+	if closed.ClosedAt == nil || closed.ClosedBy != "user-789" {
+		t.Errorf("closed period = %+v, want closed by user-789", closed)
+	}
+	if _, err := ClosePeriod(ctx, project.ID); err == nil || !strings.Contains(err.Error(), "already closed") {
+		t.Errorf("closing twice = %v, want rejected", err)
+	}
+	if got := listed(&ListPeriodsRequest{ProjectID: "project-periods"}); !reflect.DeepEqual(got, []string{quarter.ID}) {
+		t.Errorf("open periods = %q, want the closed one left out", got)
+	}
+	if got := listed(&ListPeriodsRequest{ProjectID: "project-periods", IncludeClosed: true}); len(got) != 2 {
+		t.Errorf("periods including closed = %q, want both", got)
+	}
+
+	t.Setenv("DEMO_USER_ID", "")
+	title := "Sprint report, late"
+	if _, err := UpdateReport(ctx, report.ID, &UpdateReportRequest{Title: &title}); err == nil || !strings.Contains(err.Error(), "period is closed") {
+		t.Errorf("editing in a closed period = %v, want rejected", err)
+	}
+	if _, err := ReopenPeriod(ctx, project.ID); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Errorf("ReopenPeriod as user-123 = %v, want permission denied", err)
+	}
+
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if _, err := ReopenPeriod(ctx, project.ID); err != nil {
+		t.Fatalf("ReopenPeriod: %v", err)
+	}
+	if _, err := ReopenPeriod(ctx, project.ID); err == nil || !strings.Contains(err.Error(), "not closed") {
+		t.Errorf("reopening an open period = %v, want rejected", err)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/ratelimit"
+	"encore.dev/beta/errs"
+	"encore.dev/middleware"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// rateLimits is read once at startup; an invalid configuration falls back
+// to the default quotas.
+var rateLimits = loadRateLimits()
+
+// BE-IN - Internal backend only
+var limiter = ratelimit.NewLimiter()
+
+// BE-IN - Internal backend only
+func loadRateLimits() ratelimit.Config {
+	config, err := ratelimit.ConfigFromEnv()
+	if err != nil {
+		rlog.Error("invalid rate limits, using the default", "error", err)
+	}
+	return config
+}
+
+// BE-OUT - External data involved
+// RateLimit applies the endpoint's quota to each authenticated user and
+// reports the bucket in RateLimit-* headers.
+//
+//encore:middleware global target=all
+func RateLimit(req middleware.Request, next middleware.Next) middleware.Response {
+	// Score: [S8,P8,M7,T7,E8,L9]
+	// Details:
+	// - Security (S8): Buckets keyed on the authenticated user, not on client-chosen tokens
+	// - Performance (P8): One bucket update per request
+	// - Memory (M7): Buckets kept only for recently active clients
+	// - Testing (T7): Bucket logic lives in a library with injectable time
+	// - Error (E8): ResourceExhausted with the retry delay
+	// - Load (L9): Stops one runaway client from saturating an endpoint
+	// Tags: BE-module-high
+
+	data := req.Data()
+	limit := rateLimits.For(data.Endpoint)
+	if limit.Unlimited() {
+		return next(req)
+	}
+
+	client := rateLimitClient(req.Context())
+	decision := limiter.Allow(client+"\x00"+data.Endpoint, limit, time.Now())
+
+	var resp middleware.Response
+	if decision.Allowed {
+		resp = next(req)
+	} else {
+		rlog.Warn("rate limit exceeded", "endpoint", data.Endpoint, "client", client)
+		resp = middleware.Response{Err: errs.ResourceExhausted(fmt.Sprintf(
+			"rate limit of %d requests for %s exceeded, retry in %ds", decision.Limit, data.Endpoint, ceilSeconds(decision.RetryAfter)))}
+		resp.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+	}
+
+	header := resp.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	return resp
+}
+
+// BE-IN - Internal backend only
+// rateLimitClient names the bucket owner: the authenticated user. Tokens
+// and API keys the request carries are not trusted as identities here,
+// since a client could send a new one with every request to get a fresh
+// bucket each time.
+func rateLimitClient(ctx context.Context) string {
+	if userID, err := models.GetUserIDFromContext(ctx); err == nil {
+		return "user:" + userID
+	}
+	return "anonymous"
+}
+
+// BE-IN - Internal backend only
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"encore.app/models"
+	"encore.app/events"
 	"encore.dev/beta/errs"
 	"encore.dev/rlog"
 )
@@ -22,9 +24,35 @@ type Report struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	TemplateID      string    `json:"template_id,omitempty"`
+	// EvaluationPrompts are the template's hints for each evaluation
+	// detail; they are never copied into the evaluation itself
+	EvaluationPrompts *EvaluationPrompts `json:"evaluation_prompts,omitempty"`
+	Tags            []string  `json:"tags,omitempty"`
 	Evaluation      *Evaluation `json:"evaluation,omitempty"`
+	CommentCount    int       `json:"comment_count"`
+	UnresolvedCommentCount int `json:"unresolved_comment_count"`
+	// Version increases with every change to the report
+	Version int `json:"version"`
+	// ETag covers the report and evaluation versions; send it back in
+	// If-Match to update only what was read
+	ETag string `header:"ETag" json:"-"`
+	// ArchivedAt is set once a retention policy has archived the report,
+	// which is then read-only
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	PeriodID   string     `json:"period_id,omitempty"`
+	// DueAt is the due date of the report's reporting period
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// Overdue is set on drafts past their due date
+	Overdue bool `json:"overdue"`
 }
 
+// BE-IN - Internal backend only
+func (r *Report) etag() string { return r.ETag }
+
+// BE-IN - Internal backend only
+func (r *Report) setETag(etag string) { r.ETag = etag }
+
 // BE-IN - Internal backend only
 type Evaluation struct {
 	SecurityScore    int    `json:"security_score"`
@@ -40,27 +68,94 @@ type Evaluation struct {
 	ErrorDetails     string `json:"error_details,omitempty"`
 	LoadDetails      string `json:"load_details,omitempty"`
 	EvaluatorID      string `json:"evaluator_id,omitempty"`
+	// Version increases with every change to the evaluation; ignored on
+	// input
+	Version int `json:"version,omitempty"`
 }
 
 // BE-IN - Internal backend only
 type CreateReportRequest struct {
 	Title        string `json:"title" validate:"required,min=5"`
-	Description  string `json:"description" validate:"required,min=20"`
-	ProjectID    string `json:"project_id" validate:"required,uuid"`
-	DepartmentID string `json:"department_id" validate:"required,uuid"`
+	Description  string `json:"description" validate:"required_without=TemplateID,omitempty,min=20"`
+	ProjectID    string `json:"project_id" validate:"required"`
+	DepartmentID string `json:"department_id" validate:"required"`
+	TemplateID   *string `json:"template_id,omitempty"`
 	Evaluation   *Evaluation `json:"evaluation,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	PeriodID     *string `json:"period_id,omitempty"`
+}
+
+// BE-IN - Internal backend only
+// Validate enforces the rules declared in the validate tags. Encore calls
+// it before the handler runs; bulk import calls it for every row.
+func (r *CreateReportRequest) Validate() error {
+	var v ValidationErrors
+	if v.required("title", r.Title) {
+		v.minLength("title", r.Title, 5)
+	}
+	if r.TemplateID == nil {
+		if v.required("description", r.Description) {
+			v.minLength("description", r.Description, 20)
+		}
+	} else if r.Description != "" {
+		v.minLength("description", r.Description, 20)
+	}
+	v.required("project_id", r.ProjectID)
+	if v.required("department_id", r.DepartmentID) {
+		v.department("department_id", r.DepartmentID)
+	}
+	v.evaluation(r.Evaluation)
+	v.tags("tags", r.Tags)
+	return v.err()
 }
 
 // BE-IN - Internal backend only
 type UpdateReportRequest struct {
 	Title        *string `json:"title,omitempty" validate:"omitempty,min=5"`
 	Description  *string `json:"description,omitempty" validate:"omitempty,min=20"`
-	ProjectID    *string `json:"project_id,omitempty" validate:"omitempty,uuid"`
-	DepartmentID *string `json:"department_id,omitempty" validate:"omitempty,uuid"`
+	ProjectID    *string `json:"project_id,omitempty"`
+	DepartmentID *string `json:"department_id,omitempty"`
 	Status       *string `json:"status,omitempty" validate:"omitempty,oneof=draft submitted approved rejected"`
 	Evaluation   *Evaluation `json:"evaluation,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	// Tags replaces the report's tags; an empty list removes them
+	Tags *[]string `json:"tags,omitempty"`
+	// PeriodID assigns the report to a reporting period; an empty string
+	// removes it from its period
+	PeriodID *string `json:"period_id,omitempty"`
+	// IfMatch is the ETag the client read; the update is refused when the
+	// report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+func (r *UpdateReportRequest) Validate() error {
+	var v ValidationErrors
+	if r.Title != nil && v.required("title", *r.Title) {
+		v.minLength("title", *r.Title, 5)
+	}
+	if r.Description != nil && v.required("description", *r.Description) {
+		v.minLength("description", *r.Description, 20)
+	}
+	if r.ProjectID != nil {
+		v.required("project_id", *r.ProjectID)
+	}
+	if r.DepartmentID != nil && v.required("department_id", *r.DepartmentID) {
+		v.department("department_id", *r.DepartmentID)
+	}
+	if r.Status != nil {
+		switch *r.Status {
+		case "draft", "submitted", "approved", "rejected":
+		default:
+			v.add("status", "must be one of draft, submitted, approved, rejected")
+		}
+	}
+	v.evaluation(r.Evaluation)
+	if r.Tags != nil {
+		v.tags("tags", *r.Tags)
+	}
+	return v.err()
 }
 
 // BE-IN - Internal backend only
@@ -73,6 +168,13 @@ type ListReportsRequest struct {
 	Status     *string `json:"status,omitempty"`
 	AuthorID   *string `json:"author_id,omitempty"`
 	ProjectID  *string `json:"project_id,omitempty"`
+	PeriodID   *string `json:"period_id,omitempty"`
+	// Overdue keeps only drafts past their due date, or only the other
+	// reports when false; status=overdue is the same as overdue=true
+	Overdue    *bool   `json:"overdue,omitempty"`
+	TagType    *string `json:"tag_type,omitempty"`
+	TagScope   *string `json:"tag_scope,omitempty"`
+	TagImpact  *string `json:"tag_impact,omitempty"`
 	Limit      int     `json:"limit,omitempty" default:"50"`
 	Offset     int     `json:"offset,omitempty" default:"0"`
 }
@@ -102,6 +204,22 @@ func CreateReport(ctx context.Context, req *CreateReportRequest) (*Report, error
 		return nil, errs.Unauthenticated("user must be authenticated")
 	}
 
+	return idempotent(ctx, req, func() (*Report, error) {
+		report, err := createReport(ctx, userID, req)
+		if err != nil {
+			return nil, err
+		}
+
+		publishReportCreated(ctx, report, events.SourceAPI)
+
+		return report, nil
+	})
+}
+
+// BE-IN - Internal backend only
+// createReport stores a validated request as a new draft on behalf of
+// userID. It is shared by CreateReport and bulk import.
+func createReport(ctx context.Context, userID string, req *CreateReportRequest) (*Report, error) {
 	// Create report in database
 	report := &models.Report{
 		Title:        req.Title,
@@ -115,6 +233,43 @@ func CreateReport(ctx context.Context, req *CreateReportRequest) (*Report, error
 		Metadata:     req.Metadata,
 	}
 
+	if req.PeriodID != nil && *req.PeriodID != "" {
+		if err := checkPeriod(ctx, *req.PeriodID, req.DepartmentID, req.ProjectID); err != nil {
+			return nil, err
+		}
+		report.PeriodID = *req.PeriodID
+	}
+
+	// Import builds requests itself, so tags are checked here too
+	tags, err := models.ParseTags(req.Tags)
+	if err != nil {
+		return nil, errs.InvalidArgument("tags: " + err.Error())
+	}
+	report.Tags = tags
+
+	// Instantiate the draft from a template if one is requested
+	if req.TemplateID != nil {
+		template, err := models.GetTemplateByID(ctx, *req.TemplateID)
+		if err != nil {
+			if err == models.ErrTemplateNotFound {
+				return nil, errs.InvalidArgument("template not found")
+			}
+			rlog.Error("failed to get template", "error", err)
+			return nil, errs.Internal("failed to get template")
+		}
+		if !templateAppliesTo(template, req.ProjectID, req.DepartmentID) {
+			return nil, errs.InvalidArgument("template does not belong to the report's project or department")
+		}
+
+		applyTemplate(template, report)
+	}
+
+	// Check the metadata, template defaults included, against the
+	// department's schema
+	if err := checkMetadata(ctx, report.DepartmentID, report.Metadata); err != nil {
+		return nil, err
+	}
+
 	// Save to database
 	err = models.SaveReport(ctx, report)
 	if err != nil {
@@ -185,11 +340,27 @@ func ListReports(ctx context.Context, req *ListReportsRequest) (*ListReportsResp
 		offset = req.Offset
 	}
 
+	tags, err := tagFilter(req.TagType, req.TagScope, req.TagImpact)
+	if err != nil {
+		return nil, err
+	}
+
+	// Overdue is derived from the reporting period rather than stored
+	status, overdue := req.Status, req.Overdue
+	if status != nil && *status == "overdue" {
+		overdueOnly := true
+		status, overdue = nil, &overdueOnly
+	}
+
 	// Get reports from database
 	reports, total, err := models.ListReports(ctx, models.ListReportsParams{
-		Status:    req.Status,
+		Status:    status,
 		AuthorID:  req.AuthorID,
 		ProjectID: req.ProjectID,
+		PeriodID:  req.PeriodID,
+		Overdue:   overdue,
+		Now:       time.Now(),
+		Tags:      tags,
 		Limit:     limit,
 		Offset:    offset,
 	})
@@ -224,10 +395,19 @@ func ListReports(ctx context.Context, req *ListReportsRequest) (*ListReportsResp
 				ErrorDetails:       evaluation.ErrorDetails,
 				LoadDetails:        evaluation.LoadDetails,
 				EvaluatorID:        evaluation.EvaluatorID,
+				Version:            evaluation.Version,
 			}
 		}
 
 		apiReports[i] = *convertModelToAPIReport(&report, apiEvaluation)
+
+		// Surface comment activity so reviewers can spot open threads
+		counts, err := models.CountCommentsByReportID(ctx, report.ID)
+		if err != nil {
+			rlog.Error("failed to count comments", "report_id", report.ID, "error", err)
+		}
+		apiReports[i].CommentCount = counts.Total
+		apiReports[i].UnresolvedCommentCount = counts.Unresolved
 	}
 
 	return &ListReportsResponse{
@@ -236,9 +416,202 @@ func ListReports(ctx context.Context, req *ListReportsRequest) (*ListReportsResp
 	}, nil
 }
 
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id
+func GetReport(ctx context.Context, id string) (*Report, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check
+	// - Performance (P8): Lookups by ID only
+	// - Memory (M8): Single report and evaluation
+	// - Testing (T7): Not found, evaluation and comment counts tested
+	// - Error (E8): Not found distinguished from storage errors
+	// - Load (L8): Read-only, suitable for polling
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		rlog.Error("failed to get evaluation", "report_id", report.ID, "error", err)
+		// Continue even if evaluation retrieval fails
+	}
+
+	response := convertModelToAPIReport(report, convertModelToAPIEvaluation(evaluation))
+
+	counts, err := models.CountCommentsByReportID(ctx, report.ID)
+	if err != nil {
+		rlog.Error("failed to count comments", "report_id", report.ID, "error", err)
+	}
+	response.CommentCount = counts.Total
+	response.UnresolvedCommentCount = counts.Unresolved
+
+	return response, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=PUT path=/api/reports/:id
+func UpdateReport(ctx context.Context, id string, req *UpdateReportRequest) (*Report, error) {
+	// Score: [S8,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S8): Author only, drafts only, metadata checked against the department schema, tags against the taxonomy
+	// - Performance (P7): Single read and write
+	// - Memory (M7): Updates the report in place
+	// - Testing (T7): Tag, metadata, status and author checks tested
+	// - Error (E8): Path-level metadata errors, lifecycle changes and stale If-Match refused
+	// - Load (L7): Low write volume
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Hold the report so the version check and the write are atomic
+	unlock := models.LockReport(ctx, id)
+	defer unlock()
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	// Check if user is the author
+	if report.AuthorID != userID {
+		return nil, errs.Permission("only the author can update the report")
+	}
+	if report.Status != "draft" {
+		return nil, errs.InvalidArgument("only draft reports can be updated")
+	}
+	if err := checkNotArchived(report); err != nil {
+		return nil, err
+	}
+	if err := checkPeriodOpen(ctx, report); err != nil {
+		return nil, err
+	}
+	// Lifecycle changes have endpoints of their own
+	if req.Status != nil && *req.Status != report.Status {
+		return nil, errs.InvalidArgument("use the submit, approve and reject endpoints to change the status")
+	}
+	if err := checkIfMatch(ctx, report, req.IfMatch); err != nil {
+		return nil, err
+	}
+
+	// Metadata is checked when it or the department it is checked
+	// against changes
+	departmentID, metadata := report.DepartmentID, report.Metadata
+	if req.DepartmentID != nil {
+		departmentID = *req.DepartmentID
+	}
+	if req.Metadata != nil {
+		metadata = req.Metadata
+	}
+	if req.DepartmentID != nil || req.Metadata != nil {
+		if err := checkMetadata(ctx, departmentID, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	// The period must still fit when it or the department and project
+	// it applies to change
+	projectID, periodID := report.ProjectID, report.PeriodID
+	if req.ProjectID != nil {
+		projectID = *req.ProjectID
+	}
+	if req.PeriodID != nil {
+		periodID = *req.PeriodID
+	}
+	if periodID != "" && (req.PeriodID != nil || req.DepartmentID != nil || req.ProjectID != nil) {
+		if err := checkPeriod(ctx, periodID, departmentID, projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	tags := report.Tags
+	if req.Tags != nil {
+		tags, err = models.ParseTags(*req.Tags)
+		if err != nil {
+			return nil, errs.InvalidArgument("tags: " + err.Error())
+		}
+	}
+
+	if req.Title != nil {
+		report.Title = *req.Title
+	}
+	if req.Description != nil {
+		report.Description = *req.Description
+	}
+	report.Tags = tags
+	report.ProjectID = projectID
+	report.PeriodID = periodID
+	report.DepartmentID = departmentID
+	report.Metadata = metadata
+
+	// Save to database
+	err = models.SaveReport(ctx, report)
+	if err != nil {
+		rlog.Error("failed to save report", "error", err)
+		return nil, errs.Internal("failed to save report")
+	}
+
+	var evaluation *models.Evaluation
+	if req.Evaluation != nil {
+		evaluation, err = recordEvaluation(ctx, report, userID, &EvaluateReportRequest{
+			SecurityScore:      req.Evaluation.SecurityScore,
+			PerformanceScore:   req.Evaluation.PerformanceScore,
+			MemoryScore:        req.Evaluation.MemoryScore,
+			TestingScore:       req.Evaluation.TestingScore,
+			ErrorScore:         req.Evaluation.ErrorScore,
+			LoadScore:          req.Evaluation.LoadScore,
+			SecurityDetails:    req.Evaluation.SecurityDetails,
+			PerformanceDetails: req.Evaluation.PerformanceDetails,
+			MemoryDetails:      req.Evaluation.MemoryDetails,
+			TestingDetails:     req.Evaluation.TestingDetails,
+			ErrorDetails:       req.Evaluation.ErrorDetails,
+			LoadDetails:        req.Evaluation.LoadDetails,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		evaluation, err = models.GetEvaluationByReportID(ctx, report.ID)
+		if err != nil && err != models.ErrEvaluationNotFound {
+			rlog.Error("failed to get evaluation", "report_id", report.ID, "error", err)
+			// Continue even if evaluation retrieval fails
+		}
+	}
+
+	return convertModelToAPIReport(report, convertModelToAPIEvaluation(evaluation)), nil
+}
+
 // BE-OUT - External data involved
 //encore:api public method=POST path=/api/reports/:id/submit
 func SubmitReport(ctx context.Context, id string) (*Report, error) {
+	return idempotent(ctx, nil, func() (*Report, error) { return submitReport(ctx, id) })
+}
+
+// BE-IN - Internal backend only
+func submitReport(ctx context.Context, id string) (*Report, error) {
 	// Score: [S8,P7,M6,T8,E9,L7]
 	// Details:
 	// - Security (S8): Authorization checks, validation of report ownership
@@ -255,6 +628,10 @@ func SubmitReport(ctx context.Context, id string) (*Report, error) {
 		return nil, errs.Unauthenticated("user must be authenticated")
 	}
 
+	// Hold the report while its status changes
+	unlock := models.LockReport(ctx, id)
+	defer unlock()
+
 	// Get report from database
 	report, err := models.GetReportByID(ctx, id)
 	if err != nil {
@@ -274,27 +651,68 @@ func SubmitReport(ctx context.Context, id string) (*Report, error) {
 	if report.Status != "draft" {
 		return nil, errs.InvalidArgument("report is already submitted")
 	}
+	if err := checkNotArchived(report); err != nil {
+		return nil, err
+	}
+	if err := checkPeriodOpen(ctx, report); err != nil {
+		return nil, err
+	}
+
+	// Check that every required template section has been filled in
+	if report.TemplateID != "" {
+		template, err := models.GetTemplateByID(ctx, report.TemplateID)
+		if err != nil && err != models.ErrTemplateNotFound {
+			rlog.Error("failed to get template", "error", err)
+			return nil, errs.Internal("failed to get template")
+		}
+		if template != nil {
+			if missing := template.MissingSections(report.Description); len(missing) > 0 {
+				return nil, errs.InvalidArgument("report is missing required sections: " + strings.Join(missing, ", "))
+			}
+		}
+	}
+
+	// Departments such as Security refuse reports with open critical
+	// security findings
+	if err := checkCriticalFindings(ctx, report); err != nil {
+		return nil, err
+	}
+
+	// Get evaluation
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		rlog.Error("failed to get evaluation", "error", err)
+		// Continue even if evaluation retrieval fails
+	}
 
 	// Update report status
 	now := time.Now()
+	previousSubmittedAt := report.SubmittedAt
 	report.Status = "submitted"
 	report.UpdatedAt = now
 	report.SubmittedAt = &now
 
-	// Save to database
+	// Sign the submission snapshot; a report is never submitted unsigned
+	receipt, err := signSubmission(ctx, report, evaluation)
+	if err != nil {
+		report.Status = "draft"
+		report.SubmittedAt = previousSubmittedAt
+		rlog.Error("failed to sign submission", "report_id", report.ID, "error", err)
+		return nil, errs.Internal("failed to sign submission")
+	}
+
+	// Save to database, dropping the receipt if the submission is lost
 	err = models.SaveReport(ctx, report)
 	if err != nil {
+		report.Status = "draft"
+		report.SubmittedAt = previousSubmittedAt
+		if err := models.DeleteSubmissionReceipt(ctx, report.ID, receipt.ID); err != nil {
+			rlog.Error("failed to delete submission receipt", "report_id", report.ID, "receipt_id", receipt.ID, "error", err)
+		}
 		rlog.Error("failed to save report", "error", err)
 		return nil, errs.Internal("failed to save report")
 	}
 
-	// Get evaluation
-	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
-	if err != nil && err != models.ErrEvaluationNotFound {
-		rlog.Error("failed to get evaluation", "error", err)
-		// Continue even if evaluation retrieval fails
-	}
-
 	var apiEvaluation *Evaluation
 	if evaluation != nil {
 		apiEvaluation = &Evaluation{
@@ -311,16 +729,13 @@ func SubmitReport(ctx context.Context, id string) (*Report, error) {
 			ErrorDetails:       evaluation.ErrorDetails,
 			LoadDetails:        evaluation.LoadDetails,
 			EvaluatorID:        evaluation.EvaluatorID,
+			Version:            evaluation.Version,
 		}
 	}
 
-	// Trigger automatic submission to department
-	go func() {
-		err := submitToDepartment(context.Background(), report, apiEvaluation)
-		if err != nil {
-			rlog.Error("failed to submit to department", "report_id", report.ID, "error", err)
-		}
-	}()
+	// Delivery to the department and notifications run in the
+	// ReportSubmitted subscribers
+	publishReportSubmitted(ctx, report)
 
 	// Convert to API response
 	response := convertModelToAPIReport(report, apiEvaluation)
@@ -329,7 +744,7 @@ func SubmitReport(ctx context.Context, id string) (*Report, error) {
 
 // BE-IN - Internal backend only
 func convertModelToAPIReport(model *models.Report, evaluation *Evaluation) *Report {
-	return &Report{
+	report := &Report{
 		ID:           model.ID,
 		Title:        model.Title,
 		Description:  model.Description,
@@ -341,8 +756,20 @@ func convertModelToAPIReport(model *models.Report, evaluation *Evaluation) *Repo
 		UpdatedAt:    model.UpdatedAt,
 		SubmittedAt:  model.SubmittedAt,
 		Metadata:     model.Metadata,
+		TemplateID:   model.TemplateID,
+		Tags:         tagStrings(model.Tags),
 		Evaluation:   evaluation,
+		Version:      model.Version,
+		ETag:         reportETag(model.Version, evaluation),
+		ArchivedAt:   model.ArchivedAt,
+		PeriodID:     model.PeriodID,
+	}
+	if model.EvaluationPrompts != nil {
+		prompts := EvaluationPrompts(*model.EvaluationPrompts)
+		report.EvaluationPrompts = &prompts
 	}
+	report.DueAt, report.Overdue = models.ReportDeadline(model, time.Now())
+	return report
 }
 
 // BE-OUT - External data involved
@@ -392,6 +819,16 @@ func submitToDepartment(ctx context.Context, report *models.Report, evaluation *
 		}
 	}
 
+	// Embed the submission's signed receipt so the department can verify
+	// what it received
+	receipt, err := models.GetLatestReceipt(ctx, report.ID)
+	if err != nil && err != models.ErrReceiptNotFound {
+		return err
+	}
+	if receipt != nil && report.SubmittedAt != nil && receipt.SubmittedAt.Equal(*report.SubmittedAt) {
+		payload["signature"] = convertModelToAPIReceipt(receipt)
+	}
+
 	// In a real implementation, this would call the department's API
 	// For now, we'll just log the submission
 	rlog.Info("submitting report to department", 
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGetReport(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := GetReport(ctx, "report-missing"); err == nil || !strings.Contains(err.Error(), "report not found") {
+		t.Errorf("GetReport on a missing report = %v, want not found", err)
+	}
+
+	report := draftReport(t)
+	got, err := GetReport(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetReport: %v", err)
+	}
+	if got.Evaluation != nil || got.CommentCount != 0 {
+		t.Errorf("fresh draft has evaluation %+v and %d comments, want neither", got.Evaluation, got.CommentCount)
+	}
+
+	if _, err := recordDimension(ctx, report.ID, "user-123", "", func(e *EvaluateReportRequest) { e.TestingScore = 7 }); err != nil {
+		t.Fatalf("recordDimension: %v", err)
+	}
+	root, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "Where are the load tests?"})
+	if err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	if _, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "Added them", ParentID: &root.ID}); err != nil {
+		t.Fatalf("CreateComment reply: %v", err)
+	}
+	if _, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "Looks good"}); err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	if _, err := ResolveComment(ctx, root.ID); err != nil {
+		t.Fatalf("ResolveComment: %v", err)
+	}
+
+	got, err = GetReport(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetReport: %v", err)
+	}
+	if got.Evaluation == nil || got.Evaluation.TestingScore != 7 {
+		t.Errorf("Evaluation = %+v, want the recorded TestingScore", got.Evaluation)
+	}
+	if got.CommentCount != 3 || got.UnresolvedCommentCount != 1 {
+		t.Errorf("comments = %d total, %d unresolved, want 3 and 1", got.CommentCount, got.UnresolvedCommentCount)
+	}
+}
@@ -0,0 +1,475 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/cron"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// maxRetainDays is one hundred years
+const maxRetainDays = 36500
+
+// BE-IN - Internal backend only
+// Apply retention policies once a day
+var _ = cron.NewJob("apply-retention", cron.JobConfig{
+	Title:    "Archive or purge reports past their retention period",
+	Every:    24 * cron.Hour,
+	Endpoint: ApplyRetention,
+})
+
+// BE-IN - Internal backend only
+type RetentionPolicy struct {
+	DepartmentID string    `json:"department_id"`
+	ProjectID    string    `json:"project_id,omitempty"`
+	RetainDays   int       `json:"retain_days"`
+	Action       string    `json:"action"`
+	UpdatedBy    string    `json:"updated_by"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+type SetRetentionPolicyRequest struct {
+	// RetainDays counts from submission, or from creation for reports
+	// never submitted
+	RetainDays int `json:"retain_days"`
+	// Action is archive or purge
+	Action string `json:"action"`
+}
+
+// BE-IN - Internal backend only
+func (r *SetRetentionPolicyRequest) Validate() error {
+	var v ValidationErrors
+	if r.RetainDays < 1 || r.RetainDays > maxRetainDays {
+		v.add("retain_days", "must be between 1 and %d", maxRetainDays)
+	}
+	if r.Action != models.RetentionArchive && r.Action != models.RetentionPurge {
+		v.add("action", "must be archive or purge")
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type ListRetentionPoliciesResponse struct {
+	Policies []*RetentionPolicy `json:"policies"`
+}
+
+// BE-IN - Internal backend only
+type LegalHold struct {
+	ReportID string    `json:"report_id"`
+	Reason   string    `json:"reason"`
+	PlacedBy string    `json:"placed_by"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+// BE-IN - Internal backend only
+type PlaceLegalHoldRequest struct {
+	Reason string `json:"reason"`
+}
+
+// BE-IN - Internal backend only
+func (r *PlaceLegalHoldRequest) Validate() error {
+	var v ValidationErrors
+	v.required("reason", strings.TrimSpace(r.Reason))
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type ApplyRetentionResponse struct {
+	Archived []string `json:"archived"`
+	Purged   []string `json:"purged"`
+	// Held lists expired reports kept because of a legal hold
+	Held []string `json:"held"`
+}
+
+// BE-OUT - External data involved
+// ListRetentionPolicies returns the department-wide policy and the
+// project overrides of a department.
+//
+//encore:api public method=GET path=/api/departments/:id/retention-policies
+func ListRetentionPolicies(ctx context.Context, id string) (*ListRetentionPoliciesResponse, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Any authenticated user may read the policies
+	// - Performance (P8): Scan of the policy store
+	// - Memory (M8): One entry per policy
+	// - Testing (T7): Ordering and unknown departments tested
+	// - Error (E8): Proper error handling
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	if _, err := models.GetUserIDFromContext(ctx); err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	if _, err := models.GetDepartmentByID(ctx, id); err != nil {
+		return nil, errs.NotFound("department not found")
+	}
+
+	policies, err := models.ListRetentionPolicies(ctx, id)
+	if err != nil {
+		rlog.Error("failed to list retention policies", "department_id", id, "error", err)
+		return nil, errs.Internal("failed to list retention policies")
+	}
+
+	response := &ListRetentionPoliciesResponse{Policies: make([]*RetentionPolicy, len(policies))}
+	for i := range policies {
+		response.Policies[i] = convertModelToAPIRetentionPolicy(&policies[i])
+	}
+	return response, nil
+}
+
+// BE-OUT - External data involved
+// SetRetentionPolicy sets the department-wide retention policy.
+//
+//encore:api public method=PUT path=/api/departments/:id/retention-policy
+func SetRetentionPolicy(ctx context.Context, id string, req *SetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return setRetentionPolicy(ctx, id, "", req)
+}
+
+// BE-OUT - External data involved
+// SetProjectRetentionPolicy sets a policy for one project that overrides
+// the department-wide one.
+//
+//encore:api public method=PUT path=/api/departments/:id/projects/:project/retention-policy
+func SetProjectRetentionPolicy(ctx context.Context, id, project string, req *SetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return setRetentionPolicy(ctx, id, project, req)
+}
+
+// BE-OUT - External data involved
+//encore:api public method=DELETE path=/api/departments/:id/retention-policy
+func DeleteRetentionPolicy(ctx context.Context, id string) error {
+	return deleteRetentionPolicy(ctx, id, "")
+}
+
+// BE-OUT - External data involved
+//encore:api public method=DELETE path=/api/departments/:id/projects/:project/retention-policy
+func DeleteProjectRetentionPolicy(ctx context.Context, id, project string) error {
+	return deleteRetentionPolicy(ctx, id, project)
+}
+
+// BE-OUT - External data involved
+func setRetentionPolicy(ctx context.Context, departmentID, projectID string, req *SetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Department head only, every change audited
+	// - Performance (P8): Single write
+	// - Memory (M8): One policy per department and project
+	// - Testing (T7): Head-only access, validation and auditing tested
+	// - Error (E8): Proper error handling
+	// - Load (L8): Low write volume
+	// Tags: BE-module-medium
+
+	userID, err := requireDepartmentHead(ctx, departmentID, "only the department head can change retention policies")
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.RetentionPolicy{
+		DepartmentID: departmentID,
+		ProjectID:    projectID,
+		RetainDays:   req.RetainDays,
+		Action:       req.Action,
+		UpdatedBy:    userID,
+	}
+	if err := models.SaveRetentionPolicy(ctx, policy); err != nil {
+		rlog.Error("failed to save retention policy", "department_id", departmentID, "project_id", projectID, "error", err)
+		return nil, errs.Internal("failed to save retention policy")
+	}
+
+	audit(ctx, &models.AuditEntry{
+		Action:       models.AuditRetentionPolicySet,
+		ActorID:      userID,
+		DepartmentID: departmentID,
+		ProjectID:    projectID,
+		Details: map[string]string{
+			"retain_days": strconv.Itoa(policy.RetainDays),
+			"action":      policy.Action,
+		},
+	})
+
+	return convertModelToAPIRetentionPolicy(policy), nil
+}
+
+// BE-OUT - External data involved
+func deleteRetentionPolicy(ctx context.Context, departmentID, projectID string) error {
+	userID, err := requireDepartmentHead(ctx, departmentID, "only the department head can change retention policies")
+	if err != nil {
+		return err
+	}
+
+	policy, err := models.DeleteRetentionPolicy(ctx, departmentID, projectID)
+	if err != nil {
+		if err == models.ErrRetentionPolicyNotFound {
+			return errs.NotFound("retention policy not found")
+		}
+		rlog.Error("failed to delete retention policy", "department_id", departmentID, "project_id", projectID, "error", err)
+		return errs.Internal("failed to delete retention policy")
+	}
+
+	audit(ctx, &models.AuditEntry{
+		Action:       models.AuditRetentionPolicyDeleted,
+		ActorID:      userID,
+		DepartmentID: departmentID,
+		ProjectID:    projectID,
+		Details: map[string]string{
+			"retain_days": strconv.Itoa(policy.RetainDays),
+			"action":      policy.Action,
+		},
+	})
+	return nil
+}
+
+// BE-OUT - External data involved
+// PlaceLegalHold exempts a report, including one in the trash, from
+// deletion and from purging until the hold is released.
+//
+//encore:api public method=PUT path=/api/reports/:id/legal-hold
+func PlaceLegalHold(ctx context.Context, id string, req *PlaceLegalHoldRequest) (*LegalHold, error) {
+	// Score: [S9,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S9): Head of the report's department only, audited
+	// - Performance (P8): Single write
+	// - Memory (M8): One hold per report
+	// - Testing (T7): Conflicts and holds on trashed reports tested
+	// - Error (E8): Existing holds reported as conflicts
+	// - Load (L8): Low write volume
+	// Tags: BE-module-high
+
+	report, userID, err := legalHoldReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	hold := &models.LegalHold{
+		ReportID: report.ID,
+		Reason:   strings.TrimSpace(req.Reason),
+		PlacedBy: userID,
+	}
+	if err := models.PlaceLegalHold(ctx, hold); err != nil {
+		if err == models.ErrLegalHoldExists {
+			return nil, errs.AlreadyExists("report is already under legal hold")
+		}
+		rlog.Error("failed to place legal hold", "report_id", id, "error", err)
+		return nil, errs.Internal("failed to place legal hold")
+	}
+
+	auditReport(ctx, models.AuditLegalHoldPlaced, userID, report, map[string]string{"reason": hold.Reason})
+
+	return convertModelToAPILegalHold(hold), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/legal-hold
+func GetLegalHold(ctx context.Context, id string) (*LegalHold, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Head of the report's department only
+	// - Performance (P8): Single lookup
+	// - Memory (M8): No additional allocations
+	// - Testing (T7): Held, unheld and non-head lookups tested
+	// - Error (E8): Proper error handling
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	if _, _, err := legalHoldReport(ctx, id); err != nil {
+		return nil, err
+	}
+
+	hold, err := models.GetLegalHold(ctx, id)
+	if err != nil {
+		if err == models.ErrLegalHoldNotFound {
+			return nil, errs.NotFound("report is not under legal hold")
+		}
+		rlog.Error("failed to get legal hold", "report_id", id, "error", err)
+		return nil, errs.Internal("failed to get legal hold")
+	}
+	return convertModelToAPILegalHold(hold), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=DELETE path=/api/reports/:id/legal-hold
+func ReleaseLegalHold(ctx context.Context, id string) error {
+	// Score: [S9,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S9): Head of the report's department only, audited
+	// - Performance (P8): Single delete
+	// - Memory (M8): Frees the hold
+	// - Testing (T7): Release and repeat release tested
+	// - Error (E8): Proper error handling
+	// - Load (L8): Low write volume
+	// Tags: BE-module-high
+
+	report, userID, err := legalHoldReport(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	hold, err := models.ReleaseLegalHold(ctx, id)
+	if err != nil {
+		if err == models.ErrLegalHoldNotFound {
+			return errs.NotFound("report is not under legal hold")
+		}
+		rlog.Error("failed to release legal hold", "report_id", id, "error", err)
+		return errs.Internal("failed to release legal hold")
+	}
+
+	auditReport(ctx, models.AuditLegalHoldReleased, userID, report, map[string]string{"reason": hold.Reason})
+	return nil
+}
+
+// BE-IN - Internal backend only
+// ApplyRetention archives or purges the reports past the retention
+// period of their department or project. Reports under legal hold are
+// archived when due but never purged. It runs from the apply-retention
+// cron job.
+//
+//encore:api private
+func ApplyRetention(ctx context.Context) (*ApplyRetentionResponse, error) {
+	// Score: [S8,P6,M7,T7,E7,L7]
+	// Details:
+	// - Security (S8): Private endpoint, legal holds respected, audited
+	// - Performance (P6): Full scan of reports once a day
+	// - Memory (M7): Holds the affected IDs only
+	// - Testing (T7): Purging, archiving of held reports and fresh reports tested
+	// - Error (E7): Failures on one report logged, the rest continue
+	// - Load (L7): Runs off the request path
+	// Tags: BE-DB-high
+
+	expired, err := models.FindExpiredReports(ctx, time.Now())
+	if err != nil {
+		rlog.Error("failed to find expired reports", "error", err)
+		return nil, errs.Internal("failed to find expired reports")
+	}
+
+	response := &ApplyRetentionResponse{Archived: []string{}, Purged: []string{}, Held: []string{}}
+	for _, e := range expired {
+		applyRetention(ctx, e, response)
+	}
+	if len(response.Archived)+len(response.Purged) > 0 {
+		rlog.Info("applied retention policies", "archived", len(response.Archived), "purged", len(response.Purged), "held", len(response.Held))
+	}
+	return response, nil
+}
+
+// BE-IN - Internal backend only
+func applyRetention(ctx context.Context, e models.ExpiredReport, response *ApplyRetentionResponse) {
+	unlock := models.LockReport(ctx, e.Report.ID)
+	defer unlock()
+
+	report := *e.Report
+	details := map[string]string{
+		"reason":      "retention",
+		"retain_days": strconv.Itoa(e.Policy.RetainDays),
+		"expired_at":  e.ExpiredAt.Format(time.RFC3339),
+	}
+
+	action := e.Policy.Action
+	if action == models.RetentionPurge && models.IsUnderLegalHold(ctx, report.ID) {
+		response.Held = append(response.Held, report.ID)
+		if report.ArchivedAt != nil {
+			return
+		}
+		// Held reports are still archived, so they can no longer change
+		action = models.RetentionArchive
+	}
+
+	switch action {
+	case models.RetentionArchive:
+		if err := models.ArchiveReport(ctx, report.ID); err != nil {
+			rlog.Error("failed to archive report", "report_id", report.ID, "error", err)
+			return
+		}
+		response.Archived = append(response.Archived, report.ID)
+		auditReport(ctx, models.AuditReportArchived, models.AuditActorSystem, &report, details)
+	case models.RetentionPurge:
+		if err := models.PurgeReport(ctx, report.ID); err != nil {
+			rlog.Error("failed to purge report", "report_id", report.ID, "error", err)
+			return
+		}
+		response.Purged = append(response.Purged, report.ID)
+		auditReport(ctx, models.AuditReportPurged, models.AuditActorSystem, &report, details)
+	}
+}
+
+// BE-IN - Internal backend only
+// requireDepartmentHead returns the caller when they head the
+// department, and an error otherwise.
+func requireDepartmentHead(ctx context.Context, departmentID, denied string) (string, error) {
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return "", errs.Unauthenticated("user must be authenticated")
+	}
+
+	department, err := models.GetDepartmentByID(ctx, departmentID)
+	if err != nil {
+		return "", errs.NotFound("department not found")
+	}
+	if department.HeadID != userID {
+		return "", errs.Permission(denied)
+	}
+	return userID, nil
+}
+
+// BE-IN - Internal backend only
+// legalHoldReport returns a report, in the trash or not, when the caller
+// heads its department.
+func legalHoldReport(ctx context.Context, id string) (*models.Report, string, error) {
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, "", errs.Unauthenticated("user must be authenticated")
+	}
+
+	report, err := models.GetReportByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, "", errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, "", errs.Internal("failed to get report")
+	}
+
+	department, err := models.GetDepartmentByID(ctx, report.DepartmentID)
+	if err != nil || department.HeadID != userID {
+		return nil, "", errs.Permission("only the department head can manage legal holds")
+	}
+	return report, userID, nil
+}
+
+// BE-IN - Internal backend only
+// checkNotArchived refuses changes to archived reports.
+func checkNotArchived(report *models.Report) error {
+	if report.ArchivedAt != nil {
+		return errs.FailedPrecondition("report has been archived and is read-only")
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIRetentionPolicy(model *models.RetentionPolicy) *RetentionPolicy {
+	return &RetentionPolicy{
+		DepartmentID: model.DepartmentID,
+		ProjectID:    model.ProjectID,
+		RetainDays:   model.RetainDays,
+		Action:       model.Action,
+		UpdatedBy:    model.UpdatedBy,
+		UpdatedAt:    model.UpdatedAt,
+	}
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPILegalHold(model *models.LegalHold) *LegalHold {
+	return &LegalHold{
+		ReportID: model.ReportID,
+		Reason:   model.Reason,
+		PlacedBy: model.PlacedBy,
+		PlacedAt: model.PlacedAt,
+	}
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"encore.app/models"
+)
+
+func TestRetentionPolicies(t *testing.T) {
+	ctx := context.Background()
+	policy := &SetRetentionPolicyRequest{RetainDays: 365, Action: models.RetentionArchive}
+
+	if _, err := SetRetentionPolicy(ctx, "dept-456", policy); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Errorf("SetRetentionPolicy as user-123 = %v, want permission denied", err)
+	}
+	if err := (&SetRetentionPolicyRequest{RetainDays: 0, Action: "shred"}).Validate(); err == nil || !strings.Contains(err.Error(), "retain_days") || !strings.Contains(err.Error(), "action") {
+		t.Errorf("Validate = %v, want retain_days and action errors", err)
+	}
+
+	// Jordan Lead heads dept-456
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if _, err := SetProjectRetentionPolicy(ctx, "dept-456", "project-policies", &SetRetentionPolicyRequest{RetainDays: 30, Action: models.RetentionPurge}); err != nil {
+		t.Fatalf("SetProjectRetentionPolicy: %v", err)
+	}
+	set, err := SetRetentionPolicy(ctx, "dept-456", policy)
+	if err != nil {
+		t.Fatalf("SetRetentionPolicy: %v", err)
+	}
+	if set.UpdatedBy != "user-789" || set.UpdatedAt.IsZero() {
+		t.Errorf("policy = %+v, want updated by user-789", set)
+	}
+
+	list, err := ListRetentionPolicies(ctx, "dept-456")
+	if err != nil {
+		t.Fatalf("ListRetentionPolicies: %v", err)
+	}
+	var got []string
+	for _, p := range list.Policies {
+		got = append(got, p.ProjectID+":"+p.Action)
+	}
+	if want := []string{":archive", "project-policies:purge"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("policies = %q, want the department-wide one first: %q", got, want)
+	}
+
+	if err := DeleteRetentionPolicy(ctx, "dept-456"); err != nil {
+		t.Fatalf("DeleteRetentionPolicy: %v", err)
+	}
+	if err := DeleteProjectRetentionPolicy(ctx, "dept-456", "project-policies"); err != nil {
+		t.Fatalf("DeleteProjectRetentionPolicy: %v", err)
+	}
+	if err := DeleteRetentionPolicy(ctx, "dept-456"); err == nil || !strings.Contains(err.Error(), "retention policy not found") {
+		t.Errorf("deleting twice = %v, want not found", err)
+	}
+	if _, err := ListRetentionPolicies(ctx, "dept-missing"); err == nil || !strings.Contains(err.Error(), "department not found") {
+		t.Errorf("ListRetentionPolicies for an unknown department = %v, want not found", err)
+	}
+
+	entries, err := ListAudit(ctx, "dept-456", &ListAuditRequest{Action: models.AuditRetentionPolicyDeleted, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(entries.Entries) != 2 || entries.Entries[0].ProjectID != "project-policies" || entries.Entries[1].ProjectID != "" {
+		t.Errorf("audit entries = %+v, want both deletions, newest first", entries.Entries)
+	}
+}
+
+func TestApplyRetentionAndLegalHolds(t *testing.T) {
+	ctx := context.Background()
+	const project = "project-retention"
+
+	report := func(title string, created time.Time) *models.Report {
+		t.Helper()
+		r := &models.Report{Title: title, ProjectID: project, AuthorID: "user-123", DepartmentID: "dept-456", Status: "draft", CreatedAt: created}
+		if err := models.SaveReport(ctx, r); err != nil {
+			t.Fatalf("SaveReport: %v", err)
+		}
+		t.Cleanup(func() { models.DeleteReport(ctx, r.ID) })
+		return r
+	}
+	old := time.Now().AddDate(0, 0, -60)
+	expired := report("Expired", old)
+	held := report("Held", old)
+	fresh := report("Fresh", time.Now())
+
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if _, err := SetProjectRetentionPolicy(ctx, "dept-456", project, &SetRetentionPolicyRequest{RetainDays: 30, Action: models.RetentionPurge}); err != nil {
+		t.Fatalf("SetProjectRetentionPolicy: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteRetentionPolicy(ctx, "dept-456", project) })
+
+	hold := &PlaceLegalHoldRequest{Reason: "Pending litigation"}
+	if _, err := PlaceLegalHold(ctx, held.ID, hold); err != nil {
+		t.Fatalf("PlaceLegalHold: %v", err)
+	}
+	if _, err := PlaceLegalHold(ctx, held.ID, hold); err == nil || !strings.Contains(err.Error(), "already under legal hold") {
+		t.Errorf("placing a second hold = %v, want a conflict", err)
+	}
+	if got, err := GetLegalHold(ctx, held.ID); err != nil || got.Reason != hold.Reason || got.PlacedBy != "user-789" {
+		t.Errorf("GetLegalHold = %+v, %v, want the hold placed by user-789", got, err)
+	}
+	if _, err := GetLegalHold(ctx, expired.ID); err == nil || !strings.Contains(err.Error(), "not under legal hold") {
+		t.Errorf("GetLegalHold without a hold = %v, want not found", err)
+	}
+
+	applied, err := ApplyRetention(ctx)
+	if err != nil {
+		t.Fatalf("ApplyRetention: %v", err)
+	}
+	contains := func(ids []string, id string) bool {
+		for _, x := range ids {
+			if x == id {
+				return true
+			}
+		}
+		return false
+	}
+	if !contains(applied.Purged, expired.ID) || contains(applied.Purged, held.ID) || contains(applied.Purged, fresh.ID) {
+		t.Errorf("Purged = %q, want only %s of this project", applied.Purged, expired.ID)
+	}
+	// A held report due for purging is archived instead
+	if !contains(applied.Held, held.ID) || !contains(applied.Archived, held.ID) {
+		t.Errorf("Held = %q, Archived = %q, want %s in both", applied.Held, applied.Archived, held.ID)
+	}
+	if _, err := models.GetReportByIDIncludingDeleted(ctx, expired.ID); err != models.ErrReportNotFound {
+		t.Errorf("expired report = %v, want purged", err)
+	}
+
+	t.Setenv("DEMO_USER_ID", "")
+	title := "Held, renamed"
+	if _, err := UpdateReport(ctx, held.ID, &UpdateReportRequest{Title: &title}); err == nil || !strings.Contains(err.Error(), "archived and is read-only") {
+		t.Errorf("updating an archived report = %v, want rejected", err)
+	}
+	if _, err := GetLegalHold(ctx, held.ID); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Errorf("GetLegalHold as the author = %v, want permission denied", err)
+	}
+	if _, err := ListAudit(ctx, "dept-456", &ListAuditRequest{}); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Errorf("ListAudit as the author = %v, want permission denied", err)
+	}
+
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if err := ReleaseLegalHold(ctx, held.ID); err != nil {
+		t.Fatalf("ReleaseLegalHold: %v", err)
+	}
+	if err := ReleaseLegalHold(ctx, held.ID); err == nil || !strings.Contains(err.Error(), "not under legal hold") {
+		t.Errorf("releasing twice = %v, want not found", err)
+	}
+
+	entries, err := ListAudit(ctx, "dept-456", &ListAuditRequest{ReportID: held.ID})
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	var actions []string
+	for _, e := range entries.Entries {
+		actions = append(actions, e.Action+" by "+e.ActorID)
+	}
+	want := []string{"legal_hold.released by user-789", "report.archived by system", "legal_hold.placed by user-789"}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("audit = %q, want %q", actions, want)
+	}
+	if err := (&ListAuditRequest{Limit: maxAuditEntries + 1}).Validate(); err == nil {
+		t.Error("Validate accepted a limit above the maximum")
+	}
+}
+
+func TestLegalHoldKeepsTrashedReports(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	if _, err := DeleteReport(ctx, report.ID); err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+	// Holds can be placed on reports in the trash
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if _, err := PlaceLegalHold(ctx, report.ID, &PlaceLegalHoldRequest{Reason: "Audit request"}); err != nil {
+		t.Fatalf("PlaceLegalHold: %v", err)
+	}
+
+	saved := trashRetention
+	trashRetention = 0
+	t.Cleanup(func() { trashRetention = saved })
+	if err := PurgeTrash(ctx); err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if _, err := models.GetReportByIDIncludingDeleted(ctx, report.ID); err != nil {
+		t.Fatalf("held report was purged: %v", err)
+	}
+
+	// A restored report under hold cannot be deleted again
+	t.Setenv("DEMO_USER_ID", "")
+	if _, err := RestoreReport(ctx, report.ID); err != nil {
+		t.Fatalf("RestoreReport: %v", err)
+	}
+	if _, err := DeleteReport(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "under legal hold") {
+		t.Errorf("deleting a held report = %v, want rejected", err)
+	}
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if err := ReleaseLegalHold(ctx, report.ID); err != nil {
+		t.Fatalf("ReleaseLegalHold: %v", err)
+	}
+}
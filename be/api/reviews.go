@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type ReviewReportRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/approve
+func ApproveReport(ctx context.Context, id string, req *ReviewReportRequest) (*Report, error) {
+	return idempotent(ctx, req, func() (*Report, error) { return reviewReport(ctx, id, "approved", req) })
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/reject
+func RejectReport(ctx context.Context, id string, req *ReviewReportRequest) (*Report, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errs.InvalidArgument("a reason is required to reject a report")
+	}
+	return idempotent(ctx, req, func() (*Report, error) { return reviewReport(ctx, id, "rejected", req) })
+}
+
+// BE-IN - Internal backend only
+func reviewReport(ctx context.Context, id, decision string, req *ReviewReportRequest) (*Report, error) {
+	// Score: [S8,P8,M7,T7,E8,L8]
+	// Details:
+	// - Security (S8): Only the head of the target department can decide
+	// - Performance (P8): Single read and write
+	// - Memory (M7): Updates the report in place
+	// - Testing (T7): Status, department head and reason checks tested with the notifications they send
+	// - Error (E8): Rejects decisions on reports that are not submitted
+	// - Load (L8): Low write volume
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Hold the report while its status changes
+	unlock := models.LockReport(ctx, id)
+	defer unlock()
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	// Check if user heads the department the report was submitted to
+	department, err := models.GetDepartmentByID(ctx, report.DepartmentID)
+	if err != nil || department.HeadID != userID {
+		return nil, errs.Permission("only the department head can review the report")
+	}
+
+	if report.Status != "submitted" {
+		return nil, errs.InvalidArgument("only submitted reports can be reviewed")
+	}
+	if err := checkNotArchived(report); err != nil {
+		return nil, err
+	}
+
+	// Update report status
+	report.Status = decision
+
+	// Save to database
+	err = models.SaveReport(ctx, report)
+	if err != nil {
+		rlog.Error("failed to save report", "error", err)
+		return nil, errs.Internal("failed to save report")
+	}
+
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		rlog.Error("failed to get evaluation", "error", err)
+		// Continue even if evaluation retrieval fails
+	}
+
+	publishReportReviewed(ctx, report, userID, strings.TrimSpace(req.Reason))
+
+	return convertModelToAPIReport(report, convertModelToAPIEvaluation(evaluation)), nil
+}
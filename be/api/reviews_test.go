@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"encore.app/services/notifications"
+)
+
+func TestReviewFlowNotifications(t *testing.T) {
+	sender := &recordingSender{}
+	saved := notifier
+	notifier = notifications.NewNotifier(sender, "")
+	t.Cleanup(func() { notifier = saved })
+
+	ctx := context.Background()
+	report := draftReport(t)
+
+	// sent returns "recipient: subject" for the messages since the last call
+	sent := func() []string {
+		var got []string
+		for _, message := range sender.messages {
+			got = append(got, message.To+": "+message.Subject)
+		}
+		sender.messages = nil
+		return got
+	}
+	check := func(step string, want ...string) {
+		t.Helper()
+		if got := sent(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s notified %q, want %q", step, got, want)
+		}
+	}
+
+	// Sam evaluates Alex's draft; only the author hears about it
+	t.Setenv("DEMO_USER_ID", "user-456")
+	if _, err := evaluateReport(ctx, report.ID, &EvaluateReportRequest{SecurityScore: 8, TestingScore: 6}); err != nil {
+		t.Fatalf("evaluateReport: %v", err)
+	}
+	check("evaluation", "alex@example.com: Evaluation recorded: Concurrency test")
+
+	// Reviews need a submitted report and the department head
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if _, err := reviewReport(ctx, report.ID, "approved", &ReviewReportRequest{}); err == nil || !strings.Contains(err.Error(), "only submitted reports") {
+		t.Errorf("reviewing a draft = %v, want rejected", err)
+	}
+
+	// Alex submits; the head of dept-456 is told, the submitter is not
+	t.Setenv("DEMO_USER_ID", "")
+	if _, err := submitReport(ctx, report.ID); err != nil {
+		t.Fatalf("submitReport: %v", err)
+	}
+	check("submission", "jordan@example.com: Report submitted: Concurrency test")
+
+	t.Setenv("DEMO_USER_ID", "user-456")
+	if _, err := reviewReport(ctx, report.ID, "approved", &ReviewReportRequest{}); err == nil || !strings.Contains(err.Error(), "department head") {
+		t.Errorf("review by another department's head = %v, want permission denied", err)
+	}
+	if _, err := RejectReport(ctx, report.ID, &ReviewReportRequest{Reason: "  "}); err == nil || !strings.Contains(err.Error(), "reason is required") {
+		t.Errorf("rejecting without a reason = %v, want rejected", err)
+	}
+
+	// Jordan approves; author and evaluator are told
+	t.Setenv("DEMO_USER_ID", "user-789")
+	approved, err := reviewReport(ctx, report.ID, "approved", &ReviewReportRequest{})
+	if err != nil {
+		t.Fatalf("reviewReport: %v", err)
+	}
+	if approved.Status != "approved" {
+		t.Errorf("Status = %q, want approved", approved.Status)
+	}
+	check("approval", "alex@example.com: Report approved: Concurrency test", "sam@example.com: Report approved: Concurrency test")
+
+	// Decided reports keep their evaluation
+	if _, err := evaluateReport(ctx, report.ID, &EvaluateReportRequest{SecurityScore: 2}); err == nil || !strings.Contains(err.Error(), "already been reviewed") {
+		t.Errorf("evaluating an approved report = %v, want rejected", err)
+	}
+	check("refused evaluation")
+}
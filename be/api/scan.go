@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+
+	"encore.app/models"
+	"encore.app/services/scorescan"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// Upload limits for annotation scans
+const (
+	maxScanFiles = 5000
+	maxScanBytes = 20 << 20
+)
+
+// BE-IN - Internal backend only
+type ScanSourceFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// BE-IN - Internal backend only
+type ScanAnnotationsRequest struct {
+	Files []ScanSourceFile `json:"files" validate:"required,min=1"`
+	Apply bool             `json:"apply,omitempty"`
+	// IfMatch is the report ETag the client read; applying is refused
+	// when the report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+func (r *ScanAnnotationsRequest) Validate() error {
+	var v ValidationErrors
+	if len(r.Files) == 0 {
+		v.add("files", "at least one file is required")
+	}
+	if len(r.Files) > maxScanFiles {
+		v.add("files", "at most %d files can be scanned at once", maxScanFiles)
+	}
+	total := 0
+	for _, f := range r.Files {
+		total += len(f.Content)
+	}
+	if total > maxScanBytes {
+		v.add("files", "sources exceed %d MB", maxScanBytes>>20)
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type AnnotationScores struct {
+	Security    int `json:"security"`
+	Performance int `json:"performance"`
+	Memory      int `json:"memory"`
+	Testing     int `json:"testing"`
+	Error       int `json:"error"`
+	Load        int `json:"load"`
+}
+
+// BE-IN - Internal backend only
+type ScoreAnnotation struct {
+	File     string            `json:"file"`
+	Line     int               `json:"line"`
+	Package  string            `json:"package"`
+	Function string            `json:"function"`
+	Scores   AnnotationScores  `json:"scores"`
+	Details  map[string]string `json:"details,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type AnnotationIssue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// BE-IN - Internal backend only
+type ScanAnnotationsResponse struct {
+	Files       int                `json:"files"`
+	Functions   int                `json:"functions"`
+	Annotations []*ScoreAnnotation `json:"annotations"`
+	Issues      []*AnnotationIssue `json:"issues"`
+	Proposed    *Evaluation        `json:"proposed"`
+	Applied     bool               `json:"applied"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/evaluation/scan
+func ScanAnnotations(ctx context.Context, id string, req *ScanAnnotationsRequest) (*ScanAnnotationsResponse, error) {
+	// Score: [S8,P6,M6,T7,E8,L6]
+	// Details:
+	// - Security (S8): Sources are parsed only, upload size bounded
+	// - Performance (P6): Parses every uploaded file per request
+	// - Memory (M6): Whole upload held in memory while scanning
+	// - Testing (T7): Scanner is a pure library shared with the CLI
+	// - Error (E8): Annotation problems reported per line, applying blocked on errors
+	// - Load (L6): CPU-bound, intended for CI runs rather than interactive traffic
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	sources := make([]scorescan.Source, len(req.Files))
+	for i, f := range req.Files {
+		sources[i] = scorescan.Source{Path: f.Path, Content: f.Content}
+	}
+	result := scorescan.ScanSources(sources)
+	proposed := scorescan.ProposeEvaluation(result.Annotations)
+	proposed.EvaluatorID = userID
+
+	response := &ScanAnnotationsResponse{
+		Files:       result.Files,
+		Functions:   result.Functions,
+		Annotations: make([]*ScoreAnnotation, len(result.Annotations)),
+		Issues:      make([]*AnnotationIssue, len(result.Issues)),
+		Proposed:    convertModelToAPIEvaluation(proposed),
+	}
+	for i, a := range result.Annotations {
+		response.Annotations[i] = &ScoreAnnotation{
+			File:     a.File,
+			Line:     a.Line,
+			Package:  a.Package,
+			Function: a.Function,
+			Scores: AnnotationScores{
+				Security:    a.Scores[0],
+				Performance: a.Scores[1],
+				Memory:      a.Scores[2],
+				Testing:     a.Scores[3],
+				Error:       a.Scores[4],
+				Load:        a.Scores[5],
+			},
+			Details: a.Details,
+			Tags:    a.Tags,
+		}
+	}
+	for i, issue := range result.Issues {
+		response.Issues[i] = &AnnotationIssue{
+			File:     issue.File,
+			Line:     issue.Line,
+			Function: issue.Function,
+			Severity: issue.Severity,
+			Message:  issue.Message,
+		}
+	}
+
+	if !req.Apply {
+		return response, nil
+	}
+
+	if len(result.Annotations) == 0 {
+		return nil, errs.InvalidArgument("no score annotations found")
+	}
+	if result.HasErrors() {
+		return nil, errs.InvalidArgument("fix the annotation errors before applying the evaluation")
+	}
+
+	// Hold the report so the version check and the write are atomic
+	report, unlock, err := lockReportForWrite(ctx, report.ID, req.IfMatch)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	_, err = recordEvaluation(ctx, report, userID, &EvaluateReportRequest{
+		SecurityScore:      proposed.SecurityScore,
+		PerformanceScore:   proposed.PerformanceScore,
+		MemoryScore:        proposed.MemoryScore,
+		TestingScore:       proposed.TestingScore,
+		ErrorScore:         proposed.ErrorScore,
+		LoadScore:          proposed.LoadScore,
+		SecurityDetails:    proposed.SecurityDetails,
+		PerformanceDetails: proposed.PerformanceDetails,
+		MemoryDetails:      proposed.MemoryDetails,
+		TestingDetails:     proposed.TestingDetails,
+		ErrorDetails:       proposed.ErrorDetails,
+		LoadDetails:        proposed.LoadDetails,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.Applied = true
+
+	return response, nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type SearchReportsRequest struct {
+	Query     string  `json:"q" query:"q" validate:"required"`
+	Status    *string `json:"status,omitempty"`
+	ProjectID *string `json:"project_id,omitempty"`
+	Limit     int     `json:"limit,omitempty" default:"20"`
+	Offset    int     `json:"offset,omitempty" default:"0"`
+}
+
+// BE-IN - Internal backend only
+type SearchSnippet struct {
+	Field string `json:"field"`
+	Text  string `json:"text"`
+}
+
+// BE-IN - Internal backend only
+type SearchHit struct {
+	Report   Report          `json:"report"`
+	Score    float64         `json:"score"`
+	Snippets []SearchSnippet `json:"snippets"`
+}
+
+// BE-IN - Internal backend only
+type SearchReportsResponse struct {
+	Hits  []SearchHit `json:"hits"`
+	Total int         `json:"total"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/search/reports
+func SearchReports(ctx context.Context, req *SearchReportsRequest) (*SearchReportsResponse, error) {
+	// Score: [S7,P7,M7,T6,E7,L7]
+	// Details:
+	// - Security (S7): Authentication check, snippets are HTML-escaped
+	// - Performance (P7): Inverted index lookups, pagination
+	// - Memory (M7): Only the requested page is converted
+	// - Testing (T6): Search itself tested in models, the handler only maps fields
+	// - Error (E7): Rejects empty queries
+	// - Load (L7): Concurrent read access to the index
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, errs.InvalidArgument("query is required")
+	}
+
+	// Set defaults
+	limit := 20
+	if req.Limit > 0 && req.Limit <= 100 {
+		limit = req.Limit
+	}
+
+	offset := 0
+	if req.Offset > 0 {
+		offset = req.Offset
+	}
+
+	hits, total, err := models.SearchReports(ctx, models.SearchParams{
+		Query:     req.Query,
+		Status:    req.Status,
+		ProjectID: req.ProjectID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		rlog.Error("failed to search reports", "error", err)
+		return nil, errs.Internal("failed to search reports")
+	}
+
+	// Convert to API response
+	apiHits := make([]SearchHit, len(hits))
+	for i, hit := range hits {
+		snippets := make([]SearchSnippet, len(hit.Snippets))
+		for j, snippet := range hit.Snippets {
+			snippets[j] = SearchSnippet{Field: snippet.Field, Text: snippet.Text}
+		}
+
+		apiHits[i] = SearchHit{
+			Report:   *convertModelToAPIReport(&hits[i].Report, nil),
+			Score:    hit.Score,
+			Snippets: snippets,
+		}
+	}
+
+	return &SearchReportsResponse{
+		Hits:  apiHits,
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,475 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/sarif"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// maxSARIFBytes bounds uploaded scanner results
+const maxSARIFBytes = 32 << 20
+
+// BE-IN - Internal backend only
+// securityPolicy is read once at startup; an invalid configuration falls
+// back to the default policy.
+var securityPolicy = loadSecurityPolicy()
+
+// BE-IN - Internal backend only
+func loadSecurityPolicy() sarif.Policy {
+	policy, err := sarif.PolicyFromEnv()
+	if err != nil {
+		rlog.Error("invalid security score policy, using the default", "error", err)
+	}
+	return policy
+}
+
+// BE-IN - Internal backend only
+type IngestSecurityScanRequest struct {
+	// SARIF is a SARIF 2.1 log or the output of govulncheck -json
+	SARIF string `json:"sarif" validate:"required"`
+	Apply bool   `json:"apply,omitempty"`
+	// IfMatch is the report ETag the client read; applying is refused
+	// when the report or its evaluation changed since
+	IfMatch string `header:"If-Match"`
+}
+
+// BE-IN - Internal backend only
+func (r *IngestSecurityScanRequest) Validate() error {
+	var v ValidationErrors
+	v.required("sarif", r.SARIF)
+	if len(r.SARIF) > maxSARIFBytes {
+		v.add("sarif", "must be at most %d MB", maxSARIFBytes>>20)
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type SecurityFinding struct {
+	ID              string     `json:"id"`
+	ReportID        string     `json:"report_id"`
+	Tool            string     `json:"tool"`
+	RuleID          string     `json:"rule_id"`
+	Severity        string     `json:"severity"`
+	Message         string     `json:"message"`
+	File            string     `json:"file,omitempty"`
+	Line            int        `json:"line,omitempty"`
+	Column          int        `json:"column,omitempty"`
+	HelpURI         string     `json:"help_uri,omitempty"`
+	Status          string     `json:"status"`
+	DismissedBy     string     `json:"dismissed_by,omitempty"`
+	DismissalReason string     `json:"dismissal_reason,omitempty"`
+	FirstSeenAt     time.Time  `json:"first_seen_at"`
+	LastSeenAt      time.Time  `json:"last_seen_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+}
+
+// BE-IN - Internal backend only
+// SecurityScan is the latest upload of a report together with the
+// report's current findings. SuggestedScore and Details reflect the
+// findings that are open now.
+type SecurityScan struct {
+	ID               string             `json:"id"`
+	ReportID         string             `json:"report_id"`
+	UploadedBy       string             `json:"uploaded_by"`
+	Format           string             `json:"format"`
+	Tools            []string           `json:"tools"`
+	Found            int                `json:"found"`
+	New              int                `json:"new"`
+	Fixed            int                `json:"fixed"`
+	Reopened         int                `json:"reopened"`
+	OpenBySeverity   map[string]int     `json:"open_by_severity"`
+	BlocksSubmission bool               `json:"blocks_submission"`
+	SuggestedScore   int                `json:"suggested_score"`
+	Details          string             `json:"details"`
+	Applied          bool               `json:"applied"`
+	Findings         []*SecurityFinding `json:"findings"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+type ListSecurityFindingsRequest struct {
+	Status *string `json:"status,omitempty" validate:"omitempty,oneof=open fixed dismissed"`
+}
+
+// BE-IN - Internal backend only
+func (r *ListSecurityFindingsRequest) Validate() error {
+	var v ValidationErrors
+	if r.Status != nil {
+		switch *r.Status {
+		case models.FindingOpen, models.FindingFixed, models.FindingDismissed:
+		default:
+			v.add("status", "must be one of open, fixed, dismissed")
+		}
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type ListSecurityFindingsResponse struct {
+	Findings []*SecurityFinding `json:"findings"`
+	Total    int                `json:"total"`
+}
+
+// BE-IN - Internal backend only
+type DismissFindingRequest struct {
+	// Reason says why the finding is a false positive or an accepted risk
+	Reason string `json:"reason" validate:"required,min=10"`
+}
+
+// BE-IN - Internal backend only
+func (r *DismissFindingRequest) Validate() error {
+	var v ValidationErrors
+	if v.required("reason", r.Reason) {
+		v.minLength("reason", r.Reason, 10)
+	}
+	return v.err()
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/evaluation/security
+func IngestSecurityScan(ctx context.Context, id string, req *IngestSecurityScanRequest) (*SecurityScan, error) {
+	// Score: [S8,P7,M6,T7,E8,L6]
+	// Details:
+	// - Security (S8): Upload size bounded, severity policy is server-side
+	// - Performance (P7): Single decode and merge by fingerprint
+	// - Memory (M6): Whole upload held in memory while parsing
+	// - Testing (T7): Parsing and scoring live in a pure library
+	// - Error (E8): Unsupported formats rejected with a reason
+	// - Load (L6): Intended for CI runs rather than interactive traffic
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	parsed, err := sarif.Parse(strings.NewReader(req.SARIF))
+	if err != nil {
+		return nil, errs.InvalidArgument(err.Error())
+	}
+
+	found := make([]*models.SecurityFinding, len(parsed.Findings))
+	for i, f := range parsed.Findings {
+		found[i] = &models.SecurityFinding{
+			Tool:        f.Tool,
+			RuleID:      f.RuleID,
+			Severity:    f.Severity,
+			Message:     f.Message,
+			File:        f.File,
+			Line:        f.Line,
+			Column:      f.Column,
+			HelpURI:     f.HelpURI,
+			Fingerprint: f.Fingerprint,
+		}
+	}
+	scan := &models.SecurityScan{
+		ReportID:   report.ID,
+		UploadedBy: userID,
+		Format:     parsed.Format,
+		Tools:      parsed.Tools,
+	}
+	if err := models.MergeSecurityFindings(ctx, scan, found); err != nil {
+		rlog.Error("failed to save security findings", "error", err)
+		return nil, errs.Internal("failed to save security findings")
+	}
+
+	open, err := models.ListSecurityFindings(ctx, report.ID, models.FindingOpen)
+	if err != nil {
+		rlog.Error("failed to list security findings", "error", err)
+		return nil, errs.Internal("failed to list security findings")
+	}
+	scan.SuggestedScore, scan.Details = securityPolicy.Suggest(convertModelToSARIFFindings(open), findingTools(open, scan.Tools))
+
+	if req.Apply {
+		_, err = recordDimension(ctx, report.ID, userID, req.IfMatch, func(e *EvaluateReportRequest) {
+			e.SecurityScore = scan.SuggestedScore
+			e.SecurityDetails = scan.Details
+		})
+		if err != nil {
+			return nil, err
+		}
+		scan.Applied = true
+	}
+
+	rlog.Info("security scan ingested", "report_id", report.ID, "tools", strings.Join(scan.Tools, ","),
+		"found", scan.Found, "new", scan.New, "fixed", scan.Fixed)
+	return securityScanResponse(ctx, report, scan)
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/evaluation/security
+func GetSecurityScan(ctx context.Context, id string) (*SecurityScan, error) {
+	// Score: [S7,P8,M7,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check
+	// - Performance (P8): Lookups by report ID
+	// - Memory (M7): Returns the findings of one report
+	// - Testing (T7): Missing and uploaded scans tested
+	// - Error (E8): Missing uploads reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	scan, err := models.GetSecurityScanByReportID(ctx, report.ID)
+	if err != nil {
+		if err == models.ErrSecurityScanNotFound {
+			return nil, errs.NotFound("no security scan has been uploaded for this report")
+		}
+		rlog.Error("failed to get security scan", "error", err)
+		return nil, errs.Internal("failed to get security scan")
+	}
+	return securityScanResponse(ctx, report, scan)
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/findings
+func ListSecurityFindings(ctx context.Context, id string, req *ListSecurityFindingsRequest) (*ListSecurityFindingsResponse, error) {
+	// Score: [S7,P8,M7,T7,E7,L8]
+	// Details:
+	// - Security (S7): Authentication check, trashed reports not served
+	// - Performance (P8): Single pass over the findings of one report
+	// - Memory (M7): Returns the findings of one report
+	// - Testing (T7): Status filters and trashed reports tested
+	// - Error (E7): Status filter validated
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database; trashed reports are not found
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	status := ""
+	if req.Status != nil {
+		status = *req.Status
+	}
+	findings, err := models.ListSecurityFindings(ctx, id, status)
+	if err != nil {
+		rlog.Error("failed to list security findings", "error", err)
+		return nil, errs.Internal("failed to list security findings")
+	}
+
+	result := make([]*SecurityFinding, len(findings))
+	for i, f := range findings {
+		result[i] = convertModelToAPISecurityFinding(f)
+	}
+	return &ListSecurityFindingsResponse{
+		Findings: result,
+		Total:    len(result),
+	}, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/findings/:id/dismiss
+func DismissFinding(ctx context.Context, id string, req *DismissFindingRequest) (*SecurityFinding, error) {
+	return setFindingStatus(ctx, id, models.FindingDismissed, req.Reason)
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/findings/:id/reopen
+func ReopenFinding(ctx context.Context, id string) (*SecurityFinding, error) {
+	return setFindingStatus(ctx, id, models.FindingOpen, "")
+}
+
+// BE-IN - Internal backend only
+func setFindingStatus(ctx context.Context, id, status, reason string) (*SecurityFinding, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Only the department head decides, dismissals record who and why
+	// - Performance (P8): Single lookup and write
+	// - Memory (M8): Updates in place
+	// - Testing (T7): Permissions and each transition tested
+	// - Error (E8): Rejects transitions that do not apply
+	// - Load (L8): Lightweight state change
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	finding, err := models.GetSecurityFindingByID(ctx, id)
+	if err != nil {
+		if err == models.ErrFindingNotFound {
+			return nil, errs.NotFound("finding not found")
+		}
+		rlog.Error("failed to get security finding", "error", err)
+		return nil, errs.Internal("failed to get security finding")
+	}
+
+	report, err := models.GetReportByID(ctx, finding.ReportID)
+	if err != nil {
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	// Only the head of the report's department may accept a finding
+	department, err := models.GetDepartmentByID(ctx, report.DepartmentID)
+	if err != nil || department.HeadID != userID {
+		return nil, errs.Permission("only the department head can dismiss or reopen findings")
+	}
+
+	switch {
+	case status == models.FindingDismissed && finding.Status != models.FindingOpen:
+		return nil, errs.InvalidArgument("only open findings can be dismissed")
+	case status == models.FindingOpen && finding.Status != models.FindingDismissed:
+		return nil, errs.InvalidArgument("only dismissed findings can be reopened")
+	}
+
+	if err := models.SetFindingStatus(ctx, finding, status, userID, reason); err != nil {
+		rlog.Error("failed to update security finding", "error", err)
+		return nil, errs.Internal("failed to update security finding")
+	}
+	rlog.Info("security finding status changed", "finding_id", finding.ID, "report_id", report.ID, "status", status, "user_id", userID)
+
+	return convertModelToAPISecurityFinding(finding), nil
+}
+
+// BE-IN - Internal backend only
+// checkCriticalFindings refuses submission to departments that block
+// reports with open critical findings.
+func checkCriticalFindings(ctx context.Context, report *models.Report) error {
+	department, err := models.GetDepartmentByID(ctx, report.DepartmentID)
+	if err != nil || !department.BlockCriticalFindings {
+		return nil
+	}
+	if n := models.CountOpenFindings(ctx, report.ID, sarif.Critical); n > 0 {
+		return errs.InvalidArgument(fmt.Sprintf("the %s department does not accept reports with open critical security findings; %d must be fixed or dismissed first", department.Name, n))
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func securityScanResponse(ctx context.Context, report *models.Report, model *models.SecurityScan) (*SecurityScan, error) {
+	findings, err := models.ListSecurityFindings(ctx, report.ID, "")
+	if err != nil {
+		rlog.Error("failed to list security findings", "error", err)
+		return nil, errs.Internal("failed to list security findings")
+	}
+
+	scan := &SecurityScan{
+		ID:             model.ID,
+		ReportID:       model.ReportID,
+		UploadedBy:     model.UploadedBy,
+		Format:         model.Format,
+		Tools:          model.Tools,
+		Found:          model.Found,
+		New:            model.New,
+		Fixed:          model.Fixed,
+		Reopened:       model.Reopened,
+		OpenBySeverity: make(map[string]int),
+		Applied:        model.Applied,
+		Findings:       make([]*SecurityFinding, len(findings)),
+		CreatedAt:      model.CreatedAt,
+	}
+	var open []*models.SecurityFinding
+	for i, f := range findings {
+		scan.Findings[i] = convertModelToAPISecurityFinding(f)
+		if f.Status == models.FindingOpen {
+			open = append(open, f)
+			scan.OpenBySeverity[f.Severity]++
+		}
+	}
+	scan.SuggestedScore, scan.Details = securityPolicy.Suggest(convertModelToSARIFFindings(open), findingTools(open, model.Tools))
+	scan.BlocksSubmission = checkCriticalFindings(ctx, report) != nil
+	return scan, nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToSARIFFindings(findings []*models.SecurityFinding) []*sarif.Finding {
+	result := make([]*sarif.Finding, len(findings))
+	for i, f := range findings {
+		result[i] = &sarif.Finding{
+			Tool:     f.Tool,
+			RuleID:   f.RuleID,
+			Severity: f.Severity,
+			Message:  f.Message,
+			File:     f.File,
+			Line:     f.Line,
+		}
+	}
+	return result
+}
+
+// BE-IN - Internal backend only
+// findingTools names the tools of the latest scan and any tool that
+// still has open findings from an earlier one.
+func findingTools(open []*models.SecurityFinding, latest []string) []string {
+	tools := append([]string(nil), latest...)
+	for _, f := range open {
+		known := false
+		for _, t := range tools {
+			known = known || t == f.Tool
+		}
+		if !known {
+			tools = append(tools, f.Tool)
+		}
+	}
+	return tools
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPISecurityFinding(model *models.SecurityFinding) *SecurityFinding {
+	return &SecurityFinding{
+		ID:              model.ID,
+		ReportID:        model.ReportID,
+		Tool:            model.Tool,
+		RuleID:          model.RuleID,
+		Severity:        model.Severity,
+		Message:         model.Message,
+		File:            model.File,
+		Line:            model.Line,
+		Column:          model.Column,
+		HelpURI:         model.HelpURI,
+		Status:          model.Status,
+		DismissedBy:     model.DismissedBy,
+		DismissalReason: model.DismissalReason,
+		FirstSeenAt:     model.FirstSeenAt,
+		LastSeenAt:      model.LastSeenAt,
+		ResolvedAt:      model.ResolvedAt,
+	}
+}
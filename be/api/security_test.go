@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestSecurityFindingReview(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	if _, err := GetSecurityScan(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "no security scan has been uploaded") {
+		t.Errorf("GetSecurityScan before an upload = %v, want not found", err)
+	}
+
+	log, err := os.ReadFile("../services/sarif/testdata/gosec.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploaded, err := IngestSecurityScan(ctx, report.ID, &IngestSecurityScanRequest{SARIF: string(log)})
+	if err != nil {
+		t.Fatalf("IngestSecurityScan: %v", err)
+	}
+	scan, err := GetSecurityScan(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetSecurityScan: %v", err)
+	}
+	if scan.ID != uploaded.ID || len(scan.Findings) == 0 {
+		t.Fatalf("GetSecurityScan = run %s with %d findings, want run %s with findings", scan.ID, len(scan.Findings), uploaded.ID)
+	}
+
+	count := func(status string) int {
+		t.Helper()
+		resp, err := ListSecurityFindings(ctx, report.ID, &ListSecurityFindingsRequest{Status: &status})
+		if err != nil {
+			t.Fatalf("ListSecurityFindings: %v", err)
+		}
+		return resp.Total
+	}
+	open := count(models.FindingOpen)
+	if open != len(scan.Findings) {
+		t.Errorf("%d open findings, want all %d", open, len(scan.Findings))
+	}
+	unknown := "ignored"
+	if err := (&ListSecurityFindingsRequest{Status: &unknown}).Validate(); err == nil {
+		t.Error("Validate accepted an unknown status")
+	}
+
+	// Only the head of dept-456 decides on findings
+	finding := scan.Findings[0].ID
+	reason := &DismissFindingRequest{Reason: "Test fixture, not a real credential"}
+	if _, err := DismissFinding(ctx, finding, reason); err == nil || !strings.Contains(err.Error(), "only the department head") {
+		t.Errorf("dismissal by the author = %v, want permission denied", err)
+	}
+	t.Setenv("DEMO_USER_ID", "user-789")
+	if _, err := ReopenFinding(ctx, finding); err == nil || !strings.Contains(err.Error(), "only dismissed findings") {
+		t.Errorf("reopening an open finding = %v, want rejected", err)
+	}
+	dismissed, err := DismissFinding(ctx, finding, reason)
+	if err != nil {
+		t.Fatalf("DismissFinding: %v", err)
+	}
+	if dismissed.Status != models.FindingDismissed || dismissed.DismissedBy != "user-789" || dismissed.DismissalReason != reason.Reason {
+		t.Errorf("dismissed finding = %+v, want dismissed by user-789 with the reason", dismissed)
+	}
+	if _, err := DismissFinding(ctx, finding, reason); err == nil || !strings.Contains(err.Error(), "only open findings") {
+		t.Errorf("dismissing twice = %v, want rejected", err)
+	}
+	if got := count(models.FindingOpen); got != open-1 {
+		t.Errorf("%d open findings after a dismissal, want %d", got, open-1)
+	}
+	if got := count(models.FindingDismissed); got != 1 {
+		t.Errorf("%d dismissed findings, want 1", got)
+	}
+
+	reopened, err := ReopenFinding(ctx, finding)
+	if err != nil {
+		t.Fatalf("ReopenFinding: %v", err)
+	}
+	if reopened.Status != models.FindingOpen {
+		t.Errorf("Status = %q, want open", reopened.Status)
+	}
+	if _, err := ReopenFinding(ctx, "finding-missing"); err == nil || !strings.Contains(err.Error(), "finding not found") {
+		t.Errorf("reopening a missing finding = %v, want not found", err)
+	}
+}
@@ -0,0 +1,472 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/signing"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// submissionKeys signs submissions. It is read once at startup; without
+// configured keys an ephemeral key is used. Invalid keys leave the ring
+// empty, so submissions fail rather than get signed with a throwaway key.
+var submissionKeys = loadSubmissionKeys()
+
+// BE-IN - Internal backend only
+func loadSubmissionKeys() *signing.KeyRing {
+	keys, ephemeral, err := signing.KeyRingFromEnv()
+	if err != nil {
+		rlog.Error("invalid submission signing keys, submissions will fail until they are fixed", "error", err)
+		return &signing.KeyRing{}
+	}
+	if ephemeral {
+		rlog.Warn("SUBMISSION_SIGNING_KEYS is not set, signing submissions with an ephemeral key")
+	}
+	return keys
+}
+
+// BE-IN - Internal backend only
+type SigningKey struct {
+	ID        string `json:"id"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+	Active    bool   `json:"active"`
+}
+
+// BE-IN - Internal backend only
+type ListSigningKeysResponse struct {
+	Keys []*SigningKey `json:"keys"`
+}
+
+// BE-IN - Internal backend only
+type RegisterAuthorKeyRequest struct {
+	// PublicKey is a base64 Ed25519 public key
+	PublicKey string `json:"public_key" validate:"required"`
+}
+
+// BE-IN - Internal backend only
+func (r *RegisterAuthorKeyRequest) Validate() error {
+	var v ValidationErrors
+	if v.required("public_key", r.PublicKey) {
+		if _, err := signing.ParsePublicKey(r.PublicKey); err != nil {
+			v.add("public_key", "must be a base64 Ed25519 public key")
+		}
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type AuthorKey struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Algorithm string    `json:"algorithm"`
+	PublicKey string    `json:"public_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+type Receipt struct {
+	ID              string          `json:"id,omitempty"`
+	ReportID        string          `json:"report_id,omitempty"`
+	Algorithm       string          `json:"algorithm"`
+	Snapshot        json.RawMessage `json:"snapshot"`
+	Digest          string          `json:"digest"`
+	KeyID           string          `json:"key_id"`
+	Signature       string          `json:"signature"`
+	SignedAt        time.Time       `json:"signed_at"`
+	AuthorKeyID     string          `json:"author_key_id,omitempty"`
+	AuthorSignature string          `json:"author_signature,omitempty"`
+	CosignedAt      *time.Time      `json:"cosigned_at,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type CosignReceiptRequest struct {
+	// Signature is the base64 Ed25519 signature of
+	// "report-submission-v1\n<digest>" made with the author's current key
+	Signature string `json:"signature" validate:"required"`
+}
+
+// BE-IN - Internal backend only
+func (r *CosignReceiptRequest) Validate() error {
+	var v ValidationErrors
+	v.required("signature", r.Signature)
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type VerifyReceiptRequest struct {
+	Receipt *Receipt `json:"receipt" validate:"required"`
+}
+
+// BE-IN - Internal backend only
+func (r *VerifyReceiptRequest) Validate() error {
+	var v ValidationErrors
+	if r.Receipt == nil {
+		v.add("receipt", "is required")
+	} else if len(r.Receipt.Snapshot) == 0 {
+		v.add("receipt.snapshot", "is required")
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type VerifyReceiptResponse struct {
+	signing.Result
+	ReportID string `json:"report_id,omitempty"`
+	// Current reports whether the snapshot still matches the stored
+	// report; it is omitted when the report no longer exists
+	Current *bool `json:"current,omitempty"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/signing/keys
+func ListSigningKeys(ctx context.Context) (*ListSigningKeysResponse, error) {
+	// Score: [S8,P9,M9,T7,E8,L9]
+	// Details:
+	// - Security (S8): Public keys only, so anyone can verify receipts
+	// - Performance (P9): Served from memory
+	// - Memory (M9): A handful of keys
+	// - Testing (T7): Key ring is a pure library
+	// - Error (E8): Cannot fail
+	// - Load (L9): Read-only, cacheable
+	// Tags: BE-OUT-low
+
+	keys := submissionKeys.Keys()
+	response := &ListSigningKeysResponse{Keys: make([]*SigningKey, len(keys))}
+	for i, key := range keys {
+		response.Keys[i] = &SigningKey{
+			ID:        key.ID,
+			Algorithm: signing.Algorithm,
+			PublicKey: base64.StdEncoding.EncodeToString(key.Public),
+			Active:    key.Active,
+		}
+	}
+	return response, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=PUT path=/api/signing/author-key
+func RegisterAuthorKey(ctx context.Context, req *RegisterAuthorKeyRequest) (*AuthorKey, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Keys are bound to the authenticated user, previous keys kept for old co-signatures
+	// - Performance (P8): Single write
+	// - Memory (M8): One key per registration
+	// - Testing (T7): Key parsing lives in the signing library
+	// - Error (E8): Malformed keys rejected in validation
+	// - Load (L8): Rare writes
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	public, err := signing.ParsePublicKey(req.PublicKey)
+	if err != nil {
+		return nil, errs.InvalidArgument(err.Error())
+	}
+	key := &models.AuthorKey{
+		ID:        signing.KeyID(public),
+		UserID:    userID,
+		PublicKey: base64.StdEncoding.EncodeToString(public),
+	}
+	if existing, err := models.GetAuthorKeyByID(ctx, key.ID); err == nil && existing.UserID != userID {
+		return nil, errs.InvalidArgument("key is registered to another user")
+	}
+	if err := models.SaveAuthorKey(ctx, key); err != nil {
+		rlog.Error("failed to save author key", "error", err)
+		return nil, errs.Internal("failed to save author key")
+	}
+	return convertModelToAPIAuthorKey(key), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/signing/author-keys/:id
+func GetAuthorKey(ctx context.Context, id string) (*AuthorKey, error) {
+	// Score: [S8,P9,M9,T7,E8,L9]
+	// Details:
+	// - Security (S8): Public keys only, so co-signatures can be verified offline
+	// - Performance (P9): Single lookup by key ID
+	// - Memory (M9): Returns one key
+	// - Testing (T7): Unknown and registered keys tested
+	// - Error (E8): Unknown keys reported as not found
+	// - Load (L9): Read-only
+	// Tags: BE-OUT-low
+
+	key, err := models.GetAuthorKeyByID(ctx, id)
+	if err != nil {
+		if err == models.ErrAuthorKeyNotFound {
+			return nil, errs.NotFound("author key not found")
+		}
+		rlog.Error("failed to get author key", "error", err)
+		return nil, errs.Internal("failed to get author key")
+	}
+	return convertModelToAPIAuthorKey(key), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/reports/:id/receipt
+func GetReceipt(ctx context.Context, id string) (*Receipt, error) {
+	// Score: [S7,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S7): Authentication check, trashed reports not served
+	// - Performance (P8): Single lookup by report ID
+	// - Memory (M8): Returns the latest receipt only
+	// - Testing (T7): Unsubmitted, signed and co-signed receipts tested
+	// - Error (E8): Unsubmitted reports reported as not found
+	// - Load (L8): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database; trashed reports are not found
+	if _, err := models.GetReportByID(ctx, id); err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	receipt, err := models.GetLatestReceipt(ctx, id)
+	if err != nil {
+		if err == models.ErrReceiptNotFound {
+			return nil, errs.NotFound("report has not been submitted")
+		}
+		rlog.Error("failed to get receipt", "error", err)
+		return nil, errs.Internal("failed to get receipt")
+	}
+	return convertModelToAPIReceipt(receipt), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/receipt/cosign
+func CosignReceipt(ctx context.Context, id string, req *CosignReceiptRequest) (*Receipt, error) {
+	// Score: [S9,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S9): Author only, signature checked against the author's registered key before storing
+	// - Performance (P8): One signature check and write
+	// - Memory (M8): Updates the receipt in place
+	// - Testing (T7): Verification lives in the signing library
+	// - Error (E8): Missing keys, bad signatures and repeat co-signing reported
+	// - Load (L8): One call per submission
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+	if report.AuthorID != userID {
+		return nil, errs.Permission("only the author can co-sign the report")
+	}
+
+	receipt, err := models.GetLatestReceipt(ctx, report.ID)
+	if err != nil {
+		if err == models.ErrReceiptNotFound {
+			return nil, errs.InvalidArgument("report has not been submitted")
+		}
+		rlog.Error("failed to get receipt", "error", err)
+		return nil, errs.Internal("failed to get receipt")
+	}
+
+	key, err := models.GetCurrentAuthorKey(ctx, userID)
+	if err != nil {
+		if err == models.ErrAuthorKeyNotFound {
+			return nil, errs.InvalidArgument("register a signing key with PUT /api/signing/author-key first")
+		}
+		rlog.Error("failed to get author key", "error", err)
+		return nil, errs.Internal("failed to get author key")
+	}
+	public, err := signing.ParsePublicKey(key.PublicKey)
+	if err != nil {
+		rlog.Error("stored author key is invalid", "key_id", key.ID, "error", err)
+		return nil, errs.Internal("failed to get author key")
+	}
+	if !signing.VerifySignature(public, receipt.Digest, req.Signature) {
+		return nil, errs.InvalidArgument("signature does not verify against your key " + key.ID)
+	}
+
+	cosigned, err := models.CosignReceipt(ctx, report.ID, receipt.ID, key.ID, req.Signature)
+	if err != nil {
+		if err == models.ErrAlreadyCosigned {
+			return nil, errs.InvalidArgument("receipt is already co-signed")
+		}
+		rlog.Error("failed to co-sign receipt", "error", err)
+		return nil, errs.Internal("failed to co-sign receipt")
+	}
+	return convertModelToAPIReceipt(cosigned), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/receipts/verify
+func VerifyReceipt(ctx context.Context, req *VerifyReceiptRequest) (*VerifyReceiptResponse, error) {
+	// Score: [S9,P8,M8,T8,E8,L8]
+	// Details:
+	// - Security (S9): Digest recomputed from the snapshot, keys looked up by ID
+	// - Performance (P8): One hash and up to two signature checks
+	// - Memory (M8): Snapshot held once
+	// - Testing (T8): Verification is a pure library shared with the CLI
+	// - Error (E8): Every failed check named in the response
+	// - Load (L8): No writes
+	// Tags: BE-module-medium
+
+	receipt := &signing.Receipt{
+		Algorithm:       req.Receipt.Algorithm,
+		Snapshot:        req.Receipt.Snapshot,
+		Digest:          req.Receipt.Digest,
+		KeyID:           req.Receipt.KeyID,
+		Signature:       req.Receipt.Signature,
+		AuthorKeyID:     req.Receipt.AuthorKeyID,
+		AuthorSignature: req.Receipt.AuthorSignature,
+	}
+
+	var authorKey ed25519.PublicKey
+	if receipt.AuthorKeyID != "" {
+		if key, err := models.GetAuthorKeyByID(ctx, receipt.AuthorKeyID); err == nil {
+			authorKey, _ = signing.ParsePublicKey(key.PublicKey)
+		}
+	}
+	response := &VerifyReceiptResponse{Result: *signing.Verify(receipt, submissionKeys.PublicKeys(), authorKey)}
+
+	// Compare with the stored report, so changes made after submission
+	// are visible even when the receipt itself is intact
+	var snapshot signing.Snapshot
+	if err := json.Unmarshal(receipt.Snapshot, &snapshot); err != nil || snapshot.ReportID == "" {
+		return response, nil
+	}
+	response.ReportID = snapshot.ReportID
+	report, err := models.GetReportByID(ctx, snapshot.ReportID)
+	if err != nil {
+		return response, nil
+	}
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		rlog.Error("failed to get evaluation", "report_id", report.ID, "error", err)
+		return nil, errs.Internal("failed to get evaluation")
+	}
+	current := false
+	signedDigest, signedErr := signing.Digest(receipt.Snapshot)
+	if encoded, err := json.Marshal(snapshotFromReport(report, evaluation)); err == nil && signedErr == nil {
+		storedDigest, err := signing.Digest(encoded)
+		current = err == nil && storedDigest == signedDigest
+	}
+	response.Current = &current
+	if !current {
+		response.Problems = append(response.Problems, "the stored report differs from the signed snapshot")
+	}
+	return response, nil
+}
+
+// BE-IN - Internal backend only
+// signSubmission signs the snapshot of a report being submitted and
+// stores the receipt.
+func signSubmission(ctx context.Context, report *models.Report, evaluation *models.Evaluation) (*models.SubmissionReceipt, error) {
+	signed, err := submissionKeys.SignSnapshot(snapshotFromReport(report, evaluation), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	receipt := &models.SubmissionReceipt{
+		ReportID:    report.ID,
+		SubmittedAt: *report.SubmittedAt,
+		Algorithm:   signed.Algorithm,
+		Snapshot:    signed.Snapshot,
+		Digest:      signed.Digest,
+		KeyID:       signed.KeyID,
+		Signature:   signed.Signature,
+		SignedAt:    signed.SignedAt,
+	}
+	if err := models.SaveSubmissionReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// BE-IN - Internal backend only
+// snapshotFromReport is the content a submission signature covers.
+func snapshotFromReport(report *models.Report, evaluation *models.Evaluation) *signing.Snapshot {
+	snapshot := &signing.Snapshot{
+		ReportID:     report.ID,
+		Title:        report.Title,
+		Description:  report.Description,
+		ProjectID:    report.ProjectID,
+		AuthorID:     report.AuthorID,
+		DepartmentID: report.DepartmentID,
+		Metadata:     report.Metadata,
+	}
+	if report.SubmittedAt != nil {
+		snapshot.SubmittedAt = report.SubmittedAt.UTC()
+	}
+	for _, tag := range report.Tags {
+		snapshot.Tags = append(snapshot.Tags, tag.String())
+	}
+	if evaluation != nil {
+		snapshot.Evaluation = &signing.SnapshotEvaluation{
+			SecurityScore:      evaluation.SecurityScore,
+			PerformanceScore:   evaluation.PerformanceScore,
+			MemoryScore:        evaluation.MemoryScore,
+			TestingScore:       evaluation.TestingScore,
+			ErrorScore:         evaluation.ErrorScore,
+			LoadScore:          evaluation.LoadScore,
+			SecurityDetails:    evaluation.SecurityDetails,
+			PerformanceDetails: evaluation.PerformanceDetails,
+			MemoryDetails:      evaluation.MemoryDetails,
+			TestingDetails:     evaluation.TestingDetails,
+			ErrorDetails:       evaluation.ErrorDetails,
+			LoadDetails:        evaluation.LoadDetails,
+			EvaluatorID:        evaluation.EvaluatorID,
+		}
+	}
+	return snapshot
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIReceipt(model *models.SubmissionReceipt) *Receipt {
+	return &Receipt{
+		ID:              model.ID,
+		ReportID:        model.ReportID,
+		Algorithm:       model.Algorithm,
+		Snapshot:        model.Snapshot,
+		Digest:          model.Digest,
+		KeyID:           model.KeyID,
+		Signature:       model.Signature,
+		SignedAt:        model.SignedAt,
+		AuthorKeyID:     model.AuthorKeyID,
+		AuthorSignature: model.AuthorSignature,
+		CosignedAt:      model.CosignedAt,
+	}
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIAuthorKey(model *models.AuthorKey) *AuthorKey {
+	return &AuthorKey{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Algorithm: signing.Algorithm,
+		PublicKey: model.PublicKey,
+		CreatedAt: model.CreatedAt,
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"encore.app/services/signing"
+)
+
+func TestLoadSubmissionKeys(t *testing.T) {
+	t.Setenv("SUBMISSION_SIGNING_KEYS", "")
+	if keys := loadSubmissionKeys(); len(keys.Keys()) != 1 {
+		t.Errorf("unset keys gave %d keys, want one ephemeral key", len(keys.Keys()))
+	}
+
+	// A broken production key must not be swapped for a throwaway one
+	t.Setenv("SUBMISSION_SIGNING_KEYS", "not base64")
+	keys := loadSubmissionKeys()
+	if len(keys.Keys()) != 0 {
+		t.Fatalf("invalid keys gave %d keys, want an empty ring", len(keys.Keys()))
+	}
+	if _, err := keys.SignSnapshot(&signing.Snapshot{ReportID: "report-123"}, time.Now()); !errors.Is(err, signing.ErrNoSigningKey) {
+		t.Errorf("SignSnapshot with an empty ring = %v, want ErrNoSigningKey", err)
+	}
+}
+
+func TestSubmissionReceipt(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	if _, err := GetReceipt(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "has not been submitted") {
+		t.Errorf("GetReceipt on a draft = %v, want not found", err)
+	}
+	if _, err := submitReport(ctx, report.ID); err != nil {
+		t.Fatalf("submitReport: %v", err)
+	}
+	receipt, err := GetReceipt(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetReceipt: %v", err)
+	}
+	verified, err := VerifyReceipt(ctx, &VerifyReceiptRequest{Receipt: receipt})
+	if err != nil {
+		t.Fatalf("VerifyReceipt: %v", err)
+	}
+	if !verified.Valid || verified.Current == nil || !*verified.Current || verified.ReportID != report.ID {
+		t.Errorf("VerifyReceipt = %+v, want a valid receipt of the current report", verified)
+	}
+
+	if _, err := GetAuthorKey(ctx, "ed25519:missing"); err == nil || !strings.Contains(err.Error(), "author key not found") {
+		t.Errorf("GetAuthorKey for an unknown key = %v, want not found", err)
+	}
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registered, err := RegisterAuthorKey(ctx, &RegisterAuthorKeyRequest{PublicKey: base64.StdEncoding.EncodeToString(public)})
+	if err != nil {
+		t.Fatalf("RegisterAuthorKey: %v", err)
+	}
+	key, err := GetAuthorKey(ctx, registered.ID)
+	if err != nil || key.UserID != "user-123" || key.PublicKey != registered.PublicKey {
+		t.Errorf("GetAuthorKey = %+v, %v, want user-123's key", key, err)
+	}
+
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, signing.Message(receipt.Digest)))
+	if _, err := CosignReceipt(ctx, report.ID, &CosignReceiptRequest{Signature: signature}); err != nil {
+		t.Fatalf("CosignReceipt: %v", err)
+	}
+	cosigned, err := GetReceipt(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("GetReceipt: %v", err)
+	}
+	if cosigned.ID != receipt.ID || cosigned.AuthorKeyID != registered.ID || cosigned.CosignedAt == nil {
+		t.Errorf("co-signed receipt = %+v, want receipt %s signed with %s", cosigned, receipt.ID, registered.ID)
+	}
+	verified, err = VerifyReceipt(ctx, &VerifyReceiptRequest{Receipt: cosigned})
+	if err != nil {
+		t.Fatalf("VerifyReceipt: %v", err)
+	}
+	if !verified.Valid || !verified.AuthorValid {
+		t.Errorf("VerifyReceipt = %+v, want valid server and author signatures", verified)
+	}
+}
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"encore.app/events"
+	"encore.app/models"
+	"encore.app/services/notifications"
+	"encore.dev"
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// Department delivery is retried with backoff; after the last attempt the
+// author and department head are told delivery failed.
+const deliveryMaxRetries = 5
+
+// BE-OUT - External data involved
+// Department delivery
+var _ = pubsub.NewSubscription(events.ReportSubmittedTopic, "deliver-to-department", pubsub.SubscriptionConfig[*events.ReportSubmitted]{
+	Handler: deliverToDepartment,
+	RetryPolicy: &pubsub.RetryPolicy{
+		MinBackoff: 10 * time.Second,
+		MaxBackoff: 10 * time.Minute,
+		MaxRetries: deliveryMaxRetries,
+	},
+})
+
+// BE-OUT - External data involved
+func deliverToDepartment(ctx context.Context, event *events.ReportSubmitted) error {
+	// Score: [S8,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S8): Delivers the stored report, not the event payload
+	// - Performance (P7): Runs off the request path
+	// - Memory (M7): Loads one report and evaluation
+	// - Testing (T7): Handler callable directly with a constructed event
+	// - Error (E8): Retried with backoff, failure notified after the last attempt
+	// - Load (L7): Throughput controlled by the subscription
+	// Tags: BE-OUT-high
+
+	report, err := models.GetReportByID(ctx, event.ReportID)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			rlog.Warn("submitted report no longer exists, skipping delivery", "report_id", event.ReportID)
+			return nil
+		}
+		return err
+	}
+
+	// The report may have been decided or resubmitted since the event was
+	// published; only deliver the submission the event refers to
+	if report.Status != "submitted" || report.SubmittedAt == nil || !report.SubmittedAt.Equal(event.SubmittedAt) {
+		return nil
+	}
+
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		return err
+	}
+
+	attempt := deliveryAttempt(ctx)
+	if err := submitToDepartment(ctx, report, convertModelToAPIEvaluation(evaluation)); err != nil {
+		rlog.Error("failed to submit to department", "report_id", report.ID, "attempt", attempt, "error", err)
+		if attempt > deliveryMaxRetries {
+			notifyReportEvent(ctx, notifications.KindDeliveryFailed, report, evaluation, report.AuthorID, err.Error())
+		}
+		return fmt.Errorf("deliver report %s: %w", report.ID, err)
+	}
+
+	_, err = events.SubmissionDeliveredTopic.Publish(ctx, &events.SubmissionDelivered{
+		ReportID:     report.ID,
+		ProjectID:    report.ProjectID,
+		DepartmentID: report.DepartmentID,
+		Attempt:      attempt,
+		DeliveredAt:  time.Now(),
+	})
+	if err != nil {
+		rlog.Error("failed to publish event", "event", events.NameSubmissionDelivered, "report_id", report.ID, "error", err)
+	}
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+// deliveryAttempt returns the delivery attempt of the message being
+// handled, starting at 1.
+func deliveryAttempt(ctx context.Context) int {
+	if message := encore.CurrentRequest().Message; message != nil && message.DeliveryAttempt > 0 {
+		return message.DeliveryAttempt
+	}
+	return 1
+}
+
+// BE-OUT - External data involved
+// Notifications
+var (
+	_ = pubsub.NewSubscription(events.ReportSubmittedTopic, "notify-report-submitted", pubsub.SubscriptionConfig[*events.ReportSubmitted]{
+		Handler: notifyReportSubmitted,
+	})
+
+	_ = pubsub.NewSubscription(events.EvaluationRecordedTopic, "notify-evaluation-recorded", pubsub.SubscriptionConfig[*events.EvaluationRecorded]{
+		Handler: notifyEvaluationRecorded,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportApprovedTopic, "notify-report-approved", pubsub.SubscriptionConfig[*events.ReportApproved]{
+		Handler: notifyReportApproved,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportRejectedTopic, "notify-report-rejected", pubsub.SubscriptionConfig[*events.ReportRejected]{
+		Handler: notifyReportRejected,
+	})
+
+	_ = pubsub.NewSubscription(events.CommentCreatedTopic, "notify-comment-mentions", pubsub.SubscriptionConfig[*events.CommentCreated]{
+		Handler: notifyCommentCreated,
+	})
+)
+
+// BE-OUT - External data involved
+func notifyReportSubmitted(ctx context.Context, event *events.ReportSubmitted) error {
+	return notifyFromEvent(ctx, notifications.KindReportSubmitted, event.ReportID, event.AuthorID, "")
+}
+
+// BE-OUT - External data involved
+func notifyEvaluationRecorded(ctx context.Context, event *events.EvaluationRecorded) error {
+	return notifyFromEvent(ctx, notifications.KindReportEvaluated, event.ReportID, event.EvaluatorID, "")
+}
+
+// BE-OUT - External data involved
+func notifyReportApproved(ctx context.Context, event *events.ReportApproved) error {
+	return notifyFromEvent(ctx, notifications.KindReportApproved, event.ReportID, event.ReviewerID, event.Reason)
+}
+
+// BE-OUT - External data involved
+func notifyReportRejected(ctx context.Context, event *events.ReportRejected) error {
+	return notifyFromEvent(ctx, notifications.KindReportRejected, event.ReportID, event.ReviewerID, event.Reason)
+}
+
+// BE-OUT - External data involved
+func notifyCommentCreated(ctx context.Context, event *events.CommentCreated) error {
+	comment, err := models.GetCommentByID(ctx, event.CommentID)
+	if err != nil {
+		if err == models.ErrCommentNotFound {
+			return nil
+		}
+		return err
+	}
+	report, err := models.GetReportByID(ctx, comment.ReportID)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil
+		}
+		return err
+	}
+
+	notifyMentions(ctx, report, comment)
+	return nil
+}
+
+// BE-IN - Internal backend only
+func notifyFromEvent(ctx context.Context, kind, reportID, actorID, reason string) error {
+	report, err := models.GetReportByID(ctx, reportID)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil
+		}
+		return err
+	}
+
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		return err
+	}
+
+	notifyReportEvent(ctx, kind, report, evaluation, actorID, reason)
+	return nil
+}
+
+// BE-IN - Internal backend only
+// Analytics
+var (
+	_ = pubsub.NewSubscription(events.ReportCreatedTopic, "analytics-report-created", pubsub.SubscriptionConfig[*events.ReportCreated]{
+		Handler: recordReportCreated,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportSubmittedTopic, "analytics-report-submitted", pubsub.SubscriptionConfig[*events.ReportSubmitted]{
+		Handler: recordReportSubmitted,
+	})
+
+	_ = pubsub.NewSubscription(events.EvaluationRecordedTopic, "analytics-evaluation-recorded", pubsub.SubscriptionConfig[*events.EvaluationRecorded]{
+		Handler: recordEvaluationRecorded,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportApprovedTopic, "analytics-report-approved", pubsub.SubscriptionConfig[*events.ReportApproved]{
+		Handler: recordReportApproved,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportRejectedTopic, "analytics-report-rejected", pubsub.SubscriptionConfig[*events.ReportRejected]{
+		Handler: recordReportRejected,
+	})
+
+	_ = pubsub.NewSubscription(events.SubmissionDeliveredTopic, "analytics-submission-delivered", pubsub.SubscriptionConfig[*events.SubmissionDelivered]{
+		Handler: recordSubmissionDelivered,
+	})
+)
+
+// BE-IN - Internal backend only
+func recordReportCreated(ctx context.Context, event *events.ReportCreated) error {
+	return recordActivity(ctx, models.ActivityEvent{
+		Name:         events.NameReportCreated,
+		ReportID:     event.ReportID,
+		DepartmentID: event.DepartmentID,
+		At:           event.CreatedAt,
+	})
+}
+
+// BE-IN - Internal backend only
+func recordReportSubmitted(ctx context.Context, event *events.ReportSubmitted) error {
+	return recordActivity(ctx, models.ActivityEvent{
+		Name:         events.NameReportSubmitted,
+		ReportID:     event.ReportID,
+		DepartmentID: event.DepartmentID,
+		At:           event.SubmittedAt,
+		StartsReview: true,
+	})
+}
+
+// BE-IN - Internal backend only
+func recordEvaluationRecorded(ctx context.Context, event *events.EvaluationRecorded) error {
+	return recordActivity(ctx, models.ActivityEvent{
+		Name:         events.NameEvaluationRecorded,
+		ReportID:     event.ReportID,
+		DepartmentID: event.DepartmentID,
+		At:           event.RecordedAt,
+	})
+}
+
+// BE-IN - Internal backend only
+func recordReportApproved(ctx context.Context, event *events.ReportApproved) error {
+	return recordActivity(ctx, models.ActivityEvent{
+		Name:         events.NameReportApproved,
+		ReportID:     event.ReportID,
+		DepartmentID: event.DepartmentID,
+		At:           event.ApprovedAt,
+		EndsReview:   true,
+	})
+}
+
+// BE-IN - Internal backend only
+func recordReportRejected(ctx context.Context, event *events.ReportRejected) error {
+	return recordActivity(ctx, models.ActivityEvent{
+		Name:         events.NameReportRejected,
+		ReportID:     event.ReportID,
+		DepartmentID: event.DepartmentID,
+		At:           event.RejectedAt,
+		EndsReview:   true,
+	})
+}
+
+// BE-IN - Internal backend only
+func recordSubmissionDelivered(ctx context.Context, event *events.SubmissionDelivered) error {
+	return recordActivity(ctx, models.ActivityEvent{
+		Name:         events.NameSubmissionDelivered,
+		ReportID:     event.ReportID,
+		DepartmentID: event.DepartmentID,
+		At:           event.DeliveredAt,
+	})
+}
+
+// BE-IN - Internal backend only
+// recordActivity keys the event by name, report and time so a redelivered
+// message is counted once.
+func recordActivity(ctx context.Context, event models.ActivityEvent) error {
+	event.Key = event.Name + "/" + event.ReportID + "/" + event.At.UTC().Format(time.RFC3339Nano)
+	return models.RecordActivity(ctx, event)
+}
+
+// BE-IN - Internal backend only
+// Benchmark baselines
+var (
+	_ = pubsub.NewSubscription(events.ReportApprovedTopic, "benchmark-baseline", pubsub.SubscriptionConfig[*events.ReportApproved]{
+		Handler: promoteBenchmarkBaseline,
+	})
+)
+
+// BE-OUT - External data involved
+// Webhooks
+var (
+	_ = pubsub.NewSubscription(events.ReportCreatedTopic, "webhooks-report-created", pubsub.SubscriptionConfig[*events.ReportCreated]{
+		Handler: dispatchReportCreated,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportSubmittedTopic, "webhooks-report-submitted", pubsub.SubscriptionConfig[*events.ReportSubmitted]{
+		Handler: dispatchReportSubmitted,
+	})
+
+	_ = pubsub.NewSubscription(events.EvaluationRecordedTopic, "webhooks-evaluation-recorded", pubsub.SubscriptionConfig[*events.EvaluationRecorded]{
+		Handler: dispatchEvaluationRecorded,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportApprovedTopic, "webhooks-report-approved", pubsub.SubscriptionConfig[*events.ReportApproved]{
+		Handler: dispatchReportApproved,
+	})
+
+	_ = pubsub.NewSubscription(events.ReportRejectedTopic, "webhooks-report-rejected", pubsub.SubscriptionConfig[*events.ReportRejected]{
+		Handler: dispatchReportRejected,
+	})
+
+	_ = pubsub.NewSubscription(events.SubmissionDeliveredTopic, "webhooks-submission-delivered", pubsub.SubscriptionConfig[*events.SubmissionDelivered]{
+		Handler: dispatchSubmissionDelivered,
+	})
+
+	// Receivers get up to a day of retries with exponential backoff
+	_ = pubsub.NewSubscription(webhookDeliveryTopic, "deliver-webhook", pubsub.SubscriptionConfig[*WebhookDeliveryMessage]{
+		Handler: handleWebhookDelivery,
+		RetryPolicy: &pubsub.RetryPolicy{
+			MinBackoff: 30 * time.Second,
+			MaxBackoff: 4 * time.Hour,
+			MaxRetries: 10,
+		},
+	})
+)
+
+// BE-OUT - External data involved
+func dispatchReportCreated(ctx context.Context, event *events.ReportCreated) error {
+	return dispatchWebhooks(ctx, events.NameReportCreated, event.ProjectID, event.DepartmentID, event)
+}
+
+// BE-OUT - External data involved
+func dispatchReportSubmitted(ctx context.Context, event *events.ReportSubmitted) error {
+	return dispatchWebhooks(ctx, events.NameReportSubmitted, event.ProjectID, event.DepartmentID, event)
+}
+
+// BE-OUT - External data involved
+func dispatchEvaluationRecorded(ctx context.Context, event *events.EvaluationRecorded) error {
+	return dispatchWebhooks(ctx, events.NameEvaluationRecorded, event.ProjectID, event.DepartmentID, event)
+}
+
+// BE-OUT - External data involved
+func dispatchReportApproved(ctx context.Context, event *events.ReportApproved) error {
+	return dispatchWebhooks(ctx, events.NameReportApproved, event.ProjectID, event.DepartmentID, event)
+}
+
+// BE-OUT - External data involved
+func dispatchReportRejected(ctx context.Context, event *events.ReportRejected) error {
+	return dispatchWebhooks(ctx, events.NameReportRejected, event.ProjectID, event.DepartmentID, event)
+}
+
+// BE-OUT - External data involved
+func dispatchSubmissionDelivered(ctx context.Context, event *events.SubmissionDelivered) error {
+	return dispatchWebhooks(ctx, events.NameSubmissionDelivered, event.ProjectID, event.DepartmentID, event)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type TagAnalyticsRequest struct {
+	Type    *string `json:"type,omitempty"`
+	Scope   *string `json:"scope,omitempty"`
+	Impact  *string `json:"impact,omitempty"`
+	GroupBy string  `json:"group_by,omitempty" validate:"omitempty,oneof=tag type scope impact"`
+}
+
+// BE-IN - Internal backend only
+type TagScores struct {
+	Key              string  `json:"key"`
+	Reports          int     `json:"reports"`
+	Evaluated        int     `json:"evaluated"`
+	SecurityScore    float64 `json:"security_score"`
+	PerformanceScore float64 `json:"performance_score"`
+	MemoryScore      float64 `json:"memory_score"`
+	TestingScore     float64 `json:"testing_score"`
+	ErrorScore       float64 `json:"error_score"`
+	LoadScore        float64 `json:"load_score"`
+	OverallScore     float64 `json:"overall_score"`
+}
+
+// BE-IN - Internal backend only
+type TagAnalyticsResponse struct {
+	GroupBy string       `json:"group_by"`
+	Groups  []*TagScores `json:"groups"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/analytics/tags
+func GetTagAnalytics(ctx context.Context, req *TagAnalyticsRequest) (*TagAnalyticsResponse, error) {
+	// Score: [S7,P6,M7,T6,E8,L6]
+	// Details:
+	// - Security (S7): Authentication check, aggregate scores only
+	// - Performance (P6): Scans every report per request
+	// - Memory (M7): One accumulator per group
+	// - Testing (T6): Parameter checks tested here, the aggregation in models
+	// - Error (E8): Invalid filter components and groupings rejected
+	// - Load (L6): Intended for dashboards
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	filter, err := tagFilter(req.Type, req.Scope, req.Impact)
+	if err != nil {
+		return nil, err
+	}
+
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = models.TagGroupTag
+	}
+	switch groupBy {
+	case models.TagGroupTag, models.TagGroupType, models.TagGroupScope, models.TagGroupImpact:
+	default:
+		return nil, errs.InvalidArgument("group_by must be one of tag, type, scope, impact")
+	}
+
+	groups, err := models.ScoresByTag(ctx, filter, groupBy)
+	if err != nil {
+		rlog.Error("failed to aggregate scores by tag", "error", err)
+		return nil, errs.Internal("failed to get tag analytics")
+	}
+
+	response := &TagAnalyticsResponse{GroupBy: groupBy, Groups: make([]*TagScores, len(groups))}
+	for i, g := range groups {
+		response.Groups[i] = &TagScores{
+			Key:              g.Key,
+			Reports:          g.Reports,
+			Evaluated:        g.Evaluated,
+			SecurityScore:    g.SecurityScore,
+			PerformanceScore: g.PerformanceScore,
+			MemoryScore:      g.MemoryScore,
+			TestingScore:     g.TestingScore,
+			ErrorScore:       g.ErrorScore,
+			LoadScore:        g.LoadScore,
+			OverallScore:     g.OverallScore,
+		}
+	}
+
+	return response, nil
+}
+
+// BE-IN - Internal backend only
+// tagFilter builds a filter from optional type, scope and impact query
+// parameters, rejecting values outside the taxonomy.
+func tagFilter(typ, scope, impact *string) (models.TagFilter, error) {
+	var filter models.TagFilter
+	var v ValidationErrors
+	check := func(field string, value *string, allowed []string, target *string) {
+		if value == nil || *value == "" {
+			return
+		}
+		for _, a := range allowed {
+			if strings.EqualFold(a, *value) {
+				*target = a
+				return
+			}
+		}
+		v.add(field, "must be one of %s", strings.Join(allowed, ", "))
+	}
+	check("tag_type", typ, models.TagTypes, &filter.Type)
+	check("tag_scope", scope, models.TagScopes, &filter.Scope)
+	check("tag_impact", impact, models.TagImpacts, &filter.Impact)
+	return filter, v.err()
+}
+
+// BE-IN - Internal backend only
+func tagStrings(tags []models.Tag) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	result := make([]string, len(tags))
+	for i, tag := range tags {
+		result[i] = tag.String()
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestReportTags(t *testing.T) {
+	ctx := context.Background()
+
+	// Import builds requests without Validate, so createReport checks tags itself
+	bad := &CreateReportRequest{Title: "Tagged report", Description: "Report carrying an invalid tag", ProjectID: "project-456", DepartmentID: "dept-456", Tags: []string{"BE-OUT-high", "BE-CLOUD-high"}}
+	if _, err := createReport(ctx, "user-123", bad); err == nil || !strings.Contains(err.Error(), "tags:") {
+		t.Errorf("createReport with an invalid tag = %v, want rejected", err)
+	}
+
+	created, err := createReport(ctx, "user-123", &CreateReportRequest{
+		Title: "Tagged report", Description: "Report carrying tags to edit", ProjectID: "project-456", DepartmentID: "dept-456",
+		Tags: []string{"be-out-HIGH"},
+	})
+	if err != nil {
+		t.Fatalf("createReport: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteReport(ctx, created.ID) })
+
+	stored := func() string {
+		t.Helper()
+		report, err := models.GetReportByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("GetReportByID: %v", err)
+		}
+		return strings.Join(tagStrings(report.Tags), ",")
+	}
+	if got := stored(); got != "BE-OUT-high" {
+		t.Errorf("tags after create = %q, want BE-OUT-high", got)
+	}
+
+	title := "Tagged report, renamed"
+	tests := []struct {
+		name    string
+		req     *UpdateReportRequest
+		want    string
+		wantErr string
+	}{
+		{"other fields keep tags", &UpdateReportRequest{Title: &title}, "BE-OUT-high", ""},
+		{"replace", &UpdateReportRequest{Tags: &[]string{"fe-in-low", "BE-DB-medium"}}, "FE-IN-low,BE-DB-medium", ""},
+		{"invalid", &UpdateReportRequest{Title: &title, Tags: &[]string{"FE-IN-urgent"}}, "FE-IN-low,BE-DB-medium", "tags:"},
+		{"remove", &UpdateReportRequest{Tags: &[]string{}}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UpdateReport(ctx, created.ID, tt.req)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("UpdateReport: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("UpdateReport = %v, want %q", err, tt.wantErr)
+			}
+			if got := stored(); got != tt.want {
+				t.Errorf("tags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	invalid := &UpdateReportRequest{Tags: &[]string{"nope"}}
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "tags: ") {
+		t.Errorf("Validate = %v, want a tags field error", err)
+	}
+}
+
+func TestGetTagAnalyticsRejectsBadParameters(t *testing.T) {
+	ctx := context.Background()
+	cloud, frontend := "CLOUD", "fe"
+
+	if _, err := GetTagAnalytics(ctx, &TagAnalyticsRequest{Scope: &cloud}); err == nil || !strings.Contains(err.Error(), "tag_scope: must be one of") {
+		t.Errorf("unknown scope = %v, want a tag_scope field error", err)
+	}
+	if _, err := GetTagAnalytics(ctx, &TagAnalyticsRequest{GroupBy: "author"}); err == nil || !strings.Contains(err.Error(), "group_by") {
+		t.Errorf("unknown grouping = %v, want rejected", err)
+	}
+	resp, err := GetTagAnalytics(ctx, &TagAnalyticsRequest{Type: &frontend, GroupBy: "scope"})
+	if err != nil {
+		t.Fatalf("GetTagAnalytics: %v", err)
+	}
+	if resp.GroupBy != "scope" {
+		t.Errorf("GroupBy = %q, want scope", resp.GroupBy)
+	}
+}
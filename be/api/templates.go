@@ -0,0 +1,287 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type TemplateSection struct {
+	Heading  string `json:"heading" validate:"required"`
+	Prompt   string `json:"prompt,omitempty"`
+	Required bool   `json:"required"`
+}
+
+// BE-IN - Internal backend only
+type EvaluationPrompts struct {
+	SecurityDetails    string `json:"security_details,omitempty"`
+	PerformanceDetails string `json:"performance_details,omitempty"`
+	MemoryDetails      string `json:"memory_details,omitempty"`
+	TestingDetails     string `json:"testing_details,omitempty"`
+	ErrorDetails       string `json:"error_details,omitempty"`
+	LoadDetails        string `json:"load_details,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type Template struct {
+	ID                string                 `json:"id"`
+	Name              string                 `json:"name"`
+	OwnerType         string                 `json:"owner_type"`
+	OwnerID           string                 `json:"owner_id"`
+	Sections          []TemplateSection      `json:"sections"`
+	DefaultMetadata   map[string]interface{} `json:"default_metadata,omitempty"`
+	EvaluationPrompts EvaluationPrompts      `json:"evaluation_prompts"`
+	CreatedBy         string                 `json:"created_by"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+type CreateTemplateRequest struct {
+	Name              string                 `json:"name" validate:"required,min=3"`
+	OwnerType         string                 `json:"owner_type" validate:"required,oneof=department project"`
+	OwnerID           string                 `json:"owner_id" validate:"required"`
+	Sections          []TemplateSection      `json:"sections" validate:"required,min=1"`
+	DefaultMetadata   map[string]interface{} `json:"default_metadata,omitempty"`
+	EvaluationPrompts *EvaluationPrompts     `json:"evaluation_prompts,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type ListTemplatesRequest struct {
+	OwnerType *string `json:"owner_type,omitempty"`
+	OwnerID   *string `json:"owner_id,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type ListTemplatesResponse struct {
+	Templates []Template `json:"templates"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/templates
+func CreateTemplate(ctx context.Context, req *CreateTemplateRequest) (*Template, error) {
+	return idempotent(ctx, req, func() (*Template, error) { return createTemplate(ctx, req) })
+}
+
+// BE-IN - Internal backend only
+func createTemplate(ctx context.Context, req *CreateTemplateRequest) (*Template, error) {
+	// Score: [S7,P8,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Authentication check, owner type validation
+	// - Performance (P8): Single in-memory write
+	// - Memory (M7): Copies sections once into the model
+	// - Testing (T7): Owner and section validation tested, as is instantiating a report from the template
+	// - Error (E8): Validation of owner type and sections
+	// - Load (L7): Low write volume, admin-style endpoint
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Validate owner
+	switch req.OwnerType {
+	case models.TemplateOwnerDepartment:
+		if _, err := models.GetDepartmentByID(ctx, req.OwnerID); err != nil {
+			return nil, errs.InvalidArgument("department not found")
+		}
+	case models.TemplateOwnerProject:
+	default:
+		return nil, errs.InvalidArgument("owner_type must be department or project")
+	}
+
+	sections := make([]models.TemplateSection, len(req.Sections))
+	for i, section := range req.Sections {
+		if section.Heading == "" {
+			return nil, errs.InvalidArgument("section heading is required")
+		}
+		sections[i] = models.TemplateSection{
+			Heading:  section.Heading,
+			Prompt:   section.Prompt,
+			Required: section.Required,
+		}
+	}
+
+	template := &models.Template{
+		Name:            req.Name,
+		OwnerType:       req.OwnerType,
+		OwnerID:         req.OwnerID,
+		Sections:        sections,
+		DefaultMetadata: req.DefaultMetadata,
+		CreatedBy:       userID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	if req.EvaluationPrompts != nil {
+		template.EvaluationPrompts = models.EvaluationPrompts(*req.EvaluationPrompts)
+	}
+
+	// Save to database
+	err = models.SaveTemplate(ctx, template)
+	if err != nil {
+		rlog.Error("failed to save template", "error", err)
+		return nil, errs.Internal("failed to save template")
+	}
+
+	return convertModelToAPITemplate(template), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/templates
+func ListTemplates(ctx context.Context, req *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	// Score: [S7,P8,M7,T4,E7,L8]
+	// Details:
+	// - Security (S7): Authentication check
+	// - Performance (P8): In-memory filtering of a small collection
+	// - Memory (M7): Result slice sized to the filtered set
+	// - Testing (T4): No tests, a thin wrapper over the model query
+	// - Error (E7): Proper error handling for DB queries
+	// - Load (L8): Read-only, cache friendly
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	templates, err := models.ListTemplates(ctx, models.ListTemplatesParams{
+		OwnerType: req.OwnerType,
+		OwnerID:   req.OwnerID,
+	})
+	if err != nil {
+		rlog.Error("failed to list templates", "error", err)
+		return nil, errs.Internal("failed to list templates")
+	}
+
+	apiTemplates := make([]Template, len(templates))
+	for i := range templates {
+		apiTemplates[i] = *convertModelToAPITemplate(&templates[i])
+	}
+
+	return &ListTemplatesResponse{Templates: apiTemplates}, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/templates/:id
+func GetTemplate(ctx context.Context, id string) (*Template, error) {
+	// Validate user is authenticated
+	_, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	template, err := models.GetTemplateByID(ctx, id)
+	if err != nil {
+		if err == models.ErrTemplateNotFound {
+			return nil, errs.NotFound("template not found")
+		}
+		rlog.Error("failed to get template", "error", err)
+		return nil, errs.Internal("failed to get template")
+	}
+
+	return convertModelToAPITemplate(template), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=DELETE path=/api/templates/:id
+func DeleteTemplate(ctx context.Context, id string) error {
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return errs.Unauthenticated("user must be authenticated")
+	}
+
+	template, err := models.GetTemplateByID(ctx, id)
+	if err != nil {
+		if err == models.ErrTemplateNotFound {
+			return errs.NotFound("template not found")
+		}
+		rlog.Error("failed to get template", "error", err)
+		return errs.Internal("failed to get template")
+	}
+
+	// Check if user created the template
+	if template.CreatedBy != userID {
+		return errs.Permission("only the creator can delete the template")
+	}
+
+	err = models.DeleteTemplate(ctx, id)
+	if err != nil {
+		rlog.Error("failed to delete template", "error", err)
+		return errs.Internal("failed to delete template")
+	}
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+func templateAppliesTo(template *models.Template, projectID, departmentID string) bool {
+	switch template.OwnerType {
+	case models.TemplateOwnerDepartment:
+		return template.OwnerID == departmentID
+	case models.TemplateOwnerProject:
+		return template.OwnerID == projectID
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+// applyTemplate fills a new draft from a template. Metadata supplied in the
+// request wins over the template defaults. Detail prompts are kept on the
+// draft as hints rather than written into the evaluation, so they survive
+// drafts created without one and never end up in a scored evaluation.
+func applyTemplate(template *models.Template, report *models.Report) {
+	report.TemplateID = template.ID
+	report.Description = template.RenderDescription(report.Description)
+	report.Metadata = templateMetadata(template, report.Metadata)
+
+	if template.EvaluationPrompts != (models.EvaluationPrompts{}) {
+		prompts := template.EvaluationPrompts
+		report.EvaluationPrompts = &prompts
+	}
+}
+
+// BE-IN - Internal backend only
+// templateMetadata merges request metadata over the template defaults.
+func templateMetadata(template *models.Template, requested map[string]interface{}) map[string]interface{} {
+	metadata := make(map[string]interface{}, len(template.DefaultMetadata)+len(requested))
+	for key, value := range template.DefaultMetadata {
+		metadata[key] = value
+	}
+	for key, value := range requested {
+		metadata[key] = value
+	}
+	return metadata
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPITemplate(model *models.Template) *Template {
+	sections := make([]TemplateSection, len(model.Sections))
+	for i, section := range model.Sections {
+		sections[i] = TemplateSection{
+			Heading:  section.Heading,
+			Prompt:   section.Prompt,
+			Required: section.Required,
+		}
+	}
+
+	return &Template{
+		ID:                model.ID,
+		Name:              model.Name,
+		OwnerType:         model.OwnerType,
+		OwnerID:           model.OwnerID,
+		Sections:          sections,
+		DefaultMetadata:   model.DefaultMetadata,
+		EvaluationPrompts: EvaluationPrompts(model.EvaluationPrompts),
+		CreatedBy:         model.CreatedBy,
+		CreatedAt:         model.CreatedAt,
+		UpdatedAt:         model.UpdatedAt,
+	}
+}
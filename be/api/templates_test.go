@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestCreateTemplateValidatesOwnerAndSections(t *testing.T) {
+	ctx := context.Background()
+	sections := []TemplateSection{{Heading: "Summary", Required: true}}
+
+	tests := []struct {
+		name string
+		req  CreateTemplateRequest
+		want string
+	}{
+		{"unknown owner type", CreateTemplateRequest{Name: "Weekly", OwnerType: "team", OwnerID: "team-1", Sections: sections}, "owner_type must be department or project"},
+		{"unknown department", CreateTemplateRequest{Name: "Weekly", OwnerType: models.TemplateOwnerDepartment, OwnerID: "dept-missing", Sections: sections}, "department not found"},
+		{"empty heading", CreateTemplateRequest{Name: "Weekly", OwnerType: models.TemplateOwnerProject, OwnerID: "project-456", Sections: []TemplateSection{{Prompt: "Anything"}}}, "section heading is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := createTemplate(ctx, &tt.req); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("createTemplate error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateReportFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	template, err := createTemplate(ctx, &CreateTemplateRequest{
+		Name:      "Release review",
+		OwnerType: models.TemplateOwnerProject,
+		OwnerID:   "project-456",
+		Sections: []TemplateSection{
+			{Heading: "Summary", Prompt: "What changed?", Required: true},
+			{Heading: "Risks", Prompt: "What could break?"},
+		},
+		DefaultMetadata:   map[string]interface{}{"component": "api", "priority": "low"},
+		EvaluationPrompts: &EvaluationPrompts{SecurityDetails: "Mention new endpoints"},
+	})
+	if err != nil {
+		t.Fatalf("createTemplate: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteTemplate(ctx, template.ID) })
+
+	// Templates only apply to their own project or department
+	other := &CreateReportRequest{Title: "Other project", ProjectID: "project-123", DepartmentID: "dept-456", TemplateID: &template.ID}
+	if _, err := createReport(ctx, "user-123", other); err == nil || !strings.Contains(err.Error(), "does not belong") {
+		t.Errorf("createReport for another project error = %v, want template rejected", err)
+	}
+
+	report, err := createReport(ctx, "user-123", &CreateReportRequest{
+		Title:        "Release 1.4",
+		Description:  "Ships the new cache.",
+		ProjectID:    "project-456",
+		DepartmentID: "dept-456",
+		Metadata:     map[string]interface{}{"priority": "high"},
+		TemplateID:   &template.ID,
+	})
+	if err != nil {
+		t.Fatalf("createReport: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteReport(ctx, report.ID) })
+
+	wantDescription := "Ships the new cache.\n\n## Summary\n\nWhat changed?\n\n## Risks\n\nWhat could break?"
+	if report.Description != wantDescription {
+		t.Errorf("Description = %q, want %q", report.Description, wantDescription)
+	}
+	if report.Metadata["component"] != "api" || report.Metadata["priority"] != "high" {
+		t.Errorf("Metadata = %v, want template defaults with the request's priority", report.Metadata)
+	}
+	if report.TemplateID != template.ID {
+		t.Errorf("TemplateID = %q, want %q", report.TemplateID, template.ID)
+	}
+	if report.EvaluationPrompts == nil || report.EvaluationPrompts.SecurityDetails != "Mention new endpoints" {
+		t.Errorf("EvaluationPrompts = %+v, want the template's prompts", report.EvaluationPrompts)
+	}
+
+	// The untouched prompt of a required section blocks submission
+	if _, err := submitReport(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "missing required sections: Summary") {
+		t.Errorf("submitReport error = %v, want the Summary section reported missing", err)
+	}
+}
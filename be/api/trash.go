@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"encore.app/models"
+	"encore.dev/beta/errs"
+	"encore.dev/cron"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// defaultTrashRetention is how long deleted reports can be restored
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// BE-IN - Internal backend only
+// trashRetention is set by TRASH_RETENTION
+var trashRetention = durationFromEnv("TRASH_RETENTION", defaultTrashRetention)
+
+// BE-IN - Internal backend only
+// Purge reports past the retention period every hour
+var _ = cron.NewJob("purge-trash", cron.JobConfig{
+	Title:    "Purge deleted reports past the trash retention period",
+	Every:    cron.Hour,
+	Endpoint: PurgeTrash,
+})
+
+// BE-IN - Internal backend only
+type TrashedReport struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	ProjectID    string    `json:"project_id"`
+	DepartmentID string    `json:"department_id"`
+	Status       string    `json:"status"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	DeletedBy    string    `json:"deleted_by"`
+	// PurgeAt is when the report is removed for good
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// BE-IN - Internal backend only
+type ListTrashResponse struct {
+	Reports []*TrashedReport `json:"reports"`
+	Total   int              `json:"total"`
+}
+
+// BE-OUT - External data involved
+// DeleteReport moves a report to the trash, from which its author can
+// restore it until the retention period ends.
+//
+//encore:api public method=DELETE path=/api/reports/:id
+func DeleteReport(ctx context.Context, id string) (*TrashedReport, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Author only, reports under or after review are kept
+	// - Performance (P8): Marks the report and evaluation in place
+	// - Memory (M8): Nothing copied
+	// - Testing (T7): Author and status checks and the purge date tested
+	// - Error (E8): Status and ownership errors surfaced
+	// - Load (L8): Low write volume
+	// Tags: BE-module-high
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	// Hold the report while its status is checked
+	unlock := models.LockReport(ctx, id)
+	defer unlock()
+
+	// Get report from database
+	report, err := models.GetReportByID(ctx, id)
+	if err != nil {
+		if err == models.ErrReportNotFound {
+			return nil, errs.NotFound("report not found")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	// Check if user is the author
+	if report.AuthorID != userID {
+		return nil, errs.Permission("only the author can delete the report")
+	}
+
+	// Submitted and approved reports are part of the department's record
+	if report.Status != "draft" && report.Status != "rejected" {
+		return nil, errs.InvalidArgument("only draft and rejected reports can be deleted")
+	}
+	if err := checkNotArchived(report); err != nil {
+		return nil, err
+	}
+	if models.IsUnderLegalHold(ctx, id) {
+		return nil, errs.FailedPrecondition("report is under legal hold")
+	}
+
+	deleted, err := models.SoftDeleteReport(ctx, id, userID)
+	if err != nil {
+		rlog.Error("failed to delete report", "report_id", id, "error", err)
+		return nil, errs.Internal("failed to delete report")
+	}
+	auditReport(ctx, models.AuditReportDeleted, userID, deleted, nil)
+
+	return convertModelToAPITrashedReport(deleted), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/trash/reports
+func ListTrash(ctx context.Context) (*ListTrashResponse, error) {
+	// Score: [S7,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Lists the caller's own reports only
+	// - Performance (P7): Full scan of reports
+	// - Memory (M7): Returns a summary per report
+	// - Testing (T7): Per-author listing tested
+	// - Error (E8): Proper error handling
+	// - Load (L7): Read-only
+	// Tags: BE-module-low
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	deleted, err := models.ListDeletedReports(ctx, userID)
+	if err != nil {
+		rlog.Error("failed to list deleted reports", "error", err)
+		return nil, errs.Internal("failed to list deleted reports")
+	}
+
+	response := &ListTrashResponse{Reports: make([]*TrashedReport, len(deleted)), Total: len(deleted)}
+	for i := range deleted {
+		response.Reports[i] = convertModelToAPITrashedReport(&deleted[i])
+	}
+	return response, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/reports/:id/restore
+func RestoreReport(ctx context.Context, id string) (*Report, error) {
+	// Score: [S8,P8,M8,T7,E8,L8]
+	// Details:
+	// - Security (S8): Author only
+	// - Performance (P8): Clears the markers in place
+	// - Memory (M8): Nothing copied
+	// - Testing (T7): Author check, repeat and purged restores tested
+	// - Error (E8): Reports not in the trash reported as such
+	// - Load (L8): Low write volume
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	unlock := models.LockReport(ctx, id)
+	defer unlock()
+
+	report, err := models.GetDeletedReportByID(ctx, id)
+	if err != nil {
+		switch err {
+		case models.ErrReportNotFound:
+			return nil, errs.NotFound("report not found")
+		case models.ErrReportNotDeleted:
+			return nil, errs.FailedPrecondition("report is not in the trash")
+		}
+		rlog.Error("failed to get report", "error", err)
+		return nil, errs.Internal("failed to get report")
+	}
+
+	// Check if user is the author
+	if report.AuthorID != userID {
+		return nil, errs.Permission("only the author can restore the report")
+	}
+
+	report, err = models.RestoreReport(ctx, id)
+	if err != nil {
+		rlog.Error("failed to restore report", "report_id", id, "error", err)
+		return nil, errs.Internal("failed to restore report")
+	}
+	auditReport(ctx, models.AuditReportRestored, userID, report, nil)
+
+	evaluation, err := models.GetEvaluationByReportID(ctx, report.ID)
+	if err != nil && err != models.ErrEvaluationNotFound {
+		rlog.Error("failed to get evaluation", "report_id", report.ID, "error", err)
+		// Continue even if evaluation retrieval fails
+	}
+
+	return convertModelToAPIReport(report, convertModelToAPIEvaluation(evaluation)), nil
+}
+
+// BE-IN - Internal backend only
+// PurgeTrash permanently removes reports deleted longer ago than the
+// retention period, except those under legal hold. It runs from the
+// purge-trash cron job.
+//
+//encore:api private
+func PurgeTrash(ctx context.Context) error {
+	// Score: [S8,P6,M7,T7,E7,L7]
+	// Details:
+	// - Security (S8): Private endpoint, only reports past retention
+	// - Performance (P6): Full scan of reports once an hour
+	// - Memory (M7): Holds the purged IDs only
+	// - Testing (T7): Reports within and past retention tested
+	// - Error (E7): Failures logged and returned to the cron runner
+	// - Load (L7): Runs off the request path
+	// Tags: BE-DB-medium
+
+	purged, err := models.PurgeDeletedReports(ctx, time.Now().Add(-trashRetention))
+	if err != nil {
+		rlog.Error("failed to purge deleted reports", "error", err)
+		return errs.Internal("failed to purge deleted reports")
+	}
+	ids := make([]string, len(purged))
+	for i := range purged {
+		ids[i] = purged[i].ID
+		auditReport(ctx, models.AuditReportPurged, models.AuditActorSystem, &purged[i], map[string]string{"reason": "trash"})
+	}
+	if len(purged) > 0 {
+		rlog.Info("purged deleted reports", "count", len(purged), "report_ids", ids)
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPITrashedReport(model *models.Report) *TrashedReport {
+	trashed := &TrashedReport{
+		ID:           model.ID,
+		Title:        model.Title,
+		ProjectID:    model.ProjectID,
+		DepartmentID: model.DepartmentID,
+		Status:       model.Status,
+		DeletedBy:    model.DeletedBy,
+	}
+	if model.DeletedAt != nil {
+		trashed.DeletedAt = *model.DeletedAt
+		trashed.PurgeAt = model.DeletedAt.Add(trashRetention)
+	}
+	return trashed
+}
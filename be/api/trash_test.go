@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encore.app/models"
+)
+
+func TestTrashedReportHidesAttachments(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	if _, err := IngestCoverage(ctx, report.ID, &IngestCoverageRequest{Profile: "mode: set\nencore.app/api/reports.go:10.2,12.3 2 1\n"}); err != nil {
+		t.Fatalf("IngestCoverage: %v", err)
+	}
+	comment, err := CreateComment(ctx, report.ID, &CreateCommentRequest{Body: "Please add load tests"})
+	if err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+
+	if _, err := DeleteReport(ctx, report.ID); err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+
+	reads := map[string]func() error{
+		"GetCoverage":   func() error { _, err := GetCoverage(ctx, report.ID); return err },
+		"GetBenchmarks": func() error { _, err := GetBenchmarks(ctx, report.ID); return err },
+		"GetLoadTest":   func() error { _, err := GetLoadTest(ctx, report.ID); return err },
+		"GetAnalysis":   func() error { _, err := GetAnalysis(ctx, report.ID); return err },
+		"GetReceipt":    func() error { _, err := GetReceipt(ctx, report.ID); return err },
+		"ListSecurityFindings": func() error {
+			_, err := ListSecurityFindings(ctx, report.ID, &ListSecurityFindingsRequest{})
+			return err
+		},
+		"ListComments": func() error { _, err := ListComments(ctx, report.ID, &ListCommentsRequest{}); return err },
+	}
+	for name, read := range reads {
+		if err := read(); err == nil || !strings.Contains(err.Error(), "report not found") {
+			t.Errorf("%s on a trashed report = %v, want not found", name, err)
+		}
+	}
+	if _, err := ResolveComment(ctx, comment.ID); err == nil || !strings.Contains(err.Error(), "comment not found") {
+		t.Errorf("ResolveComment on a trashed report = %v, want not found", err)
+	}
+
+	// Restoring brings everything back
+	if _, err := RestoreReport(ctx, report.ID); err != nil {
+		t.Fatalf("RestoreReport: %v", err)
+	}
+	if _, err := GetCoverage(ctx, report.ID); err != nil {
+		t.Errorf("GetCoverage after restore: %v", err)
+	}
+	if _, err := ResolveComment(ctx, comment.ID); err != nil {
+		t.Errorf("ResolveComment after restore: %v", err)
+	}
+}
+
+func TestTrashLifecycle(t *testing.T) {
+	ctx := context.Background()
+	report := draftReport(t)
+
+	// trashed lists the IDs in the caller's trash
+	trashed := func() []string {
+		t.Helper()
+		resp, err := ListTrash(ctx)
+		if err != nil {
+			t.Fatalf("ListTrash: %v", err)
+		}
+		var ids []string
+		for _, r := range resp.Reports {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+	contains := func(ids []string, id string) bool {
+		for _, x := range ids {
+			if x == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	submitted := &models.Report{Title: "Under review", ProjectID: "project-456", AuthorID: "user-123", DepartmentID: "dept-456", Status: "submitted"}
+	if err := models.SaveReport(ctx, submitted); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	t.Cleanup(func() { models.DeleteReport(ctx, submitted.ID) })
+	if _, err := DeleteReport(ctx, submitted.ID); err == nil || !strings.Contains(err.Error(), "only draft and rejected") {
+		t.Errorf("deleting a submitted report = %v, want rejected", err)
+	}
+	t.Setenv("DEMO_USER_ID", "user-456")
+	if _, err := DeleteReport(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "only the author") {
+		t.Errorf("deletion by another user = %v, want permission denied", err)
+	}
+
+	t.Setenv("DEMO_USER_ID", "")
+	deleted, err := DeleteReport(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+	if deleted.DeletedBy != "user-123" || !deleted.PurgeAt.Equal(deleted.DeletedAt.Add(trashRetention)) {
+		t.Errorf("trashed report = %+v, want deleted by user-123 and purged after the retention period", deleted)
+	}
+	if !contains(trashed(), report.ID) {
+		t.Errorf("trash does not list %s", report.ID)
+	}
+
+	t.Setenv("DEMO_USER_ID", "user-456")
+	if contains(trashed(), report.ID) {
+		t.Errorf("another user's trash lists %s", report.ID)
+	}
+	if _, err := RestoreReport(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "only the author") {
+		t.Errorf("restore by another user = %v, want permission denied", err)
+	}
+
+	t.Setenv("DEMO_USER_ID", "")
+	if _, err := RestoreReport(ctx, report.ID); err != nil {
+		t.Fatalf("RestoreReport: %v", err)
+	}
+	if _, err := RestoreReport(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "not in the trash") {
+		t.Errorf("restoring twice = %v, want rejected", err)
+	}
+
+	// Reports still within the retention period survive a purge
+	if _, err := DeleteReport(ctx, report.ID); err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+	if err := PurgeTrash(ctx); err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if _, err := models.GetReportByIDIncludingDeleted(ctx, report.ID); err != nil {
+		t.Errorf("purge removed a report within retention: %v", err)
+	}
+
+	saved := trashRetention
+	trashRetention = 0
+	t.Cleanup(func() { trashRetention = saved })
+	if err := PurgeTrash(ctx); err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if _, err := models.GetReportByIDIncludingDeleted(ctx, report.ID); err != models.ErrReportNotFound {
+		t.Errorf("report past retention = %v, want purged", err)
+	}
+	if _, err := RestoreReport(ctx, report.ID); err == nil || !strings.Contains(err.Error(), "report not found") {
+		t.Errorf("restoring a purged report = %v, want not found", err)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"encore.app/models"
+)
+
+// BE-IN - Internal backend only
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// BE-IN - Internal backend only
+// ValidationErrors collects every failed rule instead of stopping at the
+// first one, so clients can fix all fields in one round trip.
+type ValidationErrors []FieldError
+
+// BE-IN - Internal backend only
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, e := range v {
+		messages[i] = e.Field + ": " + e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// BE-IN - Internal backend only
+func (v *ValidationErrors) add(field, format string, args ...interface{}) {
+	*v = append(*v, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// BE-IN - Internal backend only
+func (v ValidationErrors) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// BE-IN - Internal backend only
+func (v *ValidationErrors) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// BE-IN - Internal backend only
+func (v *ValidationErrors) minLength(field, value string, min int) {
+	if utf8.RuneCountInString(value) < min {
+		v.add(field, "must be at least %d characters", min)
+	}
+}
+
+// BE-IN - Internal backend only
+// department checks that the department exists. Projects have no
+// directory of their own, so project IDs are only required.
+func (v *ValidationErrors) department(field, id string) {
+	if _, err := models.GetDepartmentByID(context.Background(), id); err != nil {
+		v.add(field, "department %q not found", id)
+	}
+}
+
+// BE-IN - Internal backend only
+func (v *ValidationErrors) score(field string, value int) {
+	if value < 0 || value > 10 {
+		v.add(field, "must be between 0 and 10")
+	}
+}
+
+// BE-IN - Internal backend only
+func (v *ValidationErrors) evaluation(evaluation *Evaluation) {
+	if evaluation == nil {
+		return
+	}
+	v.score("evaluation.security_score", evaluation.SecurityScore)
+	v.score("evaluation.performance_score", evaluation.PerformanceScore)
+	v.score("evaluation.memory_score", evaluation.MemoryScore)
+	v.score("evaluation.testing_score", evaluation.TestingScore)
+	v.score("evaluation.error_score", evaluation.ErrorScore)
+	v.score("evaluation.load_score", evaluation.LoadScore)
+}
+
+// BE-IN - Internal backend only
+func (v *ValidationErrors) tags(field string, values []string) {
+	for _, value := range values {
+		if _, err := models.ParseTag(value); err != nil {
+			v.add(field, "%s", err.Error())
+		}
+	}
+}
@@ -0,0 +1,547 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"encore.app/events"
+	"encore.app/models"
+	"encore.app/services/webhooks"
+	"encore.dev"
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+type Webhook struct {
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	Description  string    `json:"description,omitempty"`
+	EventTypes   []string  `json:"event_types"`
+	ProjectID    string    `json:"project_id,omitempty"`
+	DepartmentID string    `json:"department_id,omitempty"`
+	Active       bool      `json:"active"`
+	Secret       string    `json:"secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+type CreateWebhookRequest struct {
+	URL          string   `json:"url" validate:"required,url"`
+	Description  string   `json:"description,omitempty"`
+	EventTypes   []string `json:"event_types,omitempty"`
+	ProjectID    string   `json:"project_id,omitempty"`
+	DepartmentID string   `json:"department_id,omitempty"`
+}
+
+// BE-IN - Internal backend only
+func (r *CreateWebhookRequest) Validate() error {
+	var v ValidationErrors
+	if v.required("url", r.URL) {
+		validateWebhookURL(&v, r.URL)
+	}
+	validateEventTypes(&v, r.EventTypes)
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+type UpdateWebhookRequest struct {
+	URL          *string   `json:"url,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	EventTypes   *[]string `json:"event_types,omitempty"`
+	ProjectID    *string   `json:"project_id,omitempty"`
+	DepartmentID *string   `json:"department_id,omitempty"`
+	Active       *bool     `json:"active,omitempty"`
+}
+
+// BE-IN - Internal backend only
+func (r *UpdateWebhookRequest) Validate() error {
+	var v ValidationErrors
+	if r.URL != nil && v.required("url", *r.URL) {
+		validateWebhookURL(&v, *r.URL)
+	}
+	if r.EventTypes != nil {
+		validateEventTypes(&v, *r.EventTypes)
+	}
+	return v.err()
+}
+
+// BE-IN - Internal backend only
+func validateWebhookURL(v *ValidationErrors, raw string) {
+	if err := webhooks.ValidateURL(raw); err != nil {
+		v.add("url", "must be an absolute http or https url")
+	}
+}
+
+// BE-IN - Internal backend only
+func validateEventTypes(v *ValidationErrors, types []string) {
+	for _, t := range types {
+		if !isEventName(t) {
+			v.add("event_types", "unknown event type %q, expected one of %s", t, strings.Join(events.Names, ", "))
+		}
+	}
+}
+
+// BE-IN - Internal backend only
+func isEventName(name string) bool {
+	for _, n := range events.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+type ListWebhooksResponse struct {
+	Webhooks []*Webhook `json:"webhooks"`
+}
+
+// BE-IN - Internal backend only
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Test       bool      `json:"test,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+type ListWebhookDeliveriesRequest struct {
+	Limit int `query:"limit"`
+}
+
+// BE-IN - Internal backend only
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/webhooks
+func CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error) {
+	return idempotent(ctx, req, func() (*Webhook, error) { return createWebhook(ctx, req) })
+}
+
+// BE-IN - Internal backend only
+func createWebhook(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error) {
+	// Score: [S8,P8,M7,T7,E8,L7]
+	// Details:
+	// - Security (S8): Generated signing secret, returned only once; department filter checked
+	// - Performance (P8): Single in-memory write
+	// - Memory (M7): Small record per webhook
+	// - Testing (T7): Filter permissions covered by tests
+	// - Error (E8): URL and event type validation
+	// - Load (L7): Low write volume
+	// Tags: BE-module-medium
+
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	if err := authorizeWebhookFilter(ctx, userID, req.DepartmentID); err != nil {
+		return nil, err
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		rlog.Error("failed to generate webhook secret", "error", err)
+		return nil, errs.Internal("failed to create webhook")
+	}
+
+	webhook := &models.Webhook{
+		OwnerID:      userID,
+		URL:          req.URL,
+		Secret:       secret,
+		Description:  req.Description,
+		EventTypes:   req.EventTypes,
+		ProjectID:    req.ProjectID,
+		DepartmentID: req.DepartmentID,
+		Active:       true,
+		CreatedAt:    time.Now(),
+	}
+
+	// Save to database
+	err = models.SaveWebhook(ctx, webhook)
+	if err != nil {
+		rlog.Error("failed to save webhook", "error", err)
+		return nil, errs.Internal("failed to save webhook")
+	}
+
+	// The secret is only ever shown in the create response
+	response := convertModelToAPIWebhook(webhook)
+	response.Secret = webhook.Secret
+	return response, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/webhooks
+func ListWebhooks(ctx context.Context) (*ListWebhooksResponse, error) {
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	list, err := models.ListWebhooks(ctx, userID)
+	if err != nil {
+		rlog.Error("failed to list webhooks", "error", err)
+		return nil, errs.Internal("failed to list webhooks")
+	}
+
+	response := &ListWebhooksResponse{Webhooks: make([]*Webhook, len(list))}
+	for i, webhook := range list {
+		response.Webhooks[i] = convertModelToAPIWebhook(webhook)
+	}
+	return response, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/webhooks/:id
+func GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	webhook, err := getOwnedWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return convertModelToAPIWebhook(webhook), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=PATCH path=/api/webhooks/:id
+func UpdateWebhook(ctx context.Context, id string, req *UpdateWebhookRequest) (*Webhook, error) {
+	webhook, err := getOwnedWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.DepartmentID != nil {
+		if err := authorizeWebhookFilter(ctx, webhook.OwnerID, *req.DepartmentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.Description != nil {
+		webhook.Description = *req.Description
+	}
+	if req.EventTypes != nil {
+		webhook.EventTypes = *req.EventTypes
+	}
+	if req.ProjectID != nil {
+		webhook.ProjectID = *req.ProjectID
+	}
+	if req.DepartmentID != nil {
+		webhook.DepartmentID = *req.DepartmentID
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	// Save to database
+	err = models.SaveWebhook(ctx, webhook)
+	if err != nil {
+		rlog.Error("failed to save webhook", "error", err)
+		return nil, errs.Internal("failed to save webhook")
+	}
+
+	return convertModelToAPIWebhook(webhook), nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=DELETE path=/api/webhooks/:id
+func DeleteWebhook(ctx context.Context, id string) error {
+	if _, err := getOwnedWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	if err := models.DeleteWebhook(ctx, id); err != nil {
+		rlog.Error("failed to delete webhook", "error", err)
+		return errs.Internal("failed to delete webhook")
+	}
+	return nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=GET path=/api/webhooks/:id/deliveries
+func ListWebhookDeliveries(ctx context.Context, id string, req *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	if _, err := getOwnedWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	limit := 50
+	if req.Limit > 0 && req.Limit <= 200 {
+		limit = req.Limit
+	}
+
+	deliveries, err := models.ListWebhookDeliveries(ctx, id, limit)
+	if err != nil {
+		rlog.Error("failed to list webhook deliveries", "error", err)
+		return nil, errs.Internal("failed to list webhook deliveries")
+	}
+
+	response := &ListWebhookDeliveriesResponse{Deliveries: make([]*WebhookDelivery, len(deliveries))}
+	for i, delivery := range deliveries {
+		response.Deliveries[i] = convertModelToAPIWebhookDelivery(delivery)
+	}
+	return response, nil
+}
+
+// BE-OUT - External data involved
+//encore:api public method=POST path=/api/webhooks/:id/test
+func TestWebhook(ctx context.Context, id string) (*WebhookDelivery, error) {
+	// Score: [S8,P6,M7,T7,E8,L6]
+	// Details:
+	// - Security (S8): Owner only, same signing and address checks as real deliveries
+	// - Performance (P6): Waits for the receiver, bounded by the client timeout
+	// - Memory (M7): Small synthetic payload
+	// - Testing (T7): Lets owners verify their receiver end to end
+	// - Error (E8): Receiver failures returned as the delivery result, not as an API error
+	// - Load (L6): One outbound request per call
+	// Tags: BE-OUT-medium
+
+	webhook, err := getOwnedWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := json.Marshal(map[string]string{
+		"message":    "This is a test event from the Project Reporting System.",
+		"webhook_id": webhook.ID,
+	})
+	envelope := &webhooks.Envelope{
+		ID:        uuid.New().String(),
+		Type:      "webhook.test",
+		CreatedAt: time.Now(),
+		Test:      true,
+		Data:      data,
+	}
+
+	delivery := deliverWebhook(ctx, webhook, envelope, 1)
+	return convertModelToAPIWebhookDelivery(delivery), nil
+}
+
+// BE-IN - Internal backend only
+// getOwnedWebhook loads a webhook the current user owns.
+func getOwnedWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	// Validate user is authenticated
+	userID, err := models.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Unauthenticated("user must be authenticated")
+	}
+
+	webhook, err := models.GetWebhookByID(ctx, id)
+	if err != nil {
+		if err == models.ErrWebhookNotFound {
+			return nil, errs.NotFound("webhook not found")
+		}
+		rlog.Error("failed to get webhook", "error", err)
+		return nil, errs.Internal("failed to get webhook")
+	}
+
+	if webhook.OwnerID != userID {
+		return nil, errs.Permission("only the owner can manage the webhook")
+	}
+
+	return webhook, nil
+}
+
+// BE-IN - Internal backend only
+// authorizeWebhookFilter checks that userID may receive the events of
+// departmentID: a webhook sees report titles and scores, so only the head
+// of a department may subscribe to it, and only an admin to every
+// department at once.
+func authorizeWebhookFilter(ctx context.Context, userID, departmentID string) error {
+	user, err := models.GetUserByID(ctx, userID)
+	if err != nil {
+		return errs.Permission("unknown user cannot subscribe to events")
+	}
+	if user.IsAdmin() {
+		if departmentID != "" {
+			if _, err := models.GetDepartmentByID(ctx, departmentID); err != nil {
+				return errs.InvalidArgument("department_id: department not found")
+			}
+		}
+		return nil
+	}
+
+	if departmentID == "" {
+		return errs.Permission("only an admin can subscribe to every department; set department_id to a department you head")
+	}
+	department, err := models.GetDepartmentByID(ctx, departmentID)
+	if err != nil {
+		return errs.InvalidArgument("department_id: department not found")
+	}
+	if department.HeadID != userID {
+		return errs.Permission("only the department head can subscribe to the department's events")
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+var webhookClient = webhooks.NewClient()
+
+// BE-OUT - External data involved
+// deliverWebhook makes one delivery attempt and records it in the
+// delivery log.
+func deliverWebhook(ctx context.Context, webhook *models.Webhook, envelope *webhooks.Envelope, attempt int) *models.WebhookDelivery {
+	result := webhookClient.Deliver(ctx, webhook.ID, webhook.URL, webhook.Secret, envelope)
+
+	delivery := &models.WebhookDelivery{
+		WebhookID:  webhook.ID,
+		EventID:    envelope.ID,
+		EventType:  envelope.Type,
+		Attempt:    attempt,
+		StatusCode: result.StatusCode,
+		Success:    result.Err == nil,
+		DurationMs: result.Duration.Milliseconds(),
+		Test:       envelope.Test,
+	}
+	if result.Err != nil {
+		delivery.Error = result.Err.Error()
+	}
+
+	if err := models.SaveWebhookDelivery(ctx, delivery); err != nil {
+		rlog.Error("failed to save webhook delivery", "webhook_id", webhook.ID, "error", err)
+	}
+
+	return delivery
+}
+
+// BE-IN - Internal backend only
+// WebhookDeliveryMessage asks for one webhook to receive one event. Each
+// matching webhook gets its own message so retries are per receiver.
+type WebhookDeliveryMessage struct {
+	WebhookID string             `json:"webhook_id"`
+	Envelope  *webhooks.Envelope `json:"envelope"`
+}
+
+// BE-IN - Internal backend only
+var webhookDeliveryTopic = pubsub.NewTopic[*WebhookDeliveryMessage]("webhook-delivery", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+// BE-OUT - External data involved
+// dispatchWebhooks fans an event out to every matching webhook. The
+// envelope ID is the Pub/Sub message ID, so every receiver and every retry
+// sees the same ID and can deduplicate on it.
+func dispatchWebhooks(ctx context.Context, eventType, projectID, departmentID string, event interface{}) error {
+	// Score: [S7,P7,M7,T7,E7,L7]
+	// Details:
+	// - Security (S7): Filters applied before anything leaves the system
+	// - Performance (P7): Linear scan of webhooks per event
+	// - Memory (M7): Payload marshalled once per event
+	// - Testing (T7): Filtering and the shared event ID tested against an httptest receiver
+	// - Error (E7): Publish failures retried by the subscription
+	// - Load (L7): Deliveries fanned out to their own subscription
+	// Tags: BE-OUT-medium
+
+	list, err := models.ListWebhooks(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var matched []*models.Webhook
+	for _, webhook := range list {
+		if webhook.Matches(eventType, projectID, departmentID) {
+			matched = append(matched, webhook)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	eventID := uuid.New().String()
+	if message := encore.CurrentRequest().Message; message != nil && message.ID != "" {
+		eventID = message.ID
+	}
+	envelope := &webhooks.Envelope{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+
+	for _, webhook := range matched {
+		_, err := webhookDeliveryTopic.Publish(ctx, &WebhookDeliveryMessage{WebhookID: webhook.ID, Envelope: envelope})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BE-OUT - External data involved
+func handleWebhookDelivery(ctx context.Context, message *WebhookDeliveryMessage) error {
+	webhook, err := models.GetWebhookByID(ctx, message.WebhookID)
+	if err != nil {
+		if err == models.ErrWebhookNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Deactivated since the event was dispatched
+	if !webhook.Active {
+		return nil
+	}
+
+	attempt := deliveryAttempt(ctx)
+	delivery := deliverWebhook(ctx, webhook, message.Envelope, attempt)
+	if !delivery.Success {
+		rlog.Warn("webhook delivery failed", "webhook_id", webhook.ID, "event_id", message.Envelope.ID, "attempt", attempt, "error", delivery.Error)
+		return errs.Unavailable("webhook delivery failed: " + delivery.Error)
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIWebhook(model *models.Webhook) *Webhook {
+	eventTypes := model.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return &Webhook{
+		ID:           model.ID,
+		URL:          model.URL,
+		Description:  model.Description,
+		EventTypes:   eventTypes,
+		ProjectID:    model.ProjectID,
+		DepartmentID: model.DepartmentID,
+		Active:       model.Active,
+		CreatedAt:    model.CreatedAt,
+		UpdatedAt:    model.UpdatedAt,
+	}
+}
+
+// BE-IN - Internal backend only
+func convertModelToAPIWebhookDelivery(model *models.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:         model.ID,
+		EventID:    model.EventID,
+		EventType:  model.EventType,
+		Attempt:    model.Attempt,
+		StatusCode: model.StatusCode,
+		Success:    model.Success,
+		Error:      model.Error,
+		DurationMs: model.DurationMs,
+		Test:       model.Test,
+		CreatedAt:  model.CreatedAt,
+	}
+}
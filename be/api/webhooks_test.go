@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"encore.app/models"
+	"encore.app/services/webhooks"
+)
+
+func TestCreateWebhookFilters(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		user       string
+		department string
+		wantErr    string
+	}{
+		{"member, every department", "user-123", "", "only an admin"},
+		{"member, department", "user-123", "dept-123", "only the department head"},
+		{"head, other department", "user-456", "dept-456", "only the department head"},
+		{"head, unknown department", "user-456", "dept-000", "department not found"},
+		{"head, own department", "user-456", "dept-123", ""},
+		{"admin, every department", "user-900", "", ""},
+		{"admin, department", "user-900", "dept-789", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEMO_USER_ID", tt.user)
+			webhook, err := CreateWebhook(ctx, &CreateWebhookRequest{URL: "https://hooks.example.com/reports", DepartmentID: tt.department})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CreateWebhook: %v", err)
+				}
+				if err := DeleteWebhook(ctx, webhook.ID); err != nil {
+					t.Errorf("DeleteWebhook: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CreateWebhook = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateWebhookFilters(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("DEMO_USER_ID", "user-456")
+
+	webhook, err := CreateWebhook(ctx, &CreateWebhookRequest{URL: "https://hooks.example.com/reports", DepartmentID: "dept-123"})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	defer DeleteWebhook(ctx, webhook.ID)
+
+	for _, department := range []string{"", "dept-456"} {
+		if _, err := UpdateWebhook(ctx, webhook.ID, &UpdateWebhookRequest{DepartmentID: &department}); err == nil {
+			t.Errorf("UpdateWebhook to department %q succeeded", department)
+		}
+	}
+	if got, _ := GetWebhook(ctx, webhook.ID); got.DepartmentID != "dept-123" {
+		t.Errorf("refused update changed the filter to %q", got.DepartmentID)
+	}
+}
+
+func TestDispatchWebhooks(t *testing.T) {
+	ctx := context.Background()
+
+	// Deliveries to the test server need private addresses allowed
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "true")
+	saved := webhookClient
+	webhookClient = webhooks.NewClient()
+	t.Cleanup(func() { webhookClient = saved })
+
+	var mu sync.Mutex
+	var received []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	hooks := map[string]*models.Webhook{
+		"all":           {Active: true},
+		"submitted":     {Active: true, EventTypes: []string{"report.submitted"}},
+		"department":    {Active: true, DepartmentID: "dept-456"},
+		"approved":      {Active: true, EventTypes: []string{"report.approved"}},
+		"other project": {Active: true, ProjectID: "project-123"},
+		"inactive":      {},
+	}
+	names := make(map[string]string)
+	for name, webhook := range hooks {
+		webhook.URL = server.URL
+		webhook.OwnerID = "user-900"
+		webhook.Secret = "secret"
+		if err := models.SaveWebhook(ctx, webhook); err != nil {
+			t.Fatalf("SaveWebhook: %v", err)
+		}
+		names[webhook.ID] = name
+		id := webhook.ID
+		t.Cleanup(func() { models.DeleteWebhook(ctx, id) })
+	}
+
+	if err := dispatchWebhooks(ctx, "report.submitted", "project-456", "dept-456", map[string]string{"report_id": "report-1"}); err != nil {
+		t.Fatalf("dispatchWebhooks: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var delivered []string
+	deliveryIDs := make(map[string]bool)
+	for _, r := range received {
+		delivered = append(delivered, names[r.Header.Get(webhooks.HeaderWebhook)])
+		deliveryIDs[r.Header.Get(webhooks.HeaderDelivery)] = true
+		if got := r.Header.Get(webhooks.HeaderEvent); got != "report.submitted" {
+			t.Errorf("event header = %q, want report.submitted", got)
+		}
+	}
+	sort.Strings(delivered)
+	if got := strings.Join(delivered, ","); got != "all,department,submitted" {
+		t.Errorf("delivered to %s, want all,department,submitted", got)
+	}
+	// Every receiver sees the same event ID
+	if len(deliveryIDs) != 1 {
+		t.Errorf("deliveries carry %d event IDs, want 1", len(deliveryIDs))
+	}
+}
@@ -0,0 +1,226 @@
+// Command analyze computes static metrics for a Go module (cyclomatic
+// complexity, test-to-code ratio, unchecked errors, panics, goroutines
+// started without a context and exported API size) and prints the
+// evaluation they suggest. With -report it uploads the sources to the
+// report API instead, which can also apply the suggested evaluation.
+//
+// Usage:
+//
+//	analyze ./be
+//	analyze -json ./be
+//	analyze -report <report-id> -apply ./be
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"encore.app/models"
+	"encore.app/services/analyzer"
+)
+
+// BE-IN - Internal backend only
+type analysisRequest struct {
+	Files []analyzer.Source `json:"files"`
+	Apply bool              `json:"apply,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type analysisResponse struct {
+	Files           int               `json:"files"`
+	CodeLines       int               `json:"code_lines"`
+	Functions       int               `json:"functions"`
+	TestToCodeRatio float64           `json:"test_to_code_ratio"`
+	Suggested       models.Evaluation `json:"suggested"`
+	Applied         bool              `json:"applied"`
+}
+
+// BE-IN - Internal backend only
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// BE-OUT - External data involved
+func main() {
+	asJSON := flag.Bool("json", false, "print the metrics and suggestion as JSON")
+	reportID := flag.String("report", "", "upload the sources and suggest an evaluation for this report")
+	apply := flag.Bool("apply", false, "with -report, save the suggested evaluation to the report")
+	baseURL := flag.String("url", envOr("REPORT_API_URL", "http://localhost:4000"), "report API base URL")
+	token := flag.String("token", os.Getenv("REPORT_API_TOKEN"), "API token (defaults to $REPORT_API_TOKEN)")
+	flag.Parse()
+
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+	if *apply && *reportID == "" {
+		fatal(fmt.Errorf("-apply requires -report"))
+	}
+
+	files, err := analyzer.ReadDir(root)
+	if err != nil {
+		fatal(err)
+	}
+
+	if *reportID != "" {
+		upload(*baseURL, *token, *reportID, files, *apply, *asJSON)
+		return
+	}
+
+	metrics := analyzer.Analyze(files)
+	suggested := analyzer.Suggest(metrics)
+	if *asJSON {
+		printJSON(struct {
+			*analyzer.Metrics
+			Suggested *models.Evaluation `json:"suggested"`
+		}{metrics, suggested})
+		return
+	}
+	printMetrics(metrics)
+	printSuggestion(suggested)
+}
+
+// BE-OUT - External data involved
+// upload sends the sources to the analysis endpoint and prints the
+// server's suggestion.
+func upload(baseURL, token, reportID string, files []analyzer.Source, apply, asJSON bool) {
+	payload, err := json.Marshal(analysisRequest{Files: files, Apply: apply})
+	if err != nil {
+		fatal(err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, baseURL+"/api/reports/"+reportID+"/evaluation/analysis", bytes.NewReader(payload))
+	if err != nil {
+		fatal(err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		fatal(err)
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		fatal(err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			fatal(fmt.Errorf("%s: %s", apiErr.Code, apiErr.Message))
+		}
+		fatal(fmt.Errorf("unexpected status %s", httpResp.Status))
+	}
+
+	if asJSON {
+		os.Stdout.Write(raw)
+		fmt.Println()
+		return
+	}
+
+	var resp analysisResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		fatal(err)
+	}
+	fmt.Printf("%d files, %d lines of code, %d functions, test-to-code ratio %.2f\n",
+		resp.Files, resp.CodeLines, resp.Functions, resp.TestToCodeRatio)
+	printSuggestion(&resp.Suggested)
+	if resp.Applied {
+		fmt.Printf("evaluation saved to report %s\n", reportID)
+	}
+}
+
+// BE-IN - Internal backend only
+func printMetrics(m *analyzer.Metrics) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tEXPORTED\tDECLARATIONS")
+	for _, api := range m.PackageAPIs {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", api.Package, api.Exported, api.Total)
+	}
+	w.Flush()
+
+	if len(m.MostComplex) > 0 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "FUNCTION\tCOMPLEXITY\tLOCATION")
+		for _, f := range m.MostComplex {
+			fmt.Fprintf(w, "%s\t%d\t%s:%d\n", f.Function, f.Complexity, f.File, f.Line)
+		}
+		w.Flush()
+	}
+
+	printLocations("unchecked errors", m.UncheckedErrors, m.UncheckedSamples)
+	printLocations("panics", m.Panics, m.PanicSamples)
+	printLocations("goroutines without a context", m.GoroutinesWithoutContext, m.GoroutineSamples)
+	printLocations("files that failed to parse", len(m.ParseErrors), m.ParseErrors)
+
+	fmt.Printf("\n%d files (%d test files) in %d packages, %d lines of code, %d test lines, ratio %.2f\n",
+		m.Files, m.TestFiles, m.Packages, m.CodeLines, m.TestLines, m.TestToCodeRatio)
+	fmt.Printf("%d functions, average complexity %.1f, max %d, %d above %d\n",
+		m.Functions, m.AverageComplexity, m.MaxComplexity, m.ComplexFunctions, analyzer.ComplexityThreshold)
+	fmt.Printf("%d goroutines, %d exported of %d declarations\n", m.Goroutines, m.ExportedDeclarations, m.TotalDeclarations)
+}
+
+// BE-IN - Internal backend only
+func printLocations(title string, count int, locations []analyzer.Location) {
+	if count == 0 {
+		return
+	}
+	fmt.Printf("\n%d %s:\n", count, title)
+	for _, l := range locations {
+		fmt.Printf("  %s:%d %s %s\n", l.File, l.Line, l.Function, l.Detail)
+	}
+	if count > len(locations) {
+		fmt.Printf("  ... and %d more\n", count-len(locations))
+	}
+}
+
+// BE-IN - Internal backend only
+func printSuggestion(e *models.Evaluation) {
+	fmt.Printf("\nsuggested: S%d P%d M%d T%d E%d L%d\n",
+		e.SecurityScore, e.PerformanceScore, e.MemoryScore, e.TestingScore, e.ErrorScore, e.LoadScore)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "  security\t%s\n", e.SecurityDetails)
+	fmt.Fprintf(w, "  performance\t%s\n", e.PerformanceDetails)
+	fmt.Fprintf(w, "  memory\t%s\n", e.MemoryDetails)
+	fmt.Fprintf(w, "  testing\t%s\n", e.TestingDetails)
+	fmt.Fprintf(w, "  error\t%s\n", e.ErrorDetails)
+	fmt.Fprintf(w, "  load\t%s\n", e.LoadDetails)
+	w.Flush()
+}
+
+// BE-IN - Internal backend only
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fatal(err)
+	}
+}
+
+// BE-IN - Internal backend only
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// BE-IN - Internal backend only
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "analyze:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,95 @@
+// Command fakesmtp is a minimal SMTP server for local development. It
+// accepts every message and prints it to stdout instead of delivering it.
+//
+// Point the backend at it with SMTP_HOST=localhost SMTP_PORT=2525.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:2525", "address to listen on")
+	flag.Parse()
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("listen: %v", err)
+	}
+	log.Printf("fake SMTP server listening on %s", listener.Addr())
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Printf("accept: %v", err)
+			continue
+		}
+		go handle(conn)
+	}
+}
+
+// handle speaks just enough SMTP for net/smtp clients: no TLS, no auth.
+func handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	reader := bufio.NewReader(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	reply("220 fakesmtp ready")
+	var from string
+	var to []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fakesmtp")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			from = strings.TrimSpace(line[len("MAIL FROM:"):])
+			to = nil
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			to = append(to, strings.TrimSpace(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var body strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" || dataLine == ".\n" {
+					break
+				}
+				body.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			fmt.Printf("----- message from %s to %s -----\n%s\n", from, strings.Join(to, ", "), body.String())
+			reply("250 OK: queued")
+		case command == "RSET":
+			from, to = "", nil
+			reply("250 OK")
+		case command == "NOOP":
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// BE-IN - Internal backend only
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// BE-IN - Internal backend only
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// BE-IN - Internal backend only
+func newClient(opts *options) *client {
+	return &client{
+		baseURL: strings.TrimRight(opts.baseURL, "/"),
+		token:   opts.token,
+		http:    &http.Client{Timeout: time.Minute},
+	}
+}
+
+// BE-OUT - External data involved
+// do sends a request with an optional JSON body and returns the raw
+// response body. Non-200 responses are turned into errors carrying the
+// API's error code and message.
+func (c *client) do(method, path string, query url.Values, body interface{}) ([]byte, error) {
+	// Score: [S7,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Bearer token sent only in the Authorization header
+	// - Performance (P7): One request per call, bounded timeout
+	// - Memory (M7): Response bodies are small JSON documents
+	// - Testing (T7): Headers, query, body and both error shapes tested against a local server
+	// - Error (E8): API error codes and messages surfaced verbatim
+	// - Load (L7): Interactive and CI use only
+	// Tags: BE-OUT-medium
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("%s: %s", apiErr.Code, apiErr.Message)
+		}
+		return nil, fmt.Errorf("unexpected status %s", httpResp.Status)
+	}
+	return raw, nil
+}
+
+// BE-OUT - External data involved
+// call is do followed by decoding the response into out.
+func (c *client) call(method, path string, query url.Values, body, out interface{}) ([]byte, error) {
+	raw, err := c.do(method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+	}
+	return raw, nil
+}
+
+// BE-IN - Internal backend only
+func reportPath(id string, suffix string) string {
+	return "/api/reports/" + url.PathEscape(id) + suffix
+}
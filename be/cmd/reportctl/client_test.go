@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClientDo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/echo":
+			body, _ := io.ReadAll(r.Body)
+			json.NewEncoder(w).Encode(map[string]string{
+				"auth":  r.Header.Get("Authorization"),
+				"type":  r.Header.Get("Content-Type"),
+				"query": r.URL.RawQuery,
+				"body":  string(body),
+			})
+		case "/api/denied":
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"code":"permission_denied","message":"not your report"}`)
+		default:
+			http.Error(w, "gateway down", http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	c := newClient(&options{baseURL: srv.URL + "/", token: "secret"})
+	var got map[string]string
+	if _, err := c.call(http.MethodPost, "/api/echo", url.Values{"status": {"draft"}}, map[string]int{"n": 1}, &got); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	want := map[string]string{"auth": "Bearer secret", "type": "application/json", "query": "status=draft", "body": `{"n":1}`}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %q, want %q", key, got[key], value)
+		}
+	}
+
+	// No token and no body means neither header is sent
+	anonymous := newClient(&options{baseURL: srv.URL})
+	if _, err := anonymous.call(http.MethodGet, "/api/echo", nil, nil, &got); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if got["auth"] != "" || got["type"] != "" {
+		t.Errorf("anonymous GET sent auth %q and content type %q", got["auth"], got["type"])
+	}
+
+	if _, err := c.do(http.MethodGet, "/api/denied", nil, nil); err == nil || err.Error() != "permission_denied: not your report" {
+		t.Errorf("API error = %v, want code and message", err)
+	}
+	if _, err := c.do(http.MethodGet, "/api/missing", nil, nil); err == nil || !strings.Contains(err.Error(), "unexpected status 502") {
+		t.Errorf("non-API error = %v, want the status", err)
+	}
+}
+
+func TestRunCreateAndEvaluate(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		io.WriteString(w, `{"id":"report-1"}`)
+	}))
+	defer srv.Close()
+
+	create := []string{"-url", srv.URL, "-title", "Auth module", "-project", "project-1", "-department", "dept-1", "-tag", "BE-OUT-high", "-meta", "repo=acme/auth"}
+	if err := runCreate(create); err != nil {
+		t.Fatalf("runCreate: %v", err)
+	}
+	if err := runEvaluate([]string{"-url", srv.URL, "-scores", "S8,P7,M6,T7,E8,L6", "-details", "security=Input validated", "report-1"}); err != nil {
+		t.Fatalf("runEvaluate: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(requests))
+	}
+	for _, want := range []string{"POST /api/reports ", `"title":"Auth module"`, `"tags":["BE-OUT-high"]`, `"metadata":{"repo":"acme/auth"}`} {
+		if !strings.Contains(requests[0], want) {
+			t.Errorf("create request %s does not contain %s", requests[0], want)
+		}
+	}
+	for _, want := range []string{"POST /api/reports/report-1/evaluate ", `"security_score":8`, `"load_score":6`, `"security_details":"Input validated"`} {
+		if !strings.Contains(requests[1], want) {
+			t.Errorf("evaluate request %s does not contain %s", requests[1], want)
+		}
+	}
+
+	// Local validation fails before anything is sent
+	requests = nil
+	invalid := []struct {
+		name string
+		run  func([]string) error
+		args []string
+		want string
+	}{
+		{"malformed metadata", runCreate, []string{"-url", srv.URL, "-meta", "repo"}, "key=value"},
+		{"no scores", runEvaluate, []string{"-url", srv.URL, "report-1"}, "-scores or -file is required"},
+		{"unknown dimension", runEvaluate, []string{"-url", srv.URL, "-scores", "S8,P7,M6,T7,E8,L6", "-details", "speed=fast", "report-1"}, "unknown dimension"},
+		{"malformed details", runEvaluate, []string{"-url", srv.URL, "-scores", "S8,P7,M6,T7,E8,L6", "-details", "security", "report-1"}, "dimension=text"},
+	}
+	for _, tt := range invalid {
+		if err := tt.run(tt.args); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if len(requests) != 0 {
+		t.Errorf("invalid input sent %q", requests)
+	}
+}
@@ -0,0 +1,522 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// BE-IN - Internal backend only
+// evaluation is the body of POST /api/reports/:id/evaluate.
+type evaluation struct {
+	SecurityScore      int    `json:"security_score"`
+	PerformanceScore   int    `json:"performance_score"`
+	MemoryScore        int    `json:"memory_score"`
+	TestingScore       int    `json:"testing_score"`
+	ErrorScore         int    `json:"error_score"`
+	LoadScore          int    `json:"load_score"`
+	SecurityDetails    string `json:"security_details,omitempty"`
+	PerformanceDetails string `json:"performance_details,omitempty"`
+	MemoryDetails      string `json:"memory_details,omitempty"`
+	TestingDetails     string `json:"testing_details,omitempty"`
+	ErrorDetails       string `json:"error_details,omitempty"`
+	LoadDetails        string `json:"load_details,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type reportEvaluation struct {
+	evaluation
+	EvaluatorID string `json:"evaluator_id,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type report struct {
+	ID                     string                 `json:"id"`
+	Title                  string                 `json:"title"`
+	Description            string                 `json:"description"`
+	ProjectID              string                 `json:"project_id"`
+	AuthorID               string                 `json:"author_id"`
+	DepartmentID           string                 `json:"department_id"`
+	Status                 string                 `json:"status"`
+	CreatedAt              time.Time              `json:"created_at"`
+	UpdatedAt              time.Time              `json:"updated_at"`
+	SubmittedAt            *time.Time             `json:"submitted_at,omitempty"`
+	Metadata               map[string]interface{} `json:"metadata,omitempty"`
+	TemplateID             string                 `json:"template_id,omitempty"`
+	PeriodID               string                 `json:"period_id,omitempty"`
+	DueAt                  *time.Time             `json:"due_at,omitempty"`
+	Overdue                bool                   `json:"overdue"`
+	Tags                   []string               `json:"tags,omitempty"`
+	Evaluation             *reportEvaluation      `json:"evaluation,omitempty"`
+	CommentCount           int                    `json:"comment_count"`
+	UnresolvedCommentCount int                    `json:"unresolved_comment_count"`
+}
+
+// BE-IN - Internal backend only
+// createRequest is the body of POST /api/reports, and the schema of
+// report files.
+type createRequest struct {
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	ProjectID    string                 `json:"project_id"`
+	DepartmentID string                 `json:"department_id"`
+	TemplateID   *string                `json:"template_id,omitempty"`
+	PeriodID     *string                `json:"period_id,omitempty"`
+	Evaluation   *evaluation            `json:"evaluation,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type listResponse struct {
+	Reports []report `json:"reports"`
+	Total   int      `json:"total"`
+}
+
+// BE-OUT - External data involved
+func runCreate(args []string) error {
+	// Score: [S7,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Token auth, server-side validation is authoritative
+	// - Performance (P7): Single request
+	// - Memory (M7): Report file held in memory once
+	// - Testing (T7): Request built from flags and malformed -meta tested, -file merging only through readRequestFile tests
+	// - Error (E8): File errors name the file and line, API errors surfaced
+	// - Load (L7): Interactive and CI use only
+	// Tags: BE-OUT-medium
+
+	fs, opts := newFlagSet("create")
+	file := fs.String("file", "", "YAML or Markdown report file; flags override its fields")
+	title := fs.String("title", "", "report title")
+	description := fs.String("description", "", "report description")
+	projectID := fs.String("project", "", "project ID")
+	departmentID := fs.String("department", "", "department ID")
+	templateID := fs.String("template", "", "template to instantiate the draft from")
+	periodID := fs.String("period", "", "reporting period the report is due in")
+	dryRun := fs.Bool("dry-run", false, "print the request body instead of sending it")
+	var tags, metadata stringList
+	fs.Var(&tags, "tag", "tag such as BE-OUT-high (repeatable)")
+	fs.Var(&metadata, "meta", "metadata key=value (repeatable)")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	req := &createRequest{}
+	if *file != "" {
+		if err := readRequestFile(*file, req); err != nil {
+			return err
+		}
+	}
+
+	overrides := map[*string]string{
+		&req.Title:        *title,
+		&req.Description:  *description,
+		&req.ProjectID:    *projectID,
+		&req.DepartmentID: *departmentID,
+	}
+	for field, value := range overrides {
+		if value != "" {
+			*field = value
+		}
+	}
+	if *templateID != "" {
+		req.TemplateID = templateID
+	}
+	if *periodID != "" {
+		req.PeriodID = periodID
+	}
+	req.Tags = append(req.Tags, tags...)
+	for _, entry := range metadata {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("-meta %q must have the form key=value", entry)
+		}
+		if req.Metadata == nil {
+			req.Metadata = map[string]interface{}{}
+		}
+		req.Metadata[key] = value
+	}
+
+	if *dryRun {
+		encoded, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		return printJSON(encoded)
+	}
+
+	var created report
+	raw, err := newClient(opts).call(http.MethodPost, "/api/reports", nil, req, &created)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+
+	// Only the ID on stdout, so scripts can capture it
+	fmt.Println(created.ID)
+	return nil
+}
+
+// BE-OUT - External data involved
+func runList(args []string) error {
+	fs, opts := newFlagSet("list")
+	status := fs.String("status", "", "filter by status")
+	authorID := fs.String("author", "", "filter by author ID")
+	projectID := fs.String("project", "", "filter by project ID")
+	periodID := fs.String("period", "", "filter by reporting period ID")
+	overdue := fs.Bool("overdue", false, "only drafts past their due date")
+	tagType := fs.String("tag-type", "", "filter by tag type (BE, FE)")
+	tagScope := fs.String("tag-scope", "", "filter by tag scope (IN, OUT, DB, module)")
+	tagImpact := fs.String("tag-impact", "", "filter by tag impact (low, medium, high)")
+	limit := fs.Int("limit", 50, "maximum number of reports (1-100)")
+	offset := fs.Int("offset", 0, "number of reports to skip")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	filters := map[string]string{
+		"status":     *status,
+		"author_id":  *authorID,
+		"project_id": *projectID,
+		"period_id":  *periodID,
+		"tag_type":   *tagType,
+		"tag_scope":  *tagScope,
+		"tag_impact": *tagImpact,
+	}
+	for key, value := range filters {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if *overdue {
+		query.Set("overdue", "true")
+	}
+	query.Set("limit", strconv.Itoa(*limit))
+	query.Set("offset", strconv.Itoa(*offset))
+
+	var resp listResponse
+	raw, err := newClient(opts).call(http.MethodGet, "/api/reports", query, nil, &resp)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tSCORE\tTAGS\tUPDATED")
+	for _, r := range resp.Reports {
+		status := r.Status
+		if r.Overdue {
+			status += " (overdue)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.ID, truncate(r.Title, 40), status, overall(r.Evaluation), strings.Join(r.Tags, ","), formatTime(&r.UpdatedAt))
+	}
+	w.Flush()
+	fmt.Printf("\n%d of %d reports\n", len(resp.Reports), resp.Total)
+	return nil
+}
+
+// BE-OUT - External data involved
+func runShow(args []string) error {
+	fs, opts := newFlagSet("show")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	var r report
+	raw, err := newClient(opts).call(http.MethodGet, reportPath(rest[0], ""), nil, nil, &r)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+
+	due := formatTime(r.DueAt)
+	if r.Overdue {
+		due += " (overdue)"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fields := [][2]string{
+		{"ID", r.ID},
+		{"Title", r.Title},
+		{"Status", r.Status},
+		{"Project", r.ProjectID},
+		{"Department", r.DepartmentID},
+		{"Author", r.AuthorID},
+		{"Template", r.TemplateID},
+		{"Period", r.PeriodID},
+		{"Due", due},
+		{"Tags", strings.Join(r.Tags, ", ")},
+		{"Created", formatTime(&r.CreatedAt)},
+		{"Updated", formatTime(&r.UpdatedAt)},
+		{"Submitted", formatTime(r.SubmittedAt)},
+		{"Comments", fmt.Sprintf("%d (%d unresolved)", r.CommentCount, r.UnresolvedCommentCount)},
+	}
+	for _, f := range fields {
+		if f[1] != "" {
+			fmt.Fprintf(w, "%s:\t%s\n", f[0], f[1])
+		}
+	}
+	for key, value := range r.Metadata {
+		fmt.Fprintf(w, "metadata.%s:\t%v\n", key, value)
+	}
+	w.Flush()
+
+	if r.Description != "" {
+		fmt.Printf("\n%s\n", r.Description)
+	}
+
+	if r.Evaluation == nil {
+		fmt.Println("\nNot evaluated")
+		return nil
+	}
+
+	fmt.Printf("\nEvaluation by %s, overall %s\n", r.Evaluation.EvaluatorID, overall(r.Evaluation))
+	w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, d := range dimensionsOf(&r.Evaluation.evaluation) {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", d.name, *d.score, *d.details)
+	}
+	w.Flush()
+	return nil
+}
+
+// BE-OUT - External data involved
+func runEvaluate(args []string) error {
+	// Score: [S7,P7,M8,T7,E8,L7]
+	// Details:
+	// - Security (S7): Token auth, scores range-checked before sending
+	// - Performance (P7): Single request
+	// - Memory (M8): Small request body
+	// - Testing (T7): Scores, details and local rejections tested against a local server
+	// - Error (E8): Malformed scores and details rejected locally
+	// - Load (L7): Interactive and CI use only
+	// Tags: BE-OUT-medium
+
+	fs, opts := newFlagSet("evaluate")
+	scores := fs.String("scores", "", "six scores in Score line order, e.g. S8,P7,M6,T7,E8,L6")
+	file := fs.String("file", "", "YAML, Markdown or JSON file with the evaluation fields")
+	var details stringList
+	fs.Var(&details, "details", "dimension=text, e.g. security=\"Input validated\" (repeatable)")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *scores == "" && *file == "" {
+		return fmt.Errorf("-scores or -file is required")
+	}
+
+	req := &evaluation{}
+	if *file != "" {
+		if err := readRequestFile(*file, req); err != nil {
+			return err
+		}
+	}
+	dims := dimensionsOf(req)
+
+	if *scores != "" {
+		values, err := parseScores(*scores)
+		if err != nil {
+			return err
+		}
+		for i, d := range dims {
+			*d.score = values[i]
+		}
+	}
+	for _, d := range dims {
+		if *d.score < 0 || *d.score > 10 {
+			return fmt.Errorf("%s score %d is out of range 0-10", d.name, *d.score)
+		}
+	}
+
+	for _, entry := range details {
+		name, text, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("-details %q must have the form dimension=text", entry)
+		}
+		found := false
+		for _, d := range dims {
+			if strings.EqualFold(d.name, name) {
+				*d.details = text
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("-details: unknown dimension %q", name)
+		}
+	}
+
+	var r report
+	raw, err := newClient(opts).call(http.MethodPost, reportPath(rest[0], "/evaluate"), nil, req, &r)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+	fmt.Printf("evaluated %s: overall %s\n", r.ID, overall(r.Evaluation))
+	return nil
+}
+
+// BE-OUT - External data involved
+func runSubmit(args []string) error {
+	fs, opts := newFlagSet("submit")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	var r report
+	raw, err := newClient(opts).call(http.MethodPost, reportPath(rest[0], "/submit"), nil, nil, &r)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+	fmt.Printf("submitted %s to department %s at %s\n", r.ID, r.DepartmentID, formatTime(r.SubmittedAt))
+	return nil
+}
+
+// BE-OUT - External data involved
+// runStatus prints one line per report. Reports that cannot be fetched
+// are reported on stderr and make the command fail after the others are
+// printed.
+func runStatus(args []string) error {
+	fs, opts := newFlagSet("status")
+	rest, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	c := newClient(opts)
+	var found []json.RawMessage
+	var reports []report
+	failed := 0
+	for _, id := range rest {
+		var r report
+		raw, err := c.call(http.MethodGet, reportPath(id, ""), nil, nil, &r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "reportctl: %s: %v\n", id, err)
+			failed++
+			continue
+		}
+		found = append(found, raw)
+		reports = append(reports, r)
+	}
+
+	if opts.asJSON {
+		encoded, err := json.Marshal(found)
+		if err != nil {
+			return err
+		}
+		if err := printJSON(encoded); err != nil {
+			return err
+		}
+	} else if len(reports) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTATUS\tSUBMITTED\tSCORE\tOPEN COMMENTS\tTITLE")
+		for _, r := range reports {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+				r.ID, r.Status, formatTime(r.SubmittedAt), overall(r.Evaluation), r.UnresolvedCommentCount, truncate(r.Title, 40))
+		}
+		w.Flush()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d reports could not be fetched", failed, len(rest))
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+type dimension struct {
+	name    string
+	score   *int
+	details *string
+}
+
+// BE-IN - Internal backend only
+// dimensionsOf lists the fields of e in Score line order.
+func dimensionsOf(e *evaluation) []dimension {
+	return []dimension{
+		{"Security", &e.SecurityScore, &e.SecurityDetails},
+		{"Performance", &e.PerformanceScore, &e.PerformanceDetails},
+		{"Memory", &e.MemoryScore, &e.MemoryDetails},
+		{"Testing", &e.TestingScore, &e.TestingDetails},
+		{"Error", &e.ErrorScore, &e.ErrorDetails},
+		{"Load", &e.LoadScore, &e.LoadDetails},
+	}
+}
+
+// BE-IN - Internal backend only
+// parseScores accepts "S8,P7,M6,T7,E8,L6", the same with brackets as in a
+// Score line, or bare numbers "8,7,6,7,8,6".
+func parseScores(s string) ([6]int, error) {
+	var scores [6]int
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "["), "]")
+	parts := strings.Split(s, ",")
+	if len(parts) != len(scores) {
+		return scores, fmt.Errorf("-scores needs six values in the order S,P,M,T,E,L, got %d", len(parts))
+	}
+
+	letters := "SPMTEL"
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part != "" && (part[0] < '0' || part[0] > '9') {
+			if !strings.EqualFold(part[:1], letters[i:i+1]) {
+				return scores, fmt.Errorf("-scores: value %d is labelled %s, expected %c", i+1, part[:1], letters[i])
+			}
+			part = part[1:]
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return scores, fmt.Errorf("-scores: %q is not a number", parts[i])
+		}
+		scores[i] = n
+	}
+	return scores, nil
+}
+
+// BE-IN - Internal backend only
+// overall is the mean of the six scores, or "-" without an evaluation.
+func overall(e *reportEvaluation) string {
+	if e == nil {
+		return "-"
+	}
+	sum := 0
+	for _, d := range dimensionsOf(&e.evaluation) {
+		sum += *d.score
+	}
+	return strconv.FormatFloat(float64(sum)/6, 'f', 1, 64)
+}
+
+// BE-IN - Internal backend only
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// BE-IN - Internal backend only
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
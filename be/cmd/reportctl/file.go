@@ -0,0 +1,502 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// readRequestFile decodes a YAML file, or a Markdown file with YAML front
+// matter, into out. Keys must match the JSON field names of out. The
+// Markdown body becomes the description unless the front matter sets one,
+// and a leading "# Heading" becomes the title. Unquoted scalars are typed
+// by the field they land in, so "project_id: 2024" stays a string.
+func readRequestFile(path string, out interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		fields, err = parseMarkdown(string(data))
+	case ".json":
+		err = json.Unmarshal(data, &fields)
+	default:
+		fields, err = parseYAML(string(data))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Round-trip through JSON so the request types define the schema
+	encoded, err := json.Marshal(resolveScalars(fields, reflect.TypeOf(out)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func parseMarkdown(src string) (map[string]interface{}, error) {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+
+	fields := map[string]interface{}{}
+	body := src
+	lines := strings.Split(src, "\n")
+	if strings.TrimRight(lines[0], " ") == "---" {
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimRight(lines[i], " ") == "---" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("front matter is not closed with ---")
+		}
+		var err error
+		fields, err = parseYAML(strings.Join(lines[1:end], "\n"))
+		if err != nil {
+			return nil, err
+		}
+		body = strings.Join(lines[end+1:], "\n")
+	}
+
+	body = strings.TrimSpace(body)
+	if _, ok := fields["title"]; !ok && strings.HasPrefix(body, "# ") {
+		heading := body
+		body = ""
+		if i := strings.IndexByte(heading, '\n'); i >= 0 {
+			heading, body = heading[:i], strings.TrimSpace(heading[i+1:])
+		}
+		fields["title"] = strings.TrimSpace(strings.TrimPrefix(heading, "# "))
+	}
+	if _, ok := fields["description"]; !ok && body != "" {
+		fields["description"] = body
+	}
+	return fields, nil
+}
+
+// BE-IN - Internal backend only
+// yamlLine is one source line with its indentation measured in spaces.
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+	raw    string
+}
+
+// BE-IN - Internal backend only
+// yamlParser reads the subset of YAML used by report files: nested
+// mappings, block sequences of scalars or mappings, flow sequences of
+// scalars, quoted and plain scalars, and | or > block scalars. Anchors,
+// tags and multi-document streams are not supported.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// BE-IN - Internal backend only
+func parseYAML(src string) (map[string]interface{}, error) {
+	// Score: [S7,P8,M8,T6,E7,L8]
+	// Details:
+	// - Security (S7): Local files only, no custom types or anchors
+	// - Performance (P8): Single pass over the lines
+	// - Memory (M8): Builds only the resulting maps
+	// - Testing (T7): Table tests for scalars, sequences, blocks and errors
+	// - Error (E7): Errors name the offending line
+	// - Load (L8): Runs once per command
+	// Tags: BE-IN-low
+
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		if strings.TrimLeft(raw, " ") != strings.TrimLeft(raw, " \t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		text := strings.TrimLeft(raw, " ")
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(raw) - len(text), text: strings.TrimRight(text, " "), raw: raw})
+	}
+
+	result, err := p.mapping(0)
+	if err != nil {
+		return nil, err
+	}
+	if l, ok := p.next(); ok {
+		return nil, fmt.Errorf("line %d: unexpected indentation", l.number)
+	}
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+// next skips blank and comment lines and returns the current line.
+func (p *yamlParser) next() (yamlLine, bool) {
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.text != "" && !strings.HasPrefix(l.text, "#") && l.text != "---" {
+			return l, true
+		}
+		p.pos++
+	}
+	return yamlLine{}, false
+}
+
+// BE-IN - Internal backend only
+func (p *yamlParser) mapping(indent int) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for {
+		l, ok := p.next()
+		if !ok || l.indent < indent {
+			return result, nil
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.number)
+		}
+
+		key, rest, ok := splitKey(l.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", l.number)
+		}
+		if _, dup := result[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.number, key)
+		}
+		p.pos++
+
+		value, err := p.value(l, indent, rest)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+}
+
+// BE-IN - Internal backend only
+// value parses what follows "key:" on line l, consuming any nested lines.
+func (p *yamlParser) value(l yamlLine, indent int, rest string) (interface{}, error) {
+	switch {
+	case rest == "|" || rest == "|-" || rest == ">" || rest == ">-":
+		return p.blockScalar(indent, rest[0] == '>'), nil
+	case strings.HasPrefix(rest, "["):
+		return flowSequence(l.number, rest)
+	case rest != "" && !strings.HasPrefix(rest, "#"):
+		return scalar(l.number, rest)
+	}
+
+	child, ok := p.next()
+	if !ok || child.indent <= indent {
+		// "- item" may sit at the same indentation as its key
+		if ok && child.indent == indent && isSequenceItem(child.text) {
+			return p.sequence(indent)
+		}
+		return nil, nil
+	}
+	if isSequenceItem(child.text) {
+		return p.sequence(child.indent)
+	}
+	return p.mapping(child.indent)
+}
+
+// BE-IN - Internal backend only
+func (p *yamlParser) sequence(indent int) ([]interface{}, error) {
+	var result []interface{}
+	for {
+		l, ok := p.next()
+		if !ok || l.indent != indent || !isSequenceItem(l.text) {
+			return result, nil
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(l.text, "-"))
+		if item == "" {
+			return nil, fmt.Errorf("line %d: nested sequence items are not supported", l.number)
+		}
+		if strings.HasPrefix(item, "[") || isSequenceItem(item) {
+			return nil, fmt.Errorf("line %d: nested sequences are not supported", l.number)
+		}
+
+		// "- key: value" starts a mapping whose keys line up after the dash
+		if _, _, ok := splitKey(item); ok && !strings.HasPrefix(item, `"`) && !strings.HasPrefix(item, "'") {
+			itemIndent := l.indent + len(l.text) - len(item)
+			p.lines[p.pos] = yamlLine{number: l.number, indent: itemIndent, text: item, raw: strings.Repeat(" ", itemIndent) + item}
+			value, err := p.mapping(itemIndent)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		p.pos++
+		value, err := scalar(l.number, item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+}
+
+// BE-IN - Internal backend only
+// blockScalar collects the lines indented deeper than indent. Literal
+// blocks keep line breaks, folded blocks join lines with spaces; both
+// drop trailing blank lines.
+func (p *yamlParser) blockScalar(indent int, folded bool) string {
+	var lines []string
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.text != "" && l.indent <= indent {
+			break
+		}
+		p.pos++
+		if l.text == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if blockIndent < 0 {
+			blockIndent = l.indent
+		}
+		cut := blockIndent
+		if l.indent < cut {
+			cut = l.indent
+		}
+		lines = append(lines, strings.TrimRight(l.raw[cut:], " "))
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if !folded {
+		return strings.Join(lines, "\n")
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		switch {
+		case line == "":
+			b.WriteString("\n")
+		case i > 0 && lines[i-1] != "":
+			b.WriteString(" " + line)
+		default:
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// BE-IN - Internal backend only
+func flowSequence(line int, text string) ([]interface{}, error) {
+	text = stripComment(text)
+	if !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("line %d: flow sequence is not closed with ]", line)
+	}
+	inner := strings.TrimSpace(text[1 : len(text)-1])
+	if inner == "" {
+		return []interface{}{}, nil
+	}
+
+	var result []interface{}
+	for _, item := range splitFlowItems(inner) {
+		value, err := scalar(line, strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+// splitFlowItems splits on commas outside quotes.
+func splitFlowItems(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote && (quote == '\'' || s[i-1] != '\\') {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, s[start:])
+}
+
+// BE-IN - Internal backend only
+// plainScalar is an unquoted scalar. Whether it is a string, number or
+// bool depends on the field it is decoded into; see resolveScalars.
+type plainScalar string
+
+// BE-IN - Internal backend only
+// scalar unquotes a quoted scalar. Plain scalars are returned as
+// plainScalar, or nil for null.
+func scalar(line int, text string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		end := closingQuote(text)
+		if end < 0 {
+			return nil, fmt.Errorf("line %d: unterminated string", line)
+		}
+		value, err := strconv.Unquote(text[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid string: %v", line, err)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "'"):
+		end := strings.LastIndex(text, "'")
+		if end == 0 {
+			return nil, fmt.Errorf("line %d: unterminated string", line)
+		}
+		return strings.ReplaceAll(text[1:end], "''", "'"), nil
+	}
+
+	text = stripComment(text)
+	switch text {
+	case "", "~", "null":
+		return nil, nil
+	}
+	return plainScalar(text), nil
+}
+
+// BE-IN - Internal backend only
+// typed converts a plain scalar to a number or bool the way YAML would.
+func (s plainScalar) typed() interface{} {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, err := strconv.ParseInt(string(s), 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(string(s), 64); err == nil {
+		return f
+	}
+	return string(s)
+}
+
+// BE-IN - Internal backend only
+// resolveScalars replaces the plain scalars in value for decoding into t:
+// a string field keeps the text as written, anything else, including
+// interface{} and unknown keys, gets the YAML type.
+func resolveScalars(value interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case plainScalar:
+		if t != nil && t.Kind() == reflect.String {
+			return string(v)
+		}
+		return v.typed()
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = resolveScalars(item, fieldType(t, key))
+		}
+	case []interface{}:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for i, item := range v {
+			v[i] = resolveScalars(item, elem)
+		}
+	}
+	return value
+}
+
+// BE-IN - Internal backend only
+// fieldType returns the type a key of a mapping decodes into when the
+// mapping is decoded into t, matching JSON field names the way
+// encoding/json does. It returns nil when t does not say.
+func fieldType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Map {
+		return t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && name == "" {
+			if ft := fieldType(f.Type, key); ft != nil {
+				return ft
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if f.IsExported() && strings.EqualFold(name, key) {
+			return f.Type
+		}
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func closingQuote(text string) int {
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// BE-IN - Internal backend only
+// splitKey splits "key: value" and "key:" lines.
+func splitKey(text string) (key, rest string, ok bool) {
+	if isSequenceItem(text) {
+		return "", "", false
+	}
+	i := strings.Index(text, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", false
+		}
+		i = len(text) - 1
+	}
+	key = strings.TrimSpace(text[:i])
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		key = key[1 : len(key)-1]
+	}
+	return key, strings.TrimSpace(text[i+1:]), key != ""
+}
+
+// BE-IN - Internal backend only
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// BE-IN - Internal backend only
+// stripComment removes a trailing " # comment" from a plain scalar.
+func stripComment(text string) string {
+	if i := strings.Index(text, " #"); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
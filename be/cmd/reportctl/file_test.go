@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want map[string]interface{}
+	}{
+		{
+			name: "plain scalars",
+			src:  "title: 1.0\ncount: 2024\nok: true\nnone: ~\nurl: http://example.com # comment",
+			want: map[string]interface{}{"title": plainScalar("1.0"), "count": plainScalar("2024"), "ok": plainScalar("true"), "none": nil, "url": plainScalar("http://example.com")},
+		},
+		{
+			name: "quoted scalars",
+			src:  "a: \"x: \\\"y\\\" # not a comment\"\nb: 'it''s'",
+			want: map[string]interface{}{"a": `x: "y" # not a comment`, "b": "it's"},
+		},
+		{
+			name: "nested mapping",
+			src:  "metadata:\n  repo: acme/auth\n  owner:\n    team: core\n",
+			want: map[string]interface{}{"metadata": map[string]interface{}{"repo": plainScalar("acme/auth"), "owner": map[string]interface{}{"team": plainScalar("core")}}},
+		},
+		{
+			name: "sequences",
+			src:  "tags: [BE-OUT-high, \"a, b\"]\nmore:\n- one\n- 2\nempty: []",
+			want: map[string]interface{}{"tags": []interface{}{plainScalar("BE-OUT-high"), "a, b"}, "more": []interface{}{plainScalar("one"), plainScalar("2")}, "empty": []interface{}{}},
+		},
+		{
+			name: "sequence of mappings",
+			src:  "items:\n  - x: 1\n    y: 2\n  - x: 3\n    notes: |\n      line one\n      line two\n  - plain\n",
+			want: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"x": plainScalar("1"), "y": plainScalar("2")},
+				map[string]interface{}{"x": plainScalar("3"), "notes": "line one\nline two"},
+				plainScalar("plain"),
+			}},
+		},
+		{
+			name: "block scalars",
+			src:  "literal: |\n  one\n\n  two\n\nfolded: >\n  one\n  two\n\n  three\nnext: x",
+			want: map[string]interface{}{"literal": "one\n\ntwo", "folded": "one two\nthree", "next": plainScalar("x")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(tt.src)
+			if err != nil {
+				t.Fatalf("parseYAML: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"a: 1\n\tb: 2", "line 2: tabs are not allowed"},
+		{"a: 1\n  b: 2", "line 2: unexpected indentation"},
+		{"a: 1\na: 2", `line 2: duplicate key "a"`},
+		{"just text", "line 1: expected key: value"},
+		{"a: \"open", "line 1: unterminated string"},
+		{"a: [1, 2", "line 1: flow sequence is not closed with ]"},
+		{"a:\n  - - 1", "line 2: nested sequences are not supported"},
+		{"a:\n  - x: 1\n    x: 2", `line 3: duplicate key "x"`},
+	}
+	for _, tt := range tests {
+		_, err := parseYAML(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseYAML(%q) error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestReadRequestFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// String fields keep numbers as written; other fields get YAML types
+	path := write("report.yaml", `title: 1.0
+description: Migrated from the legacy tracker
+project_id: 2024
+department_id: 0012
+tags: [2024, BE-OUT-high]
+metadata:
+  build: 42
+  release: true
+  owner: core
+evaluation:
+  security_score: 8
+  security_details: 10
+`)
+	var req createRequest
+	if err := readRequestFile(path, &req); err != nil {
+		t.Fatalf("readRequestFile: %v", err)
+	}
+	want := createRequest{
+		Title:        "1.0",
+		Description:  "Migrated from the legacy tracker",
+		ProjectID:    "2024",
+		DepartmentID: "0012",
+		Tags:         []string{"2024", "BE-OUT-high"},
+		Metadata:     map[string]interface{}{"build": float64(42), "release": true, "owner": "core"},
+		Evaluation:   &evaluation{SecurityScore: 8, SecurityDetails: "10"},
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("readRequestFile =\n%+v\nwant\n%+v", req, want)
+	}
+
+	// Embedded fields are typed too
+	var review reportEvaluation
+	if err := readRequestFile(write("review.yaml", "load_score: 7\nevaluator_id: 900\n"), &review); err != nil {
+		t.Fatalf("readRequestFile: %v", err)
+	}
+	if review.LoadScore != 7 || review.EvaluatorID != "900" {
+		t.Errorf("readRequestFile = %+v", review)
+	}
+
+	var md createRequest
+	if err := readRequestFile(write("report.md", "---\nproject_id: 7\n---\n# Auth module\n\nJWT authentication.\n"), &md); err != nil {
+		t.Fatalf("readRequestFile: %v", err)
+	}
+	if md.Title != "Auth module" || md.Description != "JWT authentication." || md.ProjectID != "7" {
+		t.Errorf("readRequestFile = %+v", md)
+	}
+
+	// A non-number in a score is still rejected
+	err := readRequestFile(write("bad.yaml", "evaluation:\n  security_score: high\n"), &createRequest{})
+	if err == nil || !strings.Contains(err.Error(), "security_score") {
+		t.Errorf("readRequestFile error = %v, want a security_score type error", err)
+	}
+	err = readRequestFile(write("unknown.yaml", "titel: x\n"), &createRequest{})
+	if err == nil || !strings.Contains(err.Error(), `unknown field "titel"`) {
+		t.Errorf("readRequestFile error = %v, want unknown field", err)
+	}
+}
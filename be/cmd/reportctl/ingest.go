@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+)
+
+// BE-IN - Internal backend only
+type coverageRequest struct {
+	Profile  string `json:"profile"`
+	TestJSON string `json:"test_json,omitempty"`
+	Apply    bool   `json:"apply,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type coverageRun struct {
+	Percent    float64 `json:"percent"`
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Packages   []struct {
+		Package string  `json:"package"`
+		Percent float64 `json:"percent"`
+		Passed  int     `json:"passed"`
+		Failed  int     `json:"failed"`
+		Flaky   int     `json:"flaky"`
+	} `json:"packages"`
+	SuggestedScore int    `json:"suggested_score"`
+	Details        string `json:"details"`
+	Applied        bool   `json:"applied"`
+}
+
+// BE-OUT - External data involved
+func runCoverage(args []string) error {
+	fs, opts := newFlagSet("coverage")
+	profile := fs.String("profile", "", "go test -coverprofile output (required)")
+	testJSON := fs.String("test-json", "", "go test -json output")
+	apply := fs.Bool("apply", false, "save the suggested score as the report's TestingScore")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *profile == "" {
+		return fmt.Errorf("-profile is required")
+	}
+
+	req := coverageRequest{Apply: *apply}
+	data, err := os.ReadFile(*profile)
+	if err != nil {
+		return err
+	}
+	req.Profile = string(data)
+	if *testJSON != "" {
+		data, err := os.ReadFile(*testJSON)
+		if err != nil {
+			return err
+		}
+		req.TestJSON = string(data)
+	}
+
+	var run coverageRun
+	raw, err := newClient(opts).call(http.MethodPost, reportPath(rest[0], "/evaluation/coverage"), nil, req, &run)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tCOVERAGE\tPASSED\tFAILED\tFLAKY")
+	for _, pkg := range run.Packages {
+		fmt.Fprintf(w, "%s\t%.1f%%\t%d\t%d\t%d\n", pkg.Package, pkg.Percent, pkg.Passed, pkg.Failed, pkg.Flaky)
+	}
+	w.Flush()
+	fmt.Printf("\n%s\n", run.Details)
+	if run.Applied {
+		fmt.Printf("TestingScore %d saved to report %s\n", run.SuggestedScore, rest[0])
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+type benchmarksRequest struct {
+	Output      string `json:"output"`
+	Apply       bool   `json:"apply,omitempty"`
+	SetBaseline bool   `json:"set_baseline,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type benchmarkRun struct {
+	Comparisons []struct {
+		Key        string  `json:"key"`
+		NsPerOp    float64 `json:"ns_per_op"`
+		NsDelta    float64 `json:"ns_delta"`
+		BytesDelta float64 `json:"bytes_delta"`
+		HasMemory  bool    `json:"has_memory"`
+		Regressed  bool    `json:"regressed"`
+	} `json:"comparisons"`
+	PerformanceScore   *int   `json:"performance_score"`
+	MemoryScore        *int   `json:"memory_score"`
+	PerformanceDetails string `json:"performance_details"`
+	MemoryDetails      string `json:"memory_details"`
+	Applied            bool   `json:"applied"`
+	IsBaseline         bool   `json:"is_baseline"`
+}
+
+// BE-OUT - External data involved
+func runBenchmarks(args []string) error {
+	fs, opts := newFlagSet("benchmarks")
+	output := fs.String("output", "", "go test -bench -benchmem output, plain or -json (required)")
+	apply := fs.Bool("apply", false, "save the suggested scores as the report's PerformanceScore and MemoryScore")
+	setBaseline := fs.Bool("set-baseline", false, "make this run the project's benchmark baseline")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		return fmt.Errorf("-output is required")
+	}
+
+	data, err := os.ReadFile(*output)
+	if err != nil {
+		return err
+	}
+	req := benchmarksRequest{Output: string(data), Apply: *apply, SetBaseline: *setBaseline}
+
+	var run benchmarkRun
+	raw, err := newClient(opts).call(http.MethodPost, reportPath(rest[0], "/evaluation/benchmarks"), nil, req, &run)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+
+	if len(run.Comparisons) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "BENCHMARK\tNS/OP\tTIME\tMEMORY\t\n")
+		for _, c := range run.Comparisons {
+			memory, flag := "-", ""
+			if c.HasMemory {
+				memory = fmt.Sprintf("%+.1f%%", c.BytesDelta)
+			}
+			if c.Regressed {
+				flag = "regressed"
+			}
+			fmt.Fprintf(w, "%s\t%.0f\t%+.1f%%\t%s\t%s\n", c.Key, c.NsPerOp, c.NsDelta, memory, flag)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+	fmt.Println(run.PerformanceDetails)
+	fmt.Println(run.MemoryDetails)
+	if run.Applied {
+		fmt.Printf("Scores saved to report %s\n", rest[0])
+	}
+	if run.IsBaseline {
+		fmt.Println("This run is now the project's benchmark baseline")
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+type securityRequest struct {
+	SARIF string `json:"sarif"`
+	Apply bool   `json:"apply,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type securityScan struct {
+	Tools    []string `json:"tools"`
+	New      int      `json:"new"`
+	Fixed    int      `json:"fixed"`
+	Reopened int      `json:"reopened"`
+	Findings []struct {
+		ID       string `json:"id"`
+		Tool     string `json:"tool"`
+		RuleID   string `json:"rule_id"`
+		Severity string `json:"severity"`
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Status   string `json:"status"`
+	} `json:"findings"`
+	BlocksSubmission bool   `json:"blocks_submission"`
+	SuggestedScore   int    `json:"suggested_score"`
+	Details          string `json:"details"`
+	Applied          bool   `json:"applied"`
+}
+
+// BE-OUT - External data involved
+func runSecurity(args []string) error {
+	fs, opts := newFlagSet("security")
+	file := fs.String("sarif", "", "SARIF 2.1 log or govulncheck -json output (required)")
+	apply := fs.Bool("apply", false, "save the suggested score as the report's SecurityScore")
+	all := fs.Bool("all", false, "list fixed and dismissed findings too")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("-sarif is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	req := securityRequest{SARIF: string(data), Apply: *apply}
+
+	var scan securityScan
+	raw, err := newClient(opts).call(http.MethodPost, reportPath(rest[0], "/evaluation/security"), nil, req, &scan)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEVERITY\tTOOL\tRULE\tLOCATION\tSTATUS")
+	for _, f := range scan.Findings {
+		if f.Status != "open" && !*all {
+			continue
+		}
+		location := f.File
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", f.ID, f.Severity, f.Tool, f.RuleID, location, f.Status)
+	}
+	w.Flush()
+	fmt.Printf("\n%d new, %d fixed, %d reopened\n%s\n", scan.New, scan.Fixed, scan.Reopened, scan.Details)
+	if scan.BlocksSubmission {
+		fmt.Println("Open critical findings block submission to the report's department")
+	}
+	if scan.Applied {
+		fmt.Printf("SecurityScore %d saved to report %s\n", scan.SuggestedScore, rest[0])
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+type loadRequest struct {
+	Results         string  `json:"results"`
+	Format          string  `json:"format,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	Apply           bool    `json:"apply,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type loadRun struct {
+	SuggestedScore int    `json:"suggested_score"`
+	Details        string `json:"details"`
+	Applied        bool   `json:"applied"`
+}
+
+// BE-OUT - External data involved
+func runLoad(args []string) error {
+	fs, opts := newFlagSet("load")
+	results := fs.String("results", "", "vegeta or k6 JSON, or a CSV of latency samples (required)")
+	format := fs.String("format", "", "vegeta, vegeta-results, k6 or csv (detected by default)")
+	duration := fs.Duration("duration", 0, "test duration for CSV samples without timestamps")
+	apply := fs.Bool("apply", false, "save the suggested score as the report's LoadScore")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *results == "" {
+		return fmt.Errorf("-results is required")
+	}
+
+	data, err := os.ReadFile(*results)
+	if err != nil {
+		return err
+	}
+	req := loadRequest{Results: string(data), Format: *format, DurationSeconds: duration.Seconds(), Apply: *apply}
+
+	var run loadRun
+	raw, err := newClient(opts).call(http.MethodPost, reportPath(rest[0], "/evaluation/load"), nil, req, &run)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+
+	fmt.Println(run.Details)
+	if run.Applied {
+		fmt.Printf("LoadScore %d saved to report %s\n", run.SuggestedScore, rest[0])
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Command reportctl is a terminal client for the report API. It creates,
+// lists, shows, evaluates and submits reports, and can create a report
+// from a YAML file or a Markdown file with YAML front matter.
+//
+// Usage:
+//
+//	reportctl create -file report.md
+//	reportctl create -title "Auth module" -project <id> -department <id> -tag BE-OUT-high
+//	reportctl list -status draft -tag-scope OUT
+//	reportctl show <report-id>
+//	reportctl evaluate -scores S8,P7,M6,T7,E8,L6 <report-id>
+//	reportctl submit <report-id>
+//	reportctl status <report-id>...
+//	reportctl coverage -profile cover.out -test-json test.json -apply <report-id>
+//	reportctl benchmarks -output bench.txt -apply <report-id>
+//	reportctl security -sarif gosec.sarif -apply <report-id>
+//	reportctl load -results vegeta.json -apply <report-id>
+//	reportctl receipt <report-id> > receipt.json
+//	reportctl verify -file receipt.json
+//	reportctl keygen -out author.key -register
+//	reportctl cosign -key author.key <report-id>
+//
+// Every command takes -url, -token and -json. The token defaults to
+// $REPORT_API_TOKEN and the URL to $REPORT_API_URL.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// options are the flags shared by every command.
+type options struct {
+	baseURL string
+	token   string
+	asJSON  bool
+}
+
+// BE-IN - Internal backend only
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(args []string) error
+}
+
+// BE-IN - Internal backend only
+var commands []command
+
+// BE-IN - Internal backend only
+func init() {
+	commands = []command{
+		{"create", "[flags]", "create a draft report from flags or a YAML/Markdown file", runCreate},
+		{"list", "[flags]", "list reports", runList},
+		{"show", "[flags] <report-id>", "show a report and its evaluation", runShow},
+		{"evaluate", "[flags] <report-id>", "record evaluation scores for a report", runEvaluate},
+		{"submit", "[flags] <report-id>", "submit a draft report to its department", runSubmit},
+		{"status", "[flags] <report-id>...", "show the lifecycle status of reports", runStatus},
+		{"coverage", "[flags] <report-id>", "upload Go coverage and test results to suggest a TestingScore", runCoverage},
+		{"benchmarks", "[flags] <report-id>", "upload Go benchmark output to suggest PerformanceScore and MemoryScore", runBenchmarks},
+		{"security", "[flags] <report-id>", "upload SARIF or govulncheck results to suggest a SecurityScore", runSecurity},
+		{"load", "[flags] <report-id>", "upload load-test results to suggest a LoadScore", runLoad},
+		{"receipt", "[flags] <report-id>", "print the signed receipt of a report's latest submission", runReceipt},
+		{"verify", "[flags] [-file receipt.json | <report-id>]", "verify a submission receipt against the published keys", runVerify},
+		{"keygen", "[flags] -out <file>", "generate an Ed25519 key for co-signing submissions", runKeygen},
+		{"cosign", "[flags] -key <file> <report-id>", "co-sign a report's latest submission as its author", runCosign},
+	}
+}
+
+// BE-OUT - External data involved
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "-help" || os.Args[1] == "help" {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(os.Args[2:]); err != nil {
+			if err == flag.ErrHelp {
+				os.Exit(2)
+			}
+			fatal(err)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "reportctl: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// BE-IN - Internal backend only
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: reportctl <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'reportctl <command> -h' for the flags of a command.")
+}
+
+// BE-IN - Internal backend only
+// newFlagSet returns a flag set for the named command with the shared
+// flags already registered.
+func newFlagSet(name string) (*flag.FlagSet, *options) {
+	var cmd command
+	for _, c := range commands {
+		if c.name == name {
+			cmd = c
+		}
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	opts := &options{}
+	fs.StringVar(&opts.baseURL, "url", envOr("REPORT_API_URL", "http://localhost:4000"), "report API base URL")
+	fs.StringVar(&opts.token, "token", os.Getenv("REPORT_API_TOKEN"), "API token (defaults to $REPORT_API_TOKEN)")
+	fs.BoolVar(&opts.asJSON, "json", false, "print the API response as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: reportctl %s %s\n\n%s\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs, opts
+}
+
+// BE-IN - Internal backend only
+// parseArgs parses flags and checks the number of positional arguments.
+// max < 0 means no upper bound.
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	rest := fs.Args()
+	if len(rest) < min || (max >= 0 && len(rest) > max) {
+		fs.Usage()
+		return nil, flag.ErrHelp
+	}
+	return rest, nil
+}
+
+// BE-IN - Internal backend only
+// stringList is a repeatable string flag.
+type stringList []string
+
+// BE-IN - Internal backend only
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// BE-IN - Internal backend only
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// BE-IN - Internal backend only
+func printJSON(raw []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// BE-IN - Internal backend only
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// BE-IN - Internal backend only
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "reportctl:", err)
+	os.Exit(1)
+}
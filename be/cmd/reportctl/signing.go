@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"encore.app/services/signing"
+)
+
+// BE-IN - Internal backend only
+type signingKeysResponse struct {
+	Keys []struct {
+		ID        string `json:"id"`
+		PublicKey string `json:"public_key"`
+		Active    bool   `json:"active"`
+	} `json:"keys"`
+}
+
+// BE-IN - Internal backend only
+type authorKeyResponse struct {
+	ID        string `json:"id"`
+	PublicKey string `json:"public_key"`
+}
+
+// BE-OUT - External data involved
+// runKeygen writes a new Ed25519 seed for co-signing, or for the server's
+// SUBMISSION_SIGNING_KEYS, and optionally registers its public key.
+func runKeygen(args []string) error {
+	fs, opts := newFlagSet("keygen")
+	out := fs.String("out", "", "file to write the base64 seed to (required)")
+	register := fs.Bool("register", false, "register the public key as your co-signing key")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("-out is required")
+	}
+
+	seed, err := signing.NewSeed()
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(file, base64.StdEncoding.EncodeToString(seed)); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	public := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	fmt.Printf("key %s written to %s\npublic key %s\n", signing.KeyID(public), *out, base64.StdEncoding.EncodeToString(public))
+	if *register {
+		return registerKey(newClient(opts), public)
+	}
+	return nil
+}
+
+// BE-OUT - External data involved
+// runCosign signs the latest receipt of a report with the author's key.
+func runCosign(args []string) error {
+	fs, opts := newFlagSet("cosign")
+	keyFile := fs.String("key", "", "file with the base64 seed written by keygen (required)")
+	register := fs.Bool("register", false, "register the key's public key before co-signing")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *keyFile == "" {
+		return fmt.Errorf("-key is required")
+	}
+
+	data, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+	private, err := signing.ParseSeed(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", *keyFile, err)
+	}
+
+	c := newClient(opts)
+	if *register {
+		if err := registerKey(c, private.Public().(ed25519.PublicKey)); err != nil {
+			return err
+		}
+	}
+
+	var receipt signing.Receipt
+	if _, err := c.call(http.MethodGet, reportPath(rest[0], "/receipt"), nil, nil, &receipt); err != nil {
+		return err
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, signing.Message(receipt.Digest)))
+
+	raw, err := c.call(http.MethodPost, reportPath(rest[0], "/receipt/cosign"), nil, map[string]string{"signature": signature}, &receipt)
+	if err != nil {
+		return err
+	}
+	if opts.asJSON {
+		return printJSON(raw)
+	}
+	fmt.Printf("co-signed submission of %s (digest %s) with key %s\n", rest[0], receipt.Digest, receipt.AuthorKeyID)
+	return nil
+}
+
+// BE-OUT - External data involved
+// runReceipt prints the signed receipt of a report's latest submission.
+// The snapshot is printed as stored, so the output verifies as is.
+func runReceipt(args []string) error {
+	fs, opts := newFlagSet("receipt")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	raw, err := newClient(opts).do(http.MethodGet, reportPath(rest[0], "/receipt"), nil, nil)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return err
+	}
+	fmt.Println(out.String())
+	return nil
+}
+
+// BE-OUT - External data involved
+// runVerify checks a receipt, from a file or fetched for a report, against
+// the published server and author keys. Verification happens locally
+// unless -server is given, which also compares it with the stored report.
+func runVerify(args []string) error {
+	fs, opts := newFlagSet("verify")
+	file := fs.String("file", "", "receipt file, such as the output of receipt or the signature of a department payload")
+	server := fs.Bool("server", false, "verify with the API, which also compares the snapshot with the stored report")
+	rest, err := parseArgs(fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+	if (*file == "") == (len(rest) == 0) {
+		fs.Usage()
+		return fmt.Errorf("give either -file or a report ID")
+	}
+
+	c := newClient(opts)
+	var receipt signing.Receipt
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &receipt); err != nil {
+			return fmt.Errorf("%s: %w", *file, err)
+		}
+	} else if _, err := c.call(http.MethodGet, reportPath(rest[0], "/receipt"), nil, nil, &receipt); err != nil {
+		return err
+	}
+
+	var result struct {
+		signing.Result
+		Current *bool `json:"current"`
+	}
+	var raw []byte
+	if *server {
+		if raw, err = c.call(http.MethodPost, "/api/receipts/verify", nil, map[string]interface{}{"receipt": receipt}, &result); err != nil {
+			return err
+		}
+	} else {
+		serverKeys, authorKey, err := fetchVerificationKeys(c, receipt.AuthorKeyID)
+		if err != nil {
+			return err
+		}
+		result.Result = *signing.Verify(&receipt, serverKeys, authorKey)
+		if raw, err = json.Marshal(result); err != nil {
+			return err
+		}
+	}
+
+	if opts.asJSON {
+		if err := printJSON(raw); err != nil {
+			return err
+		}
+	} else {
+		printVerification(&result.Result, result.Current)
+	}
+	if !result.Valid || (result.Current != nil && !*result.Current) {
+		return fmt.Errorf("receipt does not verify")
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func printVerification(result *signing.Result, current *bool) {
+	check := func(ok bool) string {
+		if ok {
+			return "ok"
+		}
+		return "FAILED"
+	}
+	fmt.Printf("digest            %s\n", check(result.DigestValid))
+	fmt.Printf("server signature  %s (key %s)\n", check(result.SignatureValid), result.KeyID)
+	if result.AuthorSigned {
+		fmt.Printf("author signature  %s\n", check(result.AuthorValid))
+	} else {
+		fmt.Println("author signature  not co-signed")
+	}
+	if current != nil {
+		fmt.Printf("stored report     %s\n", check(*current))
+	}
+	for _, problem := range result.Problems {
+		fmt.Println("  " + problem)
+	}
+}
+
+// BE-OUT - External data involved
+// fetchVerificationKeys downloads the published server keys and, when
+// authorKeyID is set, the author's public key. An unknown author key is
+// left nil so Verify reports it.
+func fetchVerificationKeys(c *client, authorKeyID string) (map[string]ed25519.PublicKey, ed25519.PublicKey, error) {
+	var keys signingKeysResponse
+	if _, err := c.call(http.MethodGet, "/api/signing/keys", nil, nil, &keys); err != nil {
+		return nil, nil, err
+	}
+	serverKeys := make(map[string]ed25519.PublicKey, len(keys.Keys))
+	for _, key := range keys.Keys {
+		public, err := signing.ParsePublicKey(key.PublicKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("server key %s: %w", key.ID, err)
+		}
+		serverKeys[key.ID] = public
+	}
+
+	if authorKeyID == "" {
+		return serverKeys, nil, nil
+	}
+	var author authorKeyResponse
+	if _, err := c.call(http.MethodGet, "/api/signing/author-keys/"+url.PathEscape(authorKeyID), nil, nil, &author); err != nil {
+		if strings.HasPrefix(err.Error(), "not_found:") {
+			return serverKeys, nil, nil
+		}
+		return nil, nil, err
+	}
+	public, err := signing.ParsePublicKey(author.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("author key %s: %w", authorKeyID, err)
+	}
+	return serverKeys, public, nil
+}
+
+// BE-OUT - External data involved
+func registerKey(c *client, public ed25519.PublicKey) error {
+	var key authorKeyResponse
+	body := map[string]string{"public_key": base64.StdEncoding.EncodeToString(public)}
+	if _, err := c.call(http.MethodPut, "/api/signing/author-key", nil, body, &key); err != nil {
+		return err
+	}
+	fmt.Printf("registered co-signing key %s\n", key.ID)
+	return nil
+}
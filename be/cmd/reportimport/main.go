@@ -0,0 +1,227 @@
+// Command reportimport bulk imports reports from a CSV or JSON Lines file
+// through the report API.
+//
+// Usage:
+//
+//	reportimport -file legacy.csv -dry-run
+//	reportimport -file legacy.csv -mode chunked -chunk-size 200
+//	reportimport -file legacy.csv -mode chunked -job <job-id>
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BE-IN - Internal backend only
+type importRequest struct {
+	Format    string  `json:"format"`
+	Data      string  `json:"data"`
+	DryRun    bool    `json:"dry_run,omitempty"`
+	Mode      string  `json:"mode,omitempty"`
+	ChunkSize int     `json:"chunk_size,omitempty"`
+	JobID     *string `json:"job_id,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type importResponse struct {
+	JobID     string `json:"job_id"`
+	Status    string `json:"status"`
+	TotalRows int    `json:"total_rows"`
+	ValidRows int    `json:"valid_rows"`
+	NextRow   int    `json:"next_row"`
+	Imported  int    `json:"imported"`
+	Failed    int    `json:"failed"`
+	Done      bool   `json:"done"`
+	Errors    []struct {
+		Row     int    `json:"row"`
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// BE-IN - Internal backend only
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// BE-OUT - External data involved
+func main() {
+	baseURL := flag.String("url", envOr("REPORT_API_URL", "http://localhost:4000"), "report API base URL")
+	token := flag.String("token", os.Getenv("REPORT_API_TOKEN"), "API token (defaults to $REPORT_API_TOKEN)")
+	file := flag.String("file", "", "CSV or JSON Lines file to import")
+	format := flag.String("format", "", "csv or jsonl (inferred from the file extension if empty)")
+	dryRun := flag.Bool("dry-run", false, "validate every row without importing")
+	mode := flag.String("mode", "atomic", "atomic or chunked")
+	chunkSize := flag.Int("chunk-size", 500, "rows per request in chunked mode")
+	jobID := flag.String("job", "", "resume a chunked import job")
+	flag.Parse()
+
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "reportimport: -file is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		fatal(err)
+	}
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*file)) {
+		case ".csv":
+			*format = "csv"
+		case ".jsonl", ".ndjson":
+			*format = "jsonl"
+		default:
+			fatal(fmt.Errorf("cannot infer format from %q, pass -format", *file))
+		}
+	}
+
+	req := importRequest{
+		Format:    *format,
+		Data:      string(data),
+		DryRun:    *dryRun,
+		Mode:      *mode,
+		ChunkSize: *chunkSize,
+	}
+	if *jobID != "" {
+		req.JobID = jobID
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	exitCode := 0
+	nextRow := 0
+	for {
+		resp, err := post(client, *baseURL+"/api/imports/reports", *token, req)
+		if err != nil {
+			if req.JobID != nil {
+				fmt.Fprintf(os.Stderr, "resume with: reportimport -file %s -mode chunked -job %s\n", *file, *req.JobID)
+			}
+			fatal(err)
+		}
+
+		for _, e := range resp.Errors {
+			exitCode = 1
+			if e.Field != "" {
+				fmt.Printf("row %d: %s: %s\n", e.Row, e.Field, e.Message)
+			} else {
+				fmt.Printf("row %d: %s\n", e.Row, e.Message)
+			}
+		}
+
+		if *mode == "chunked" && !*dryRun {
+			fmt.Printf("job %s: %d/%d rows processed, %d imported in this chunk, %d failed so far\n",
+				resp.JobID, resp.NextRow-1, resp.TotalRows, resp.Imported, resp.Failed)
+			req.JobID = &resp.JobID
+		}
+
+		if resp.Done {
+			fmt.Printf("%s: %d rows, %d valid, %d failed\n", resp.Status, resp.TotalRows, resp.ValidRows, resp.Failed)
+			break
+		}
+
+		// Posting the same chunk again would not get further
+		if resp.NextRow <= nextRow {
+			fmt.Fprintf(os.Stderr, "resume with: reportimport -file %s -mode chunked -job %s\n", *file, resp.JobID)
+			fatal(fmt.Errorf("import stopped at row %d", resp.NextRow))
+		}
+		nextRow = resp.NextRow
+	}
+
+	os.Exit(exitCode)
+}
+
+// BE-IN - Internal backend only
+// maxRateLimitRetries bounds how often a request refused with 429 is
+// sent again
+const maxRateLimitRetries = 10
+
+// BE-OUT - External data involved
+// post sends one import request. A 429 response is retried after its
+// Retry-After delay, so chunked imports pace themselves to the server's
+// quota instead of failing.
+func post(client *http.Client, url, token string, body importRequest) (*importResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		httpResp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if httpResp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			wait := retryAfter(httpResp.Header.Get("Retry-After"))
+			fmt.Fprintf(os.Stderr, "rate limited, retrying in %s\n", wait)
+			time.Sleep(wait)
+			continue
+		}
+
+		if httpResp.StatusCode != http.StatusOK {
+			var apiErr apiError
+			if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+				return nil, fmt.Errorf("%s: %s", apiErr.Code, apiErr.Message)
+			}
+			return nil, fmt.Errorf("unexpected status %s", httpResp.Status)
+		}
+
+		var resp importResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	}
+}
+
+// BE-IN - Internal backend only
+// retryAfter reads a Retry-After header in seconds, waiting a second when
+// it is missing or not a number of seconds.
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 1 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// BE-IN - Internal backend only
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// BE-IN - Internal backend only
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "reportimport:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,230 @@
+// Command scorescan extracts // Score: annotations from a Go source tree,
+// reports annotation problems and prints the evaluation they add up to.
+// With -report it uploads the sources to the report API instead, which
+// can also apply the proposed evaluation to the report.
+//
+// Usage:
+//
+//	scorescan ./be
+//	scorescan -json ./be
+//	scorescan -report <report-id> -apply ./be
+//
+// The exit code is 1 when annotation errors are found (or warnings, with
+// -strict), so the command can gate CI.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"encore.app/services/scorescan"
+)
+
+// BE-IN - Internal backend only
+type scanRequest struct {
+	Files []scorescan.Source `json:"files"`
+	Apply bool               `json:"apply,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type scanResponse struct {
+	Files       int               `json:"files"`
+	Functions   int               `json:"functions"`
+	Annotations []json.RawMessage `json:"annotations"`
+	Issues      []scorescan.Issue `json:"issues"`
+	Proposed    struct {
+		SecurityScore      int    `json:"security_score"`
+		PerformanceScore   int    `json:"performance_score"`
+		MemoryScore        int    `json:"memory_score"`
+		TestingScore       int    `json:"testing_score"`
+		ErrorScore         int    `json:"error_score"`
+		LoadScore          int    `json:"load_score"`
+		SecurityDetails    string `json:"security_details"`
+		PerformanceDetails string `json:"performance_details"`
+		MemoryDetails      string `json:"memory_details"`
+		TestingDetails     string `json:"testing_details"`
+		ErrorDetails       string `json:"error_details"`
+		LoadDetails        string `json:"load_details"`
+	} `json:"proposed"`
+	Applied bool `json:"applied"`
+}
+
+// BE-IN - Internal backend only
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// BE-OUT - External data involved
+func main() {
+	asJSON := flag.Bool("json", false, "print the scan result as JSON")
+	strict := flag.Bool("strict", false, "exit with status 1 on warnings as well as errors")
+	reportID := flag.String("report", "", "upload the sources and propose an evaluation for this report")
+	apply := flag.Bool("apply", false, "with -report, save the proposed evaluation to the report")
+	baseURL := flag.String("url", envOr("REPORT_API_URL", "http://localhost:4000"), "report API base URL")
+	token := flag.String("token", os.Getenv("REPORT_API_TOKEN"), "API token (defaults to $REPORT_API_TOKEN)")
+	flag.Parse()
+
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+	if *apply && *reportID == "" {
+		fatal(fmt.Errorf("-apply requires -report"))
+	}
+
+	if *reportID != "" {
+		upload(*baseURL, *token, *reportID, root, *apply, *asJSON, *strict)
+		return
+	}
+
+	result, err := scorescan.ScanDir(root)
+	if err != nil {
+		fatal(err)
+	}
+
+	if *asJSON {
+		out := struct {
+			*scorescan.Result
+			Proposed interface{} `json:"proposed"`
+		}{result, scorescan.ProposeEvaluation(result.Annotations)}
+		printJSON(out)
+	} else {
+		printResult(result)
+	}
+
+	os.Exit(exitCode(result.Issues, *strict))
+}
+
+// BE-OUT - External data involved
+// upload sends the source tree to the scan endpoint and prints the
+// server's result.
+func upload(baseURL, token, reportID, root string, apply, asJSON, strict bool) {
+	files, err := scorescan.ReadDir(root)
+	if err != nil {
+		fatal(err)
+	}
+
+	payload, err := json.Marshal(scanRequest{Files: files, Apply: apply})
+	if err != nil {
+		fatal(err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, baseURL+"/api/reports/"+reportID+"/evaluation/scan", bytes.NewReader(payload))
+	if err != nil {
+		fatal(err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		fatal(err)
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		fatal(err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			fatal(fmt.Errorf("%s: %s", apiErr.Code, apiErr.Message))
+		}
+		fatal(fmt.Errorf("unexpected status %s", httpResp.Status))
+	}
+
+	var resp scanResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		fatal(err)
+	}
+
+	if asJSON {
+		os.Stdout.Write(raw)
+		fmt.Println()
+	} else {
+		for _, issue := range resp.Issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("%d files, %d functions, %d annotated\n", resp.Files, resp.Functions, len(resp.Annotations))
+		p := resp.Proposed
+		fmt.Printf("proposed: S%d P%d M%d T%d E%d L%d\n",
+			p.SecurityScore, p.PerformanceScore, p.MemoryScore, p.TestingScore, p.ErrorScore, p.LoadScore)
+		if resp.Applied {
+			fmt.Printf("evaluation saved to report %s\n", reportID)
+		}
+	}
+
+	os.Exit(exitCode(resp.Issues, strict))
+}
+
+// BE-IN - Internal backend only
+func printResult(result *scorescan.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FUNCTION\tS\tP\tM\tT\tE\tL\tTAGS\tLOCATION")
+	for _, a := range result.Annotations {
+		fmt.Fprintf(w, "%s.%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\t%s:%d\n",
+			a.Package, a.Function, a.Scores[0], a.Scores[1], a.Scores[2], a.Scores[3], a.Scores[4], a.Scores[5],
+			strings.Join(a.Tags, ","), a.File, a.Line)
+	}
+	w.Flush()
+
+	if len(result.Issues) > 0 {
+		fmt.Println()
+		for _, issue := range result.Issues {
+			fmt.Println(issue)
+		}
+	}
+
+	fmt.Printf("\n%d files, %d functions, %d annotated\n", result.Files, result.Functions, len(result.Annotations))
+	if len(result.Annotations) > 0 {
+		p := scorescan.ProposeEvaluation(result.Annotations)
+		fmt.Printf("proposed: S%d P%d M%d T%d E%d L%d\n",
+			p.SecurityScore, p.PerformanceScore, p.MemoryScore, p.TestingScore, p.ErrorScore, p.LoadScore)
+	}
+}
+
+// BE-IN - Internal backend only
+func exitCode(issues []scorescan.Issue, strict bool) int {
+	for _, issue := range issues {
+		if issue.Severity == scorescan.SeverityError || strict {
+			return 1
+		}
+	}
+	return 0
+}
+
+// BE-IN - Internal backend only
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fatal(err)
+	}
+}
+
+// BE-IN - Internal backend only
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// BE-IN - Internal backend only
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "scorescan:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,148 @@
+// Package events defines the report domain events and the Pub/Sub topics
+// they are published on. Handlers publish; reactions such as department
+// delivery, notifications and analytics subscribe.
+package events
+
+import (
+	"time"
+
+	"encore.dev/pubsub"
+)
+
+// BE-IN - Internal backend only
+// Event names, shared by analytics and the event payloads.
+const (
+	NameReportCreated       = "report.created"
+	NameReportSubmitted     = "report.submitted"
+	NameEvaluationRecorded  = "evaluation.recorded"
+	NameReportApproved      = "report.approved"
+	NameReportRejected      = "report.rejected"
+	NameSubmissionDelivered = "submission.delivered"
+)
+
+// BE-IN - Internal backend only
+// Names lists every event name, in lifecycle order.
+var Names = []string{
+	NameReportCreated,
+	NameReportSubmitted,
+	NameEvaluationRecorded,
+	NameReportApproved,
+	NameReportRejected,
+	NameSubmissionDelivered,
+}
+
+// BE-IN - Internal backend only
+// Sources of a ReportCreated event.
+const (
+	SourceAPI    = "api"
+	SourceImport = "import"
+)
+
+// BE-IN - Internal backend only
+// Events carry identifiers and the fields subscribers filter on.
+// Subscribers load the current report state themselves, so a redelivered
+// message never acts on stale content.
+type ReportCreated struct {
+	ReportID     string    `json:"report_id"`
+	ProjectID    string    `json:"project_id"`
+	DepartmentID string    `json:"department_id"`
+	AuthorID     string    `json:"author_id"`
+	TemplateID   string    `json:"template_id,omitempty"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+type ReportSubmitted struct {
+	ReportID     string    `json:"report_id"`
+	ProjectID    string    `json:"project_id"`
+	DepartmentID string    `json:"department_id"`
+	AuthorID     string    `json:"author_id"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+}
+
+// BE-IN - Internal backend only
+type EvaluationRecorded struct {
+	ReportID     string    `json:"report_id"`
+	EvaluationID string    `json:"evaluation_id"`
+	ProjectID    string    `json:"project_id"`
+	DepartmentID string    `json:"department_id"`
+	EvaluatorID  string    `json:"evaluator_id"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// BE-IN - Internal backend only
+type ReportApproved struct {
+	ReportID     string    `json:"report_id"`
+	ProjectID    string    `json:"project_id"`
+	DepartmentID string    `json:"department_id"`
+	ReviewerID   string    `json:"reviewer_id"`
+	Reason       string    `json:"reason,omitempty"`
+	ApprovedAt   time.Time `json:"approved_at"`
+}
+
+// BE-IN - Internal backend only
+type ReportRejected struct {
+	ReportID     string    `json:"report_id"`
+	ProjectID    string    `json:"project_id"`
+	DepartmentID string    `json:"department_id"`
+	ReviewerID   string    `json:"reviewer_id"`
+	Reason       string    `json:"reason"`
+	RejectedAt   time.Time `json:"rejected_at"`
+}
+
+// BE-IN - Internal backend only
+type SubmissionDelivered struct {
+	ReportID     string    `json:"report_id"`
+	ProjectID    string    `json:"project_id"`
+	DepartmentID string    `json:"department_id"`
+	Attempt      int       `json:"attempt"`
+	DeliveredAt  time.Time `json:"delivered_at"`
+}
+
+// BE-IN - Internal backend only
+// CommentCreated is not a lifecycle event: it is left out of Names, so
+// webhooks cannot subscribe to it.
+const NameCommentCreated = "comment.created"
+
+// BE-IN - Internal backend only
+type CommentCreated struct {
+	CommentID string    `json:"comment_id"`
+	ReportID  string    `json:"report_id"`
+	AuthorID  string    `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BE-OUT - External data involved
+// Topics for the report lifecycle. Every topic is at-least-once, so
+// subscribers must tolerate redelivery.
+var (
+	ReportCreatedTopic = pubsub.NewTopic[*ReportCreated]("report-created", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	ReportSubmittedTopic = pubsub.NewTopic[*ReportSubmitted]("report-submitted", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	EvaluationRecordedTopic = pubsub.NewTopic[*EvaluationRecorded]("evaluation-recorded", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	ReportApprovedTopic = pubsub.NewTopic[*ReportApproved]("report-approved", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	ReportRejectedTopic = pubsub.NewTopic[*ReportRejected]("report-rejected", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	SubmissionDeliveredTopic = pubsub.NewTopic[*SubmissionDelivered]("submission-delivered", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+)
+
+// BE-OUT - External data involved
+var CommentCreatedTopic = pubsub.NewTopic[*CommentCreated]("comment-created", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrAnalysisRunNotFound = errors.New("analysis run not found")
+)
+
+// BE-IN - Internal backend only
+// AnalysisRun is a static analysis of uploaded Go sources reduced to its
+// module-wide metrics, with the evaluation suggested from them. The
+// locations behind each metric are named in the suggested details.
+type AnalysisRun struct {
+	ID                       string      `json:"id"`
+	ReportID                 string      `json:"report_id"`
+	UploadedBy               string      `json:"uploaded_by"`
+	Files                    int         `json:"files"`
+	TestFiles                int         `json:"test_files"`
+	Packages                 int         `json:"packages"`
+	CodeLines                int         `json:"code_lines"`
+	TestLines                int         `json:"test_lines"`
+	Functions                int         `json:"functions"`
+	TestToCodeRatio          float64     `json:"test_to_code_ratio"`
+	AverageComplexity        float64     `json:"average_complexity"`
+	MaxComplexity            int         `json:"max_complexity"`
+	ComplexFunctions         int         `json:"complex_functions"`
+	UncheckedErrors          int         `json:"unchecked_errors"`
+	Panics                   int         `json:"panics"`
+	Goroutines               int         `json:"goroutines"`
+	GoroutinesWithoutContext int         `json:"goroutines_without_context"`
+	ExportedDeclarations     int         `json:"exported_declarations"`
+	TotalDeclarations        int         `json:"total_declarations"`
+	ParseErrors              int         `json:"parse_errors"`
+	Suggested                *Evaluation `json:"suggested"`
+	Applied                  bool        `json:"applied"`
+	CreatedAt                time.Time   `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes; only the latest run of each
+// report is kept
+var (
+	analysisMu         sync.RWMutex
+	analysisByReportID = make(map[string]*AnalysisRun)
+)
+
+// BE-IN - Internal backend only
+func SaveAnalysisRun(ctx context.Context, run *AnalysisRun) error {
+	// Score: [S6,P8,M7,T5,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P8): Single map write
+	// - Memory (M7): Replaces the previous run of the report
+	// - Testing (T5): Only exercised through the api analysis tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	analysisMu.Lock()
+	defer analysisMu.Unlock()
+	analysisByReportID[run.ReportID] = run
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetAnalysisRunByReportID(ctx context.Context, reportID string) (*AnalysisRun, error) {
+	analysisMu.RLock()
+	defer analysisMu.RUnlock()
+
+	run, ok := analysisByReportID[reportID]
+	if !ok {
+		return nil, ErrAnalysisRunNotFound
+	}
+	return run, nil
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BE-IN - Internal backend only
+// ActivityEvent is one lifecycle event as seen by analytics. Key
+// identifies the event so redelivered messages are only counted once.
+// StartsReview and EndsReview mark the events review time is measured
+// between.
+type ActivityEvent struct {
+	Key          string
+	Name         string
+	ReportID     string
+	DepartmentID string
+	At           time.Time
+	StartsReview bool
+	EndsReview   bool
+}
+
+// BE-IN - Internal backend only
+type DepartmentActivity struct {
+	DepartmentID   string         `json:"department_id"`
+	Events         map[string]int `json:"events"`
+	Reviewed       int            `json:"reviewed"`
+	AvgReviewHours float64        `json:"avg_review_hours"`
+}
+
+// BE-IN - Internal backend only
+type ActivitySummary struct {
+	Events      map[string]int        `json:"events"`
+	Departments []*DepartmentActivity `json:"departments"`
+}
+
+// BE-IN - Internal backend only
+// Activity counters, fed by the domain event subscribers
+var (
+	activityMu        sync.Mutex
+	activitySeen      = make(map[string]bool)
+	activityTotals    = make(map[string]int)
+	activityByDept    = make(map[string]*departmentCounters)
+	activitySubmitted = make(map[string]time.Time)
+)
+
+// BE-IN - Internal backend only
+type departmentCounters struct {
+	events      map[string]int
+	reviewed    int
+	reviewTotal time.Duration
+}
+
+// BE-IN - Internal backend only
+// RecordActivity counts a lifecycle event. Review time is measured from
+// the most recent submission of a report to its approval or rejection.
+func RecordActivity(ctx context.Context, event ActivityEvent) error {
+	// Score: [S7,P8,M6,T7,E7,L7]
+	// Details:
+	// - Security (S7): Only fed by internal subscribers
+	// - Performance (P8): Constant time per event
+	// - Memory (M6): Dedup keys are kept for the process lifetime
+	// - Testing (T7): Redelivery and review time measurement tested
+	// - Error (E7): Redelivered events ignored
+	// - Load (L7): Single lock around small updates
+	// Tags: BE-DB-medium
+
+	activityMu.Lock()
+	defer activityMu.Unlock()
+
+	if event.Key != "" {
+		if activitySeen[event.Key] {
+			return nil
+		}
+		activitySeen[event.Key] = true
+	}
+
+	activityTotals[event.Name]++
+
+	counters, ok := activityByDept[event.DepartmentID]
+	if !ok {
+		counters = &departmentCounters{events: make(map[string]int)}
+		activityByDept[event.DepartmentID] = counters
+	}
+	counters.events[event.Name]++
+
+	switch {
+	case event.StartsReview:
+		activitySubmitted[event.ReportID] = event.At
+	case event.EndsReview:
+		if submittedAt, ok := activitySubmitted[event.ReportID]; ok && event.At.After(submittedAt) {
+			counters.reviewed++
+			counters.reviewTotal += event.At.Sub(submittedAt)
+			delete(activitySubmitted, event.ReportID)
+		}
+	}
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetActivitySummary(ctx context.Context) (*ActivitySummary, error) {
+	activityMu.Lock()
+	defer activityMu.Unlock()
+
+	summary := &ActivitySummary{
+		Events:      make(map[string]int, len(activityTotals)),
+		Departments: make([]*DepartmentActivity, 0, len(activityByDept)),
+	}
+	for name, count := range activityTotals {
+		summary.Events[name] = count
+	}
+
+	for departmentID, counters := range activityByDept {
+		department := &DepartmentActivity{
+			DepartmentID: departmentID,
+			Events:       make(map[string]int, len(counters.events)),
+			Reviewed:     counters.reviewed,
+		}
+		for name, count := range counters.events {
+			department.Events[name] = count
+		}
+		if counters.reviewed > 0 {
+			department.AvgReviewHours = counters.reviewTotal.Hours() / float64(counters.reviewed)
+		}
+		summary.Departments = append(summary.Departments, department)
+	}
+	sort.Slice(summary.Departments, func(i, j int) bool {
+		return summary.Departments[i].DepartmentID < summary.Departments[j].DepartmentID
+	})
+
+	return summary, nil
+}
+
+// BE-IN - Internal backend only
+// Groupings for ScoresByTag
+const (
+	TagGroupTag    = "tag"
+	TagGroupType   = "type"
+	TagGroupScope  = "scope"
+	TagGroupImpact = "impact"
+)
+
+// BE-IN - Internal backend only
+// TagScores aggregates the evaluations of the reports carrying a tag, or
+// a tag component when grouped by type, scope or impact.
+type TagScores struct {
+	Key              string  `json:"key"`
+	Reports          int     `json:"reports"`
+	Evaluated        int     `json:"evaluated"`
+	SecurityScore    float64 `json:"security_score"`
+	PerformanceScore float64 `json:"performance_score"`
+	MemoryScore      float64 `json:"memory_score"`
+	TestingScore     float64 `json:"testing_score"`
+	ErrorScore       float64 `json:"error_score"`
+	LoadScore        float64 `json:"load_score"`
+	OverallScore     float64 `json:"overall_score"`
+}
+
+// BE-IN - Internal backend only
+// ScoresByTag averages evaluation scores per tag. Only tags passing filter
+// are counted, and a report counts once per group even if several of its
+// tags fall into it. Averages cover evaluated reports only.
+func ScoresByTag(ctx context.Context, filter TagFilter, groupBy string) ([]*TagScores, error) {
+	// Score: [S6,P6,M7,T7,E7,L6]
+	// Details:
+	// - Security (S6): Aggregates only, no report content returned
+	// - Performance (P6): Full scan of reports per request
+	// - Memory (M7): One accumulator per group
+	// - Testing (T7): Groupings, filters and once-per-group counting tested
+	// - Error (E7): Unknown groupings rejected
+	// - Load (L6): Intended for dashboards, not hot paths
+	// Tags: BE-DB-medium
+
+	key := func(tag Tag) string { return tag.String() }
+	switch groupBy {
+	case "", TagGroupTag:
+	case TagGroupType:
+		key = func(tag Tag) string { return tag.Type }
+	case TagGroupScope:
+		key = func(tag Tag) string { return tag.Scope }
+	case TagGroupImpact:
+		key = func(tag Tag) string { return tag.Impact }
+	default:
+		return nil, fmt.Errorf("unknown grouping %q", groupBy)
+	}
+
+	groups := make(map[string]*TagScores)
+	for _, report := range reports {
+		if report.DeletedAt != nil {
+			continue
+		}
+		evaluation := evaluationsByReportID[report.ID]
+
+		seen := make(map[string]bool)
+		for _, tag := range report.Tags {
+			if !filter.Matches(tag) {
+				continue
+			}
+			k := key(tag)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+
+			group, ok := groups[k]
+			if !ok {
+				group = &TagScores{Key: k}
+				groups[k] = group
+			}
+			group.Reports++
+			if evaluation != nil {
+				group.Evaluated++
+				group.SecurityScore += float64(evaluation.SecurityScore)
+				group.PerformanceScore += float64(evaluation.PerformanceScore)
+				group.MemoryScore += float64(evaluation.MemoryScore)
+				group.TestingScore += float64(evaluation.TestingScore)
+				group.ErrorScore += float64(evaluation.ErrorScore)
+				group.LoadScore += float64(evaluation.LoadScore)
+			}
+		}
+	}
+
+	result := make([]*TagScores, 0, len(groups))
+	for _, group := range groups {
+		if group.Evaluated > 0 {
+			n := float64(group.Evaluated)
+			group.SecurityScore /= n
+			group.PerformanceScore /= n
+			group.MemoryScore /= n
+			group.TestingScore /= n
+			group.ErrorScore /= n
+			group.LoadScore /= n
+			group.OverallScore = (group.SecurityScore + group.PerformanceScore + group.MemoryScore +
+				group.TestingScore + group.ErrorScore + group.LoadScore) / 6
+		}
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordActivity(t *testing.T) {
+	ctx := context.Background()
+	const dept = "dept-activity-test"
+	submitted := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []ActivityEvent{
+		{Key: "e1", Name: "report.submitted", ReportID: "r1", StartsReview: true, At: submitted},
+		// Redelivery of the same event is not counted again
+		{Key: "e1", Name: "report.submitted", ReportID: "r1", StartsReview: true, At: submitted},
+		{Key: "e2", Name: "report.approved", ReportID: "r1", EndsReview: true, At: submitted.Add(6 * time.Hour)},
+		{Key: "e3", Name: "report.submitted", ReportID: "r2", StartsReview: true, At: submitted},
+		{Key: "e4", Name: "report.rejected", ReportID: "r2", EndsReview: true, At: submitted.Add(2 * time.Hour)},
+		// A decision without a submission, or dated before it, has no review time
+		{Key: "e5", Name: "report.approved", ReportID: "r3", EndsReview: true, At: submitted},
+		{Key: "e6", Name: "report.submitted", ReportID: "r4", StartsReview: true, At: submitted},
+		{Key: "e7", Name: "report.approved", ReportID: "r4", EndsReview: true, At: submitted.Add(-time.Hour)},
+	}
+	for _, event := range events {
+		event.DepartmentID = dept
+		if err := RecordActivity(ctx, event); err != nil {
+			t.Fatalf("RecordActivity: %v", err)
+		}
+	}
+
+	summary, err := GetActivitySummary(ctx)
+	if err != nil {
+		t.Fatalf("GetActivitySummary: %v", err)
+	}
+	var got *DepartmentActivity
+	for _, department := range summary.Departments {
+		if department.DepartmentID == dept {
+			got = department
+		}
+	}
+	if got == nil {
+		t.Fatalf("no activity recorded for %s", dept)
+	}
+
+	want := &DepartmentActivity{
+		DepartmentID:   dept,
+		Events:         map[string]int{"report.submitted": 3, "report.approved": 3, "report.rejected": 1},
+		Reviewed:       2,
+		AvgReviewHours: 4,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("activity = %+v, want %+v", got, want)
+	}
+}
+
+func TestScoresByTag(t *testing.T) {
+	ctx := context.Background()
+
+	// Sample data only uses BE tags, so FE groups hold just these reports
+	save := func(tags []Tag, score int) {
+		t.Helper()
+		report := &Report{Title: "Tagged", ProjectID: "project-tags", Status: "draft", Tags: tags}
+		if err := SaveReport(ctx, report); err != nil {
+			t.Fatalf("SaveReport: %v", err)
+		}
+		t.Cleanup(func() { DeleteReport(ctx, report.ID) })
+		if score == 0 {
+			return
+		}
+		evaluation := &Evaluation{
+			ReportID: report.ID, SecurityScore: score, PerformanceScore: score, MemoryScore: score,
+			TestingScore: score, ErrorScore: score, LoadScore: score,
+		}
+		if err := SaveEvaluation(ctx, evaluation); err != nil {
+			t.Fatalf("SaveEvaluation: %v", err)
+		}
+	}
+	save([]Tag{{"FE", "IN", "low"}}, 6)
+	save([]Tag{{"FE", "IN", "low"}, {"FE", "IN", "high"}}, 2)
+	save([]Tag{{"FE", "OUT", "low"}}, 0)
+
+	type group struct {
+		Key                string
+		Reports, Evaluated int
+		Overall            float64
+	}
+	tests := []struct {
+		name    string
+		filter  TagFilter
+		groupBy string
+		want    []group
+	}{
+		{"by tag", TagFilter{Type: "FE"}, TagGroupTag, []group{{"FE-IN-high", 1, 1, 2}, {"FE-IN-low", 2, 2, 4}, {"FE-OUT-low", 1, 0, 0}}},
+		// A report with two IN tags counts once towards IN
+		{"by scope", TagFilter{Type: "FE"}, TagGroupScope, []group{{"IN", 2, 2, 4}, {"OUT", 1, 0, 0}}},
+		{"by impact within a scope", TagFilter{Type: "FE", Scope: "IN"}, TagGroupImpact, []group{{"high", 1, 1, 2}, {"low", 2, 2, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores, err := ScoresByTag(ctx, tt.filter, tt.groupBy)
+			if err != nil {
+				t.Fatalf("ScoresByTag: %v", err)
+			}
+			var got []group
+			for _, s := range scores {
+				got = append(got, group{s.Key, s.Reports, s.Evaluated, s.OverallScore})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groups = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ScoresByTag(ctx, TagFilter{}, "author"); err == nil {
+		t.Error("ScoresByTag accepted grouping by author")
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+// Audit actions
+const (
+	AuditRetentionPolicySet     = "retention_policy.set"
+	AuditRetentionPolicyDeleted = "retention_policy.deleted"
+	AuditLegalHoldPlaced        = "legal_hold.placed"
+	AuditLegalHoldReleased      = "legal_hold.released"
+	AuditReportDeleted          = "report.deleted"
+	AuditReportRestored         = "report.restored"
+	AuditReportArchived         = "report.archived"
+	AuditReportPurged           = "report.purged"
+)
+
+// BE-IN - Internal backend only
+// AuditActorSystem is the actor of scheduled jobs
+const AuditActorSystem = "system"
+
+// BE-IN - Internal backend only
+// AuditEntry records one retention, legal-hold or deletion action. Entries
+// are append-only and outlive the reports they describe.
+type AuditEntry struct {
+	ID           string            `json:"id"`
+	Action       string            `json:"action"`
+	ActorID      string            `json:"actor_id"`
+	DepartmentID string            `json:"department_id"`
+	ProjectID    string            `json:"project_id,omitempty"`
+	ReportID     string            `json:"report_id,omitempty"`
+	Details      map[string]string `json:"details,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+type AuditFilter struct {
+	DepartmentID string
+	ReportID     string
+	Action       string
+	Limit        int
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes; entries are kept in the order
+// they were recorded
+var (
+	auditMu      sync.RWMutex
+	auditEntries []*AuditEntry
+)
+
+// BE-IN - Internal backend only
+func SaveAuditEntry(ctx context.Context, entry *AuditEntry) error {
+	// Score: [S8,P8,M6,T5,E7,L7]
+	// Details:
+	// - Security (S8): Append-only, no update or delete
+	// - Performance (P8): Single append
+	// - Memory (M6): Grows with every audited action
+	// - Testing (T5): Only exercised through the api audit tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	entry.CreatedAt = time.Now()
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditEntries = append(auditEntries, entry)
+	return nil
+}
+
+// BE-IN - Internal backend only
+// ListAuditEntries returns the matching entries, newest first.
+func ListAuditEntries(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+
+	var result []AuditEntry
+	for i := len(auditEntries) - 1; i >= 0; i-- {
+		entry := auditEntries[i]
+		if filter.DepartmentID != "" && entry.DepartmentID != filter.DepartmentID {
+			continue
+		}
+		if filter.ReportID != "" && entry.ReportID != filter.ReportID {
+			continue
+		}
+		if filter.Action != "" && entry.Action != filter.Action {
+			continue
+		}
+		result = append(result, *entry)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
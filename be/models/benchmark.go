@@ -0,0 +1,136 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrBenchmarkRunNotFound = errors.New("benchmark run not found")
+)
+
+// BE-IN - Internal backend only
+type BenchmarkResult struct {
+	Package     string  `json:"package"`
+	Name        string  `json:"name"`
+	Procs       int     `json:"procs"`
+	Runs        int     `json:"runs"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	HasMemory   bool    `json:"has_memory"`
+}
+
+// BE-IN - Internal backend only
+type BenchmarkComparison struct {
+	Key            string  `json:"key"`
+	NsPerOp        float64 `json:"ns_per_op"`
+	BaselineNsOp   float64 `json:"baseline_ns_per_op"`
+	NsDelta        float64 `json:"ns_delta"`
+	BytesPerOp     float64 `json:"bytes_per_op"`
+	BaselineBytes  float64 `json:"baseline_bytes_per_op"`
+	BytesDelta     float64 `json:"bytes_delta"`
+	AllocsPerOp    float64 `json:"allocs_per_op"`
+	BaselineAllocs float64 `json:"baseline_allocs_per_op"`
+	AllocsDelta    float64 `json:"allocs_delta"`
+	HasMemory      bool    `json:"has_memory"`
+	Regressed      bool    `json:"regressed"`
+}
+
+// BE-IN - Internal backend only
+// BenchmarkRun is an uploaded benchmark output, compared with the
+// project's baseline at upload time.
+type BenchmarkRun struct {
+	ID                 string                `json:"id"`
+	ReportID           string                `json:"report_id"`
+	ProjectID          string                `json:"project_id"`
+	UploadedBy         string                `json:"uploaded_by"`
+	GoOS               string                `json:"goos,omitempty"`
+	GoArch             string                `json:"goarch,omitempty"`
+	CPU                string                `json:"cpu,omitempty"`
+	Results            []BenchmarkResult     `json:"results"`
+	BaselineRunID      string                `json:"baseline_run_id,omitempty"`
+	BaselineReportID   string                `json:"baseline_report_id,omitempty"`
+	Comparisons        []BenchmarkComparison `json:"comparisons"`
+	Unmatched          int                   `json:"unmatched"`
+	Regressions        int                   `json:"regressions"`
+	PerformanceScore   *int                  `json:"performance_score,omitempty"`
+	MemoryScore        *int                  `json:"memory_score,omitempty"`
+	PerformanceDetails string                `json:"performance_details"`
+	MemoryDetails      string                `json:"memory_details"`
+	Applied            bool                  `json:"applied"`
+	CreatedAt          time.Time             `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes. The latest run of each report and
+// the baseline run of each project are kept.
+var (
+	benchmarkMu                sync.RWMutex
+	benchmarkRunsByReportID    = make(map[string]*BenchmarkRun)
+	benchmarkBaselineByProject = make(map[string]*BenchmarkRun)
+)
+
+// BE-IN - Internal backend only
+func SaveBenchmarkRun(ctx context.Context, run *BenchmarkRun) error {
+	// Score: [S6,P8,M7,T5,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P8): Single map write
+	// - Memory (M7): Replaces the previous run of the report
+	// - Testing (T5): Only exercised through the api benchmark tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	benchmarkMu.Lock()
+	defer benchmarkMu.Unlock()
+	benchmarkRunsByReportID[run.ReportID] = run
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetBenchmarkRunByReportID(ctx context.Context, reportID string) (*BenchmarkRun, error) {
+	benchmarkMu.RLock()
+	defer benchmarkMu.RUnlock()
+
+	run, ok := benchmarkRunsByReportID[reportID]
+	if !ok {
+		return nil, ErrBenchmarkRunNotFound
+	}
+	return run, nil
+}
+
+// BE-IN - Internal backend only
+// SetBenchmarkBaseline makes run the baseline later runs of its project
+// are compared with.
+func SetBenchmarkBaseline(ctx context.Context, run *BenchmarkRun) error {
+	benchmarkMu.Lock()
+	defer benchmarkMu.Unlock()
+	benchmarkBaselineByProject[run.ProjectID] = run
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetBenchmarkBaseline(ctx context.Context, projectID string) (*BenchmarkRun, error) {
+	benchmarkMu.RLock()
+	defer benchmarkMu.RUnlock()
+
+	run, ok := benchmarkBaselineByProject[projectID]
+	if !ok {
+		return nil, ErrBenchmarkRunNotFound
+	}
+	return run, nil
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+// BE-IN - Internal backend only
+// Evaluation dimensions a comment can be attached to. An empty dimension
+// means the comment is about the report as a whole.
+var EvaluationDimensions = []string{"security", "performance", "memory", "testing", "error", "load"}
+
+// BE-IN - Internal backend only
+type Comment struct {
+	ID         string     `json:"id"`
+	ReportID   string     `json:"report_id"`
+	ParentID   string     `json:"parent_id,omitempty"`
+	Dimension  string     `json:"dimension,omitempty"`
+	AuthorID   string     `json:"author_id"`
+	Body       string     `json:"body"`
+	Mentions   []string   `json:"mentions,omitempty"`
+	Resolved   bool       `json:"resolved"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+type CommentCounts struct {
+	Total      int `json:"total"`
+	Unresolved int `json:"unresolved"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+// In a real application, this would be a database
+var comments = make(map[string]*Comment)
+var commentsByReportID = make(map[string][]*Comment)
+
+// BE-IN - Internal backend only
+// A handle cannot end in "." or "-", so punctuation after a mention is
+// not part of it.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9](?:[A-Za-z0-9_.-]*[A-Za-z0-9_])?)`)
+
+// BE-IN - Internal backend only
+func SaveComment(ctx context.Context, comment *Comment) error {
+	// Score: [S6,P7,M7,T5,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation, no injection concerns with in-memory
+	// - Performance (P7): Fast in-memory operations
+	// - Memory (M7): Efficient storage with pointers
+	// - Testing (T5): Only exercised through the comment handler tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Handles concurrent operations with locking (in real impl)
+	// Tags: BE-DB-medium
+
+	// Generate ID if not provided
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+
+	// Update timestamps
+	comment.UpdatedAt = time.Now()
+
+	// Store in memory
+	if _, exists := comments[comment.ID]; !exists {
+		commentsByReportID[comment.ReportID] = append(commentsByReportID[comment.ReportID], comment)
+	}
+	comments[comment.ID] = comment
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetCommentByID(ctx context.Context, id string) (*Comment, error) {
+	// Score: [S6,P8,M8,T5,E7,L8]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P8): Fast lookup by ID
+	// - Memory (M8): No additional allocations
+	// - Testing (T5): Only exercised through the comment handler tests
+	// - Error (E7): Proper error handling
+	// - Load (L8): Efficient for concurrent reads
+	// Tags: BE-DB-medium
+
+	comment, ok := comments[id]
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+
+	return comment, nil
+}
+
+// BE-IN - Internal backend only
+func ListCommentsByReportID(ctx context.Context, reportID string) ([]Comment, error) {
+	// Score: [S6,P7,M6,T5,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P7): Indexed by report ID
+	// - Memory (M6): Creates new slice for results
+	// - Testing (T5): Ordering only checked through the thread listing test
+	// - Error (E7): Proper error handling
+	// - Load (L7): Handles concurrent reads
+	// Tags: BE-DB-medium
+
+	result := make([]Comment, 0, len(commentsByReportID[reportID]))
+	for _, comment := range commentsByReportID[reportID] {
+		result = append(result, *comment)
+	}
+
+	// Oldest first so threads read top to bottom
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+// CountCommentsByReportID counts comments on a report. Only thread roots
+// carry the resolved flag, so unresolved counts open threads.
+func CountCommentsByReportID(ctx context.Context, reportID string) (CommentCounts, error) {
+	var counts CommentCounts
+	for _, comment := range commentsByReportID[reportID] {
+		counts.Total++
+		if comment.ParentID == "" && !comment.Resolved {
+			counts.Unresolved++
+		}
+	}
+	return counts, nil
+}
+
+// BE-IN - Internal backend only
+// ParseMentions returns the distinct user handles mentioned with @ in body,
+// in order of first appearance.
+func ParseMentions(body string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handle := match[1]
+		if !seen[handle] {
+			seen[handle] = true
+			mentions = append(mentions, handle)
+		}
+	}
+	return mentions
+}
+
+// BE-IN - Internal backend only
+func IsEvaluationDimension(dimension string) bool {
+	for _, d := range EvaluationDimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
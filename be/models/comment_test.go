@@ -0,0 +1,31 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"@user-123 please review", []string{"user-123"}},
+		{"Thanks @user-123.", []string{"user-123"}},
+		{"Ask @user-456- or @user-789...", []string{"user-456", "user-789"}},
+		{"(@user-123), @user_456!", []string{"user-123", "user_456"}},
+		{"@user-123 and @user-123 again", []string{"user-123"}},
+		{"@a.b.c", []string{"a.b.c"}},
+		{"@a", []string{"a"}},
+		// Email addresses and doubled @ are not mentions
+		{"mail alex@example.com", nil},
+		{"@@user-123", nil},
+		{"@-user", nil},
+		{"no mentions here", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseMentions(tt.body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMentions(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrCoverageRunNotFound = errors.New("coverage run not found")
+)
+
+// BE-IN - Internal backend only
+type CoveragePackage struct {
+	Package    string  `json:"package"`
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percent    float64 `json:"percent"`
+	Passed     int     `json:"passed"`
+	Failed     int     `json:"failed"`
+	Skipped    int     `json:"skipped"`
+	Flaky      int     `json:"flaky"`
+}
+
+// BE-IN - Internal backend only
+// CoverageRun is an uploaded coverage profile, with test results when go
+// test -json output was uploaded too, and the testing score suggested
+// for it.
+type CoverageRun struct {
+	ID             string            `json:"id"`
+	ReportID       string            `json:"report_id"`
+	UploadedBy     string            `json:"uploaded_by"`
+	Mode           string            `json:"mode"`
+	Statements     int               `json:"statements"`
+	Covered        int               `json:"covered"`
+	Percent        float64           `json:"percent"`
+	HasTests       bool              `json:"has_tests"`
+	Passed         int               `json:"passed"`
+	Failed         int               `json:"failed"`
+	Skipped        int               `json:"skipped"`
+	Flaky          int               `json:"flaky"`
+	FailedTests    []string          `json:"failed_tests,omitempty"`
+	FlakyTests     []string          `json:"flaky_tests,omitempty"`
+	FailedPackages []string          `json:"failed_packages,omitempty"`
+	Packages       []CoveragePackage `json:"packages"`
+	SuggestedScore int               `json:"suggested_score"`
+	Details        string            `json:"details"`
+	Applied        bool              `json:"applied"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes; only the latest run of each
+// report is kept
+var (
+	coverageMu         sync.RWMutex
+	coverageByReportID = make(map[string]*CoverageRun)
+)
+
+// BE-IN - Internal backend only
+func SaveCoverageRun(ctx context.Context, run *CoverageRun) error {
+	// Score: [S6,P8,M7,T5,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P8): Single map write
+	// - Memory (M7): Replaces the previous run of the report
+	// - Testing (T5): Only exercised through the api coverage tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	coverageMu.Lock()
+	defer coverageMu.Unlock()
+	coverageByReportID[run.ReportID] = run
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetCoverageRunByReportID(ctx context.Context, reportID string) (*CoverageRun, error) {
+	coverageMu.RLock()
+	defer coverageMu.RUnlock()
+
+	run, ok := coverageByReportID[reportID]
+	if !ok {
+		return nil, ErrCoverageRunNotFound
+	}
+	return run, nil
+}
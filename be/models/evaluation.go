@@ -32,6 +32,12 @@ type Evaluation struct {
 	EvaluatorID       string    `json:"evaluator_id"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	// Version increases with every save, starting at 1
+	Version int `json:"version"`
+	// DeletedAt is set while the report is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// ArchivedAt is set when the report is archived
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
 
 // BE-IN - Internal backend only
@@ -57,13 +63,19 @@ func SaveEvaluation(ctx context.Context, evaluation *Evaluation) error {
 		evaluation.ID = uuid.New().String()
 	}
 
-	// Update timestamps
+	// Update timestamps and version
 	evaluation.UpdatedAt = time.Now()
+	evaluation.Version++
 
 	// Store in memory
 	evaluations[evaluation.ID] = evaluation
 	evaluationsByReportID[evaluation.ReportID] = evaluation
 
+	// Keep the search index in sync
+	if report, ok := reports[evaluation.ReportID]; ok {
+		indexReport(report)
+	}
+
 	return nil
 }
 
@@ -80,7 +92,7 @@ func GetEvaluationByID(ctx context.Context, id string) (*Evaluation, error) {
 	// Tags: BE-DB-medium
 
 	evaluation, ok := evaluations[id]
-	if !ok {
+	if !ok || evaluation.DeletedAt != nil {
 		return nil, ErrEvaluationNotFound
 	}
 
@@ -100,7 +112,7 @@ func GetEvaluationByReportID(ctx context.Context, reportID string) (*Evaluation,
 	// Tags: BE-DB-medium
 
 	evaluation, ok := evaluationsByReportID[reportID]
-	if !ok {
+	if !ok || evaluation.DeletedAt != nil {
 		return nil, ErrEvaluationNotFound
 	}
 
@@ -127,6 +139,11 @@ func DeleteEvaluation(ctx context.Context, id string) error {
 	delete(evaluations, id)
 	delete(evaluationsByReportID, evaluation.ReportID)
 
+	// Keep the search index in sync
+	if report, ok := reports[evaluation.ReportID]; ok {
+		indexReport(report)
+	}
+
 	return nil
 }
 
@@ -152,6 +169,7 @@ func init() {
 			EvaluatorID:       "user-123",
 			CreatedAt:         time.Now().Add(-24 * time.Hour),
 			UpdatedAt:         time.Now().Add(-24 * time.Hour),
+			Version:           1,
 		},
 	}
 
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrFindingNotFound      = errors.New("security finding not found")
+	ErrSecurityScanNotFound = errors.New("security scan not found")
+)
+
+// BE-IN - Internal backend only
+// Finding statuses. Open findings count towards the security score;
+// fixed findings disappeared from a later scan by the same tool and
+// dismissed findings were judged false positives or accepted risks.
+const (
+	FindingOpen      = "open"
+	FindingFixed     = "fixed"
+	FindingDismissed = "dismissed"
+)
+
+// BE-IN - Internal backend only
+type SecurityFinding struct {
+	ID              string     `json:"id"`
+	ReportID        string     `json:"report_id"`
+	Tool            string     `json:"tool"`
+	RuleID          string     `json:"rule_id"`
+	Severity        string     `json:"severity"`
+	Message         string     `json:"message"`
+	File            string     `json:"file,omitempty"`
+	Line            int        `json:"line,omitempty"`
+	Column          int        `json:"column,omitempty"`
+	HelpURI         string     `json:"help_uri,omitempty"`
+	Fingerprint     string     `json:"fingerprint"`
+	Status          string     `json:"status"`
+	DismissedBy     string     `json:"dismissed_by,omitempty"`
+	DismissalReason string     `json:"dismissal_reason,omitempty"`
+	FirstSeenAt     time.Time  `json:"first_seen_at"`
+	LastSeenAt      time.Time  `json:"last_seen_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+}
+
+// BE-IN - Internal backend only
+// SecurityScan summarizes one upload and how it changed the findings of
+// the report.
+type SecurityScan struct {
+	ID             string    `json:"id"`
+	ReportID       string    `json:"report_id"`
+	UploadedBy     string    `json:"uploaded_by"`
+	Format         string    `json:"format"`
+	Tools          []string  `json:"tools"`
+	Found          int       `json:"found"`
+	New            int       `json:"new"`
+	Fixed          int       `json:"fixed"`
+	Reopened       int       `json:"reopened"`
+	SuggestedScore int       `json:"suggested_score"`
+	Details        string    `json:"details"`
+	Applied        bool      `json:"applied"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes. Findings are kept per report;
+// only the latest scan summary of each report is kept.
+var (
+	findingMu              sync.RWMutex
+	findingsByReportID     = make(map[string][]*SecurityFinding)
+	findingsByID           = make(map[string]*SecurityFinding)
+	securityScanByReportID = make(map[string]*SecurityScan)
+)
+
+// BE-IN - Internal backend only
+// MergeSecurityFindings records the findings of a scan by tools against a
+// report. Findings seen before keep their ID and status, except that
+// fixed findings seen again are reopened; open findings of the same
+// tools that are no longer reported are marked fixed. Dismissed findings
+// stay dismissed. scan is updated with the counts and saved.
+func MergeSecurityFindings(ctx context.Context, scan *SecurityScan, found []*SecurityFinding) error {
+	// Score: [S7,P7,M7,T7,E7,L6]
+	// Details:
+	// - Security (S7): Dismissals survive re-uploads
+	// - Performance (P7): One map lookup per finding
+	// - Memory (M7): Findings kept per report
+	// - Testing (T7): Deterministic merge by fingerprint
+	// - Error (E7): Findings of other tools left untouched
+	// - Load (L6): Single lock for the whole merge
+	// Tags: BE-DB-medium
+
+	now := time.Now()
+	if scan.ID == "" {
+		scan.ID = uuid.New().String()
+	}
+	if scan.CreatedAt.IsZero() {
+		scan.CreatedAt = now
+	}
+	tools := make(map[string]bool, len(scan.Tools))
+	for _, tool := range scan.Tools {
+		tools[tool] = true
+	}
+
+	findingMu.Lock()
+	defer findingMu.Unlock()
+
+	existing := findingsByReportID[scan.ReportID]
+	byFingerprint := make(map[string]*SecurityFinding, len(existing))
+	for _, f := range existing {
+		byFingerprint[f.Tool+"/"+f.Fingerprint] = f
+	}
+
+	seen := make(map[string]bool, len(found))
+	scan.Found, scan.New, scan.Fixed, scan.Reopened = len(found), 0, 0, 0
+	for _, f := range found {
+		key := f.Tool + "/" + f.Fingerprint
+		seen[key] = true
+		current, ok := byFingerprint[key]
+		if !ok {
+			f.ID = uuid.New().String()
+			f.ReportID = scan.ReportID
+			f.Status = FindingOpen
+			f.FirstSeenAt, f.LastSeenAt = now, now
+			existing = append(existing, f)
+			findingsByID[f.ID] = f
+			byFingerprint[key] = f
+			scan.New++
+			continue
+		}
+
+		// Location and message may move with the code
+		current.Severity, current.Message, current.HelpURI = f.Severity, f.Message, f.HelpURI
+		current.File, current.Line, current.Column = f.File, f.Line, f.Column
+		current.LastSeenAt = now
+		if current.Status == FindingFixed {
+			current.Status = FindingOpen
+			current.ResolvedAt = nil
+			scan.Reopened++
+		}
+	}
+
+	for _, f := range existing {
+		if f.Status == FindingOpen && tools[f.Tool] && !seen[f.Tool+"/"+f.Fingerprint] {
+			f.Status = FindingFixed
+			f.ResolvedAt = &now
+			scan.Fixed++
+		}
+	}
+
+	findingsByReportID[scan.ReportID] = existing
+	securityScanByReportID[scan.ReportID] = scan
+	return nil
+}
+
+// BE-IN - Internal backend only
+// ListSecurityFindings returns the findings of a report, open findings
+// first and then most severe first. An empty status returns every
+// finding.
+func ListSecurityFindings(ctx context.Context, reportID, status string) ([]*SecurityFinding, error) {
+	findingMu.RLock()
+	defer findingMu.RUnlock()
+
+	var result []*SecurityFinding
+	for _, f := range findingsByReportID[reportID] {
+		if status == "" || f.Status == status {
+			result = append(result, f)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if open := result[i].Status == FindingOpen; open != (result[j].Status == FindingOpen) {
+			return open
+		}
+		if severityOrder[result[i].Severity] != severityOrder[result[j].Severity] {
+			return severityOrder[result[i].Severity] < severityOrder[result[j].Severity]
+		}
+		return result[i].FirstSeenAt.Before(result[j].FirstSeenAt)
+	})
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+var severityOrder = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// BE-IN - Internal backend only
+func GetSecurityFindingByID(ctx context.Context, id string) (*SecurityFinding, error) {
+	findingMu.RLock()
+	defer findingMu.RUnlock()
+
+	finding, ok := findingsByID[id]
+	if !ok {
+		return nil, ErrFindingNotFound
+	}
+	return finding, nil
+}
+
+// BE-IN - Internal backend only
+// SetFindingStatus dismisses or reopens a finding.
+func SetFindingStatus(ctx context.Context, finding *SecurityFinding, status, userID, reason string) error {
+	findingMu.Lock()
+	defer findingMu.Unlock()
+
+	now := time.Now()
+	finding.Status = status
+	switch status {
+	case FindingDismissed:
+		finding.DismissedBy = userID
+		finding.DismissalReason = reason
+		finding.ResolvedAt = &now
+	default:
+		finding.DismissedBy = ""
+		finding.DismissalReason = ""
+		finding.ResolvedAt = nil
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+// CountOpenFindings counts the open findings of a report with the given
+// severity.
+func CountOpenFindings(ctx context.Context, reportID, severity string) int {
+	findingMu.RLock()
+	defer findingMu.RUnlock()
+
+	count := 0
+	for _, f := range findingsByReportID[reportID] {
+		if f.Status == FindingOpen && f.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// BE-IN - Internal backend only
+func GetSecurityScanByReportID(ctx context.Context, reportID string) (*SecurityScan, error) {
+	findingMu.RLock()
+	defer findingMu.RUnlock()
+
+	scan, ok := securityScanByReportID[reportID]
+	if !ok {
+		return nil, ErrSecurityScanNotFound
+	}
+	return scan, nil
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BE-IN - Internal backend only
+var ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+
+// BE-IN - Internal backend only
+// IdempotencyRecord is the outcome of the first request sent with a key.
+// Keys are scoped to the user and endpoint. Fingerprint identifies the
+// request; Response is empty until the request completes.
+type IdempotencyRecord struct {
+	UserID      string          `json:"user_id"`
+	Endpoint    string          `json:"endpoint"`
+	Key         string          `json:"key"`
+	Fingerprint string          `json:"fingerprint"`
+	Response    json.RawMessage `json:"response,omitempty"`
+	// ETag is the response's ETag header, which the JSON body leaves out
+	ETag      string    `json:"etag,omitempty"`
+	Completed bool      `json:"completed"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// done is closed when the request completes or is released
+	done chan struct{}
+}
+
+// BE-IN - Internal backend only
+// Done is closed once the request holding the key completes or fails.
+func (r *IdempotencyRecord) Done() <-chan struct{} {
+	return r.done
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes; expired records are dropped at
+// most once a minute when a key is claimed
+var (
+	idempotencyMu        sync.Mutex
+	idempotencyRecords   = make(map[string]*IdempotencyRecord)
+	idempotencyLastSweep time.Time
+)
+
+// BE-IN - Internal backend only
+func idempotencyIndex(userID, endpoint, key string) string {
+	return userID + "\x00" + endpoint + "\x00" + key
+}
+
+// BE-IN - Internal backend only
+// ClaimIdempotencyKey stores a pending record for the key and returns
+// claimed true, or returns a copy of the live record from an earlier
+// request. A live record with a different fingerprint is
+// ErrIdempotencyKeyReused.
+func ClaimIdempotencyKey(ctx context.Context, userID, endpoint, key, fingerprint string, window time.Duration) (*IdempotencyRecord, bool, error) {
+	// Score: [S8,P8,M7,T7,E8,L7]
+	// Details:
+	// - Security (S8): Keys scoped to the user, reuse with another request refused
+	// - Performance (P8): Single map lookup, periodic sweep of expired keys
+	// - Memory (M7): Responses kept for the configured window only
+	// - Testing (T7): Claim, complete and release are separate steps
+	// - Error (E8): Reuse reported as a distinct error
+	// - Load (L7): Claims are atomic under one mutex
+	// Tags: BE-DB-medium
+
+	now := time.Now()
+	index := idempotencyIndex(userID, endpoint, key)
+
+	idempotencyMu.Lock()
+	defer idempotencyMu.Unlock()
+
+	if now.Sub(idempotencyLastSweep) > time.Minute {
+		for i, record := range idempotencyRecords {
+			if record.Completed && now.After(record.ExpiresAt) {
+				delete(idempotencyRecords, i)
+			}
+		}
+		idempotencyLastSweep = now
+	}
+
+	if record, ok := idempotencyRecords[index]; ok && (!record.Completed || now.Before(record.ExpiresAt)) {
+		if record.Fingerprint != fingerprint {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+		copied := *record
+		return &copied, false, nil
+	}
+
+	record := &IdempotencyRecord{
+		UserID:      userID,
+		Endpoint:    endpoint,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(window),
+		done:        make(chan struct{}),
+	}
+	idempotencyRecords[index] = record
+	copied := *record
+	return &copied, true, nil
+}
+
+// BE-IN - Internal backend only
+// CompleteIdempotencyKey stores the response of a claimed key. Repeats
+// replay it until the window that started with the first request ends.
+func CompleteIdempotencyKey(ctx context.Context, userID, endpoint, key string, response json.RawMessage, etag string) error {
+	idempotencyMu.Lock()
+	defer idempotencyMu.Unlock()
+
+	record, ok := idempotencyRecords[idempotencyIndex(userID, endpoint, key)]
+	if !ok || record.Completed {
+		return nil
+	}
+	record.Response = response
+	record.ETag = etag
+	record.Completed = true
+	close(record.done)
+	return nil
+}
+
+// BE-IN - Internal backend only
+// ReleaseIdempotencyKey forgets a claimed key whose request failed, so a
+// retry runs the request again.
+func ReleaseIdempotencyKey(ctx context.Context, userID, endpoint, key string) {
+	idempotencyMu.Lock()
+	defer idempotencyMu.Unlock()
+
+	index := idempotencyIndex(userID, endpoint, key)
+	record, ok := idempotencyRecords[index]
+	if !ok || record.Completed {
+		return
+	}
+	delete(idempotencyRecords, index)
+	close(record.done)
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrImportJobNotFound = errors.New("import job not found")
+)
+
+// BE-IN - Internal backend only
+// Import job statuses
+const (
+	ImportStatusInProgress = "in_progress"
+	ImportStatusCompleted  = "completed"
+	ImportStatusFailed     = "failed"
+)
+
+// BE-IN - Internal backend only
+// ImportJob tracks a chunked import so it can be resumed. The payload is
+// not stored; clients resend it and the checksum proves it is unchanged.
+type ImportJob struct {
+	ID        string    `json:"id"`
+	OwnerID   string    `json:"owner_id"`
+	Format    string    `json:"format"`
+	Checksum  string    `json:"checksum"`
+	Status    string    `json:"status"`
+	TotalRows int       `json:"total_rows"`
+	NextRow   int       `json:"next_row"`
+	Imported  int       `json:"imported"`
+	Failed    int       `json:"failed"`
+	ReportIDs []string  `json:"report_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+// In a real application, this would be a database
+var importJobs = make(map[string]*ImportJob)
+
+// BE-IN - Internal backend only
+func SaveImportJob(ctx context.Context, job *ImportJob) error {
+	// Generate ID if not provided
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+
+	// Update timestamps
+	job.UpdatedAt = time.Now()
+
+	// Store in memory
+	importJobs[job.ID] = job
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetImportJobByID(ctx context.Context, id string) (*ImportJob, error) {
+	job, ok := importJobs[id]
+	if !ok {
+		return nil, ErrImportJobNotFound
+	}
+
+	return job, nil
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrLoadTestRunNotFound = errors.New("load test run not found")
+)
+
+// BE-IN - Internal backend only
+// LoadTestRun is an uploaded load-test result reduced to throughput,
+// latency percentiles and error rate, with the load score suggested for
+// it. Latencies are milliseconds.
+type LoadTestRun struct {
+	ID             string    `json:"id"`
+	ReportID       string    `json:"report_id"`
+	UploadedBy     string    `json:"uploaded_by"`
+	Format         string    `json:"format"`
+	Requests       int       `json:"requests"`
+	Errors         int       `json:"errors"`
+	ErrorRate      float64   `json:"error_rate"`
+	Duration       float64   `json:"duration_seconds"`
+	Throughput     float64   `json:"throughput"`
+	MeanMs         float64   `json:"mean_ms"`
+	P50Ms          float64   `json:"p50_ms"`
+	P95Ms          float64   `json:"p95_ms"`
+	P99Ms          float64   `json:"p99_ms"`
+	MaxMs          float64   `json:"max_ms"`
+	Missing        []string  `json:"missing,omitempty"`
+	SuggestedScore int       `json:"suggested_score"`
+	Details        string    `json:"details"`
+	Applied        bool      `json:"applied"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes; only the latest run of each
+// report is kept
+var (
+	loadTestMu         sync.RWMutex
+	loadTestByReportID = make(map[string]*LoadTestRun)
+)
+
+// BE-IN - Internal backend only
+func SaveLoadTestRun(ctx context.Context, run *LoadTestRun) error {
+	// Score: [S6,P8,M7,T5,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P8): Single map write
+	// - Memory (M7): Replaces the previous run of the report
+	// - Testing (T5): Only exercised through the api load test tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	loadTestMu.Lock()
+	defer loadTestMu.Unlock()
+	loadTestByReportID[run.ReportID] = run
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetLoadTestRunByReportID(ctx context.Context, reportID string) (*LoadTestRun, error) {
+	loadTestMu.RLock()
+	defer loadTestMu.RUnlock()
+
+	run, ok := loadTestByReportID[reportID]
+	if !ok {
+		return nil, ErrLoadTestRunNotFound
+	}
+	return run, nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrMetadataSchemaNotFound = errors.New("metadata schema not found")
+)
+
+// BE-IN - Internal backend only
+// MetadataSchema is the JSON Schema a department requires report Metadata
+// to satisfy. Version increases with every change.
+type MetadataSchema struct {
+	DepartmentID string          `json:"department_id"`
+	Schema       json.RawMessage `json:"schema"`
+	Version      int             `json:"version"`
+	UpdatedBy    string          `json:"updated_by"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+var (
+	metadataSchemaMu             sync.RWMutex
+	metadataSchemaByDepartmentID = make(map[string]*MetadataSchema)
+)
+
+// BE-IN - Internal backend only
+// SaveMetadataSchema publishes a department's schema, replacing the
+// previous one and bumping its version.
+func SaveMetadataSchema(ctx context.Context, schema *MetadataSchema) error {
+	// Score: [S6,P8,M8,T6,E7,L7]
+	// Details:
+	// - Security (S6): Schema validated by the caller
+	// - Performance (P8): Single map write
+	// - Memory (M8): One schema per department
+	// - Testing (T6): Version bump exercised through the api schema tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	metadataSchemaMu.Lock()
+	defer metadataSchemaMu.Unlock()
+
+	schema.Version = 1
+	if previous, ok := metadataSchemaByDepartmentID[schema.DepartmentID]; ok {
+		schema.Version = previous.Version + 1
+	}
+	schema.UpdatedAt = time.Now()
+	metadataSchemaByDepartmentID[schema.DepartmentID] = schema
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetMetadataSchemaByDepartmentID(ctx context.Context, departmentID string) (*MetadataSchema, error) {
+	metadataSchemaMu.RLock()
+	defer metadataSchemaMu.RUnlock()
+
+	schema, ok := metadataSchemaByDepartmentID[departmentID]
+	if !ok {
+		return nil, ErrMetadataSchemaNotFound
+	}
+	return schema, nil
+}
+
+// BE-IN - Internal backend only
+// DeleteMetadataSchema stops validating the department's report metadata.
+func DeleteMetadataSchema(ctx context.Context, departmentID string) error {
+	metadataSchemaMu.Lock()
+	defer metadataSchemaMu.Unlock()
+
+	if _, ok := metadataSchemaByDepartmentID[departmentID]; !ok {
+		return ErrMetadataSchemaNotFound
+	}
+	delete(metadataSchemaByDepartmentID, departmentID)
+	return nil
+}
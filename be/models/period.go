@@ -0,0 +1,293 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrPeriodNotFound  = errors.New("reporting period not found")
+	ErrPeriodClosed    = errors.New("reporting period is closed")
+	ErrPeriodNotClosed = errors.New("reporting period is not closed")
+)
+
+// BE-IN - Internal backend only
+// ReportingPeriod is a window, such as a quarter, in which a department
+// or one of its projects expects reports. Draft reports assigned to it
+// become overdue after DueAt; once it is closed they can no longer be
+// edited or submitted.
+type ReportingPeriod struct {
+	ID           string `json:"id"`
+	DepartmentID string `json:"department_id"`
+	// ProjectID limits the period to one project; empty means every
+	// project of the department
+	ProjectID string     `json:"project_id,omitempty"`
+	Name      string     `json:"name"`
+	StartsAt  time.Time  `json:"starts_at"`
+	DueAt     time.Time  `json:"due_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	ClosedBy  string     `json:"closed_by,omitempty"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+// AppliesTo reports whether reports of the department and project can be
+// assigned to the period.
+func (p *ReportingPeriod) AppliesTo(departmentID, projectID string) bool {
+	return p.DepartmentID == departmentID && (p.ProjectID == "" || p.ProjectID == projectID)
+}
+
+// BE-IN - Internal backend only
+// PeriodStats counts the reports assigned to a period
+type PeriodStats struct {
+	Reports   int `json:"reports"`
+	Submitted int `json:"submitted"`
+	Overdue   int `json:"overdue"`
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+var (
+	periodMu  sync.RWMutex
+	periods   = make(map[string]*ReportingPeriod)
+	reminders = make(map[string]bool)
+)
+
+// BE-IN - Internal backend only
+func SavePeriod(ctx context.Context, period *ReportingPeriod) error {
+	// Score: [S6,P8,M8,T5,E7,L7]
+	// Details:
+	// - Security (S6): Permissions checked by the caller
+	// - Performance (P8): Single map write
+	// - Memory (M8): One entry per period
+	// - Testing (T5): Only exercised through the api period tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	if period.ID == "" {
+		period.ID = uuid.New().String()
+	}
+	if period.CreatedAt.IsZero() {
+		period.CreatedAt = time.Now()
+	}
+
+	periodMu.Lock()
+	defer periodMu.Unlock()
+	periods[period.ID] = period
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetPeriodByID(ctx context.Context, id string) (*ReportingPeriod, error) {
+	periodMu.RLock()
+	defer periodMu.RUnlock()
+
+	period, ok := periods[id]
+	if !ok {
+		return nil, ErrPeriodNotFound
+	}
+	copied := *period
+	return &copied, nil
+}
+
+// BE-IN - Internal backend only
+// ListPeriods returns a department's periods by due date, latest first.
+// A projectID limits them to the periods that apply to that project.
+func ListPeriods(ctx context.Context, departmentID, projectID string, includeClosed bool) ([]ReportingPeriod, error) {
+	periodMu.RLock()
+	defer periodMu.RUnlock()
+
+	var result []ReportingPeriod
+	for _, period := range periods {
+		if period.DepartmentID != departmentID {
+			continue
+		}
+		if projectID != "" && !period.AppliesTo(departmentID, projectID) {
+			continue
+		}
+		if period.ClosedAt != nil && !includeClosed {
+			continue
+		}
+		result = append(result, *period)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DueAt.Equal(result[j].DueAt) {
+			return result[i].DueAt.After(result[j].DueAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+// ClosePeriod locks the reports assigned to a period against further
+// edits and submissions.
+func ClosePeriod(ctx context.Context, id, userID string) (*ReportingPeriod, error) {
+	periodMu.Lock()
+	defer periodMu.Unlock()
+
+	period, ok := periods[id]
+	if !ok {
+		return nil, ErrPeriodNotFound
+	}
+	if period.ClosedAt != nil {
+		return nil, ErrPeriodClosed
+	}
+	now := time.Now()
+	period.ClosedAt = &now
+	period.ClosedBy = userID
+	copied := *period
+	return &copied, nil
+}
+
+// BE-IN - Internal backend only
+// ReopenPeriod unlocks a closed period.
+func ReopenPeriod(ctx context.Context, id string) (*ReportingPeriod, error) {
+	periodMu.Lock()
+	defer periodMu.Unlock()
+
+	period, ok := periods[id]
+	if !ok {
+		return nil, ErrPeriodNotFound
+	}
+	if period.ClosedAt == nil {
+		return nil, ErrPeriodNotClosed
+	}
+	period.ClosedAt = nil
+	period.ClosedBy = ""
+	copied := *period
+	return &copied, nil
+}
+
+// BE-IN - Internal backend only
+// IsPeriodClosed reports whether the report's period has been closed.
+func IsPeriodClosed(ctx context.Context, report *Report) bool {
+	if report.PeriodID == "" {
+		return false
+	}
+	periodMu.RLock()
+	defer periodMu.RUnlock()
+
+	period, ok := periods[report.PeriodID]
+	return ok && period.ClosedAt != nil
+}
+
+// BE-IN - Internal backend only
+// ReportDeadline returns when the report is due, or nil when it has no
+// period, and whether it is overdue: still a draft after the due date.
+func ReportDeadline(report *Report, now time.Time) (*time.Time, bool) {
+	periodMu.RLock()
+	defer periodMu.RUnlock()
+
+	return reportDeadline(report, now)
+}
+
+// BE-IN - Internal backend only
+// reportDeadline is ReportDeadline for callers holding periodMu.
+func reportDeadline(report *Report, now time.Time) (*time.Time, bool) {
+	if report.PeriodID == "" {
+		return nil, false
+	}
+	period, ok := periods[report.PeriodID]
+	if !ok {
+		return nil, false
+	}
+	dueAt := period.DueAt
+	return &dueAt, report.Status == "draft" && now.After(dueAt)
+}
+
+// BE-IN - Internal backend only
+// GetPeriodStats counts the reports assigned to a period, leaving out
+// those in the trash.
+func GetPeriodStats(ctx context.Context, id string, now time.Time) (PeriodStats, error) {
+	periodMu.RLock()
+	defer periodMu.RUnlock()
+
+	var stats PeriodStats
+	if _, ok := periods[id]; !ok {
+		return stats, ErrPeriodNotFound
+	}
+	for _, report := range reports {
+		if report.PeriodID != id || report.DeletedAt != nil {
+			continue
+		}
+		stats.Reports++
+		if report.SubmittedAt != nil {
+			stats.Submitted++
+		}
+		if _, overdue := reportDeadline(report, now); overdue {
+			stats.Overdue++
+		}
+	}
+	return stats, nil
+}
+
+// BE-IN - Internal backend only
+// DueReport is a draft whose period is due within the reminder window
+type DueReport struct {
+	Report *Report
+	Period ReportingPeriod
+}
+
+// BE-IN - Internal backend only
+// FindReportsDueBefore returns the drafts in open periods due between now
+// and deadline whose authors have not been reminded yet.
+func FindReportsDueBefore(ctx context.Context, now, deadline time.Time) ([]DueReport, error) {
+	// Score: [S7,P6,M7,T7,E7,L6]
+	// Details:
+	// - Security (S7): Read-only
+	// - Performance (P6): Full scan of reports for the due periods
+	// - Memory (M7): Holds the due reports only
+	// - Testing (T7): Time is passed in
+	// - Error (E7): Proper error handling
+	// - Load (L6): Meant for a scheduled job rather than request paths
+	// Tags: BE-DB-medium
+
+	periodMu.RLock()
+	defer periodMu.RUnlock()
+
+	var due []DueReport
+	for _, report := range reports {
+		if report.PeriodID == "" || report.Status != "draft" || report.DeletedAt != nil || report.ArchivedAt != nil {
+			continue
+		}
+		period, ok := periods[report.PeriodID]
+		if !ok || period.ClosedAt != nil || !period.DueAt.After(now) || period.DueAt.After(deadline) {
+			continue
+		}
+		if reminders[reminderKey(period.ID, report.ID)] {
+			continue
+		}
+		due = append(due, DueReport{Report: report, Period: *period})
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Report.ID < due[j].Report.ID })
+	return due, nil
+}
+
+// BE-IN - Internal backend only
+// MarkReminderSent records that the author of a report was reminded of
+// the period's deadline. It returns false when they already were.
+func MarkReminderSent(ctx context.Context, periodID, reportID string) bool {
+	periodMu.Lock()
+	defer periodMu.Unlock()
+
+	key := reminderKey(periodID, reportID)
+	if reminders[key] {
+		return false
+	}
+	reminders[key] = true
+	return true
+}
+
+// BE-IN - Internal backend only
+func reminderKey(periodID, reportID string) string {
+	return periodID + "\x00" + reportID
+}
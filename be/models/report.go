@@ -3,6 +3,8 @@ package models
 import (
 	"context"
 	"errors"
+	"sort"
+	"sync"
 	"time"
 
 	"encore.dev/rlog"
@@ -27,6 +29,21 @@ type Report struct {
 	UpdatedAt    time.Time              `json:"updated_at"`
 	SubmittedAt  *time.Time             `json:"submitted_at,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	TemplateID   string                 `json:"template_id,omitempty"`
+	// EvaluationPrompts are the template's hints for the evaluation
+	// details, kept apart from the details the author writes
+	EvaluationPrompts *EvaluationPrompts `json:"evaluation_prompts,omitempty"`
+	PeriodID          string             `json:"period_id,omitempty"`
+	Tags              []Tag              `json:"tags,omitempty"`
+	// Version increases with every save, starting at 1
+	Version int `json:"version"`
+	// DeletedAt marks a report in the trash; it is purged once the
+	// retention period has passed
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+	// ArchivedAt is set once a retention policy archives the report,
+	// after which it is read-only
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
 
 // BE-IN - Internal backend only
@@ -34,8 +51,40 @@ type ListReportsParams struct {
 	Status    *string
 	AuthorID  *string
 	ProjectID *string
-	Limit     int
-	Offset    int
+	PeriodID  *string
+	// Overdue keeps only drafts past their period's due date, or only
+	// the other reports when false, as of Now
+	Overdue *bool
+	Now     time.Time
+	Tags    TagFilter
+	Limit   int
+	Offset  int
+}
+
+// BE-IN - Internal backend only
+func (p ListReportsParams) matches(report *Report) bool {
+	// Reports in the trash are only listed by ListDeletedReports
+	if report.DeletedAt != nil {
+		return false
+	}
+	if p.Status != nil && report.Status != *p.Status {
+		return false
+	}
+	if p.AuthorID != nil && report.AuthorID != *p.AuthorID {
+		return false
+	}
+	if p.ProjectID != nil && report.ProjectID != *p.ProjectID {
+		return false
+	}
+	if p.PeriodID != nil && report.PeriodID != *p.PeriodID {
+		return false
+	}
+	if p.Overdue != nil {
+		if _, overdue := ReportDeadline(report, p.Now); overdue != *p.Overdue {
+			return false
+		}
+	}
+	return p.Tags.MatchesAny(report.Tags)
 }
 
 // BE-IN - Internal backend only
@@ -60,15 +109,34 @@ func SaveReport(ctx context.Context, report *Report) error {
 		report.ID = uuid.New().String()
 	}
 
-	// Update timestamps
+	// Update timestamps and version
 	report.UpdatedAt = time.Now()
+	report.Version++
 
 	// Store in memory
 	reports[report.ID] = report
 
+	// Keep the search index in sync
+	indexReport(report)
+
 	return nil
 }
 
+// BE-IN - Internal backend only
+// reportLocks holds one mutex per report for status transitions
+var reportLocks sync.Map
+
+// BE-IN - Internal backend only
+// LockReport serializes status transitions of a report, so two
+// concurrent submissions or reviews cannot both pass the status check.
+// The returned function releases the lock.
+func LockReport(ctx context.Context, id string) func() {
+	lock, _ := reportLocks.LoadOrStore(id, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
 // BE-IN - Internal backend only
 func GetReportByID(ctx context.Context, id string) (*Report, error) {
 	// Score: [S6,P8,M8,T6,E7,L8]
@@ -82,7 +150,7 @@ func GetReportByID(ctx context.Context, id string) (*Report, error) {
 	// Tags: BE-DB-medium
 
 	report, ok := reports[id]
-	if !ok {
+	if !ok || report.DeletedAt != nil {
 		return nil, ErrReportNotFound
 	}
 
@@ -104,13 +172,7 @@ func ListReports(ctx context.Context, params ListReportsParams) ([]Report, int,
 	var result []Report
 	for _, report := range reports {
 		// Apply filters
-		if params.Status != nil && report.Status != *params.Status {
-			continue
-		}
-		if params.AuthorID != nil && report.AuthorID != *params.AuthorID {
-			continue
-		}
-		if params.ProjectID != nil && report.ProjectID != *params.ProjectID {
+		if !params.matches(report) {
 			continue
 		}
 
@@ -135,6 +197,54 @@ func ListReports(ctx context.Context, params ListReportsParams) ([]Report, int,
 }
 
 // BE-IN - Internal backend only
+// ForEachReport calls fn for every report matching the filters, oldest
+// first. Only the matching IDs are held in memory, so callers can stream
+// large result sets. Limit and Offset are ignored.
+func ForEachReport(ctx context.Context, params ListReportsParams, fn func(*Report) error) error {
+	// Score: [S6,P7,M8,T7,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P7): Single filtering pass plus sort of matching IDs
+	// - Memory (M8): Holds IDs only, reports are visited one at a time
+	// - Testing (T7): Ordering and early stop tested
+	// - Error (E7): Stops at the first callback error
+	// - Load (L7): Suitable for exports of large result sets
+	// Tags: BE-DB-medium
+
+	var ids []string
+	for _, report := range reports {
+		// Apply filters
+		if !params.matches(report) {
+			continue
+		}
+		ids = append(ids, report.ID)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := reports[ids[i]], reports[ids[j]]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	for _, id := range ids {
+		report, ok := reports[id]
+		if !ok {
+			// Deleted while iterating
+			continue
+		}
+		if err := fn(report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+// DeleteReport removes a report permanently, without going through the
+// trash. Bulk import uses it to roll back.
 func DeleteReport(ctx context.Context, id string) error {
 	// Score: [S6,P8,M8,T6,E7,L7]
 	// Details:
@@ -151,7 +261,8 @@ func DeleteReport(ctx context.Context, id string) error {
 		return ErrReportNotFound
 	}
 
-	delete(reports, id)
+	// Remove the evaluation and everything else stored for the report
+	purgeReport(id)
 	return nil
 }
 
@@ -159,21 +270,28 @@ func DeleteReport(ctx context.Context, id string) error {
 func GetUserIDFromContext(ctx context.Context) (string, error) {
 	// In a real application, this would extract the user ID from the context
 	// For demo purposes, we'll return a mock user ID
+	if id, ok := demoUserID(); ok {
+		return id, nil
+	}
 	return "user-123", nil
 }
 
 // BE-IN - Internal backend only
 type Department struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	HeadID string `json:"head_id,omitempty"`
+	// BlockCriticalFindings refuses submissions while the report has open
+	// critical security findings
+	BlockCriticalFindings bool `json:"block_critical_findings,omitempty"`
 }
 
 // BE-IN - Internal backend only
 // Mock departments for demo
 var departments = map[string]*Department{
-	"dept-123": {ID: "dept-123", Name: "Security"},
-	"dept-456": {ID: "dept-456", Name: "Backend"},
-	"dept-789": {ID: "dept-789", Name: "Frontend"},
+	"dept-123": {ID: "dept-123", Name: "Security", HeadID: "user-456", BlockCriticalFindings: true},
+	"dept-456": {ID: "dept-456", Name: "Backend", HeadID: "user-789"},
+	"dept-789": {ID: "dept-789", Name: "Frontend", HeadID: "user-789"},
 }
 
 // BE-IN - Internal backend only
@@ -209,6 +327,8 @@ func init() {
 			CreatedAt:    time.Now().Add(-48 * time.Hour),
 			UpdatedAt:    time.Now().Add(-24 * time.Hour),
 			SubmittedAt:  timePtr(time.Now().Add(-24 * time.Hour)),
+			Tags:         []Tag{{Type: "BE", Scope: "module", Impact: "high"}},
+			Version:      1,
 		},
 		{
 			ID:           "report-456",
@@ -220,6 +340,8 @@ func init() {
 			Status:       "draft",
 			CreatedAt:    time.Now().Add(-24 * time.Hour),
 			UpdatedAt:    time.Now().Add(-12 * time.Hour),
+			Tags:         []Tag{{Type: "BE", Scope: "OUT", Impact: "high"}},
+			Version:      1,
 		},
 	}
 
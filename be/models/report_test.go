@@ -0,0 +1,54 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestForEachReport(t *testing.T) {
+	ctx := context.Background()
+	projectID := "project-foreach"
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Saved newest first, visited oldest first with ties broken by ID
+	for _, r := range []*Report{
+		{ID: "foreach-c", CreatedAt: base.Add(2 * time.Hour)},
+		{ID: "foreach-b", CreatedAt: base},
+		{ID: "foreach-a", CreatedAt: base},
+	} {
+		r.ProjectID = projectID
+		r.Status = "draft"
+		if err := SaveReport(ctx, r); err != nil {
+			t.Fatalf("SaveReport: %v", err)
+		}
+		id := r.ID
+		t.Cleanup(func() { DeleteReport(ctx, id) })
+	}
+	want := []string{"foreach-a", "foreach-b", "foreach-c"}
+
+	var got []string
+	err := ForEachReport(ctx, ListReportsParams{ProjectID: &projectID}, func(r *Report) error {
+		got = append(got, r.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachReport: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("visited %v, want %v", got, want)
+	}
+
+	// The first callback error stops the iteration
+	stop := errors.New("stop")
+	visited := 0
+	err = ForEachReport(ctx, ListReportsParams{ProjectID: &projectID}, func(r *Report) error {
+		visited++
+		return stop
+	})
+	if err != stop || visited != 1 {
+		t.Errorf("ForEachReport = %v after %d calls, want stop after 1", err, visited)
+	}
+}
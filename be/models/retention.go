@@ -0,0 +1,255 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrRetentionPolicyNotFound = errors.New("retention policy not found")
+	ErrLegalHoldNotFound       = errors.New("legal hold not found")
+	ErrLegalHoldExists         = errors.New("report is already under legal hold")
+	ErrUnderLegalHold          = errors.New("report is under legal hold")
+)
+
+// BE-IN - Internal backend only
+// Retention actions taken when a report expires
+const (
+	RetentionArchive = "archive"
+	RetentionPurge   = "purge"
+)
+
+// BE-IN - Internal backend only
+// RetentionPolicy keeps a department's reports for RetainDays after
+// submission, or after creation for reports never submitted, and then
+// archives or purges them. A policy with a ProjectID overrides the
+// department-wide one for that project's reports.
+type RetentionPolicy struct {
+	DepartmentID string    `json:"department_id"`
+	ProjectID    string    `json:"project_id,omitempty"`
+	RetainDays   int       `json:"retain_days"`
+	Action       string    `json:"action"`
+	UpdatedBy    string    `json:"updated_by"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+// LegalHold exempts a report from purging, by retention or from the
+// trash, and from deletion until it is released.
+type LegalHold struct {
+	ReportID string    `json:"report_id"`
+	Reason   string    `json:"reason"`
+	PlacedBy string    `json:"placed_by"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+// BE-IN - Internal backend only
+// ExpiredReport is a report past the retention period of Policy
+type ExpiredReport struct {
+	Report    *Report
+	Policy    *RetentionPolicy
+	ExpiredAt time.Time
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+var (
+	retentionMu       sync.RWMutex
+	retentionPolicies = make(map[string]*RetentionPolicy)
+	legalHolds        = make(map[string]*LegalHold)
+)
+
+// BE-IN - Internal backend only
+func retentionKey(departmentID, projectID string) string {
+	return departmentID + "\x00" + projectID
+}
+
+// BE-IN - Internal backend only
+// SaveRetentionPolicy sets the policy of a department, or of one of its
+// projects when ProjectID is set, replacing the previous one.
+func SaveRetentionPolicy(ctx context.Context, policy *RetentionPolicy) error {
+	// Score: [S6,P8,M8,T5,E7,L7]
+	// Details:
+	// - Security (S6): Permissions checked by the caller
+	// - Performance (P8): Single map write
+	// - Memory (M8): One policy per department and project
+	// - Testing (T5): Only exercised through the api retention tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded by a mutex
+	// Tags: BE-DB-medium
+
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+
+	policy.UpdatedAt = time.Now()
+	retentionPolicies[retentionKey(policy.DepartmentID, policy.ProjectID)] = policy
+	return nil
+}
+
+// BE-IN - Internal backend only
+// ListRetentionPolicies returns a department's policies, the
+// department-wide one first and then the project overrides by project.
+func ListRetentionPolicies(ctx context.Context, departmentID string) ([]RetentionPolicy, error) {
+	retentionMu.RLock()
+	defer retentionMu.RUnlock()
+
+	var result []RetentionPolicy
+	for _, policy := range retentionPolicies {
+		if policy.DepartmentID == departmentID {
+			result = append(result, *policy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ProjectID < result[j].ProjectID })
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+// DeleteRetentionPolicy removes a department-wide policy, or a project
+// override when projectID is set, and returns it.
+func DeleteRetentionPolicy(ctx context.Context, departmentID, projectID string) (*RetentionPolicy, error) {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+
+	key := retentionKey(departmentID, projectID)
+	policy, ok := retentionPolicies[key]
+	if !ok {
+		return nil, ErrRetentionPolicyNotFound
+	}
+	delete(retentionPolicies, key)
+	return policy, nil
+}
+
+// BE-IN - Internal backend only
+// resolveRetentionPolicy returns the project override or else the
+// department-wide policy. The caller holds retentionMu.
+func resolveRetentionPolicy(departmentID, projectID string) *RetentionPolicy {
+	if policy, ok := retentionPolicies[retentionKey(departmentID, projectID)]; ok {
+		return policy
+	}
+	return retentionPolicies[retentionKey(departmentID, "")]
+}
+
+// BE-IN - Internal backend only
+// FindExpiredReports returns the reports, including those in the trash,
+// whose retention period ended before now. Reports already archived are
+// only returned when their policy purges.
+func FindExpiredReports(ctx context.Context, now time.Time) ([]ExpiredReport, error) {
+	// Score: [S7,P6,M7,T7,E7,L6]
+	// Details:
+	// - Security (S7): Read-only, actions are left to the caller
+	// - Performance (P6): Full scan of reports with a policy lookup each
+	// - Memory (M7): Holds the expired reports only
+	// - Testing (T7): Time is passed in
+	// - Error (E7): Proper error handling
+	// - Load (L6): Meant for a scheduled job rather than request paths
+	// Tags: BE-DB-medium
+
+	retentionMu.RLock()
+	defer retentionMu.RUnlock()
+
+	var expired []ExpiredReport
+	for _, report := range reports {
+		policy := resolveRetentionPolicy(report.DepartmentID, report.ProjectID)
+		if policy == nil {
+			continue
+		}
+		if policy.Action == RetentionArchive && report.ArchivedAt != nil {
+			continue
+		}
+		start := report.CreatedAt
+		if report.SubmittedAt != nil {
+			start = *report.SubmittedAt
+		}
+		expiresAt := start.AddDate(0, 0, policy.RetainDays)
+		if expiresAt.Before(now) {
+			copied := *policy
+			expired = append(expired, ExpiredReport{Report: report, Policy: &copied, ExpiredAt: expiresAt})
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].Report.ID < expired[j].Report.ID })
+	return expired, nil
+}
+
+// BE-IN - Internal backend only
+// ArchiveReport marks a report and its evaluation as archived. Archived
+// reports stay readable but can no longer change.
+func ArchiveReport(ctx context.Context, id string) error {
+	report, ok := reports[id]
+	if !ok {
+		return ErrReportNotFound
+	}
+	now := time.Now()
+	report.ArchivedAt = &now
+	report.Version++
+	if evaluation, ok := evaluationsByReportID[id]; ok {
+		evaluation.ArchivedAt = &now
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+// PurgeReport permanently removes a report and everything stored for it,
+// unless it is under legal hold.
+func PurgeReport(ctx context.Context, id string) error {
+	if _, ok := reports[id]; !ok {
+		return ErrReportNotFound
+	}
+	if IsUnderLegalHold(ctx, id) {
+		return ErrUnderLegalHold
+	}
+	purgeReport(id)
+	return nil
+}
+
+// BE-IN - Internal backend only
+func PlaceLegalHold(ctx context.Context, hold *LegalHold) error {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+
+	if _, ok := legalHolds[hold.ReportID]; ok {
+		return ErrLegalHoldExists
+	}
+	hold.PlacedAt = time.Now()
+	legalHolds[hold.ReportID] = hold
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetLegalHold(ctx context.Context, reportID string) (*LegalHold, error) {
+	retentionMu.RLock()
+	defer retentionMu.RUnlock()
+
+	hold, ok := legalHolds[reportID]
+	if !ok {
+		return nil, ErrLegalHoldNotFound
+	}
+	copied := *hold
+	return &copied, nil
+}
+
+// BE-IN - Internal backend only
+// ReleaseLegalHold removes the hold on a report and returns it.
+func ReleaseLegalHold(ctx context.Context, reportID string) (*LegalHold, error) {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+
+	hold, ok := legalHolds[reportID]
+	if !ok {
+		return nil, ErrLegalHoldNotFound
+	}
+	delete(legalHolds, reportID)
+	return hold, nil
+}
+
+// BE-IN - Internal backend only
+func IsUnderLegalHold(ctx context.Context, reportID string) bool {
+	retentionMu.RLock()
+	defer retentionMu.RUnlock()
+
+	_, ok := legalHolds[reportID]
+	return ok
+}
@@ -0,0 +1,536 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BE-IN - Internal backend only
+// Searchable fields and their ranking weights
+var searchFieldWeights = map[string]float64{
+	"title":               3.0,
+	"description":         1.0,
+	"metadata":            1.0,
+	"security_details":    1.0,
+	"performance_details": 1.0,
+	"memory_details":      1.0,
+	"testing_details":     1.0,
+	"error_details":       1.0,
+	"load_details":        1.0,
+}
+
+// BE-IN - Internal backend only
+const (
+	snippetRadius  = 60
+	bm25K1         = 1.2
+	maxSnippetHits = 3
+)
+
+// BE-IN - Internal backend only
+type SearchParams struct {
+	Query     string
+	Status    *string
+	ProjectID *string
+	Limit     int
+	Offset    int
+}
+
+// BE-IN - Internal backend only
+type SearchSnippet struct {
+	Field string `json:"field"`
+	Text  string `json:"text"`
+}
+
+// BE-IN - Internal backend only
+type SearchHit struct {
+	Report   Report          `json:"report"`
+	Score    float64         `json:"score"`
+	Snippets []SearchSnippet `json:"snippets"`
+}
+
+// BE-IN - Internal backend only
+type token struct {
+	term  string
+	pos   int
+	start int
+	end   int
+}
+
+// BE-IN - Internal backend only
+type posting struct {
+	field     string
+	positions []int
+}
+
+// BE-IN - Internal backend only
+// searchIndex is an inverted index from terms to the report fields that
+// contain them. Field text is kept alongside so snippets can be cut from
+// the original wording.
+type searchIndex struct {
+	mu       sync.RWMutex
+	postings map[string]map[string][]posting
+	docs     map[string]map[string]string
+	tokens   map[string]map[string][]token
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+// In a real application, this would be the database full-text index
+var reportIndex = &searchIndex{
+	postings: make(map[string]map[string][]posting),
+	docs:     make(map[string]map[string]string),
+	tokens:   make(map[string]map[string][]token),
+}
+
+// BE-IN - Internal backend only
+var indexBuilt sync.Once
+
+// BE-IN - Internal backend only
+// ensureIndex builds the index from the stored reports on first use, so
+// sample data loaded by init functions is searchable regardless of the
+// order those functions run in.
+func ensureIndex() {
+	indexBuilt.Do(func() {
+		for _, report := range reports {
+			if report.DeletedAt == nil {
+				reportIndex.put(report.ID, searchDocument(report, evaluationsByReportID[report.ID]))
+			}
+		}
+	})
+}
+
+// BE-IN - Internal backend only
+func indexReport(report *Report) {
+	ensureIndex()
+	if report.DeletedAt != nil {
+		reportIndex.remove(report.ID)
+		return
+	}
+	reportIndex.put(report.ID, searchDocument(report, evaluationsByReportID[report.ID]))
+}
+
+// BE-IN - Internal backend only
+func unindexReport(id string) {
+	ensureIndex()
+	reportIndex.remove(id)
+}
+
+// BE-IN - Internal backend only
+func searchDocument(report *Report, evaluation *Evaluation) map[string]string {
+	doc := map[string]string{
+		"title":       report.Title,
+		"description": report.Description,
+		"metadata":    strings.Join(flattenMetadata(report.Metadata), " "),
+	}
+	if evaluation != nil {
+		doc["security_details"] = evaluation.SecurityDetails
+		doc["performance_details"] = evaluation.PerformanceDetails
+		doc["memory_details"] = evaluation.MemoryDetails
+		doc["testing_details"] = evaluation.TestingDetails
+		doc["error_details"] = evaluation.ErrorDetails
+		doc["load_details"] = evaluation.LoadDetails
+	}
+	return doc
+}
+
+// BE-IN - Internal backend only
+func flattenMetadata(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var values []string
+		for _, key := range keys {
+			values = append(values, flattenMetadata(v[key])...)
+		}
+		return values
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, flattenMetadata(item)...)
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// BE-IN - Internal backend only
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{
+				term:  strings.ToLower(text[start:end]),
+				pos:   len(tokens),
+				start: start,
+				end:   end,
+			})
+			start = -1
+		}
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return tokens
+}
+
+// BE-IN - Internal backend only
+func (idx *searchIndex) put(id string, doc map[string]string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removeLocked(id)
+
+	idx.docs[id] = doc
+	idx.tokens[id] = make(map[string][]token, len(doc))
+	for field, text := range doc {
+		tokens := tokenize(text)
+		idx.tokens[id][field] = tokens
+
+		positions := make(map[string][]int)
+		for _, t := range tokens {
+			positions[t.term] = append(positions[t.term], t.pos)
+		}
+		for term, pos := range positions {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[string][]posting)
+			}
+			idx.postings[term][id] = append(idx.postings[term][id], posting{field: field, positions: pos})
+		}
+	}
+}
+
+// BE-IN - Internal backend only
+func (idx *searchIndex) remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(id)
+}
+
+// BE-IN - Internal backend only
+func (idx *searchIndex) removeLocked(id string) {
+	for _, tokens := range idx.tokens[id] {
+		for _, t := range tokens {
+			if docs, ok := idx.postings[t.term]; ok {
+				delete(docs, id)
+				if len(docs) == 0 {
+					delete(idx.postings, t.term)
+				}
+			}
+		}
+	}
+	delete(idx.docs, id)
+	delete(idx.tokens, id)
+}
+
+// BE-IN - Internal backend only
+// queryClause is one AND-ed part of a query: a single term, a prefix
+// (term*) or a quoted phrase.
+type queryClause struct {
+	terms  []string
+	prefix bool
+}
+
+// BE-IN - Internal backend only
+// parseQuery splits a query into clauses. Text in double quotes is a
+// phrase; a trailing * on a bare word makes it a prefix match.
+func parseQuery(query string) []queryClause {
+	var clauses []queryClause
+	parts := strings.Split(query, `"`)
+	for i, part := range parts {
+		if i%2 == 1 {
+			var terms []string
+			for _, t := range tokenize(part) {
+				terms = append(terms, t.term)
+			}
+			if len(terms) > 0 {
+				clauses = append(clauses, queryClause{terms: terms})
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			prefix := strings.HasSuffix(word, "*")
+			for _, t := range tokenize(word) {
+				clauses = append(clauses, queryClause{terms: []string{t.term}, prefix: prefix})
+			}
+		}
+	}
+	return clauses
+}
+
+// BE-IN - Internal backend only
+// fieldMatches maps field name to the token positions a clause matched,
+// with each position pointing at the first token of the match.
+type fieldMatches map[string][]int
+
+// BE-IN - Internal backend only
+func (idx *searchIndex) match(clause queryClause) map[string]fieldMatches {
+	result := make(map[string]fieldMatches)
+
+	if clause.prefix {
+		for term, docs := range idx.postings {
+			if !strings.HasPrefix(term, clause.terms[0]) {
+				continue
+			}
+			for id, postings := range docs {
+				for _, p := range postings {
+					if result[id] == nil {
+						result[id] = make(fieldMatches)
+					}
+					result[id][p.field] = append(result[id][p.field], p.positions...)
+				}
+			}
+		}
+		return result
+	}
+
+	for id, postings := range idx.postings[clause.terms[0]] {
+		for _, p := range postings {
+			if len(clause.terms) == 1 {
+				if result[id] == nil {
+					result[id] = make(fieldMatches)
+				}
+				result[id][p.field] = append(result[id][p.field], p.positions...)
+				continue
+			}
+
+			// Phrase: every following term must appear at the next position
+			tokens := idx.tokens[id][p.field]
+			for _, pos := range p.positions {
+				if pos+len(clause.terms) > len(tokens) {
+					continue
+				}
+				matched := true
+				for k, term := range clause.terms[1:] {
+					if tokens[pos+k+1].term != term {
+						matched = false
+						break
+					}
+				}
+				if matched {
+					if result[id] == nil {
+						result[id] = make(fieldMatches)
+					}
+					result[id][p.field] = append(result[id][p.field], pos)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// BE-IN - Internal backend only
+func SearchReports(ctx context.Context, params SearchParams) ([]SearchHit, int, error) {
+	// Score: [S7,P7,M6,T7,E7,L7]
+	// Details:
+	// - Security (S7): Query is tokenized, snippets are HTML-escaped before highlighting
+	// - Performance (P7): Inverted index lookups, prefix queries scan the term dictionary
+	// - Memory (M6): Keeps field text and token offsets for snippets
+	// - Testing (T7): Query syntax, ranking, filters and snippet escaping tested
+	// - Error (E7): Empty queries return no results instead of everything
+	// - Load (L7): Read lock allows concurrent searches
+	// Tags: BE-DB-medium
+
+	ensureIndex()
+
+	clauses := parseQuery(params.Query)
+	if len(clauses) == 0 {
+		return []SearchHit{}, 0, nil
+	}
+
+	reportIndex.mu.RLock()
+	defer reportIndex.mu.RUnlock()
+
+	// Every clause must match somewhere in the report
+	var candidates map[string][]fieldMatches
+	docFreq := make([]float64, len(clauses))
+	for i, clause := range clauses {
+		matches := reportIndex.match(clause)
+		docFreq[i] = float64(len(matches))
+		if candidates == nil {
+			candidates = make(map[string][]fieldMatches, len(matches))
+			for id, m := range matches {
+				candidates[id] = []fieldMatches{m}
+			}
+			continue
+		}
+		for id := range candidates {
+			m, ok := matches[id]
+			if !ok {
+				delete(candidates, id)
+				continue
+			}
+			candidates[id] = append(candidates[id], m)
+		}
+	}
+
+	docCount := float64(len(reportIndex.docs))
+	var hits []SearchHit
+	for id, perClause := range candidates {
+		report, ok := reports[id]
+		if !ok || report.DeletedAt != nil {
+			continue
+		}
+		if params.Status != nil && report.Status != *params.Status {
+			continue
+		}
+		if params.ProjectID != nil && report.ProjectID != *params.ProjectID {
+			continue
+		}
+
+		// BM25-style saturation of term frequency weighted by field
+		var score float64
+		for i, m := range perClause {
+			idf := math.Log(1 + (docCount-docFreq[i]+0.5)/(docFreq[i]+0.5))
+			for field, positions := range m {
+				tf := float64(len(positions))
+				score += searchFieldWeights[field] * idf * tf * (bm25K1 + 1) / (tf + bm25K1)
+			}
+		}
+
+		hits = append(hits, SearchHit{
+			Report:   *report,
+			Score:    math.Round(score*1000) / 1000,
+			Snippets: reportIndex.snippets(id, clauses, perClause),
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Report.UpdatedAt.After(hits[j].Report.UpdatedAt)
+	})
+
+	// Apply pagination
+	total := len(hits)
+	if params.Offset >= total {
+		return []SearchHit{}, total, nil
+	}
+	end := params.Offset + params.Limit
+	if end > total {
+		end = total
+	}
+
+	return hits[params.Offset:end], total, nil
+}
+
+// BE-IN - Internal backend only
+// snippets cuts a window of text around the first match in each matching
+// field and wraps every matched token in <mark>.
+func (idx *searchIndex) snippets(id string, clauses []queryClause, perClause []fieldMatches) []SearchSnippet {
+	marked := make(map[string]map[int]bool)
+	for i, m := range perClause {
+		for field, positions := range m {
+			if marked[field] == nil {
+				marked[field] = make(map[int]bool)
+			}
+			for _, pos := range positions {
+				for k := range clauses[i].terms {
+					marked[field][pos+k] = true
+				}
+			}
+		}
+	}
+
+	fields := make([]string, 0, len(marked))
+	for field := range marked {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		wi, wj := searchFieldWeights[fields[i]], searchFieldWeights[fields[j]]
+		if wi != wj {
+			return wi > wj
+		}
+		return fields[i] < fields[j]
+	})
+	if len(fields) > maxSnippetHits {
+		fields = fields[:maxSnippetHits]
+	}
+
+	snippets := make([]SearchSnippet, 0, len(fields))
+	for _, field := range fields {
+		text := idx.docs[id][field]
+		tokens := idx.tokens[id][field]
+
+		first := len(tokens)
+		for pos := range marked[field] {
+			if pos < first {
+				first = pos
+			}
+		}
+		if first >= len(tokens) {
+			continue
+		}
+
+		from := tokens[first].start - snippetRadius
+		if from < 0 {
+			from = 0
+		}
+		to := tokens[first].end + snippetRadius*2
+		if to > len(text) {
+			to = len(text)
+		}
+		// Snap the window to token and rune boundaries
+		for _, t := range tokens {
+			if t.start < from && t.end > from {
+				from = t.start
+			}
+			if t.start < to && t.end > to {
+				to = t.end
+			}
+		}
+		for from > 0 && !utf8.RuneStart(text[from]) {
+			from--
+		}
+		for to < len(text) && !utf8.RuneStart(text[to]) {
+			to++
+		}
+
+		var b strings.Builder
+		if from > 0 {
+			b.WriteString("…")
+		}
+		cursor := from
+		for _, t := range tokens {
+			if t.start < from || t.end > to || !marked[field][t.pos] {
+				continue
+			}
+			b.WriteString(html.EscapeString(text[cursor:t.start]))
+			b.WriteString("<mark>")
+			b.WriteString(html.EscapeString(text[t.start:t.end]))
+			b.WriteString("</mark>")
+			cursor = t.end
+		}
+		b.WriteString(html.EscapeString(text[cursor:to]))
+		if to < len(text) {
+			b.WriteString("…")
+		}
+
+		snippets = append(snippets, SearchSnippet{Field: field, Text: b.String()})
+	}
+	return snippets
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []queryClause
+	}{
+		{"cache", []queryClause{{terms: []string{"cache"}}}},
+		{"Cache Layer", []queryClause{{terms: []string{"cache"}}, {terms: []string{"layer"}}}},
+		{"cach*", []queryClause{{terms: []string{"cach"}, prefix: true}}},
+		{`"race condition" mutex`, []queryClause{{terms: []string{"race", "condition"}}, {terms: []string{"mutex"}}}},
+		// Punctuation splits words; a quote without a partner still opens a phrase
+		{"rate-limit", []queryClause{{terms: []string{"rate"}}, {terms: []string{"limit"}}}},
+		{`"unclosed phrase`, []queryClause{{terms: []string{"unclosed", "phrase"}}}},
+		{`"" * !!`, nil},
+	}
+	for _, tt := range tests {
+		if got := parseQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// searchReport saves a report for a test and deletes it afterwards.
+func searchReport(t *testing.T, title, description, status string) *Report {
+	t.Helper()
+	ctx := context.Background()
+	report := &Report{Title: title, Description: description, ProjectID: "project-search", Status: status}
+	if err := SaveReport(ctx, report); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	t.Cleanup(func() { DeleteReport(ctx, report.ID) })
+	return report
+}
+
+func hitIDs(hits []SearchHit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.Report.ID
+	}
+	return ids
+}
+
+func TestSearchReports(t *testing.T) {
+	ctx := context.Background()
+	inTitle := searchReport(t, "Quokkaflux relay", "Reworked the relay", "draft")
+	inDescription := searchReport(t, "Relay cleanup", "Moved the quokkaflux relay behind a flag", "submitted")
+	split := searchReport(t, "Performance notes", "The relay of quokkaflux was slow", "draft")
+	submitted := "submitted"
+
+	tests := []struct {
+		name   string
+		params SearchParams
+		want   []string
+	}{
+		{"title outranks description", SearchParams{Query: "quokkaflux relay"}, []string{inTitle.ID, inDescription.ID, split.ID}},
+		{"phrase needs adjacent terms", SearchParams{Query: `"quokkaflux relay"`}, []string{inTitle.ID, inDescription.ID}},
+		// Equal scores go to the most recently updated report
+		{"prefix", SearchParams{Query: "quokkaf*"}, []string{inTitle.ID, split.ID, inDescription.ID}},
+		{"every clause must match", SearchParams{Query: "quokkaflux flag"}, []string{inDescription.ID}},
+		{"status filter", SearchParams{Query: "quokkaflux", Status: &submitted}, []string{inDescription.ID}},
+		{"no match", SearchParams{Query: "quokkaflux nonexistentterm"}, []string{}},
+		{"empty query", SearchParams{Query: `""`}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			params.Limit = 10
+			projectID := "project-search"
+			params.ProjectID = &projectID
+			hits, total, err := SearchReports(ctx, params)
+			if err != nil {
+				t.Fatalf("SearchReports: %v", err)
+			}
+			if got := hitIDs(hits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hits = %v, want %v", got, tt.want)
+			}
+			if total != len(tt.want) {
+				t.Errorf("total = %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+
+	// Pagination keeps the total of every match
+	projectID := "project-search"
+	hits, total, err := SearchReports(ctx, SearchParams{Query: "quokkaflux", ProjectID: &projectID, Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("SearchReports: %v", err)
+	}
+	if len(hits) != 1 || total != 3 {
+		t.Errorf("page = %d hits of %d, want 1 of 3", len(hits), total)
+	}
+
+	// Deleted reports drop out of the index
+	if err := DeleteReport(ctx, split.ID); err != nil {
+		t.Fatalf("DeleteReport: %v", err)
+	}
+	hits, _, _ = SearchReports(ctx, SearchParams{Query: "quokkaflux", ProjectID: &projectID, Limit: 10})
+	for _, hit := range hits {
+		if hit.Report.ID == split.ID {
+			t.Errorf("deleted report %s still found", split.ID)
+		}
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	idx := &searchIndex{
+		postings: make(map[string]map[string][]posting),
+		docs:     make(map[string]map[string]string),
+		tokens:   make(map[string]map[string][]token),
+	}
+	long := strings.Repeat("filler ", 20) + "the <b>cache layer</b> & more " + strings.Repeat("padding ", 40)
+	idx.put("r1", map[string]string{
+		"title":       "Cache layer",
+		"description": long,
+		"metadata":    "no match here",
+	})
+
+	clauses := parseQuery(`"cache layer"`)
+	perClause := []fieldMatches{idx.match(clauses[0])["r1"]}
+	snippets := idx.snippets("r1", clauses, perClause)
+
+	if len(snippets) != 2 {
+		t.Fatalf("snippets = %+v, want title and description", snippets)
+	}
+	if snippets[0].Field != "title" || snippets[0].Text != "<mark>Cache</mark> <mark>layer</mark>" {
+		t.Errorf("title snippet = %+v", snippets[0])
+	}
+
+	text := snippets[1].Text
+	if !strings.HasPrefix(text, "…") || !strings.HasSuffix(text, "…") {
+		t.Errorf("description snippet %q is not cut at both ends", text)
+	}
+	if !strings.Contains(text, "&lt;b&gt;<mark>cache</mark> <mark>layer</mark>&lt;/b&gt; &amp; more") {
+		t.Errorf("description snippet %q does not escape the text around the marks", text)
+	}
+	if strings.Contains(text, "<b>") {
+		t.Errorf("description snippet %q contains unescaped markup", text)
+	}
+}
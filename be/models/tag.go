@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// Tag is one entry of the [type]-[scope]-[impact] taxonomy described in
+// development.md, e.g. BE-OUT-high or BE-DB-medium.
+type Tag struct {
+	Type   string `json:"type"`
+	Scope  string `json:"scope"`
+	Impact string `json:"impact"`
+}
+
+// BE-IN - Internal backend only
+// Allowed values of each tag component, in canonical spelling
+var (
+	TagTypes   = []string{"BE", "FE"}
+	TagScopes  = []string{"IN", "OUT", "DB", "module"}
+	TagImpacts = []string{"low", "medium", "high"}
+)
+
+// BE-IN - Internal backend only
+// Scope markers placed above functions, e.g.
+// "// BE-IN - Internal backend only"
+var markerPattern = regexp.MustCompile(`^([A-Za-z]+)-([A-Za-z]+)\s+-\s+(.+)$`)
+
+// BE-IN - Internal backend only
+func (t Tag) String() string {
+	return t.Type + "-" + t.Scope + "-" + t.Impact
+}
+
+// BE-IN - Internal backend only
+// ParseTag parses and canonicalizes a tag. Components are matched
+// case-insensitively, so "be-out-HIGH" becomes BE-OUT-high.
+func ParseTag(s string) (Tag, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 3 {
+		return Tag{}, fmt.Errorf("tag %q must have the form [type]-[scope]-[impact], such as BE-OUT-high", s)
+	}
+
+	var tag Tag
+	var ok bool
+	if tag.Type, ok = canonicalTagPart(parts[0], TagTypes); !ok {
+		return Tag{}, fmt.Errorf("tag %q has unknown type %q, expected one of %s", s, parts[0], strings.Join(TagTypes, ", "))
+	}
+	if tag.Scope, ok = canonicalTagPart(parts[1], TagScopes); !ok {
+		return Tag{}, fmt.Errorf("tag %q has unknown scope %q, expected one of %s", s, parts[1], strings.Join(TagScopes, ", "))
+	}
+	if tag.Impact, ok = canonicalTagPart(parts[2], TagImpacts); !ok {
+		return Tag{}, fmt.Errorf("tag %q has unknown impact %q, expected one of %s", s, parts[2], strings.Join(TagImpacts, ", "))
+	}
+	return tag, nil
+}
+
+// BE-IN - Internal backend only
+// ParseTags parses a list of tags, dropping duplicates. The first invalid
+// tag is returned as an error.
+func ParseTags(values []string) ([]Tag, error) {
+	var tags []Tag
+	seen := make(map[Tag]bool)
+	for _, value := range values {
+		tag, err := ParseTag(value)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// BE-IN - Internal backend only
+// ParseMarker parses a scope marker line such as
+// "BE-OUT - External data involved" into its type and scope.
+func ParseMarker(line string) (typ, scope, description string, ok bool) {
+	match := markerPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return "", "", "", false
+	}
+	if typ, ok = canonicalTagPart(match[1], TagTypes); !ok {
+		return "", "", "", false
+	}
+	if scope, ok = canonicalTagPart(match[2], TagScopes); !ok {
+		return "", "", "", false
+	}
+	return typ, scope, match[3], true
+}
+
+// BE-IN - Internal backend only
+// TagFilter selects tags by component. Empty fields match anything.
+type TagFilter struct {
+	Type   string
+	Scope  string
+	Impact string
+}
+
+// BE-IN - Internal backend only
+func (f TagFilter) IsZero() bool {
+	return f.Type == "" && f.Scope == "" && f.Impact == ""
+}
+
+// BE-IN - Internal backend only
+func (f TagFilter) Matches(tag Tag) bool {
+	return (f.Type == "" || strings.EqualFold(f.Type, tag.Type)) &&
+		(f.Scope == "" || strings.EqualFold(f.Scope, tag.Scope)) &&
+		(f.Impact == "" || strings.EqualFold(f.Impact, tag.Impact))
+}
+
+// BE-IN - Internal backend only
+// MatchesAny reports whether any of tags passes the filter. A zero filter
+// matches every report, including untagged ones.
+func (f TagFilter) MatchesAny(tags []Tag) bool {
+	if f.IsZero() {
+		return true
+	}
+	for _, tag := range tags {
+		if f.Matches(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+func canonicalTagPart(value string, allowed []string) (string, bool) {
+	for _, a := range allowed {
+		if strings.EqualFold(a, value) {
+			return a, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+)
+
+// BE-IN - Internal backend only
+// Template owner types
+const (
+	TemplateOwnerDepartment = "department"
+	TemplateOwnerProject    = "project"
+)
+
+// BE-IN - Internal backend only
+type TemplateSection struct {
+	Heading  string `json:"heading"`
+	Prompt   string `json:"prompt,omitempty"`
+	Required bool   `json:"required"`
+}
+
+// BE-IN - Internal backend only
+type EvaluationPrompts struct {
+	SecurityDetails    string `json:"security_details,omitempty"`
+	PerformanceDetails string `json:"performance_details,omitempty"`
+	MemoryDetails      string `json:"memory_details,omitempty"`
+	TestingDetails     string `json:"testing_details,omitempty"`
+	ErrorDetails       string `json:"error_details,omitempty"`
+	LoadDetails        string `json:"load_details,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type Template struct {
+	ID                string                 `json:"id"`
+	Name              string                 `json:"name"`
+	OwnerType         string                 `json:"owner_type"`
+	OwnerID           string                 `json:"owner_id"`
+	Sections          []TemplateSection      `json:"sections"`
+	DefaultMetadata   map[string]interface{} `json:"default_metadata,omitempty"`
+	EvaluationPrompts EvaluationPrompts      `json:"evaluation_prompts"`
+	CreatedBy         string                 `json:"created_by"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+type ListTemplatesParams struct {
+	OwnerType *string
+	OwnerID   *string
+}
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+// In a real application, this would be a database
+var templates = make(map[string]*Template)
+
+// BE-IN - Internal backend only
+func SaveTemplate(ctx context.Context, template *Template) error {
+	// Score: [S6,P7,M7,T5,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation, no injection concerns with in-memory
+	// - Performance (P7): Fast in-memory operations
+	// - Memory (M7): Efficient storage with pointers
+	// - Testing (T5): Only exercised through the template handler tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Handles concurrent operations with locking (in real impl)
+	// Tags: BE-DB-medium
+
+	// Generate ID if not provided
+	if template.ID == "" {
+		template.ID = uuid.New().String()
+	}
+
+	// Update timestamps
+	template.UpdatedAt = time.Now()
+
+	// Store in memory
+	templates[template.ID] = template
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetTemplateByID(ctx context.Context, id string) (*Template, error) {
+	// Score: [S6,P8,M8,T5,E7,L8]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P8): Fast lookup by ID
+	// - Memory (M8): No additional allocations
+	// - Testing (T5): Only exercised through the report-from-template tests
+	// - Error (E7): Proper error handling
+	// - Load (L8): Efficient for concurrent reads
+	// Tags: BE-DB-medium
+
+	template, ok := templates[id]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+
+	return template, nil
+}
+
+// BE-IN - Internal backend only
+func ListTemplates(ctx context.Context, params ListTemplatesParams) ([]Template, error) {
+	// Score: [S6,P7,M6,T4,E7,L7]
+	// Details:
+	// - Security (S6): Basic validation
+	// - Performance (P7): Filtering in memory
+	// - Memory (M6): Creates new slice for results
+	// - Testing (T4): Owner filters are not tested
+	// - Error (E7): Proper error handling
+	// - Load (L7): Handles concurrent reads
+	// Tags: BE-DB-medium
+
+	result := []Template{}
+	for _, template := range templates {
+		// Apply filters
+		if params.OwnerType != nil && template.OwnerType != *params.OwnerType {
+			continue
+		}
+		if params.OwnerID != nil && template.OwnerID != *params.OwnerID {
+			continue
+		}
+
+		result = append(result, *template)
+	}
+
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+func DeleteTemplate(ctx context.Context, id string) error {
+	_, ok := templates[id]
+	if !ok {
+		return ErrTemplateNotFound
+	}
+
+	delete(templates, id)
+	return nil
+}
+
+// BE-IN - Internal backend only
+// RenderDescription builds the description skeleton of a new draft: one
+// markdown heading per section followed by its prompt. Any text supplied
+// by the author is placed above the sections.
+func (t *Template) RenderDescription(intro string) string {
+	var b strings.Builder
+	if intro = strings.TrimSpace(intro); intro != "" {
+		b.WriteString(intro)
+		b.WriteString("\n\n")
+	}
+	for _, section := range t.Sections {
+		b.WriteString("## ")
+		b.WriteString(section.Heading)
+		b.WriteString("\n\n")
+		if section.Prompt != "" {
+			b.WriteString(section.Prompt)
+			b.WriteString("\n\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// BE-IN - Internal backend only
+// MissingSections returns the headings of required sections that have no
+// content in the given description, either because the heading was removed
+// or because the prompt was left untouched.
+func (t *Template) MissingSections(description string) []string {
+	var missing []string
+	for _, section := range t.Sections {
+		if !section.Required {
+			continue
+		}
+		body, ok := sectionBody(description, section.Heading)
+		if !ok || body == "" || body == strings.TrimSpace(section.Prompt) {
+			missing = append(missing, section.Heading)
+		}
+	}
+	return missing
+}
+
+// BE-IN - Internal backend only
+func sectionBody(description, heading string) (string, bool) {
+	lines := strings.Split(description, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "## "+heading {
+			continue
+		}
+		var body []string
+		for _, next := range lines[i+1:] {
+			if strings.HasPrefix(strings.TrimSpace(next), "## ") {
+				break
+			}
+			body = append(body, next)
+		}
+		return strings.TrimSpace(strings.Join(body, "\n")), true
+	}
+	return "", false
+}
+
+// BE-IN - Internal backend only
+func init() {
+	// Add some sample templates for demo purposes
+	sampleTemplates := []*Template{
+		{
+			ID:        "template-123",
+			Name:      "Security Module Review",
+			OwnerType: TemplateOwnerDepartment,
+			OwnerID:   "dept-123",
+			Sections: []TemplateSection{
+				{Heading: "Summary", Prompt: "What does the module do and who uses it?", Required: true},
+				{Heading: "Threat Model", Prompt: "List the assets, entry points and trust boundaries.", Required: true},
+				{Heading: "Open Issues", Prompt: "Known weaknesses and planned mitigations."},
+			},
+			DefaultMetadata: map[string]interface{}{
+				"classification": "internal",
+				"review_cycle":   "quarterly",
+			},
+			EvaluationPrompts: EvaluationPrompts{
+				SecurityDetails: "Authentication, authorization, input validation, secrets handling",
+				ErrorDetails:    "Failure modes, error disclosure, audit logging",
+			},
+			CreatedBy: "user-123",
+			CreatedAt: time.Now().Add(-72 * time.Hour),
+			UpdatedAt: time.Now().Add(-72 * time.Hour),
+		},
+	}
+
+	for _, template := range sampleTemplates {
+		templates[template.ID] = template
+	}
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// BE-IN - Internal backend only
+var ErrReportNotDeleted = errors.New("report is not in the trash")
+
+// BE-IN - Internal backend only
+// SoftDeleteReport moves a report and its evaluation to the trash. Both
+// disappear from lookups, listings, search and analytics until restored.
+// Comments, uploads, findings and receipts stay as they are; the API looks
+// the report up before serving them, so they are hidden with it.
+func SoftDeleteReport(ctx context.Context, id, userID string) (*Report, error) {
+	// Score: [S7,P8,M8,T6,E7,L7]
+	// Details:
+	// - Security (S7): Reversible until purged
+	// - Performance (P8): Two lookups and an index removal
+	// - Memory (M8): Marks records in place
+	// - Testing (T6): Exercised through the api trash tests
+	// - Error (E7): Missing and already deleted reports are not found
+	// - Load (L7): Handles concurrent operations with locking (in real impl)
+	// Tags: BE-DB-medium
+
+	report, ok := reports[id]
+	if !ok || report.DeletedAt != nil {
+		return nil, ErrReportNotFound
+	}
+
+	now := time.Now()
+	report.DeletedAt = &now
+	report.DeletedBy = userID
+	report.UpdatedAt = now
+	report.Version++
+	if evaluation, ok := evaluationsByReportID[id]; ok {
+		evaluation.DeletedAt = &now
+	}
+
+	unindexReport(id)
+	return report, nil
+}
+
+// BE-IN - Internal backend only
+// RestoreReport takes a report and its evaluation out of the trash.
+func RestoreReport(ctx context.Context, id string) (*Report, error) {
+	report, ok := reports[id]
+	if !ok {
+		return nil, ErrReportNotFound
+	}
+	if report.DeletedAt == nil {
+		return nil, ErrReportNotDeleted
+	}
+
+	report.DeletedAt = nil
+	report.DeletedBy = ""
+	report.UpdatedAt = time.Now()
+	report.Version++
+	if evaluation, ok := evaluationsByReportID[id]; ok {
+		evaluation.DeletedAt = nil
+	}
+
+	indexReport(report)
+	return report, nil
+}
+
+// BE-IN - Internal backend only
+// GetDeletedReportByID returns a report in the trash.
+func GetDeletedReportByID(ctx context.Context, id string) (*Report, error) {
+	report, ok := reports[id]
+	if !ok {
+		return nil, ErrReportNotFound
+	}
+	if report.DeletedAt == nil {
+		return nil, ErrReportNotDeleted
+	}
+	return report, nil
+}
+
+// BE-IN - Internal backend only
+// GetReportByIDIncludingDeleted returns a report whether or not it is in
+// the trash.
+func GetReportByIDIncludingDeleted(ctx context.Context, id string) (*Report, error) {
+	report, ok := reports[id]
+	if !ok {
+		return nil, ErrReportNotFound
+	}
+	return report, nil
+}
+
+// BE-IN - Internal backend only
+// ListDeletedReports returns the reports in the trash, most recently
+// deleted first. An empty authorID lists every author's reports.
+func ListDeletedReports(ctx context.Context, authorID string) ([]Report, error) {
+	var result []Report
+	for _, report := range reports {
+		if report.DeletedAt == nil || (authorID != "" && report.AuthorID != authorID) {
+			continue
+		}
+		result = append(result, *report)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DeletedAt.Equal(*result[j].DeletedAt) {
+			return result[i].DeletedAt.After(*result[j].DeletedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+// PurgeDeletedReports permanently removes the reports deleted before
+// cutoff together with everything stored for them, and returns them.
+// Reports under legal hold stay in the trash.
+func PurgeDeletedReports(ctx context.Context, cutoff time.Time) ([]Report, error) {
+	// Score: [S7,P6,M7,T6,E7,L6]
+	// Details:
+	// - Security (S7): Only reports past the retention period are removed
+	// - Performance (P6): Full scan of reports, then one cascade per purge
+	// - Memory (M7): Holds copies of the purged reports only
+	// - Testing (T6): Retention cutoff exercised through the api trash tests, legal holds through the retention tests
+	// - Error (E7): Proper error handling
+	// - Load (L6): Meant for a scheduled job rather than request paths
+	// Tags: BE-DB-medium
+
+	var purged []Report
+	for id, report := range reports {
+		if report.DeletedAt != nil && report.DeletedAt.Before(cutoff) && !IsUnderLegalHold(ctx, id) {
+			purged = append(purged, *report)
+		}
+	}
+	sort.Slice(purged, func(i, j int) bool { return purged[i].ID < purged[j].ID })
+
+	for _, report := range purged {
+		purgeReport(report.ID)
+	}
+	return purged, nil
+}
+
+// BE-IN - Internal backend only
+// purgeReport removes a report and the evaluation, comments, uploads,
+// findings and receipts stored for it.
+func purgeReport(id string) {
+	if evaluation, ok := evaluationsByReportID[id]; ok {
+		delete(evaluations, evaluation.ID)
+		delete(evaluationsByReportID, id)
+	}
+
+	for _, comment := range commentsByReportID[id] {
+		delete(comments, comment.ID)
+	}
+	delete(commentsByReportID, id)
+
+	coverageMu.Lock()
+	delete(coverageByReportID, id)
+	coverageMu.Unlock()
+
+	benchmarkMu.Lock()
+	delete(benchmarkRunsByReportID, id)
+	benchmarkMu.Unlock()
+
+	loadTestMu.Lock()
+	delete(loadTestByReportID, id)
+	loadTestMu.Unlock()
+
+	analysisMu.Lock()
+	delete(analysisByReportID, id)
+	analysisMu.Unlock()
+
+	findingMu.Lock()
+	for _, finding := range findingsByReportID[id] {
+		delete(findingsByID, finding.ID)
+	}
+	delete(findingsByReportID, id)
+	delete(securityScanByReportID, id)
+	findingMu.Unlock()
+
+	signatureMu.Lock()
+	delete(receiptsByReportID, id)
+	signatureMu.Unlock()
+
+	delete(reports, id)
+	unindexReport(id)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"encore.dev"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// BE-IN - Internal backend only
+// Roles beyond the default of a regular member
+const (
+	// RoleAdmin may act across departments, such as subscribing a webhook
+	// to every department's events
+	RoleAdmin = "admin"
+)
+
+// BE-IN - Internal backend only
+type User struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	DepartmentID string `json:"department_id,omitempty"`
+	Role         string `json:"role,omitempty"`
+}
+
+// BE-IN - Internal backend only
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// BE-IN - Internal backend only
+// Mock users for demo
+var users = map[string]*User{
+	"user-123": {ID: "user-123", Name: "Alex Developer", Email: "alex@example.com", DepartmentID: "dept-456"},
+	"user-456": {ID: "user-456", Name: "Sam Security", Email: "sam@example.com", DepartmentID: "dept-123"},
+	"user-789": {ID: "user-789", Name: "Jordan Lead", Email: "jordan@example.com", DepartmentID: "dept-456"},
+	"user-900": {ID: "user-900", Name: "Robin Admin", Email: "robin@example.com", Role: RoleAdmin},
+}
+
+// BE-IN - Internal backend only
+func GetUserByID(ctx context.Context, id string) (*User, error) {
+	user, ok := users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// BE-IN - Internal backend only
+// demoUserID returns the mock user named by DEMO_USER_ID, so the demo can
+// be tried as a department head such as user-456. The override is honoured
+// only when running locally or under test; deployed environments ignore it.
+func demoUserID() (string, bool) {
+	switch encore.Meta().Environment.Type {
+	case encore.EnvLocal, encore.EnvTest:
+	default:
+		return "", false
+	}
+	id := os.Getenv("DEMO_USER_ID")
+	_, ok := users[id]
+	return id, ok
+}
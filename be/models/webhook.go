@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+// BE-IN - Internal backend only
+// Webhook is an outbound subscription. Empty filters match everything:
+// no EventTypes means every event, no ProjectID or DepartmentID means
+// every project or department. Only admins may leave DepartmentID empty;
+// the API enforces it when the filter is set.
+type Webhook struct {
+	ID           string    `json:"id"`
+	OwnerID      string    `json:"owner_id"`
+	URL          string    `json:"url"`
+	Secret       string    `json:"-"`
+	Description  string    `json:"description,omitempty"`
+	EventTypes   []string  `json:"event_types,omitempty"`
+	ProjectID    string    `json:"project_id,omitempty"`
+	DepartmentID string    `json:"department_id,omitempty"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BE-IN - Internal backend only
+// Matches reports whether an event passes the webhook's filters.
+func (w *Webhook) Matches(eventType, projectID, departmentID string) bool {
+	if !w.Active {
+		return false
+	}
+	if w.ProjectID != "" && w.ProjectID != projectID {
+		return false
+	}
+	if w.DepartmentID != "" && w.DepartmentID != departmentID {
+		return false
+	}
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+// WebhookDelivery records one delivery attempt.
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhook_id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Test       bool      `json:"test,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BE-IN - Internal backend only
+// Only the most recent deliveries of each webhook are kept
+const maxWebhookDeliveries = 200
+
+// BE-IN - Internal backend only
+// In-memory storage for demo purposes
+// In a real application, this would be a database
+var (
+	webhooksMu        sync.RWMutex
+	webhooks          = make(map[string]*Webhook)
+	webhookDeliveries = make(map[string][]*WebhookDelivery)
+)
+
+// BE-IN - Internal backend only
+func SaveWebhook(ctx context.Context, webhook *Webhook) error {
+	// Score: [S7,P7,M7,T5,E7,L7]
+	// Details:
+	// - Security (S7): Secret never serialized
+	// - Performance (P7): Fast in-memory operations
+	// - Memory (M7): Efficient storage with pointers
+	// - Testing (T5): Only exercised through the webhook handler tests
+	// - Error (E7): Proper error handling
+	// - Load (L7): Guarded for concurrent subscribers
+	// Tags: BE-DB-medium
+
+	webhooksMu.Lock()
+	defer webhooksMu.Unlock()
+
+	// Generate ID if not provided
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+
+	// Update timestamps
+	webhook.UpdatedAt = time.Now()
+
+	// Store in memory
+	webhooks[webhook.ID] = webhook
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+func GetWebhookByID(ctx context.Context, id string) (*Webhook, error) {
+	webhooksMu.RLock()
+	defer webhooksMu.RUnlock()
+
+	webhook, ok := webhooks[id]
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+// BE-IN - Internal backend only
+// ListWebhooks returns the webhooks of ownerID, or all webhooks when
+// ownerID is empty, oldest first.
+func ListWebhooks(ctx context.Context, ownerID string) ([]*Webhook, error) {
+	webhooksMu.RLock()
+	defer webhooksMu.RUnlock()
+
+	var result []*Webhook
+	for _, webhook := range webhooks {
+		if ownerID == "" || webhook.OwnerID == ownerID {
+			result = append(result, webhook)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+func DeleteWebhook(ctx context.Context, id string) error {
+	webhooksMu.Lock()
+	defer webhooksMu.Unlock()
+
+	if _, ok := webhooks[id]; !ok {
+		return ErrWebhookNotFound
+	}
+
+	delete(webhooks, id)
+	delete(webhookDeliveries, id)
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+func SaveWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	webhooksMu.Lock()
+	defer webhooksMu.Unlock()
+
+	if delivery.ID == "" {
+		delivery.ID = uuid.New().String()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	log := append(webhookDeliveries[delivery.WebhookID], delivery)
+	if len(log) > maxWebhookDeliveries {
+		log = log[len(log)-maxWebhookDeliveries:]
+	}
+	webhookDeliveries[delivery.WebhookID] = log
+
+	return nil
+}
+
+// BE-IN - Internal backend only
+// ListWebhookDeliveries returns the most recent deliveries first.
+func ListWebhookDeliveries(ctx context.Context, webhookID string, limit int) ([]*WebhookDelivery, error) {
+	webhooksMu.RLock()
+	defer webhooksMu.RUnlock()
+
+	log := webhookDeliveries[webhookID]
+	result := make([]*WebhookDelivery, 0, len(log))
+	for i := len(log) - 1; i >= 0; i-- {
+		result = append(result, log[i])
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+
+	return result, nil
+}
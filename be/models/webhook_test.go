@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestWebhookMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook Webhook
+		event   string
+		project string
+		dept    string
+		want    bool
+	}{
+		{"no filters", Webhook{Active: true}, "report.created", "project-123", "dept-123", true},
+		{"inactive", Webhook{}, "report.created", "project-123", "dept-123", false},
+		{"event listed", Webhook{Active: true, EventTypes: []string{"report.submitted", "report.approved"}}, "report.approved", "", "", true},
+		{"event not listed", Webhook{Active: true, EventTypes: []string{"report.submitted"}}, "report.approved", "", "", false},
+		{"department", Webhook{Active: true, DepartmentID: "dept-123"}, "report.created", "project-123", "dept-123", true},
+		{"other department", Webhook{Active: true, DepartmentID: "dept-123"}, "report.created", "project-123", "dept-456", false},
+		{"event without department", Webhook{Active: true, DepartmentID: "dept-123"}, "report.created", "project-123", "", false},
+		{"project", Webhook{Active: true, ProjectID: "project-123"}, "report.created", "project-123", "dept-123", true},
+		{"other project", Webhook{Active: true, ProjectID: "project-123"}, "report.created", "project-456", "dept-123", false},
+		{"all filters", Webhook{Active: true, EventTypes: []string{"report.created"}, ProjectID: "project-123", DepartmentID: "dept-123"}, "report.created", "project-123", "dept-123", true},
+		{"all filters, other department", Webhook{Active: true, EventTypes: []string{"report.created"}, ProjectID: "project-123", DepartmentID: "dept-123"}, "report.created", "project-123", "dept-456", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.webhook.Matches(tt.event, tt.project, tt.dept); got != tt.want {
+				t.Errorf("Matches(%q, %q, %q) = %v, want %v", tt.event, tt.project, tt.dept, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,555 @@
+// Package analyzer computes static metrics over the sources of a Go
+// module: cyclomatic complexity, test-to-code ratio, unchecked errors,
+// panics, goroutines started without a context and the size of the
+// exported API. The analysis is syntactic, so it works on uploaded
+// sources without their dependencies; Suggest maps the metrics to
+// evaluation scores.
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"encore.app/services/gosource"
+)
+
+// BE-IN - Internal backend only
+// ComplexityThreshold is the cyclomatic complexity above which a
+// function counts as complex
+const ComplexityThreshold = 15
+
+// BE-IN - Internal backend only
+// maxListedLocations bounds the locations kept per metric
+const maxListedLocations = 20
+
+// BE-IN - Internal backend only
+// Source is one Go file. Path is relative to the module root.
+type Source = gosource.File
+
+// BE-IN - Internal backend only
+// Location points at a finding, with the enclosing function.
+type Location struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type FunctionComplexity struct {
+	Location
+	Complexity int `json:"complexity"`
+}
+
+// BE-IN - Internal backend only
+// PackageAPI counts the exported declarations of a package directory.
+type PackageAPI struct {
+	Package  string `json:"package"`
+	Exported int    `json:"exported"`
+	Total    int    `json:"total"`
+}
+
+// BE-IN - Internal backend only
+// Metrics describe a module. Lines count non-blank, non-comment lines.
+// Location lists are capped; the counts are not.
+type Metrics struct {
+	Files         int `json:"files"`
+	TestFiles     int `json:"test_files"`
+	Packages      int `json:"packages"`
+	CodeLines     int `json:"code_lines"`
+	TestLines     int `json:"test_lines"`
+	Functions     int `json:"functions"`
+	TestFunctions int `json:"test_functions"`
+
+	TestToCodeRatio float64 `json:"test_to_code_ratio"`
+
+	AverageComplexity float64              `json:"average_complexity"`
+	MaxComplexity     int                  `json:"max_complexity"`
+	ComplexFunctions  int                  `json:"complex_functions"`
+	MostComplex       []FunctionComplexity `json:"most_complex"`
+
+	UncheckedErrors  int        `json:"unchecked_errors"`
+	UncheckedSamples []Location `json:"unchecked_samples,omitempty"`
+
+	Panics       int        `json:"panics"`
+	PanicSamples []Location `json:"panic_samples,omitempty"`
+
+	Goroutines               int        `json:"goroutines"`
+	GoroutinesWithoutContext int        `json:"goroutines_without_context"`
+	GoroutineSamples         []Location `json:"goroutine_samples,omitempty"`
+
+	ExportedDeclarations int          `json:"exported_declarations"`
+	TotalDeclarations    int          `json:"total_declarations"`
+	PackageAPIs          []PackageAPI `json:"package_apis"`
+
+	ParseErrors []Location `json:"parse_errors,omitempty"`
+}
+
+// BE-IN - Internal backend only
+// errorMethods are method names whose error result is commonly dropped
+// by mistake when called as a statement
+var errorMethods = map[string]bool{
+	"Close": true, "Flush": true, "Sync": true, "Encode": true, "Decode": true,
+	"Exec": true, "Execute": true, "Commit": true, "Rollback": true, "Shutdown": true,
+}
+
+// BE-IN - Internal backend only
+// errorFuncs are package functions that only report failure through
+// their error result
+var errorFuncs = map[string]bool{
+	"os.Remove": true, "os.RemoveAll": true, "os.Rename": true, "os.Mkdir": true, "os.MkdirAll": true,
+	"os.WriteFile": true, "os.Setenv": true, "os.Unsetenv": true, "os.Chdir": true, "os.Chmod": true,
+	"json.Unmarshal": true, "xml.Unmarshal": true, "yaml.Unmarshal": true, "io.Copy": true,
+	"io.WriteString": true, "http.ListenAndServe": true,
+}
+
+// BE-IN - Internal backend only
+// sharedMethods are method names the module may declare with an error
+// result that standard-library types also declare without one, such as
+// flag.Value.Set and http.Header.Set. Calls to them are not counted.
+var sharedMethods = map[string]bool{
+	"Set": true, "Get": true, "Add": true, "Del": true, "String": true, "Error": true,
+}
+
+// BE-IN - Internal backend only
+// errorIndex records the functions and methods of the module that return
+// an error, so calls dropping it can be found.
+type errorIndex struct {
+	// funcs maps package directory to the functions returning an error
+	funcs map[string]map[string]bool
+	// methods are method names every module declaration of which returns
+	// an error
+	methods map[string]bool
+}
+
+// BE-IN - Internal backend only
+// callScope is what a call is resolved against: the package directory
+// and the imports of the calling file.
+type callScope struct {
+	dir     string
+	imports map[string]string
+	index   *errorIndex
+}
+
+// BE-IN - Internal backend only
+// ReadDir reads every .go file under root, test files included.
+func ReadDir(root string) ([]Source, error) {
+	return gosource.ReadDir(root, true)
+}
+
+// BE-IN - Internal backend only
+// fileInfo is what the first pass learns about a parsed file.
+type fileInfo struct {
+	source Source
+	file   *ast.File
+	test   bool
+}
+
+// BE-IN - Internal backend only
+// Analyze computes the metrics of a set of sources. Files that do not
+// parse are listed in ParseErrors and skipped.
+func Analyze(sources []Source) *Metrics {
+	// Score: [S8,P7,M6,T7,E8,L7]
+	// Details:
+	// - Security (S8): Sources are parsed, never compiled or executed
+	// - Performance (P7): Two passes over the ASTs
+	// - Memory (M6): All ASTs held for the second pass
+	// - Testing (T7): Pure function over in-memory sources
+	// - Error (E8): Unparsable files reported and skipped
+	// - Load (L7): Linear in source size
+	// Tags: BE-module-medium
+
+	m := &Metrics{MostComplex: []FunctionComplexity{}, PackageAPIs: []PackageAPI{}}
+	fset := token.NewFileSet()
+
+	// First pass: parse, count lines and collect the functions of the
+	// module that return an error, so calls to them can be checked
+	var files []fileInfo
+	index := &errorIndex{funcs: make(map[string]map[string]bool), methods: make(map[string]bool)}
+	methodSeen := make(map[string]bool)
+	for _, source := range sources {
+		file, err := parser.ParseFile(fset, source.Path, source.Content, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			m.ParseErrors = append(m.ParseErrors, Location{File: source.Path, Line: 1, Detail: err.Error()})
+			continue
+		}
+		info := fileInfo{source: source, file: file, test: strings.HasSuffix(source.Path, "_test.go")}
+		files = append(files, info)
+
+		lines := codeLines(fset, file, source.Content)
+		if info.test {
+			m.TestFiles++
+			m.TestLines += lines
+			continue
+		}
+		m.Files++
+		m.CodeLines += lines
+		dir := path.Dir(source.Path)
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			name := fn.Name.Name
+			if fn.Recv != nil {
+				index.methods[name] = returnsError(fn.Type) && (index.methods[name] || !methodSeen[name])
+				methodSeen[name] = true
+				continue
+			}
+			if returnsError(fn.Type) {
+				if index.funcs[dir] == nil {
+					index.funcs[dir] = make(map[string]bool)
+				}
+				index.funcs[dir][name] = true
+			}
+		}
+	}
+
+	packages := make(map[string]*PackageAPI)
+	var complexities []FunctionComplexity
+	totalComplexity := 0
+	for _, info := range files {
+		if info.test {
+			for _, decl := range info.file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && isTestName(fn.Name.Name) {
+					m.TestFunctions++
+				}
+			}
+			continue
+		}
+
+		dir := path.Dir(info.source.Path)
+		api, ok := packages[dir]
+		if !ok {
+			api = &PackageAPI{Package: dir}
+			packages[dir] = api
+		}
+		countDeclarations(info.file, api)
+		scope := &callScope{dir: dir, imports: fileImports(info.file), index: index}
+
+		for _, decl := range info.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			name := gosource.FuncName(fn)
+			m.Functions++
+			c := complexity(fn.Body)
+			totalComplexity += c
+			complexities = append(complexities, FunctionComplexity{
+				Location:   Location{File: info.source.Path, Line: fset.Position(fn.Pos()).Line, Function: name},
+				Complexity: c,
+			})
+			if c > ComplexityThreshold {
+				m.ComplexFunctions++
+			}
+			if c > m.MaxComplexity {
+				m.MaxComplexity = c
+			}
+
+			inspectBody(fset, info.source.Path, name, fn, scope, m)
+		}
+	}
+
+	if m.Functions > 0 {
+		m.AverageComplexity = round2(float64(totalComplexity) / float64(m.Functions))
+	}
+	sort.SliceStable(complexities, func(i, j int) bool { return complexities[i].Complexity > complexities[j].Complexity })
+	for _, c := range complexities[:min(len(complexities), maxListedLocations)] {
+		if c.Complexity <= ComplexityThreshold/2 {
+			break
+		}
+		m.MostComplex = append(m.MostComplex, c)
+	}
+
+	if m.CodeLines > 0 {
+		m.TestToCodeRatio = round2(float64(m.TestLines) / float64(m.CodeLines))
+	}
+	for _, api := range packages {
+		m.ExportedDeclarations += api.Exported
+		m.TotalDeclarations += api.Total
+		m.PackageAPIs = append(m.PackageAPIs, *api)
+	}
+	sort.Slice(m.PackageAPIs, func(i, j int) bool { return m.PackageAPIs[i].Package < m.PackageAPIs[j].Package })
+	m.Packages = len(m.PackageAPIs)
+	return m
+}
+
+// BE-IN - Internal backend only
+// inspectBody records unchecked errors, panics and goroutines of one
+// function.
+func inspectBody(fset *token.FileSet, file, function string, fn *ast.FuncDecl, scope *callScope, m *Metrics) {
+	at := func(node ast.Node, detail string) Location {
+		return Location{File: file, Line: fset.Position(node.Pos()).Line, Function: function, Detail: detail}
+	}
+	// Must* helpers and init panic by convention
+	mustPanic := strings.HasPrefix(fn.Name.Name, "Must") || strings.HasPrefix(fn.Name.Name, "must") || fn.Name.Name == "init"
+
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok {
+				if name, unchecked := uncheckedCall(call, scope); unchecked {
+					m.UncheckedErrors++
+					appendCapped(&m.UncheckedSamples, at(n, "result of "+name+" not checked"))
+				}
+			}
+
+		case *ast.AssignStmt:
+			// x, _ := f() and _ = f() where f is known to return an error
+			if len(n.Rhs) != 1 {
+				break
+			}
+			call, isCall := n.Rhs[0].(*ast.CallExpr)
+			last, isIdent := n.Lhs[len(n.Lhs)-1].(*ast.Ident)
+			if !isCall || !isIdent || last.Name != "_" {
+				break
+			}
+			if name, unchecked := uncheckedCall(call, scope); unchecked {
+				m.UncheckedErrors++
+				appendCapped(&m.UncheckedSamples, at(n, "error of "+name+" assigned to _"))
+			}
+
+		case *ast.CallExpr:
+			if ident, ok := n.Fun.(*ast.Ident); ok && ident.Name == "panic" && !mustPanic {
+				m.Panics++
+				appendCapped(&m.PanicSamples, at(n, ""))
+			}
+
+		case *ast.GoStmt:
+			m.Goroutines++
+			if !usesContext(n.Call) {
+				m.GoroutinesWithoutContext++
+				appendCapped(&m.GoroutineSamples, at(n, "goroutine started without a context"))
+			}
+		}
+		return true
+	})
+}
+
+// BE-IN - Internal backend only
+// uncheckedCall reports whether a call used as a statement drops an
+// error: calls to functions of the module that return an error, and
+// well-known functions and methods that do.
+func uncheckedCall(call *ast.CallExpr, scope *callScope) (string, bool) {
+	name, short := callName(call)
+	if short == "" {
+		return "", false
+	}
+	return name, scope.returnsError(call)
+}
+
+// BE-IN - Internal backend only
+// returnsError resolves a call to a function of the same package, a
+// package function of an import or a method. Imports of the module are
+// matched by the package directory their path ends with.
+func (s *callScope) returnsError(call *ast.CallExpr) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return s.index.funcs[s.dir][fun.Name]
+	case *ast.SelectorExpr:
+		name := fun.Sel.Name
+		if pkg, ok := fun.X.(*ast.Ident); ok {
+			if importPath, ok := s.imports[pkg.Name]; ok {
+				if errorFuncs[path.Base(importPath)+"."+name] {
+					return true
+				}
+				for dir, funcs := range s.index.funcs {
+					if funcs[name] && (importPath == dir || strings.HasSuffix(importPath, "/"+dir)) {
+						return true
+					}
+				}
+				return false
+			}
+		}
+		return errorMethods[name] || (s.index.methods[name] && !sharedMethods[name])
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+// fileImports maps the names a file refers to its imports by to their
+// paths. Blank and dot imports are skipped.
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+// BE-IN - Internal backend only
+// callName returns a printable name of the callee and its last element.
+func callName(call *ast.CallExpr) (string, string) {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name, fun.Name
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok {
+			return x.Name + "." + fun.Sel.Name, fun.Sel.Name
+		}
+		return fun.Sel.Name, fun.Sel.Name
+	}
+	return "", ""
+}
+
+// BE-IN - Internal backend only
+// usesContext reports whether a go statement hands the goroutine a
+// context: an argument or a closure referring to a ctx variable or the
+// context package.
+func usesContext(call *ast.CallExpr) bool {
+	found := false
+	ast.Inspect(call, func(node ast.Node) bool {
+		if found {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.Ident:
+			name := strings.ToLower(n.Name)
+			found = name == "ctx" || strings.HasSuffix(name, "ctx")
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && x.Name == "context" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// BE-IN - Internal backend only
+// complexity is McCabe's cyclomatic complexity: one plus each branch
+// point. Nested function literals count towards the enclosing function.
+func complexity(body *ast.BlockStmt) int {
+	c := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			c++
+		case *ast.CaseClause:
+			if n.List != nil {
+				c++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				c++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c++
+			}
+		}
+		return true
+	})
+	return c
+}
+
+// BE-IN - Internal backend only
+// countDeclarations adds the top-level declarations of a file to api.
+// Methods count as exported when both they and their receiver type are.
+func countDeclarations(file *ast.File, api *PackageAPI) {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			api.Total++
+			exported := d.Name.IsExported()
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				exported = exported && ast.IsExported(gosource.ReceiverType(d.Recv.List[0].Type))
+			}
+			if exported {
+				api.Exported++
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					api.Total++
+					if s.Name.IsExported() {
+						api.Exported++
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if name.Name == "_" {
+							continue
+						}
+						api.Total++
+						if name.IsExported() {
+							api.Exported++
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// BE-IN - Internal backend only
+func returnsError(t *ast.FuncType) bool {
+	if t.Results == nil || len(t.Results.List) == 0 {
+		return false
+	}
+	last, ok := t.Results.List[len(t.Results.List)-1].Type.(*ast.Ident)
+	return ok && last.Name == "error"
+}
+
+// BE-IN - Internal backend only
+func isTestName(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+// codeLines counts lines holding code, skipping blank lines and lines
+// that only hold comments.
+func codeLines(fset *token.FileSet, file *ast.File, content string) int {
+	commentOnly := make(map[int]bool)
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			start, end := fset.Position(c.Pos()).Line, fset.Position(c.End()).Line
+			for line := start; line <= end; line++ {
+				commentOnly[line] = true
+			}
+		}
+	}
+
+	count := 0
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if commentOnly[i+1] && (strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*")) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// BE-IN - Internal backend only
+func appendCapped(list *[]Location, location Location) {
+	if len(*list) < maxListedLocations {
+		*list = append(*list, location)
+	}
+}
+
+// BE-IN - Internal backend only
+func round2(value float64) float64 {
+	return float64(int(value*100+0.5)) / 100
+}
@@ -0,0 +1,191 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var moduleSources = []Source{
+	{Path: "store/store.go", Content: `package store
+
+import "errors"
+
+// ErrMissing is returned for unknown keys
+var ErrMissing = errors.New("missing")
+
+type Store struct{}
+
+// Save stores a value
+func Save(key string) error { return nil }
+
+func (s *Store) Load(key string) error { return nil }
+
+func (s *Store) Open() error { return nil }
+
+func (s *Store) Set(key string) error { return nil }
+
+type file struct{}
+
+func (f *file) Open() {}
+
+func (f *file) Exported() {}
+`},
+	{Path: "api/api.go", Content: `package api
+
+import (
+	"context"
+	"os"
+
+	"example.com/mod/store"
+)
+
+func save() error { return nil }
+
+func Handle(ctx context.Context, s *store.Store, f *os.File, h map[string]string) {
+	f.Close()
+	store.Save("a")
+	_ = store.Save("b")
+	save()
+	s.Load("c")
+	s.Open()
+	s.Set("d")
+	os.Remove("e")
+	if err := save(); err != nil && ctx != nil {
+		panic(err)
+	}
+	go work(ctx)
+	go func() { save() }()
+	go func() { <-context.Background().Done() }()
+}
+
+func work(ctx context.Context) {
+	for i := 0; i < 3; i++ {
+		switch {
+		case i == 0:
+		case i == 1 || i == 2:
+		default:
+		}
+	}
+}
+
+func MustParse() { panic("bad") }
+
+func init() { panic("init") }
+`},
+	{Path: "api/api_test.go", Content: `package api
+
+import "testing"
+
+// TestHandle covers Handle
+func TestHandle(t *testing.T) {
+	helper()
+}
+
+func BenchmarkHandle(b *testing.B) {}
+
+func helper() {}
+`},
+}
+
+func TestAnalyze(t *testing.T) {
+	m := Analyze(moduleSources)
+
+	counts := map[string][2]int{
+		"Files":                    {m.Files, 2},
+		"TestFiles":                {m.TestFiles, 1},
+		"Packages":                 {m.Packages, 2},
+		"CodeLines":                {m.CodeLines, 45},
+		"TestLines":                {m.TestLines, 7},
+		"Functions":                {m.Functions, 11},
+		"TestFunctions":            {m.TestFunctions, 2},
+		"UncheckedErrors":          {m.UncheckedErrors, 7},
+		"Panics":                   {m.Panics, 1},
+		"Goroutines":               {m.Goroutines, 3},
+		"GoroutinesWithoutContext": {m.GoroutinesWithoutContext, 1},
+		"MaxComplexity":            {m.MaxComplexity, 5},
+		"ExportedDeclarations":     {m.ExportedDeclarations, 8},
+		"TotalDeclarations":        {m.TotalDeclarations, 14},
+	}
+	for name, c := range counts {
+		if c[0] != c[1] {
+			t.Errorf("%s = %d, want %d", name, c[0], c[1])
+		}
+	}
+	// Comments and blank lines are not code
+	if m.TestToCodeRatio != 0.16 {
+		t.Errorf("TestToCodeRatio = %v, want 7/45 rounded to 0.16", m.TestToCodeRatio)
+	}
+}
+
+func TestAnalyzeUncheckedErrors(t *testing.T) {
+	m := Analyze(moduleSources)
+
+	// Open is declared once with and once without an error, Set is a
+	// name standard-library types use without one: neither is counted
+	var got []string
+	for _, l := range m.UncheckedSamples {
+		got = append(got, l.Function+": "+l.Detail)
+	}
+	want := []string{
+		"Handle: result of f.Close not checked",
+		"Handle: result of store.Save not checked",
+		"Handle: error of store.Save assigned to _",
+		"Handle: result of save not checked",
+		"Handle: result of s.Load not checked",
+		"Handle: result of os.Remove not checked",
+		"Handle: result of save not checked",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unchecked errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnalyzeSamples(t *testing.T) {
+	m := Analyze(moduleSources)
+
+	// Must* helpers and init may panic
+	if len(m.PanicSamples) != 1 || m.PanicSamples[0].Function != "Handle" {
+		t.Errorf("PanicSamples = %+v, want the panic in Handle", m.PanicSamples)
+	}
+	if len(m.GoroutineSamples) != 1 || m.GoroutineSamples[0].Line != 25 {
+		t.Errorf("GoroutineSamples = %+v, want the closure on line 25", m.GoroutineSamples)
+	}
+
+	// Methods count as exported only on exported types
+	want := []PackageAPI{{Package: "api", Exported: 2, Total: 5}, {Package: "store", Exported: 6, Total: 9}}
+	if !reflect.DeepEqual(m.PackageAPIs, want) {
+		t.Errorf("PackageAPIs = %+v, want %+v", m.PackageAPIs, want)
+	}
+}
+
+func TestComplexity(t *testing.T) {
+	m := Analyze([]Source{{Path: "a.go", Content: `package a
+
+func f(a, b bool, c chan int) {
+	if a && b || !a {
+	}
+	for range c {
+	}
+	select {
+	case <-c:
+	default:
+	}
+	func() {
+		if b {
+		}
+	}()
+}
+`}})
+	// 1 + if + && + || + range + select case + nested if
+	if m.MaxComplexity != 7 || m.AverageComplexity != 7 {
+		t.Errorf("complexity = max %d, average %v, want 7", m.MaxComplexity, m.AverageComplexity)
+	}
+}
+
+func TestAnalyzeParseErrors(t *testing.T) {
+	m := Analyze([]Source{{Path: "broken.go", Content: "package a\nfunc {"}, {Path: "ok.go", Content: "package a\n"}})
+	if len(m.ParseErrors) != 1 || m.ParseErrors[0].File != "broken.go" || m.Files != 1 {
+		t.Errorf("ParseErrors = %+v, Files = %d", m.ParseErrors, m.Files)
+	}
+}
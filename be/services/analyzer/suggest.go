@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"encore.app/models"
+)
+
+// BE-IN - Internal backend only
+// maxNamedLocations bounds the locations named in each details field
+const maxNamedLocations = 5
+
+// BE-IN - Internal backend only
+// Step maps a metric of at most Max to Score.
+type Step struct {
+	Max   float64
+	Score int
+}
+
+// BE-IN - Internal backend only
+// Steps used by Suggest. A value above the last step gets the Score of
+// the final entry with Max -1.
+var (
+	// Average cyclomatic complexity per function
+	ComplexitySteps = []Step{{3, 9}, {5, 8}, {7, 7}, {10, 5}, {-1, 3}}
+	// Unchecked errors plus twice the panics, per 1000 lines of code
+	ErrorDensitySteps = []Step{{0, 10}, {1, 9}, {2, 8}, {4, 7}, {8, 5}, {15, 3}, {-1, 2}}
+	// Test lines per line of code, highest first; scored by minimum
+	TestRatioSteps = []Step{{1.0, 9}, {0.7, 8}, {0.5, 7}, {0.3, 6}, {0.15, 5}, {0.0001, 3}, {-1, 1}}
+)
+
+// BE-IN - Internal backend only
+// Suggest maps metrics to the six evaluation scores with details naming
+// the evidence. Static analysis cannot see authorization, input
+// validation or runtime behaviour, so Security is capped at 8 and the
+// details point to the ingestion endpoints for stronger evidence.
+// ReportID and EvaluatorID are left for the caller.
+func Suggest(m *Metrics) *models.Evaluation {
+	// Score: [S7,P8,M8,T7,E7,L8]
+	// Details:
+	// - Security (S7): Suggestions only, the caller decides whether to apply them
+	// - Performance (P8): Constant work per dimension
+	// - Memory (M8): Details text capped per dimension
+	// - Testing (T7): Pure function of the metrics
+	// - Error (E7): Empty modules get the lowest scores with an explanation
+	// - Load (L8): Cheap enough to run on every upload
+	// Tags: BE-module-medium
+
+	e := &models.Evaluation{}
+	kloc := float64(m.CodeLines) / 1000
+	if kloc == 0 {
+		kloc = 1
+	}
+	errorDensity := float64(m.UncheckedErrors+2*m.Panics) / kloc
+
+	// Performance: complexity
+	e.PerformanceScore = stepDown(m.AverageComplexity, ComplexitySteps)
+	var notes []string
+	if m.Functions > 0 && float64(m.ComplexFunctions)/float64(m.Functions) > 0.05 {
+		e.PerformanceScore--
+		notes = append(notes, fmt.Sprintf("more than 5%% of functions exceed complexity %d", ComplexityThreshold))
+	}
+	if m.MaxComplexity > 2*ComplexityThreshold {
+		e.PerformanceScore--
+		notes = append(notes, fmt.Sprintf("the most complex function reaches %d", m.MaxComplexity))
+	}
+	e.PerformanceScore = clamp(e.PerformanceScore)
+	e.PerformanceDetails = fmt.Sprintf("Average cyclomatic complexity %.1f over %d functions, %d above %d%s.%s",
+		m.AverageComplexity, m.Functions, m.ComplexFunctions, ComplexityThreshold, prefixed("; ", notes),
+		complexList(m.MostComplex))
+
+	// Memory: goroutines that cannot be stopped may leak
+	e.MemoryScore = clamp(9 - min(6, 2*m.GoroutinesWithoutContext))
+	memory := fmt.Sprintf("%d of %d goroutines start without a context and may leak.%s", m.GoroutinesWithoutContext, m.Goroutines, locationList(m.GoroutineSamples))
+	if m.Goroutines == 0 {
+		memory = "No goroutines started."
+	}
+	e.MemoryDetails = memory + " Allocation behaviour needs benchmarks with -benchmem."
+
+	// Testing: test-to-code ratio
+	e.TestingScore = stepUp(m.TestToCodeRatio, TestRatioSteps)
+	e.TestingDetails = fmt.Sprintf("%d test lines for %d lines of code (ratio %.2f) in %d test files with %d tests, benchmarks, fuzz targets and examples. Coverage uploads give stronger evidence.",
+		m.TestLines, m.CodeLines, m.TestToCodeRatio, m.TestFiles, m.TestFunctions)
+
+	// Error: unchecked errors and panics
+	e.ErrorScore = stepDown(errorDensity, ErrorDensitySteps)
+	e.ErrorDetails = fmt.Sprintf("%d unchecked errors and %d panics outside Must helpers and init, %.1f per 1000 lines (panics count twice).%s",
+		m.UncheckedErrors, m.Panics, errorDensity, locationList(append(append([]Location(nil), m.UncheckedSamples...), m.PanicSamples...)))
+
+	// Load: goroutine lifetimes and panics under load
+	e.LoadScore = clamp(9 - min(4, m.GoroutinesWithoutContext) - min(3, m.Panics/2))
+	load := fmt.Sprintf("%d goroutines, %d without a context; %d panics can take the process down under load.", m.Goroutines, m.GoroutinesWithoutContext, m.Panics)
+	if m.Goroutines == 0 {
+		load = fmt.Sprintf("No goroutines started; %d panics can take the process down under load.", m.Panics)
+	}
+	e.LoadDetails = load + " Throughput and latency need a load-test upload."
+
+	// Security: exported surface, ignored errors and panics
+	e.SecurityScore = 8
+	exportedShare := 0.0
+	if m.TotalDeclarations > 0 {
+		exportedShare = float64(m.ExportedDeclarations) / float64(m.TotalDeclarations)
+	}
+	notes = nil
+	switch {
+	case exportedShare > 0.8:
+		e.SecurityScore -= 2
+		notes = append(notes, "most declarations are exported")
+	case exportedShare > 0.6:
+		e.SecurityScore--
+		notes = append(notes, "a large share of declarations is exported")
+	}
+	if errorDensity > 4 {
+		e.SecurityScore--
+		notes = append(notes, "ignored errors can hide failed checks")
+	}
+	if m.Panics > 0 {
+		e.SecurityScore--
+		notes = append(notes, "panics reachable from input are a denial-of-service risk")
+	}
+	e.SecurityScore = clamp(e.SecurityScore)
+	e.SecurityDetails = fmt.Sprintf("%d of %d top-level declarations exported (%.0f%%) across %d packages%s. Static analysis cannot verify authorization or input validation, so this score is capped at 8; SARIF uploads give stronger evidence.",
+		m.ExportedDeclarations, m.TotalDeclarations, exportedShare*100, m.Packages, prefixed("; ", notes))
+
+	if m.Files == 0 {
+		e.SecurityScore, e.PerformanceScore, e.MemoryScore, e.TestingScore, e.ErrorScore, e.LoadScore = 1, 1, 1, 1, 1, 1
+		for _, details := range []*string{&e.SecurityDetails, &e.PerformanceDetails, &e.MemoryDetails, &e.TestingDetails, &e.ErrorDetails, &e.LoadDetails} {
+			*details = "No Go source files could be analyzed."
+		}
+	}
+	return e
+}
+
+// BE-IN - Internal backend only
+// stepDown scores a metric where lower is better.
+func stepDown(value float64, steps []Step) int {
+	for _, s := range steps {
+		if s.Max < 0 || value <= s.Max {
+			return s.Score
+		}
+	}
+	return 1
+}
+
+// BE-IN - Internal backend only
+// stepUp scores a metric where higher is better; Max is the minimum.
+func stepUp(value float64, steps []Step) int {
+	for _, s := range steps {
+		if s.Max < 0 || value >= s.Max {
+			return s.Score
+		}
+	}
+	return 1
+}
+
+// BE-IN - Internal backend only
+func clamp(score int) int {
+	return max(1, min(10, score))
+}
+
+// BE-IN - Internal backend only
+func prefixed(prefix string, notes []string) string {
+	if len(notes) == 0 {
+		return ""
+	}
+	return prefix + strings.Join(notes, ", ")
+}
+
+// BE-IN - Internal backend only
+func complexList(functions []FunctionComplexity) string {
+	var names []string
+	for _, f := range functions[:min(len(functions), maxNamedLocations)] {
+		if f.Complexity <= ComplexityThreshold {
+			break
+		}
+		names = append(names, fmt.Sprintf("%s (%d) at %s:%d", f.Function, f.Complexity, f.File, f.Line))
+	}
+	return prefixed(" Most complex: ", names)
+}
+
+// BE-IN - Internal backend only
+func locationList(locations []Location) string {
+	var names []string
+	for _, l := range locations[:min(len(locations), maxNamedLocations)] {
+		names = append(names, fmt.Sprintf("%s:%d in %s", l.File, l.Line, l.Function))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return " See " + strings.Join(names, ", ") + "."
+}
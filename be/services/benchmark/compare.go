@@ -0,0 +1,234 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// DefaultRegressionThreshold is the slowdown or memory growth, in
+// percent, above which a benchmark counts as regressed.
+const DefaultRegressionThreshold = 10.0
+
+// BE-IN - Internal backend only
+// maxListedBenchmarks bounds the benchmarks named in details
+const maxListedBenchmarks = 5
+
+// BE-IN - Internal backend only
+// RatioScore maps a geometric mean ratio of at most MaxRatio (current
+// over baseline, lower is better) to Score.
+type RatioScore struct {
+	MaxRatio float64
+	Score    int
+}
+
+// BE-IN - Internal backend only
+// RatioScores is ordered from the best ratio to the worst. Ratios above
+// the last entry score 1.
+var RatioScores = []RatioScore{
+	{0.95, 10},
+	{1.02, 9},
+	{1.05, 8},
+	{1.10, 7},
+	{1.25, 5},
+	{1.50, 3},
+}
+
+// BE-IN - Internal backend only
+// Comparison is one benchmark against its baseline. Deltas are percent
+// changes; positive means slower or more memory.
+type Comparison struct {
+	Key            string  `json:"key"`
+	NsPerOp        float64 `json:"ns_per_op"`
+	BaselineNsOp   float64 `json:"baseline_ns_per_op"`
+	NsDelta        float64 `json:"ns_delta"`
+	BytesPerOp     float64 `json:"bytes_per_op"`
+	BaselineBytes  float64 `json:"baseline_bytes_per_op"`
+	BytesDelta     float64 `json:"bytes_delta"`
+	AllocsPerOp    float64 `json:"allocs_per_op"`
+	BaselineAllocs float64 `json:"baseline_allocs_per_op"`
+	AllocsDelta    float64 `json:"allocs_delta"`
+	HasMemory      bool    `json:"has_memory"`
+	Regressed      bool    `json:"regressed"`
+}
+
+// BE-IN - Internal backend only
+// Suggestion is the outcome of comparing a run with its baseline. Scores
+// are nil when there is nothing to compare.
+type Suggestion struct {
+	Comparisons        []*Comparison `json:"comparisons"`
+	Unmatched          int           `json:"unmatched"`
+	Regressions        int           `json:"regressions"`
+	PerformanceScore   *int          `json:"performance_score,omitempty"`
+	MemoryScore        *int          `json:"memory_score,omitempty"`
+	PerformanceDetails string        `json:"performance_details"`
+	MemoryDetails      string        `json:"memory_details"`
+}
+
+// BE-IN - Internal backend only
+// ThresholdFromEnv reads BENCHMARK_REGRESSION_THRESHOLD (percent).
+func ThresholdFromEnv() (float64, error) {
+	value := os.Getenv("BENCHMARK_REGRESSION_THRESHOLD")
+	if value == "" {
+		return DefaultRegressionThreshold, nil
+	}
+	threshold, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || threshold <= 0 || threshold > 1000 {
+		return DefaultRegressionThreshold, fmt.Errorf("BENCHMARK_REGRESSION_THRESHOLD must be a positive percentage")
+	}
+	return threshold, nil
+}
+
+// BE-IN - Internal backend only
+// Compare matches current results with baseline by package, name and
+// GOMAXPROCS and suggests scores from the geometric mean ratios. Memory
+// ratios add one to both sides so zero-allocation benchmarks compare
+// sensibly. baseline may be nil.
+func Compare(current, baseline *Output, threshold float64) *Suggestion {
+	// Score: [S7,P8,M7,T7,E7,L8]
+	// Details:
+	// - Security (S7): Pure computation over parsed results
+	// - Performance (P8): One map lookup per benchmark
+	// - Memory (M7): One comparison per matched benchmark
+	// - Testing (T7): Pure function of its inputs
+	// - Error (E7): Missing baseline and -benchmem data explained in details
+	// - Load (L8): Linear in the number of benchmarks
+	// Tags: BE-module-medium
+
+	s := &Suggestion{}
+	if baseline == nil {
+		s.PerformanceDetails = fmt.Sprintf("%d benchmarks recorded; no baseline for the project yet, so no performance score is suggested.", len(current.Results))
+		s.MemoryDetails = "No baseline for the project yet, so no memory score is suggested."
+		return s
+	}
+
+	base := make(map[string]*Result, len(baseline.Results))
+	for _, r := range baseline.Results {
+		base[r.Key()] = r
+	}
+
+	var nsLogSum, memLogSum float64
+	memCount := 0
+	for _, r := range current.Results {
+		b, ok := base[r.Key()]
+		if !ok || b.NsPerOp <= 0 {
+			s.Unmatched++
+			continue
+		}
+
+		c := &Comparison{
+			Key:          r.Key(),
+			NsPerOp:      r.NsPerOp,
+			BaselineNsOp: b.NsPerOp,
+			NsDelta:      delta(r.NsPerOp, b.NsPerOp),
+		}
+		nsLogSum += math.Log(r.NsPerOp / b.NsPerOp)
+
+		if r.HasMemory && b.HasMemory {
+			c.HasMemory = true
+			c.BytesPerOp, c.BaselineBytes = r.BytesPerOp, b.BytesPerOp
+			c.AllocsPerOp, c.BaselineAllocs = r.AllocsPerOp, b.AllocsPerOp
+			c.BytesDelta = delta(r.BytesPerOp+1, b.BytesPerOp+1)
+			c.AllocsDelta = delta(r.AllocsPerOp+1, b.AllocsPerOp+1)
+			memLogSum += (math.Log((r.BytesPerOp+1)/(b.BytesPerOp+1)) + math.Log((r.AllocsPerOp+1)/(b.AllocsPerOp+1))) / 2
+			memCount++
+		}
+
+		c.Regressed = c.NsDelta > threshold || c.BytesDelta > threshold || c.AllocsDelta > threshold
+		if c.Regressed {
+			s.Regressions++
+		}
+		s.Comparisons = append(s.Comparisons, c)
+	}
+
+	if len(s.Comparisons) == 0 {
+		s.PerformanceDetails = fmt.Sprintf("None of the %d benchmarks exist in the baseline, so no performance score is suggested.", len(current.Results))
+		s.MemoryDetails = "No benchmarks match the baseline, so no memory score is suggested."
+		return s
+	}
+
+	environment := ""
+	if baseline.CPU != "" && current.CPU != "" && baseline.CPU != current.CPU {
+		environment = fmt.Sprintf(" Note: the baseline ran on %q and this run on %q.", baseline.CPU, current.CPU)
+	}
+
+	nsRatio := math.Exp(nsLogSum / float64(len(s.Comparisons)))
+	performance := ratioScore(nsRatio)
+	s.PerformanceScore = &performance
+	s.PerformanceDetails = fmt.Sprintf("%d benchmarks compared with the project baseline (%d new); geometric mean time %s, suggested score %d.%s%s",
+		len(s.Comparisons), s.Unmatched, describeRatio(nsRatio), performance,
+		listChanges(s.Comparisons, threshold, func(c *Comparison) float64 { return c.NsDelta }, "ns/op"), environment)
+
+	if memCount == 0 {
+		s.MemoryDetails = "No memory statistics to compare; run the benchmarks with -benchmem."
+		return s
+	}
+	memRatio := math.Exp(memLogSum / float64(memCount))
+	memory := ratioScore(memRatio)
+	s.MemoryScore = &memory
+	s.MemoryDetails = fmt.Sprintf("%d benchmarks with memory statistics; geometric mean bytes and allocations per op %s, suggested score %d.%s%s",
+		memCount, describeRatio(memRatio), memory,
+		listChanges(s.Comparisons, threshold, func(c *Comparison) float64 { return math.Max(c.BytesDelta, c.AllocsDelta) }, "B/op or allocs/op"), environment)
+	return s
+}
+
+// BE-IN - Internal backend only
+func ratioScore(ratio float64) int {
+	for _, r := range RatioScores {
+		if ratio <= r.MaxRatio {
+			return r.Score
+		}
+	}
+	return 1
+}
+
+// BE-IN - Internal backend only
+func delta(current, baseline float64) float64 {
+	if baseline == 0 {
+		return 0
+	}
+	return math.Round((current-baseline)/baseline*1000) / 10
+}
+
+// BE-IN - Internal backend only
+func describeRatio(ratio float64) string {
+	change := (ratio - 1) * 100
+	switch {
+	case math.Abs(change) < 0.5:
+		return "unchanged"
+	case change > 0:
+		return fmt.Sprintf("%.1f%% worse", change)
+	default:
+		return fmt.Sprintf("%.1f%% better", -change)
+	}
+}
+
+// BE-IN - Internal backend only
+// listChanges names the benchmarks whose metric regressed beyond the
+// threshold, worst first.
+func listChanges(comparisons []*Comparison, threshold float64, metric func(*Comparison) float64, unit string) string {
+	var regressed []*Comparison
+	for _, c := range comparisons {
+		if metric(c) > threshold {
+			regressed = append(regressed, c)
+		}
+	}
+	if len(regressed) == 0 {
+		return fmt.Sprintf(" No regressions above %.0f%%.", threshold)
+	}
+	sort.Slice(regressed, func(i, j int) bool { return metric(regressed[i]) > metric(regressed[j]) })
+
+	var names []string
+	for _, c := range regressed[:min(len(regressed), maxListedBenchmarks)] {
+		names = append(names, fmt.Sprintf("%s +%.1f%%", c.Key, metric(c)))
+	}
+	text := fmt.Sprintf(" Regressed %s above %.0f%%: %s", unit, threshold, strings.Join(names, ", "))
+	if len(regressed) > maxListedBenchmarks {
+		text += fmt.Sprintf(" and %d more", len(regressed)-maxListedBenchmarks)
+	}
+	return text + "."
+}
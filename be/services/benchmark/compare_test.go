@@ -0,0 +1,143 @@
+package benchmark
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestCompare(t *testing.T) {
+	const cpuNote = `Note: the baseline ran on "AMD EPYC 7B13" and this run on "Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz".`
+
+	tests := []struct {
+		name        string
+		current     string
+		baseline    string
+		threshold   float64
+		performance *int
+		memory      *int
+		regressed   []string
+		perfDetails []string
+		memDetails  []string
+	}{
+		// Validate is 18% slower and SignSnapshot 5% faster: a geometric
+		// mean of 2.3% worse. Verify's bytes and Canonicalize's allocs
+		// regress, 11.6% worse on average.
+		{
+			"regressions", "current.txt", "baseline.txt", DefaultRegressionThreshold, intPtr(8), intPtr(5),
+			[]string{"encore.app/services/jsonschema.BenchmarkValidate-8", "encore.app/services/signing.BenchmarkCanonicalize/small-8", "encore.app/services/signing.BenchmarkVerify-8"},
+			[]string{"5 benchmarks compared with the project baseline (1 new); geometric mean time 2.3% worse, suggested score 8.", "Regressed ns/op above 10%: encore.app/services/jsonschema.BenchmarkValidate-8 +18.0%.", cpuNote},
+			[]string{"geometric mean bytes and allocations per op 11.6% worse, suggested score 5.", "BenchmarkCanonicalize/small-8 +100.0%, encore.app/services/signing.BenchmarkVerify-8 +50.0%."},
+		},
+		// A higher threshold leaves the scores alone but lists less
+		{
+			"lenient threshold", "current.txt", "baseline.txt", 60, intPtr(8), intPtr(5),
+			[]string{"encore.app/services/signing.BenchmarkCanonicalize/small-8"},
+			[]string{"No regressions above 60%."},
+			[]string{"Regressed B/op or allocs/op above 60%: encore.app/services/signing.BenchmarkCanonicalize/small-8 +100.0%."},
+		},
+		{
+			"no baseline", "current.txt", "", DefaultRegressionThreshold, nil, nil, nil,
+			[]string{"6 benchmarks recorded; no baseline for the project yet, so no performance score is suggested."},
+			[]string{"No baseline for the project yet, so no memory score is suggested."},
+		},
+		// Without -benchmem in the baseline only time is scored
+		{
+			"no memory", "current.txt", "baseline_nomem.txt", DefaultRegressionThreshold, intPtr(8), nil,
+			[]string{"encore.app/services/jsonschema.BenchmarkValidate-8"},
+			[]string{"geometric mean time 2.3% worse, suggested score 8."},
+			[]string{"No memory statistics to compare; run the benchmarks with -benchmem."},
+		},
+		{
+			"unchanged", "baseline.txt", "baseline.txt", DefaultRegressionThreshold, intPtr(9), intPtr(9), nil,
+			[]string{"(0 new); geometric mean time unchanged, suggested score 9. No regressions above 10%."},
+			[]string{"geometric mean bytes and allocations per op unchanged, suggested score 9. No regressions above 10%."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var baseline *Output
+			if tt.baseline != "" {
+				baseline = parseFixture(t, tt.baseline)
+			}
+			suggestion := Compare(parseFixture(t, tt.current), baseline, tt.threshold)
+			if !reflect.DeepEqual(suggestion.PerformanceScore, tt.performance) {
+				t.Errorf("PerformanceScore = %v, want %v", deref(suggestion.PerformanceScore), deref(tt.performance))
+			}
+			if !reflect.DeepEqual(suggestion.MemoryScore, tt.memory) {
+				t.Errorf("MemoryScore = %v, want %v", deref(suggestion.MemoryScore), deref(tt.memory))
+			}
+
+			var regressed []string
+			for _, c := range suggestion.Comparisons {
+				if c.Regressed {
+					regressed = append(regressed, c.Key)
+				}
+			}
+			if !reflect.DeepEqual(regressed, tt.regressed) || suggestion.Regressions != len(tt.regressed) {
+				t.Errorf("regressed = %q (%d), want %q", regressed, suggestion.Regressions, tt.regressed)
+			}
+
+			for _, want := range tt.perfDetails {
+				if !strings.Contains(suggestion.PerformanceDetails, want) {
+					t.Errorf("PerformanceDetails = %q, missing %q", suggestion.PerformanceDetails, want)
+				}
+			}
+			for _, want := range tt.memDetails {
+				if !strings.Contains(suggestion.MemoryDetails, want) {
+					t.Errorf("MemoryDetails = %q, missing %q", suggestion.MemoryDetails, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareDeltas(t *testing.T) {
+	suggestion := Compare(parseFixture(t, "current.txt"), parseFixture(t, "baseline.txt"), DefaultRegressionThreshold)
+	if suggestion.Unmatched != 1 {
+		t.Errorf("Unmatched = %d, want BenchmarkDigest as the only new benchmark", suggestion.Unmatched)
+	}
+
+	want := map[string]Comparison{
+		"encore.app/services/jsonschema.BenchmarkValidate-8":        {NsPerOp: 7200, BaselineNsOp: 6100, NsDelta: 18},
+		"encore.app/services/signing.BenchmarkSignSnapshot-8":       {NsPerOp: 38000, BaselineNsOp: 40000, NsDelta: -5},
+		"encore.app/services/signing.BenchmarkVerify-8":             {NsPerOp: 60000, BaselineNsOp: 60000, BytesDelta: 50},
+		"encore.app/services/signing.BenchmarkCanonicalize/small-8": {NsPerOp: 2000, BaselineNsOp: 2000, AllocsDelta: 100},
+	}
+	for _, c := range suggestion.Comparisons {
+		w, ok := want[c.Key]
+		if !ok {
+			continue
+		}
+		if c.NsPerOp != w.NsPerOp || c.BaselineNsOp != w.BaselineNsOp || c.NsDelta != w.NsDelta || c.BytesDelta != w.BytesDelta || c.AllocsDelta != w.AllocsDelta {
+			t.Errorf("%s = %+v, want deltas %+v", c.Key, *c, w)
+		}
+		delete(want, c.Key)
+	}
+	for key := range want {
+		t.Errorf("%s was not compared", key)
+	}
+}
+
+func deref(n *int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+func TestRatioScore(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		want  int
+	}{
+		{0.5, 10}, {0.95, 10}, {1.0, 9}, {1.02, 9}, {1.04, 8}, {1.08, 7}, {1.2, 5}, {1.5, 3}, {2, 1},
+	}
+	for _, tt := range tests {
+		if got := ratioScore(tt.ratio); got != tt.want {
+			t.Errorf("ratioScore(%v) = %d, want %d", tt.ratio, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package benchmark parses go test -bench output, compares it with a
+// baseline and turns the comparison into suggested performance and memory
+// scores.
+package benchmark
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// Result is one benchmark. Repeated runs, e.g. with -count, are reduced
+// to the median of each metric.
+type Result struct {
+	Package     string  `json:"package"`
+	Name        string  `json:"name"`
+	Procs       int     `json:"procs"`
+	Runs        int     `json:"runs"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	HasMemory   bool    `json:"has_memory"`
+}
+
+// BE-IN - Internal backend only
+// Key identifies a benchmark across runs.
+func (r *Result) Key() string {
+	return r.Package + "." + r.Name + "-" + strconv.Itoa(r.Procs)
+}
+
+// BE-IN - Internal backend only
+// Output is a parsed benchmark run with the environment it reported.
+type Output struct {
+	GoOS    string    `json:"goos,omitempty"`
+	GoArch  string    `json:"goarch,omitempty"`
+	CPU     string    `json:"cpu,omitempty"`
+	Results []*Result `json:"results"`
+}
+
+// BE-IN - Internal backend only
+type testEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Output  string `json:"Output"`
+}
+
+// BE-IN - Internal backend only
+// samples collects the repeated runs of one benchmark.
+type samples struct {
+	result *Result
+	ns     []float64
+	bytes  []float64
+	allocs []float64
+}
+
+// BE-IN - Internal backend only
+// Parse reads go test -bench output, either as printed or wrapped in
+// go test -json events. Lines other than benchmark results and the
+// goos, goarch, pkg and cpu headers are ignored.
+func Parse(r io.Reader) (*Output, error) {
+	// Score: [S7,P8,M7,T7,E7,L7]
+	// Details:
+	// - Security (S7): Plain text parsing only
+	// - Performance (P8): Single pass, medians over small sample sets
+	// - Memory (M7): Holds the samples of each benchmark
+	// - Testing (T7): Pure function over the output text
+	// - Error (E7): Malformed result lines reported with their line number
+	// - Load (L7): Bounded by the upload size limit
+	// Tags: BE-module-medium
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		text, err = joinTestOutput(text)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := &Output{}
+	byKey := make(map[string]*samples)
+	var order []string
+	pkg := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		l := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(l, "goos:"):
+			out.GoOS = strings.TrimSpace(strings.TrimPrefix(l, "goos:"))
+			continue
+		case strings.HasPrefix(l, "goarch:"):
+			out.GoArch = strings.TrimSpace(strings.TrimPrefix(l, "goarch:"))
+			continue
+		case strings.HasPrefix(l, "cpu:"):
+			out.CPU = strings.TrimSpace(strings.TrimPrefix(l, "cpu:"))
+			continue
+		case strings.HasPrefix(l, "pkg:"):
+			pkg = strings.TrimSpace(strings.TrimPrefix(l, "pkg:"))
+			continue
+		case !strings.HasPrefix(l, "Benchmark"):
+			continue
+		}
+
+		fields := strings.Fields(l)
+		if len(fields) < 4 {
+			// A benchmark name printed alone, e.g. before a failure or a
+			// log line
+			continue
+		}
+		if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+			continue
+		}
+
+		name, procs := splitProcs(fields[0])
+		result := &Result{Package: pkg, Name: name, Procs: procs}
+		var ns, bytes, allocs float64
+		sawNs := false
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid value %q for %s", line, fields[i], fields[i+1])
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				ns, sawNs = value, true
+			case "B/op":
+				bytes = value
+				result.HasMemory = true
+			case "allocs/op":
+				allocs = value
+				result.HasMemory = true
+			}
+		}
+		if !sawNs {
+			return nil, fmt.Errorf("line %d: benchmark %s has no ns/op value", line, fields[0])
+		}
+
+		key := result.Key()
+		s, ok := byKey[key]
+		if !ok {
+			s = &samples{result: result}
+			byKey[key] = s
+			order = append(order, key)
+		}
+		s.result.HasMemory = s.result.HasMemory || result.HasMemory
+		s.ns = append(s.ns, ns)
+		s.bytes = append(s.bytes, bytes)
+		s.allocs = append(s.allocs, allocs)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no benchmark results found, run go test with -bench")
+	}
+
+	for _, key := range order {
+		s := byKey[key]
+		s.result.Runs = len(s.ns)
+		s.result.NsPerOp = median(s.ns)
+		s.result.BytesPerOp = median(s.bytes)
+		s.result.AllocsPerOp = median(s.allocs)
+		out.Results = append(out.Results, s.result)
+	}
+	sort.Slice(out.Results, func(i, j int) bool {
+		return out.Results[i].Key() < out.Results[j].Key()
+	})
+	return out, nil
+}
+
+// BE-IN - Internal backend only
+// joinTestOutput reassembles the printed output of each package from
+// go test -json events. test2json splits a benchmark's name and its
+// numbers into separate events, so lines only make sense once joined.
+func joinTestOutput(text string) (string, error) {
+	var packages []string
+	outputs := make(map[string]*strings.Builder)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event testEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return "", fmt.Errorf("line %d: invalid go test -json event: %v", i+1, err)
+		}
+		if event.Action != "output" {
+			continue
+		}
+		b, ok := outputs[event.Package]
+		if !ok {
+			b = &strings.Builder{}
+			outputs[event.Package] = b
+			packages = append(packages, event.Package)
+		}
+		b.WriteString(event.Output)
+	}
+
+	var joined strings.Builder
+	for _, pkg := range packages {
+		// go test -json does not repeat the pkg: header for every package
+		fmt.Fprintf(&joined, "pkg: %s\n%s\n", pkg, outputs[pkg].String())
+	}
+	return joined.String(), nil
+}
+
+// BE-IN - Internal backend only
+// splitProcs splits the -GOMAXPROCS suffix off a benchmark name.
+func splitProcs(name string) (string, int) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return name, 1
+	}
+	procs, err := strconv.Atoi(name[i+1:])
+	if err != nil || procs <= 0 {
+		return name, 1
+	}
+	return name[:i], procs
+}
+
+// BE-IN - Internal backend only
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
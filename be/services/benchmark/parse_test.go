@@ -0,0 +1,100 @@
+package benchmark
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseFixture(t *testing.T, name string) *Output {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+	out, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	out := parseFixture(t, "current.txt")
+	if out.GoOS != "linux" || out.GoArch != "amd64" || out.CPU != "Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz" {
+		t.Errorf("headers = %q %q %q", out.GoOS, out.GoArch, out.CPU)
+	}
+
+	// Repeated runs are folded into their median, results sorted by
+	// package and name, and the skipped benchmark without a result left out
+	const jsonschema, signing = "encore.app/services/jsonschema", "encore.app/services/signing"
+	want := []Result{
+		{Package: jsonschema, Name: "BenchmarkCompile", Procs: 8, Runs: 3, NsPerOp: 25000, BytesPerOp: 8192, AllocsPerOp: 120, HasMemory: true},
+		{Package: jsonschema, Name: "BenchmarkValidate", Procs: 8, Runs: 2, NsPerOp: 7200, BytesPerOp: 1024, AllocsPerOp: 16, HasMemory: true},
+		{Package: signing, Name: "BenchmarkCanonicalize/small", Procs: 8, Runs: 1, NsPerOp: 2000, AllocsPerOp: 1, HasMemory: true},
+		{Package: signing, Name: "BenchmarkDigest", Procs: 8, Runs: 1, NsPerOp: 1000, BytesPerOp: 64, AllocsPerOp: 1, HasMemory: true},
+		{Package: signing, Name: "BenchmarkSignSnapshot", Procs: 8, Runs: 1, NsPerOp: 38000, BytesPerOp: 4096, AllocsPerOp: 40, HasMemory: true},
+		{Package: signing, Name: "BenchmarkVerify", Procs: 8, Runs: 1, NsPerOp: 60000, BytesPerOp: 3072, AllocsPerOp: 20, HasMemory: true},
+	}
+	if len(out.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(out.Results), len(want))
+	}
+	for i, result := range out.Results {
+		if *result != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, *result, want[i])
+		}
+	}
+
+	// go test -json splits each result line across two events
+	if fromJSON := parseFixture(t, "current.json"); !reflect.DeepEqual(fromJSON, out) {
+		t.Errorf("go test -json output parsed differently:\n%+v\nwant:\n%+v", fromJSON, out)
+	}
+
+	// Without -benchmem only time is recorded
+	if r := parseFixture(t, "baseline_nomem.txt").Results[0]; r.HasMemory || r.BytesPerOp != 0 {
+		t.Errorf("baseline_nomem result = %+v, want no memory statistics", *r)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"no results", "PASS\nok  \tencore.app/api\t0.1s\n", "no benchmark results found"},
+		{"invalid value", "BenchmarkX-8 \t 100 \t fast ns/op\n", `line 1: invalid value "fast" for ns/op`},
+		{"missing ns/op", "pkg: encore.app/api\nBenchmarkX-8 \t 100 \t 64 B/op\n", "line 2: benchmark BenchmarkX-8 has no ns/op value"},
+		{"invalid event", "{\"Action\":\"output\"}\n{\"Action\":\n", "line 2: invalid go test -json event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.output))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitProcs(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantName  string
+		wantProcs int
+	}{
+		{"BenchmarkCompile-8", "BenchmarkCompile", 8},
+		{"BenchmarkCompile", "BenchmarkCompile", 1},
+		{"BenchmarkCanonicalize/large-input-16", "BenchmarkCanonicalize/large-input", 16},
+		{"BenchmarkCanonicalize/large-input", "BenchmarkCanonicalize/large-input", 1},
+	}
+	for _, tt := range tests {
+		name, procs := splitProcs(tt.name)
+		if name != tt.wantName || procs != tt.wantProcs {
+			t.Errorf("splitProcs(%q) = %q, %d, want %q, %d", tt.name, name, procs, tt.wantName, tt.wantProcs)
+		}
+	}
+}
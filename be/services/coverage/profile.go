@@ -0,0 +1,149 @@
+// Package coverage reads the output of go test -coverprofile and
+// go test -json and turns it into a suggested testing score.
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// PackageCoverage is the statement coverage of one package, merged with
+// its test results when go test -json output was supplied.
+type PackageCoverage struct {
+	Package    string  `json:"package"`
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percent    float64 `json:"percent"`
+	Passed     int     `json:"passed"`
+	Failed     int     `json:"failed"`
+	Skipped    int     `json:"skipped"`
+	Flaky      int     `json:"flaky"`
+}
+
+// BE-IN - Internal backend only
+// Profile is a parsed coverage profile.
+type Profile struct {
+	Mode       string             `json:"mode"`
+	Packages   []*PackageCoverage `json:"packages"`
+	Statements int                `json:"statements"`
+	Covered    int                `json:"covered"`
+	Percent    float64            `json:"percent"`
+}
+
+// BE-IN - Internal backend only
+// block identifies a profile block. Profiles merged from several test
+// binaries, e.g. with -coverpkg, repeat blocks.
+type block struct {
+	file     string
+	position string
+}
+
+// BE-IN - Internal backend only
+// ParseProfile reads a go test -coverprofile file. Statements are counted
+// once per block however often the block is repeated, and a block is
+// covered if any repetition executed it.
+func ParseProfile(r io.Reader) (*Profile, error) {
+	// Score: [S7,P8,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Plain text parsing, no paths are opened
+	// - Performance (P8): Single streaming pass
+	// - Memory (M7): One entry per distinct block
+	// - Testing (T7): Pure function over the profile text
+	// - Error (E8): Malformed lines reported with their line number
+	// - Load (L7): Bounded by the upload size limit
+	// Tags: BE-module-medium
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	profile := &Profile{}
+	statements := make(map[block]int)
+	covered := make(map[block]bool)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "mode:") {
+			if profile.Mode == "" {
+				profile.Mode = strings.TrimSpace(strings.TrimPrefix(text, "mode:"))
+			}
+			continue
+		}
+		if profile.Mode == "" {
+			return nil, fmt.Errorf("line %d: coverage profile must start with a mode line", line)
+		}
+
+		// file.go:startLine.startCol,endLine.endCol numStmts count
+		fields := strings.Fields(text)
+		colon := strings.LastIndex(fields[0], ":")
+		if len(fields) != 3 || colon < 0 {
+			return nil, fmt.Errorf("line %d: malformed coverage block %q", line, text)
+		}
+		numStmts, err := strconv.Atoi(fields[1])
+		if err != nil || numStmts < 0 {
+			return nil, fmt.Errorf("line %d: invalid statement count %q", line, fields[1])
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("line %d: invalid execution count %q", line, fields[2])
+		}
+
+		b := block{file: fields[0][:colon], position: fields[0][colon+1:]}
+		statements[b] = numStmts
+		if count > 0 {
+			covered[b] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if profile.Mode == "" {
+		return nil, fmt.Errorf("coverage profile is empty")
+	}
+
+	packages := make(map[string]*PackageCoverage)
+	for b, n := range statements {
+		name := path.Dir(b.file)
+		pkg, ok := packages[name]
+		if !ok {
+			pkg = &PackageCoverage{Package: name}
+			packages[name] = pkg
+		}
+		pkg.Statements += n
+		profile.Statements += n
+		if covered[b] {
+			pkg.Covered += n
+			profile.Covered += n
+		}
+	}
+
+	for _, pkg := range packages {
+		pkg.Percent = percent(pkg.Covered, pkg.Statements)
+		profile.Packages = append(profile.Packages, pkg)
+	}
+	sort.Slice(profile.Packages, func(i, j int) bool {
+		return profile.Packages[i].Package < profile.Packages[j].Package
+	})
+	profile.Percent = percent(profile.Covered, profile.Statements)
+
+	return profile, nil
+}
+
+// BE-IN - Internal backend only
+// percent rounds to one decimal place.
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
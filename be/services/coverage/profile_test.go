@@ -0,0 +1,54 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProfile(t *testing.T) {
+	profile, err := ParseProfile(openFixture(t, "coverage.out"))
+	if err != nil {
+		t.Fatalf("ParseProfile: %v", err)
+	}
+	if profile.Mode != "set" || profile.Statements != 30 || profile.Covered != 22 || profile.Percent != 73.3 {
+		t.Errorf("profile = %s %d/%d %.1f%%, want set 22/30 73.3%%", profile.Mode, profile.Covered, profile.Statements, profile.Percent)
+	}
+
+	// A block repeated by a second test binary counts once, covered if
+	// either run covered it
+	want := []PackageCoverage{
+		{Package: "encore.app/api", Statements: 8, Covered: 7, Percent: 87.5},
+		{Package: "encore.app/models", Statements: 12, Covered: 10, Percent: 83.3},
+		{Package: "encore.app/services/coverage", Statements: 10, Covered: 5, Percent: 50},
+	}
+	if len(profile.Packages) != len(want) {
+		t.Fatalf("got %d packages, want %d", len(profile.Packages), len(want))
+	}
+	for i, pkg := range profile.Packages {
+		if *pkg != want[i] {
+			t.Errorf("package %d = %+v, want %+v", i, *pkg, want[i])
+		}
+	}
+}
+
+func TestParseProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    string
+	}{
+		{"empty", "", "coverage profile is empty"},
+		{"missing mode", "encore.app/api/reports.go:1.1,2.2 1 1\n", "line 1: coverage profile must start with a mode line"},
+		{"malformed block", "mode: set\nencore.app/api/reports.go 1 1\n", "line 2: malformed coverage block"},
+		{"statement count", "mode: set\nencore.app/api/reports.go:1.1,2.2 -1 1\n", `line 2: invalid statement count "-1"`},
+		{"execution count", "mode: count\nencore.app/api/reports.go:1.1,2.2 1 x\n", `line 2: invalid execution count "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProfile(strings.NewReader(tt.profile))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("ParseProfile error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// Threshold maps statement coverage of at least MinPercent to Score.
+type Threshold struct {
+	MinPercent float64 `json:"min_percent"`
+	Score      int     `json:"score"`
+}
+
+// BE-IN - Internal backend only
+// Mapping turns coverage and test results into a testing score. The
+// highest threshold reached gives the base score; failing tests or
+// packages cap it, and each flaky test costs FlakyPenalty points up to
+// MaxFlakyPenalty.
+type Mapping struct {
+	Thresholds      []Threshold `json:"thresholds"`
+	FailureCap      int         `json:"failure_cap"`
+	FlakyPenalty    int         `json:"flaky_penalty"`
+	MaxFlakyPenalty int         `json:"max_flaky_penalty"`
+}
+
+// BE-IN - Internal backend only
+var DefaultMapping = Mapping{
+	Thresholds: []Threshold{
+		{90, 10}, {80, 9}, {70, 8}, {60, 7}, {50, 6},
+		{40, 5}, {30, 4}, {20, 3}, {10, 2}, {0, 1},
+	},
+	FailureCap:      3,
+	FlakyPenalty:    1,
+	MaxFlakyPenalty: 3,
+}
+
+// BE-IN - Internal backend only
+// maxNamedItems bounds the package and test names listed in details
+const maxNamedItems = 5
+
+// BE-IN - Internal backend only
+// MappingFromEnv returns DefaultMapping with the overrides from
+// COVERAGE_SCORE_THRESHOLDS (e.g. "85=10,70=8,50=6,0=2"),
+// COVERAGE_FAILURE_CAP and COVERAGE_FLAKY_PENALTY.
+func MappingFromEnv() (Mapping, error) {
+	mapping := DefaultMapping
+	if value := os.Getenv("COVERAGE_SCORE_THRESHOLDS"); value != "" {
+		thresholds, err := ParseThresholds(value)
+		if err != nil {
+			return DefaultMapping, fmt.Errorf("COVERAGE_SCORE_THRESHOLDS: %w", err)
+		}
+		mapping.Thresholds = thresholds
+	}
+	for key, target := range map[string]*int{
+		"COVERAGE_FAILURE_CAP":   &mapping.FailureCap,
+		"COVERAGE_FLAKY_PENALTY": &mapping.FlakyPenalty,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > 10 {
+			return DefaultMapping, fmt.Errorf("%s must be an integer between 0 and 10", key)
+		}
+		*target = n
+	}
+	return mapping, nil
+}
+
+// BE-IN - Internal backend only
+// ParseThresholds parses "percent=score" pairs separated by commas. The
+// result is sorted by descending percent.
+func ParseThresholds(s string) ([]Threshold, error) {
+	var thresholds []Threshold
+	for _, pair := range strings.Split(s, ",") {
+		percentText, scoreText, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("%q must have the form percent=score", pair)
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(percentText), "%"), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("%q: percent must be between 0 and 100", pair)
+		}
+		score, err := strconv.Atoi(strings.TrimSpace(scoreText))
+		if err != nil || score < 0 || score > 10 {
+			return nil, fmt.Errorf("%q: score must be between 0 and 10", pair)
+		}
+		thresholds = append(thresholds, Threshold{MinPercent: percent, Score: score})
+	}
+	sort.Slice(thresholds, func(i, j int) bool {
+		return thresholds[i].MinPercent > thresholds[j].MinPercent
+	})
+	return thresholds, nil
+}
+
+// BE-IN - Internal backend only
+// Result is a coverage profile with optional test results and the score
+// suggested for it.
+type Result struct {
+	*Profile
+	Tests          *TestSummary `json:"tests,omitempty"`
+	SuggestedScore int          `json:"suggested_score"`
+	Details        string       `json:"details"`
+}
+
+// BE-IN - Internal backend only
+// Analyze parses a coverage profile and, when tests is not nil, go test
+// -json output, and suggests a testing score with generated details.
+func Analyze(profile io.Reader, tests io.Reader, mapping Mapping) (*Result, error) {
+	// Score: [S7,P8,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): Works only on uploaded text, no files opened
+	// - Performance (P8): Two streaming passes plus a merge
+	// - Memory (M7): Per-package summaries only
+	// - Testing (T7): Pure function of its inputs and the mapping
+	// - Error (E8): Parse errors name the input and line
+	// - Load (L7): Bounded by the upload size limit
+	// Tags: BE-module-medium
+
+	parsed, err := ParseProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("coverage profile: %w", err)
+	}
+	result := &Result{Profile: parsed}
+
+	if tests != nil {
+		summary, packages, err := ParseTests(tests)
+		if err != nil {
+			return nil, fmt.Errorf("test output: %w", err)
+		}
+		result.Tests = summary
+		mergeTests(parsed, packages)
+	}
+
+	var notes []string
+	result.SuggestedScore, notes = mapping.score(parsed.Percent, result.Tests)
+	result.Details = details(result, notes)
+	return result, nil
+}
+
+// BE-IN - Internal backend only
+// mergeTests copies per-package test counts into the coverage packages.
+// Packages with tests but no coverage blocks are added with no statements.
+func mergeTests(profile *Profile, packages map[string]*TestSummary) {
+	byName := make(map[string]*PackageCoverage, len(profile.Packages))
+	for _, pkg := range profile.Packages {
+		byName[pkg.Package] = pkg
+	}
+	for name, tests := range packages {
+		pkg, ok := byName[name]
+		if !ok {
+			pkg = &PackageCoverage{Package: name}
+			profile.Packages = append(profile.Packages, pkg)
+		}
+		pkg.Passed = tests.Passed
+		pkg.Failed = tests.Failed
+		pkg.Skipped = tests.Skipped
+		pkg.Flaky = tests.Flaky
+	}
+	sort.Slice(profile.Packages, func(i, j int) bool {
+		return profile.Packages[i].Package < profile.Packages[j].Package
+	})
+}
+
+// BE-IN - Internal backend only
+// score applies the mapping and explains every adjustment.
+func (m Mapping) score(percent float64, tests *TestSummary) (int, []string) {
+	score := 0
+	for _, t := range m.Thresholds {
+		if percent >= t.MinPercent && t.Score > score {
+			score = t.Score
+		}
+	}
+	notes := []string{fmt.Sprintf("coverage maps to %d", score)}
+
+	if tests == nil {
+		return score, notes
+	}
+	if (tests.Failed > 0 || len(tests.FailedPackages) > 0) && score > m.FailureCap {
+		score = m.FailureCap
+		notes = append(notes, fmt.Sprintf("capped at %d by failing tests", m.FailureCap))
+	}
+	if tests.Flaky > 0 && m.FlakyPenalty > 0 {
+		penalty := tests.Flaky * m.FlakyPenalty
+		if penalty > m.MaxFlakyPenalty {
+			penalty = m.MaxFlakyPenalty
+		}
+		if penalty > score {
+			penalty = score
+		}
+		score -= penalty
+		notes = append(notes, fmt.Sprintf("minus %d for flaky tests", penalty))
+	}
+	return score, notes
+}
+
+// BE-IN - Internal backend only
+func details(result *Result, notes []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Statement coverage %.1f%% (%d of %d statements) across %d packages",
+		result.Percent, result.Covered, result.Statements, len(result.Packages))
+
+	var lowest []*PackageCoverage
+	for _, pkg := range result.Packages {
+		if pkg.Statements > 0 {
+			lowest = append(lowest, pkg)
+		}
+	}
+	sort.SliceStable(lowest, func(i, j int) bool { return lowest[i].Percent < lowest[j].Percent })
+	if len(lowest) > 1 {
+		var names []string
+		for _, pkg := range lowest[:min(len(lowest), maxNamedItems)] {
+			names = append(names, fmt.Sprintf("%s %.1f%%", pkg.Package, pkg.Percent))
+		}
+		fmt.Fprintf(&b, "; lowest: %s", strings.Join(names, ", "))
+	}
+	b.WriteString(".")
+
+	if tests := result.Tests; tests != nil {
+		fmt.Fprintf(&b, " Tests: %d passed, %d failed, %d flaky, %d skipped.", tests.Passed, tests.Failed, tests.Flaky, tests.Skipped)
+		if len(tests.FailedTests) > 0 {
+			fmt.Fprintf(&b, " Failed: %s.", listNames(tests.FailedTests, tests.Failed))
+		}
+		if len(tests.FailedPackages) > 0 {
+			fmt.Fprintf(&b, " Failed packages: %s.", listNames(tests.FailedPackages, len(tests.FailedPackages)))
+		}
+		if len(tests.FlakyTests) > 0 {
+			fmt.Fprintf(&b, " Flaky: %s.", listNames(tests.FlakyTests, tests.Flaky))
+		}
+	} else {
+		b.WriteString(" No test results supplied.")
+	}
+
+	fmt.Fprintf(&b, " Suggested score %d: %s.", result.SuggestedScore, strings.Join(notes, ", "))
+	return b.String()
+}
+
+// BE-IN - Internal backend only
+func listNames(names []string, total int) string {
+	listed := names[:min(len(names), maxNamedItems)]
+	text := strings.Join(listed, ", ")
+	if total > len(listed) {
+		text += fmt.Sprintf(" and %d more", total-len(listed))
+	}
+	return text
+}
@@ -0,0 +1,107 @@
+package coverage
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openFixture(t *testing.T, name string) io.Reader {
+	t.Helper()
+	if name == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return bytes.NewReader(data)
+}
+
+func TestAnalyze(t *testing.T) {
+	strict := DefaultMapping
+	strict.Thresholds = []Threshold{{85, 10}, {70, 6}, {0, 2}}
+	strict.FlakyPenalty = 2
+
+	tests := []struct {
+		name    string
+		tests   string
+		mapping Mapping
+		score   int
+		details string
+	}{
+		// 22 of 30 statements (73.3%) map to 8
+		{"profile only", "", DefaultMapping, 8, "No test results supplied. Suggested score 8: coverage maps to 8."},
+		// Failing tests cap the score at 3, one flaky test costs a point
+		{"failing tests", "tests.json", DefaultMapping, 2, "Suggested score 2: coverage maps to 8, capped at 3 by failing tests, minus 1 for flaky tests."},
+		// Two flaky tests cost two points
+		{"flaky tests", "tests_flaky.json", DefaultMapping, 6, "Suggested score 6: coverage maps to 8, minus 2 for flaky tests."},
+		// 73.3% maps to 6, two flaky tests at 2 points each stop at the
+		// maximum penalty of 3
+		{"custom mapping", "tests_flaky.json", strict, 3, "Suggested score 3: coverage maps to 6, minus 3 for flaky tests."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze(openFixture(t, "coverage.out"), openFixture(t, tt.tests), tt.mapping)
+			if err != nil {
+				t.Fatalf("Analyze: %v", err)
+			}
+			if result.SuggestedScore != tt.score {
+				t.Errorf("SuggestedScore = %d, want %d", result.SuggestedScore, tt.score)
+			}
+			if !strings.HasSuffix(result.Details, tt.details) {
+				t.Errorf("Details = %q, want it to end with %q", result.Details, tt.details)
+			}
+			wantPrefix := "Statement coverage 73.3% (22 of 30 statements) across 3 packages; lowest: encore.app/services/coverage 50.0%"
+			if !strings.HasPrefix(result.Details, wantPrefix) {
+				t.Errorf("Details = %q, want it to start with %q", result.Details, wantPrefix)
+			}
+			if (tt.tests == "") != (result.Tests == nil) {
+				t.Errorf("Tests = %+v with test input %q", result.Tests, tt.tests)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMergesTestsIntoPackages(t *testing.T) {
+	result, err := Analyze(openFixture(t, "coverage.out"), openFixture(t, "tests.json"), DefaultMapping)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	want := map[string]PackageCoverage{
+		"encore.app/api":               {Passed: 1, Flaky: 1},
+		"encore.app/models":            {Passed: 1, Failed: 1, Skipped: 1},
+		"encore.app/services/coverage": {},
+	}
+	for _, pkg := range result.Packages {
+		w := want[pkg.Package]
+		if pkg.Passed != w.Passed || pkg.Failed != w.Failed || pkg.Skipped != w.Skipped || pkg.Flaky != w.Flaky {
+			t.Errorf("%s = %+v, want %+v", pkg.Package, *pkg, w)
+		}
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := ParseThresholds("50=6, 85%=10 ,0=2")
+	if err != nil {
+		t.Fatalf("ParseThresholds: %v", err)
+	}
+	want := []Threshold{{85, 10}, {50, 6}, {0, 2}}
+	if len(thresholds) != len(want) {
+		t.Fatalf("ParseThresholds = %v, want %v", thresholds, want)
+	}
+	for i := range want {
+		if thresholds[i] != want[i] {
+			t.Errorf("threshold %d = %v, want %v", i, thresholds[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"85", "101=10", "50=11", "x=1"} {
+		if _, err := ParseThresholds(bad); err == nil {
+			t.Errorf("ParseThresholds(%q) succeeded", bad)
+		}
+	}
+}
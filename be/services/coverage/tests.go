@@ -0,0 +1,159 @@
+package coverage
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// maxListedTests bounds the failed and flaky test names kept in a summary
+const maxListedTests = 50
+
+// BE-IN - Internal backend only
+// TestSummary counts test outcomes from go test -json output. A test that
+// both failed and passed, e.g. across -count runs or a rerun of failures,
+// is flaky and counted once as flaky rather than as passed or failed.
+type TestSummary struct {
+	Passed         int      `json:"passed"`
+	Failed         int      `json:"failed"`
+	Skipped        int      `json:"skipped"`
+	Flaky          int      `json:"flaky"`
+	FailedTests    []string `json:"failed_tests,omitempty"`
+	FlakyTests     []string `json:"flaky_tests,omitempty"`
+	FailedPackages []string `json:"failed_packages,omitempty"`
+}
+
+// BE-IN - Internal backend only
+// testEvent is one line of go test -json (test2json) output.
+type testEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+}
+
+// BE-IN - Internal backend only
+type outcomes struct {
+	passed  bool
+	failed  bool
+	skipped bool
+}
+
+// BE-IN - Internal backend only
+// ParseTests reads go test -json output and returns the overall summary
+// and per-package summaries. Lines that are not JSON, such as build
+// errors printed by go test, are ignored.
+func ParseTests(r io.Reader) (*TestSummary, map[string]*TestSummary, error) {
+	// Score: [S7,P8,M7,T7,E7,L7]
+	// Details:
+	// - Security (S7): Decodes only the fields it needs
+	// - Performance (P8): Single streaming pass
+	// - Memory (M7): One entry per test, name lists capped
+	// - Testing (T7): Pure function over the event stream
+	// - Error (E7): Tolerates interleaved non-JSON output
+	// - Load (L7): Bounded by the upload size limit
+	// Tags: BE-module-medium
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+
+	tests := make(map[[2]string]*outcomes)
+	packageResults := make(map[string]*outcomes)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event testEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+		if event.Action != "pass" && event.Action != "fail" && event.Action != "skip" {
+			continue
+		}
+
+		var o *outcomes
+		if event.Test == "" {
+			o = packageResults[event.Package]
+			if o == nil {
+				o = &outcomes{}
+				packageResults[event.Package] = o
+			}
+		} else {
+			key := [2]string{event.Package, event.Test}
+			o = tests[key]
+			if o == nil {
+				o = &outcomes{}
+				tests[key] = o
+			}
+		}
+		switch event.Action {
+		case "pass":
+			o.passed = true
+		case "fail":
+			o.failed = true
+		case "skip":
+			o.skipped = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	keys := make([][2]string, 0, len(tests))
+	for key := range tests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	total := &TestSummary{}
+	packages := make(map[string]*TestSummary)
+	for _, key := range keys {
+		pkg, ok := packages[key[0]]
+		if !ok {
+			pkg = &TestSummary{}
+			packages[key[0]] = pkg
+		}
+		name := key[0] + "." + key[1]
+
+		switch o := tests[key]; {
+		case o.passed && o.failed:
+			pkg.Flaky++
+			total.Flaky++
+			if len(total.FlakyTests) < maxListedTests {
+				total.FlakyTests = append(total.FlakyTests, name)
+			}
+		case o.failed:
+			pkg.Failed++
+			total.Failed++
+			if len(total.FailedTests) < maxListedTests {
+				total.FailedTests = append(total.FailedTests, name)
+			}
+		case o.passed:
+			pkg.Passed++
+			total.Passed++
+		default:
+			pkg.Skipped++
+			total.Skipped++
+		}
+	}
+
+	for name, o := range packageResults {
+		// A package can fail without any failing test, e.g. a build
+		// failure or a panic in TestMain
+		pkg := packages[name]
+		if o.failed && !o.passed && (pkg == nil || pkg.Failed+pkg.Flaky == 0) {
+			total.FailedPackages = append(total.FailedPackages, name)
+		}
+	}
+	sort.Strings(total.FailedPackages)
+
+	return total, packages, nil
+}
@@ -0,0 +1,36 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTests(t *testing.T) {
+	summary, packages, err := ParseTests(openFixture(t, "tests.json"))
+	if err != nil {
+		t.Fatalf("ParseTests: %v", err)
+	}
+
+	// The build error lines are skipped, a fail then pass is flaky
+	want := &TestSummary{
+		Passed:         2,
+		Failed:         1,
+		Skipped:        1,
+		Flaky:          1,
+		FailedTests:    []string{"encore.app/models.TestPurgeDeletedReports"},
+		FlakyTests:     []string{"encore.app/api.TestListReports"},
+		FailedPackages: []string{"encore.app/services/webhooks"},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+	if api := packages["encore.app/api"]; api == nil || api.Passed != 1 || api.Flaky != 1 {
+		t.Errorf("encore.app/api = %+v, want 1 passed and 1 flaky", api)
+	}
+
+	summary, _, err = ParseTests(strings.NewReader("ok  \tencore.app/api\t0.5s\n{not json\n"))
+	if err != nil || !reflect.DeepEqual(summary, &TestSummary{}) {
+		t.Errorf("ParseTests of plain output = %+v, %v, want an empty summary", summary, err)
+	}
+}
@@ -0,0 +1,218 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"encore.app/models"
+)
+
+// BE-IN - Internal backend only
+// Supported listing export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// BE-IN - Internal backend only
+// listingColumns is the column order shared by every listing format.
+var listingColumns = []string{
+	"id", "title", "description", "project_id", "author_id", "department_id", "status",
+	"created_at", "updated_at", "submitted_at", "template_id", "metadata", "tags",
+	"security_score", "performance_score", "memory_score", "testing_score", "error_score", "load_score",
+	"security_details", "performance_details", "memory_details", "testing_details", "error_details", "load_details",
+	"evaluator_id",
+}
+
+// BE-IN - Internal backend only
+// ListingWriter writes one row per report. Rows are flushed as they are
+// written so exports of any size stream without being buffered.
+type ListingWriter interface {
+	WriteReport(report *models.Report, evaluation *models.Evaluation) error
+	Close() error
+}
+
+// BE-IN - Internal backend only
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// BE-IN - Internal backend only
+func NewListingWriter(w io.Writer, format string) (ListingWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVListingWriter(w)
+	case FormatXLSX:
+		return newXLSXListingWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// BE-IN - Internal backend only
+// cell is a single listing value; numeric cells are kept as numbers in
+// spreadsheet formats.
+type cell struct {
+	text    string
+	numeric bool
+}
+
+// BE-IN - Internal backend only
+func listingRow(report *models.Report, evaluation *models.Evaluation) []cell {
+	row := []cell{
+		{text: report.ID},
+		{text: report.Title},
+		{text: report.Description},
+		{text: report.ProjectID},
+		{text: report.AuthorID},
+		{text: report.DepartmentID},
+		{text: report.Status},
+		{text: formatTimestamp(&report.CreatedAt)},
+		{text: formatTimestamp(&report.UpdatedAt)},
+		{text: formatTimestamp(report.SubmittedAt)},
+		{text: report.TemplateID},
+		{text: formatMetadata(report.Metadata)},
+		{text: formatTags(report.Tags)},
+	}
+
+	if evaluation == nil {
+		for len(row) < len(listingColumns) {
+			row = append(row, cell{})
+		}
+		return row
+	}
+
+	for _, dim := range dimensions(evaluation) {
+		row = append(row, cell{text: strconv.Itoa(dim.Score), numeric: true})
+	}
+	for _, dim := range dimensions(evaluation) {
+		row = append(row, cell{text: dim.Details})
+	}
+	return append(row, cell{text: evaluation.EvaluatorID})
+}
+
+// BE-IN - Internal backend only
+func formatTimestamp(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// BE-IN - Internal backend only
+func formatMetadata(metadata map[string]interface{}) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// BE-IN - Internal backend only
+// formatTags joins tags with commas, the form the importer reads back.
+func formatTags(tags []models.Tag) string {
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		values[i] = tag.String()
+	}
+	return strings.Join(values, ",")
+}
+
+// BE-IN - Internal backend only
+type csvListingWriter struct {
+	w *csv.Writer
+}
+
+// BE-IN - Internal backend only
+func newCSVListingWriter(w io.Writer) (*csvListingWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(listingColumns); err != nil {
+		return nil, err
+	}
+	return &csvListingWriter{w: cw}, nil
+}
+
+// BE-IN - Internal backend only
+func (c *csvListingWriter) WriteReport(report *models.Report, evaluation *models.Evaluation) error {
+	row := listingRow(report, evaluation)
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = value.text
+		if !value.numeric {
+			record[i] = neutralizeFormula(value.text)
+		}
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// BE-IN - Internal backend only
+func (c *csvListingWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// BE-IN - Internal backend only
+// neutralizeFormula prefixes values that spreadsheet applications would
+// otherwise evaluate as formulas.
+func neutralizeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+// BE-IN - Internal backend only
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// BE-IN - Internal backend only
+// xmlText escapes s for an XML text node and drops characters XML 1.0
+// does not allow.
+func xmlText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '&':
+			b.WriteString("&amp;")
+		case r == '<':
+			b.WriteString("&lt;")
+		case r == '>':
+			b.WriteString("&gt;")
+		case r == '"':
+			b.WriteString("&quot;")
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0xFFFE || r == 0xFFFF:
+			continue
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,229 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"encore.app/models"
+)
+
+func TestNeutralizeFormula(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"plain", "plain"},
+		{"=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{"+1", "'+1"},
+		{"-cmd", "'-cmd"},
+		{"@import", "'@import"},
+		{"\tindent", "'\tindent"},
+		{"\rreturn", "'\rreturn"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := neutralizeFormula(tt.in); got != tt.want {
+			t.Errorf("neutralizeFormula(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for index, want := range tests {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", index, got, want)
+		}
+	}
+}
+
+func TestNewListingWriterRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewListingWriter(io.Discard, "ods"); err == nil {
+		t.Error("NewListingWriter accepted format ods")
+	}
+}
+
+// listingFixture returns a report with an evaluation and one without.
+func listingFixture() ([]*models.Report, []*models.Evaluation) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	evaluated := &models.Report{
+		ID:          "report-1",
+		Title:       "=HYPERLINK(\"http://evil\")",
+		Description: "<b>Cache</b> & \"flags\"\x01",
+		ProjectID:   "project-123",
+		AuthorID:    "user-123",
+		Status:      "approved",
+		Metadata:    map[string]interface{}{"team": "platform"},
+		Tags:        []models.Tag{{Type: "BE", Scope: "OUT", Impact: "high"}, {Type: "BE", Scope: "DB", Impact: "low"}},
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+	evaluation := &models.Evaluation{
+		SecurityScore: 8, PerformanceScore: 7, MemoryScore: 6, TestingScore: 5, ErrorScore: 4, LoadScore: 3,
+		SecurityDetails: "-no secrets in logs",
+		EvaluatorID:     "user-456",
+	}
+	draft := &models.Report{ID: "report-2", Title: "Draft", Status: "draft", CreatedAt: created, UpdatedAt: created}
+	return []*models.Report{evaluated, draft}, []*models.Evaluation{evaluation, nil}
+}
+
+func writeListing(t *testing.T, format string) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	writer, err := NewListingWriter(&out, format)
+	if err != nil {
+		t.Fatalf("NewListingWriter: %v", err)
+	}
+	reports, evaluations := listingFixture()
+	for i := range reports {
+		if err := writer.WriteReport(reports[i], evaluations[i]); err != nil {
+			t.Fatalf("WriteReport: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return out.Bytes()
+}
+
+// record maps column names to the values of one listing row.
+func record(row []string) map[string]string {
+	values := make(map[string]string, len(row))
+	for i, value := range row {
+		values[listingColumns[i]] = value
+	}
+	return values
+}
+
+func TestCSVListingWriter(t *testing.T) {
+	rows, err := csv.NewReader(bytes.NewReader(writeListing(t, FormatCSV))).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want header and 2 reports", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], listingColumns) {
+		t.Errorf("header = %q, want %q", rows[0], listingColumns)
+	}
+
+	first := record(rows[1])
+	for column, want := range map[string]string{
+		"title":            "'=HYPERLINK(\"http://evil\")",
+		"description":      "<b>Cache</b> & \"flags\"\x01",
+		"created_at":       "2024-03-01T08:30:00Z",
+		"submitted_at":     "",
+		"metadata":         `{"team":"platform"}`,
+		"tags":             "BE-OUT-high,BE-DB-low",
+		"security_score":   "8",
+		"load_score":       "3",
+		"security_details": "'-no secrets in logs",
+		"evaluator_id":     "user-456",
+	} {
+		if first[column] != want {
+			t.Errorf("%s = %q, want %q", column, first[column], want)
+		}
+	}
+
+	second := record(rows[2])
+	if second["title"] != "Draft" {
+		t.Errorf("title = %q, want Draft", second["title"])
+	}
+	for _, column := range listingColumns[13:] {
+		if second[column] != "" {
+			t.Errorf("unevaluated report has %s = %q, want empty", column, second[column])
+		}
+	}
+}
+
+// worksheet is the part of SpreadsheetML the listing writer produces.
+type worksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Style  string `xml:"s,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXListingWriter(t *testing.T) {
+	data := writeListing(t, FormatXLSX)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	wantNames := []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("entries = %q, want %q", names, wantNames)
+	}
+
+	f, err := archive.File[len(archive.File)-1].Open()
+	if err != nil {
+		t.Fatalf("opening sheet: %v", err)
+	}
+	defer f.Close()
+	var sheet worksheet
+	if err := xml.NewDecoder(f).Decode(&sheet); err != nil {
+		t.Fatalf("sheet is not well-formed XML: %v", err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("got %d rows, want header and 2 reports", len(sheet.Rows))
+	}
+
+	header := sheet.Rows[0]
+	if len(header.Cells) != len(listingColumns) {
+		t.Fatalf("header has %d cells, want %d", len(header.Cells), len(listingColumns))
+	}
+	if last := header.Cells[len(header.Cells)-1]; last.Ref != "Z1" || last.Inline != "evaluator_id" || last.Style != "1" {
+		t.Errorf("last header cell = %+v, want bold evaluator_id in Z1", last)
+	}
+
+	cells := make(map[string]string)
+	for _, c := range sheet.Rows[1].Cells {
+		if c.Type == "inlineStr" {
+			cells[c.Ref] = "text:" + c.Inline
+		} else {
+			cells[c.Ref] = "number:" + c.Value
+		}
+	}
+	for ref, want := range map[string]string{
+		"B2": "text:=HYPERLINK(\"http://evil\")",
+		"C2": "text:<b>Cache</b> & \"flags\"",
+		"M2": "text:BE-OUT-high,BE-DB-low",
+		"N2": "number:8",
+		"S2": "number:3",
+		"T2": "text:-no secrets in logs",
+	} {
+		if cells[ref] != want {
+			t.Errorf("%s = %q, want %q", ref, cells[ref], want)
+		}
+	}
+	// Empty values are left out rather than written as empty strings
+	if _, ok := cells["J2"]; ok {
+		t.Errorf("empty submitted_at written as %q", cells["J2"])
+	}
+
+	var refs []string
+	for _, c := range sheet.Rows[2].Cells {
+		refs = append(refs, c.Ref)
+	}
+	if got := strings.Join(refs, ","); got != "A3,B3,G3,H3,I3" {
+		t.Errorf("unevaluated report fills %s, want only its set report fields", got)
+	}
+}
@@ -0,0 +1,271 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// A4 portrait in PDF points
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	marginLeft   = 50.0
+	marginRight  = 50.0
+	marginTop    = 56.0
+	marginBottom = 56.0
+	contentWidth = pageWidth - marginLeft - marginRight
+)
+
+// BE-IN - Internal backend only
+// Fonts are the standard Type 1 fonts every PDF reader ships with, so no
+// font files need to be embedded.
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// BE-IN - Internal backend only
+// helveticaWidths holds Helvetica glyph widths for ASCII 32..126 in
+// thousandths of an em, taken from the standard AFM metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// BE-IN - Internal backend only
+type rgb struct{ r, g, b float64 }
+
+// BE-IN - Internal backend only
+var (
+	colorText   = rgb{0.13, 0.13, 0.15}
+	colorMuted  = rgb{0.42, 0.45, 0.50}
+	colorRule   = rgb{0.85, 0.87, 0.90}
+	colorHeader = rgb{0.95, 0.96, 0.97}
+	colorTrack  = rgb{0.92, 0.93, 0.95}
+)
+
+// BE-IN - Internal backend only
+// pdfDocument lays out text and simple shapes top to bottom, starting a
+// new page whenever the cursor runs into the bottom margin.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+// BE-IN - Internal backend only
+func newPDFDocument() *pdfDocument {
+	doc := &pdfDocument{}
+	doc.newPage()
+	return doc
+}
+
+// BE-IN - Internal backend only
+func (d *pdfDocument) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pageHeight - marginTop
+}
+
+// BE-IN - Internal backend only
+// ensure starts a new page if less than height points remain.
+func (d *pdfDocument) ensure(height float64) {
+	if d.y-height < marginBottom {
+		d.newPage()
+	}
+}
+
+// BE-IN - Internal backend only
+func (d *pdfDocument) space(height float64) {
+	d.y -= height
+}
+
+// BE-IN - Internal backend only
+func (d *pdfDocument) text(x, y float64, font string, size float64, color rgb, s string) {
+	fmt.Fprintf(d.page, "BT %.3f %.3f %.3f rg /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		color.r, color.g, color.b, font, size, x, y, escapePDFString(s))
+}
+
+// BE-IN - Internal backend only
+func (d *pdfDocument) rect(x, y, w, h float64, color rgb) {
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n",
+		color.r, color.g, color.b, x, y, w, h)
+}
+
+// BE-IN - Internal backend only
+func (d *pdfDocument) line(x1, y1, x2, y2 float64, color rgb) {
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f RG 0.5 w %.2f %.2f m %.2f %.2f l S\n",
+		color.r, color.g, color.b, x1, y1, x2, y2)
+}
+
+// BE-IN - Internal backend only
+// paragraph writes wrapped text at the cursor and advances it.
+func (d *pdfDocument) paragraph(x, width float64, font string, size float64, color rgb, s string) {
+	leading := size * 1.4
+	for _, line := range wrapText(s, font, size, width) {
+		d.ensure(leading)
+		d.y -= leading
+		d.text(x, d.y+size*0.3, font, size, color, line)
+	}
+}
+
+// BE-IN - Internal backend only
+func textWidth(s, font string, size float64) float64 {
+	var units int
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if font == fontBold {
+		// Helvetica-Bold runs roughly 6% wider than the regular face
+		width *= 1.06
+	}
+	return width
+}
+
+// BE-IN - Internal backend only
+// wrapText breaks s into lines no wider than width, keeping explicit line
+// breaks and splitting words that do not fit on a line of their own.
+func wrapText(s, font string, size, width float64) []string {
+	var lines []string
+	for _, raw := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		words := strings.Fields(raw)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		current := ""
+		for _, word := range words {
+			for textWidth(word, font, size) > width {
+				cut := len([]rune(word))
+				for cut > 1 && textWidth(string([]rune(word)[:cut]), font, size) > width {
+					cut--
+				}
+				if current != "" {
+					lines = append(lines, current)
+					current = ""
+				}
+				lines = append(lines, string([]rune(word)[:cut]))
+				word = string([]rune(word)[cut:])
+			}
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+			if textWidth(candidate, font, size) > width && current != "" {
+				lines = append(lines, current)
+				candidate = word
+			}
+			current = candidate
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// BE-IN - Internal backend only
+// escapePDFString encodes s as the body of a PDF literal string in
+// WinAnsiEncoding. Characters outside Latin-1 are replaced with '?'.
+func escapePDFString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r <= 126:
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r == '…':
+			b.WriteString("\\205")
+		case r == '–':
+			b.WriteString("\\226")
+		case r == '—':
+			b.WriteString("\\227")
+		case r == '‘' || r == '’':
+			b.WriteString("'")
+		case r == '“' || r == '”':
+			b.WriteString(`"`)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// BE-IN - Internal backend only
+// writeTo serialises the document as PDF 1.4 with Flate-compressed page
+// content streams.
+func (d *pdfDocument) writeTo(w io.Writer, title string) error {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-5: catalog, page tree, fonts and document info. Page and
+	// content objects follow in pairs starting at 6.
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 6+i*2))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (Project Reporting System) >>", escapePDFString(title)))
+
+	for i, page := range d.pages {
+		// Page footer with page numbers
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		fmt.Fprintf(page, "BT %.3f %.3f %.3f rg /%s 8.0 Tf %.2f %.2f Td (%s) Tj ET\n",
+			colorMuted.r, colorMuted.g, colorMuted.b, fontRegular,
+			pageWidth-marginRight-textWidth(footer, fontRegular, 8), marginBottom/2, footer)
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, 7+i*2))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			compressed.Len(), compressed.String()))
+	}
+
+	// Cross-reference table
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
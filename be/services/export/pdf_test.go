@@ -0,0 +1,214 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"encore.app/models"
+)
+
+func TestEscapePDFString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{`a (b) c\d`, `a \(b\) c\\d`},
+		{"tab\there", "tab here"},
+		{"café", `caf\351`},
+		{"wait… – —", `wait\205 \226 \227`},
+		{"‘quoted’ “twice”", `'quoted' "twice"`},
+		{"emoji 🙂 and 日本", "emoji ? and ??"},
+	}
+	for _, tt := range tests {
+		if got := escapePDFString(tt.in); got != tt.want {
+			t.Errorf("escapePDFString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	const width = 100.0
+
+	lines := wrapText("the quick brown fox jumps over the lazy dog again and again", fontRegular, 10, width)
+	if len(lines) < 2 {
+		t.Fatalf("wrapText returned %q, want several lines", lines)
+	}
+	for _, line := range lines {
+		if w := textWidth(line, fontRegular, 10); w > width {
+			t.Errorf("line %q is %.1f wide, want at most %.1f", line, w, width)
+		}
+	}
+	if got := strings.Join(lines, " "); got != "the quick brown fox jumps over the lazy dog again and again" {
+		t.Errorf("wrapped words = %q, want the input words in order", got)
+	}
+
+	// A word wider than the line is split
+	long := strings.Repeat("W", 40)
+	lines = wrapText("a "+long, fontRegular, 10, width)
+	if lines[0] != "a" || strings.Join(lines[1:], "") != long {
+		t.Errorf("wrapText split %q as %q", long, lines)
+	}
+	for _, line := range lines {
+		if w := textWidth(line, fontRegular, 10); w > width {
+			t.Errorf("line %q is %.1f wide, want at most %.1f", line, w, width)
+		}
+	}
+
+	// Explicit line breaks and blank lines are kept
+	if got := wrapText("one\r\n\ntwo", fontRegular, 10, width); strings.Join(got, "|") != "one||two" {
+		t.Errorf("wrapText kept breaks as %q, want [one  two]", got)
+	}
+}
+
+// textOp is one text drawing operation in a page content stream.
+type textOp struct {
+	y    float64
+	text string
+}
+
+var (
+	streamPattern = regexp.MustCompile(`/Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	textOpPattern = regexp.MustCompile(`Tf [\d.]+ ([\d.]+) Td \(((?:\\.|[^\\)])*)\) Tj`)
+)
+
+// pdfPages decompresses the content stream of every page.
+func pdfPages(t *testing.T, pdf []byte) [][]textOp {
+	t.Helper()
+	var pages [][]textOp
+	for _, match := range streamPattern.FindAllSubmatchIndex(pdf, -1) {
+		length, _ := strconv.Atoi(string(pdf[match[2]:match[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(pdf[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatalf("page %d: %v", len(pages)+1, err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("page %d: %v", len(pages)+1, err)
+		}
+		var ops []textOp
+		for _, op := range textOpPattern.FindAllStringSubmatch(string(content), -1) {
+			y, _ := strconv.ParseFloat(op[1], 64)
+			ops = append(ops, textOp{y: y, text: op[2]})
+		}
+		pages = append(pages, ops)
+	}
+	return pages
+}
+
+func testReport() *models.Report {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	return &models.Report{
+		ID:           "report-1",
+		Title:        "Cache (v2) rollout",
+		Description:  "Moves the cache behind a feature flag.",
+		ProjectID:    "project-123",
+		AuthorID:     "user-123",
+		DepartmentID: "dept-123",
+		Status:       "draft",
+		Metadata:     map[string]interface{}{"team": "platform", "owners": []interface{}{"alex", "sam"}},
+		CreatedAt:    created,
+		UpdatedAt:    created,
+	}
+}
+
+func TestWriteReportPDF(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteReportPDF(&out, ReportDocument{Report: testReport(), Department: "Engineering"}); err != nil {
+		t.Fatalf("WriteReportPDF: %v", err)
+	}
+	pdf := out.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Errorf("output is not framed as a PDF 1.4 file")
+	}
+	if !bytes.Contains(pdf, []byte(`/Title (Cache \(v2\) rollout)`)) {
+		t.Errorf("document info does not carry the escaped title")
+	}
+	if !bytes.Contains(pdf, []byte("/Count 1")) {
+		t.Errorf("page tree does not count one page")
+	}
+
+	pages := pdfPages(t, pdf)
+	if len(pages) != 1 {
+		t.Fatalf("got %d pages, want 1", len(pages))
+	}
+	var texts []string
+	for _, op := range pages[0] {
+		texts = append(texts, op.text)
+	}
+	all := strings.Join(texts, "\n")
+	for _, want := range []string{
+		`Cache \(v2\) rollout`,
+		"Department: Engineering",
+		`["alex","sam"]`,
+		"No evaluation has been recorded for this report.",
+		"Not submitted",
+		"Page 1 of 1",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("page text does not contain %q", want)
+		}
+	}
+}
+
+func TestWriteReportPDFSplitsTallRows(t *testing.T) {
+	// More detail lines than fit on a page
+	var details []string
+	for i := 1; i <= 120; i++ {
+		details = append(details, fmt.Sprintf("finding %d", i))
+	}
+	evaluation := &models.Evaluation{
+		SecurityScore:   6,
+		SecurityDetails: strings.Join(details, "\n"),
+		TestingScore:    7,
+		TestingDetails:  "Covered by unit tests",
+		EvaluatorID:     "user-456",
+	}
+
+	var out bytes.Buffer
+	if err := WriteReportPDF(&out, ReportDocument{Report: testReport(), Evaluation: evaluation}); err != nil {
+		t.Fatalf("WriteReportPDF: %v", err)
+	}
+	pages := pdfPages(t, out.Bytes())
+	if len(pages) < 3 {
+		t.Fatalf("got %d pages, want the details spread over at least 3", len(pages))
+	}
+
+	next := 1
+	for i, page := range pages {
+		for _, op := range page {
+			if strings.HasPrefix(op.text, "Page ") {
+				continue
+			}
+			if op.y < marginBottom {
+				t.Errorf("page %d: %q drawn at y=%.2f, below the bottom margin", i+1, op.text, op.y)
+			}
+			if op.text == fmt.Sprintf("finding %d", next) {
+				next++
+			}
+		}
+	}
+	if next != len(details)+1 {
+		t.Errorf("found findings 1 to %d in order, want all %d", next-1, len(details))
+	}
+
+	// Each page the table continues on repeats the header
+	for i, page := range pages[1:] {
+		continued := false
+		for _, op := range page {
+			if strings.HasPrefix(op.text, "finding ") {
+				continued = true
+			}
+		}
+		if continued && page[0].text != "Dimension" {
+			t.Errorf("page %d continues the table without a header, starts with %q", i+2, page[0].text)
+		}
+	}
+}
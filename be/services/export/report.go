@@ -0,0 +1,269 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"encore.app/models"
+)
+
+// BE-IN - Internal backend only
+type dimension struct {
+	Name    string
+	Score   int
+	Details string
+}
+
+// BE-IN - Internal backend only
+func dimensions(evaluation *models.Evaluation) []dimension {
+	return []dimension{
+		{"Security", evaluation.SecurityScore, evaluation.SecurityDetails},
+		{"Performance", evaluation.PerformanceScore, evaluation.PerformanceDetails},
+		{"Memory", evaluation.MemoryScore, evaluation.MemoryDetails},
+		{"Testing", evaluation.TestingScore, evaluation.TestingDetails},
+		{"Error Handling", evaluation.ErrorScore, evaluation.ErrorDetails},
+		{"Load", evaluation.LoadScore, evaluation.LoadDetails},
+	}
+}
+
+// BE-IN - Internal backend only
+type ReportDocument struct {
+	Report     *models.Report
+	Evaluation *models.Evaluation
+	Department string
+}
+
+// BE-IN - Internal backend only
+const timestampLayout = "2006-01-02 15:04 MST"
+
+// BE-OUT - External data involved
+// WriteReportPDF renders a report, its metadata and its evaluation as a
+// printable PDF document.
+func WriteReportPDF(w io.Writer, data ReportDocument) error {
+	// Score: [S7,P7,M6,T7,E7,L6]
+	// Details:
+	// - Security (S7): Text is escaped for PDF literals, no external binaries invoked
+	// - Performance (P7): Single layout pass, Flate-compressed content streams
+	// - Memory (M6): Whole document is buffered before writing
+	// - Testing (T7): Escaping, wrapping and page breaks checked against the decoded content streams
+	// - Error (E7): Write and compression errors are returned
+	// - Load (L6): CPU-bound rendering, a few milliseconds per report
+	// Tags: BE-OUT-medium
+
+	report := data.Report
+	doc := newPDFDocument()
+
+	// Title block
+	doc.paragraph(marginLeft, contentWidth, fontBold, 20, colorText, report.Title)
+	doc.space(4)
+	department := data.Department
+	if department == "" {
+		department = report.DepartmentID
+	}
+	doc.paragraph(marginLeft, contentWidth, fontRegular, 9, colorMuted, fmt.Sprintf(
+		"Status: %s   Project: %s   Department: %s   Author: %s",
+		report.Status, report.ProjectID, department, report.AuthorID))
+	doc.space(8)
+	doc.line(marginLeft, doc.y, pageWidth-marginRight, doc.y, colorRule)
+	doc.space(6)
+
+	// Description
+	writeHeading(doc, "Description")
+	doc.paragraph(marginLeft, contentWidth, fontRegular, 10, colorText, report.Description)
+
+	// Metadata
+	if len(report.Metadata) > 0 {
+		writeHeading(doc, "Metadata")
+		keys := make([]string, 0, len(report.Metadata))
+		for key := range report.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			writeKeyValue(doc, key, formatMetadataValue(report.Metadata[key]))
+		}
+	}
+
+	// Evaluation
+	writeHeading(doc, "Evaluation")
+	if data.Evaluation == nil {
+		doc.paragraph(marginLeft, contentWidth, fontRegular, 10, colorMuted, "No evaluation has been recorded for this report.")
+	} else {
+		writeScoreChart(doc, data.Evaluation)
+		doc.space(10)
+		writeScoreTable(doc, data.Evaluation)
+		doc.space(6)
+		writeKeyValue(doc, "Evaluator", data.Evaluation.EvaluatorID)
+		writeKeyValue(doc, "Evaluated", data.Evaluation.UpdatedAt.Format(timestampLayout))
+	}
+
+	// Timeline
+	writeHeading(doc, "Timeline")
+	writeKeyValue(doc, "Created", report.CreatedAt.Format(timestampLayout))
+	writeKeyValue(doc, "Last updated", report.UpdatedAt.Format(timestampLayout))
+	submitted := "Not submitted"
+	if report.SubmittedAt != nil {
+		submitted = report.SubmittedAt.Format(timestampLayout)
+	}
+	writeKeyValue(doc, "Submitted", submitted)
+	writeKeyValue(doc, "Exported", time.Now().Format(timestampLayout))
+
+	return doc.writeTo(w, report.Title)
+}
+
+// BE-IN - Internal backend only
+func writeHeading(doc *pdfDocument, title string) {
+	doc.ensure(40)
+	doc.space(14)
+	doc.paragraph(marginLeft, contentWidth, fontBold, 13, colorText, title)
+	doc.space(4)
+}
+
+// BE-IN - Internal backend only
+func writeKeyValue(doc *pdfDocument, key, value string) {
+	const keyWidth = 110.0
+	lines := wrapText(value, fontRegular, 10, contentWidth-keyWidth)
+	doc.ensure(14)
+	top := doc.y
+	doc.text(marginLeft, top-10, fontBold, 10, colorMuted, key)
+	for _, line := range lines {
+		doc.ensure(14)
+		doc.y -= 14
+		doc.text(marginLeft+keyWidth, doc.y+4, fontRegular, 10, colorText, line)
+	}
+}
+
+// BE-IN - Internal backend only
+func formatMetadataValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "-"
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// BE-IN - Internal backend only
+func scoreColor(score int) rgb {
+	switch {
+	case score >= 7:
+		return rgb{0.18, 0.62, 0.38}
+	case score >= 4:
+		return rgb{0.93, 0.62, 0.15}
+	default:
+		return rgb{0.85, 0.26, 0.24}
+	}
+}
+
+// BE-IN - Internal backend only
+// writeScoreChart draws a horizontal bar per dimension on a 0-10 scale.
+func writeScoreChart(doc *pdfDocument, evaluation *models.Evaluation) {
+	const (
+		labelWidth = 100.0
+		barHeight  = 12.0
+		rowHeight  = 20.0
+		trackWidth = contentWidth - labelWidth - 40
+	)
+
+	dims := dimensions(evaluation)
+	doc.ensure(rowHeight*float64(len(dims)) + 16)
+
+	total := 0
+	for _, dim := range dims {
+		doc.y -= rowHeight
+		doc.text(marginLeft, doc.y+3, fontRegular, 10, colorText, dim.Name)
+		doc.rect(marginLeft+labelWidth, doc.y, trackWidth, barHeight, colorTrack)
+
+		score := dim.Score
+		if score < 0 {
+			score = 0
+		}
+		if score > 10 {
+			score = 10
+		}
+		doc.rect(marginLeft+labelWidth, doc.y, trackWidth*float64(score)/10, barHeight, scoreColor(score))
+		doc.text(marginLeft+labelWidth+trackWidth+8, doc.y+3, fontBold, 10, colorText, fmt.Sprintf("%d/10", dim.Score))
+		total += dim.Score
+	}
+
+	doc.y -= 16
+	doc.text(marginLeft, doc.y, fontBold, 10, colorText,
+		fmt.Sprintf("Overall: %.1f/10", float64(total)/float64(len(dims))))
+}
+
+// BE-IN - Internal backend only
+// writeScoreTable draws one row per dimension. A row that does not fit
+// moves to the next page; a row taller than a whole page is split, with
+// the details continuing under a repeated header.
+func writeScoreTable(doc *pdfDocument, evaluation *models.Evaluation) {
+	const (
+		nameWidth  = 100.0
+		scoreWidth = 50.0
+		padding    = 4.0
+		leading    = 13.0
+	)
+	detailsWidth := contentWidth - nameWidth - scoreWidth
+	// Room for a row below the header on an empty page
+	pageRoom := pageHeight - marginTop - marginBottom - (leading + padding*2)
+
+	header := func() {
+		doc.ensure(leading + padding*2)
+		doc.y -= leading + padding*2
+		doc.rect(marginLeft, doc.y, contentWidth, leading+padding*2, colorHeader)
+		doc.text(marginLeft+padding, doc.y+padding+3, fontBold, 9, colorText, "Dimension")
+		doc.text(marginLeft+nameWidth+padding, doc.y+padding+3, fontBold, 9, colorText, "Score")
+		doc.text(marginLeft+nameWidth+scoreWidth+padding, doc.y+padding+3, fontBold, 9, colorText, "Details")
+	}
+	header()
+
+	for _, dim := range dimensions(evaluation) {
+		details := dim.Details
+		if details == "" {
+			details = "-"
+		}
+		lines := wrapText(details, fontRegular, 9, detailsWidth-padding*2)
+		height := float64(len(lines))*leading + padding*2
+
+		if doc.y-height < marginBottom && height <= pageRoom {
+			doc.newPage()
+			header()
+		}
+
+		first := true
+		for len(lines) > 0 {
+			fit := int((doc.y - marginBottom - padding*2) / leading)
+			if fit < 1 {
+				doc.newPage()
+				header()
+				continue
+			}
+			if fit > len(lines) {
+				fit = len(lines)
+			}
+			chunk := lines[:fit]
+			lines = lines[fit:]
+
+			height := float64(len(chunk))*leading + padding*2
+			doc.y -= height
+			top := doc.y + height
+
+			if first {
+				doc.text(marginLeft+padding, top-padding-leading+3, fontRegular, 9, colorText, dim.Name)
+				doc.text(marginLeft+nameWidth+padding, top-padding-leading+3, fontBold, 9, scoreColor(dim.Score), fmt.Sprintf("%d", dim.Score))
+				first = false
+			}
+			for i, line := range chunk {
+				doc.text(marginLeft+nameWidth+scoreWidth+padding, top-padding-leading*float64(i+1)+3, fontRegular, 9, colorText, line)
+			}
+			doc.line(marginLeft, doc.y, pageWidth-marginRight, doc.y, colorRule)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package gosource reads Go source trees and names their declarations
+// for the tools that inspect a module's code without building it.
+package gosource
+
+import (
+	"go/ast"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// File is one Go source file. Path is relative to the directory it was
+// read from, with forward slashes.
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// BE-IN - Internal backend only
+// ReadDir reads the .go files under root, skipping vendor, node_modules,
+// testdata, hidden and _ directories. Test files are read only when
+// tests is set.
+func ReadDir(root string, tests bool) ([]File, error) {
+	// Score: [S7,P7,M6,T7,E7,L7]
+	// Details:
+	// - Security (S7): Reads only under root, files are never executed
+	// - Performance (P7): Single directory walk
+	// - Memory (M6): Holds every file in memory for the upload
+	// - Testing (T7): Table test over a temporary tree
+	// - Error (E7): Walk and read errors returned
+	// - Load (L7): Runs once per CLI invocation
+	// Tags: BE-IN-low
+
+	var files []File
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (name == "vendor" || name == "node_modules" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || (!tests && strings.HasSuffix(name, "_test.go")) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		files = append(files, File{Path: filepath.ToSlash(rel), Content: string(content)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// BE-IN - Internal backend only
+// ReceiverType returns the name of a receiver's type without the pointer
+// or type parameters, or "" when it is not a named type.
+func ReceiverType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return ReceiverType(t.X)
+	case *ast.IndexExpr:
+		return ReceiverType(t.X)
+	case *ast.IndexListExpr:
+		return ReceiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// BE-IN - Internal backend only
+// FuncName formats a function as Name, Type.Name or (*Type).Name.
+func FuncName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	typeName := ReceiverType(recv)
+	if typeName == "" {
+		typeName = "?"
+	}
+	if _, pointer := recv.(*ast.StarExpr); pointer {
+		return "(*" + typeName + ")." + fn.Name.Name
+	}
+	return typeName + "." + fn.Name.Name
+}
@@ -0,0 +1,79 @@
+package gosource
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadDir(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"main.go", "main_test.go", "README.md",
+		"api/reports.go", "api/reports_test.go",
+		"vendor/lib/lib.go", "node_modules/x/x.go", "api/testdata/fixture.go",
+		".git/hooks.go", "_tools/tool.go",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package "+filepath.Base(filepath.Dir(path))), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		tests bool
+		want  []string
+	}{
+		{false, []string{"api/reports.go", "main.go"}},
+		{true, []string{"api/reports.go", "api/reports_test.go", "main.go", "main_test.go"}},
+	}
+	for _, tt := range tests {
+		files, err := ReadDir(root, tt.tests)
+		if err != nil {
+			t.Fatalf("ReadDir: %v", err)
+		}
+		var paths []string
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+		if !reflect.DeepEqual(paths, tt.want) {
+			t.Errorf("ReadDir(tests=%v) = %q, want %q", tt.tests, paths, tt.want)
+		}
+	}
+	if files, _ := ReadDir(root, false); files[0].Content != "package api" {
+		t.Errorf("Content = %q, want the file contents", files[0].Content)
+	}
+
+	if _, err := ReadDir(filepath.Join(root, "missing"), false); err == nil {
+		t.Error("ReadDir of a missing directory succeeded")
+	}
+}
+
+func TestFuncName(t *testing.T) {
+	src := `package p
+func Plain() {}
+func (s Store) Value() {}
+func (s *Store) Pointer() {}
+func (l *List[T]) Generic() {}
+func (m Map[K, V]) Pair() {}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Plain", "Store.Value", "(*Store).Pointer", "(*List).Generic", "Map.Pair"}
+	var got []string
+	for _, decl := range file.Decls {
+		got = append(got, FuncName(decl.(*ast.FuncDecl)))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FuncName = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := "\ufeffTitle, Project_ID,department_id,tags,metadata.owner,metadata,security_score,security_details,period_id\n" +
+		"Auth,project-123,dept-123,\"BE-OUT-high, BE-DB-medium\",alex,\"{\"\"tier\"\": 1}\",8,Reviewed,\n" +
+		"Gateway,project-456,dept-456,,,,high,,q1\n" +
+		"Broken,\"unterminated\n"
+
+	records, rowErrors, err := Parse(FormatCSV, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+
+	want := Record{
+		Row:          1,
+		Title:        "Auth",
+		ProjectID:    "project-123",
+		DepartmentID: "dept-123",
+		Tags:         []string{"BE-OUT-high", "BE-DB-medium"},
+		Metadata:     map[string]interface{}{"owner": "alex", "tier": float64(1)},
+		Evaluation:   &EvaluationRecord{SecurityScore: 8, SecurityDetails: "Reviewed"},
+	}
+	if !reflect.DeepEqual(records[0], want) {
+		t.Errorf("row 1 = %+v, want %+v", records[0], want)
+	}
+	if records[1].PeriodID == nil || *records[1].PeriodID != "q1" || records[1].Evaluation != nil {
+		t.Errorf("row 2 = %+v, want period q1 and no evaluation", records[1])
+	}
+
+	// The bad score and the malformed row are reported, and the malformed
+	// row keeps its number so later rows keep their positions
+	if len(rowErrors) != 2 || rowErrors[0] != (RowError{Row: 2, Field: "security_score", Message: "must be an integer"}) || rowErrors[1].Row != 3 {
+		t.Errorf("row errors = %+v", rowErrors)
+	}
+	if records[2].Row != 3 {
+		t.Errorf("malformed row numbered %d, want 3", records[2].Row)
+	}
+}
+
+func TestParseJSONL(t *testing.T) {
+	data := `{"title": "Auth", "project_id": "project-123", "department_id": "dept-123", "evaluation": {"testing_score": 7}}
+
+{"title": "Gateway", "owner": "alex"}
+not json
+`
+	records, rowErrors, err := Parse(FormatJSONL, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// Blank lines do not count as rows
+	if len(records) != 3 || records[1].Row != 2 || records[2].Row != 3 {
+		t.Fatalf("records = %+v", records)
+	}
+	if records[0].Evaluation == nil || records[0].Evaluation.TestingScore != 7 {
+		t.Errorf("row 1 evaluation = %+v", records[0].Evaluation)
+	}
+	if len(rowErrors) != 2 || rowErrors[0].Row != 2 || !strings.Contains(rowErrors[0].Message, `unknown field "owner"`) || rowErrors[1].Row != 3 {
+		t.Errorf("row errors = %+v", rowErrors)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   string
+	}{
+		{"format", "xlsx", "title\n", "unsupported import format"},
+		{"empty csv", FormatCSV, "", "csv header row is missing"},
+		{"unknown columns", FormatCSV, "title,owner,score\n", "unknown csv columns: owner, score"},
+		{"long line", FormatJSONL, `{"title": "` + strings.Repeat("x", maxLineSize) + `"}`, "invalid json lines input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Parse(tt.format, []byte(tt.data))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if _, _, err := Parse("xlsx", nil); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Parse error = %v, want ErrUnsupportedFormat", err)
+	}
+}
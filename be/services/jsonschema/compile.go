@@ -0,0 +1,313 @@
+// Package jsonschema validates JSON values against a subset of JSON Schema
+// (draft 2020-12) large enough to describe report metadata: types,
+// properties, required, additionalProperties, enum, const, string, number
+// and array limits, pattern, format and the allOf, anyOf, oneOf and not
+// combinators. $ref is not supported; a schema using a keyword outside
+// the subset is rejected rather than silently ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// Types are the JSON Schema type names.
+var Types = []string{"string", "number", "integer", "boolean", "null", "array", "object"}
+
+// BE-IN - Internal backend only
+// Formats are the supported values of format.
+var Formats = []string{"date", "date-time", "email", "uri", "uuid"}
+
+// BE-IN - Internal backend only
+// annotations are keywords accepted but not validated
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// BE-IN - Internal backend only
+// Schema is a compiled schema. A nil constraint is not checked.
+type Schema struct {
+	// always is set for the boolean schemas true and false
+	always *bool
+
+	Types []string
+
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *Schema
+	MinProperties        *int
+	MaxProperties        *int
+
+	Enum     []interface{}
+	Const    interface{}
+	HasConst bool
+
+	MinLength *int
+	MaxLength *int
+	Pattern   *regexp.Regexp
+	Format    string
+
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum *float64
+	ExclusiveMaximum *float64
+	MultipleOf       *float64
+
+	Items       *Schema
+	MinItems    *int
+	MaxItems    *int
+	UniqueItems bool
+
+	AllOf []*Schema
+	AnyOf []*Schema
+	OneOf []*Schema
+	Not   *Schema
+}
+
+// BE-IN - Internal backend only
+// Compile parses a schema document. Errors name the keyword path, such as
+// properties.owner.minLength.
+func Compile(raw []byte) (*Schema, error) {
+	// Score: [S8,P8,M8,T8,E9,L8]
+	// Details:
+	// - Security (S8): RE2 patterns cannot backtrack, unknown keywords rejected
+	// - Performance (P8): Schemas compiled once per write
+	// - Memory (M8): Proportional to the schema document
+	// - Testing (T8): Pure function over the document
+	// - Error (E9): Errors name the keyword path
+	// - Load (L8): No shared state
+	// Tags: BE-module-medium
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	return compile(doc, "")
+}
+
+// BE-IN - Internal backend only
+func compile(node interface{}, path string) (*Schema, error) {
+	if b, ok := node.(bool); ok {
+		return &Schema{always: &b}, nil
+	}
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, schemaError(path, "", "must be an object or a boolean")
+	}
+
+	// Keywords are compiled in a fixed order so the first error reported
+	// does not depend on map iteration
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	s := &Schema{}
+	for _, key := range keys {
+		value := obj[key]
+		var err error
+		switch key {
+		case "type":
+			s.Types, err = compileTypes(value, path)
+		case "properties":
+			props, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, schemaError(path, key, "must be an object")
+			}
+			s.Properties = make(map[string]*Schema, len(props))
+			for name, prop := range props {
+				if s.Properties[name], err = compile(prop, join(join(path, key), name)); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.Required, err = compileStrings(value, path, key)
+		case "additionalProperties":
+			s.AdditionalProperties, err = compile(value, join(path, key))
+		case "items":
+			s.Items, err = compile(value, join(path, key))
+		case "not":
+			s.Not, err = compile(value, join(path, key))
+		case "allOf":
+			s.AllOf, err = compileList(value, path, key)
+		case "anyOf":
+			s.AnyOf, err = compileList(value, path, key)
+		case "oneOf":
+			s.OneOf, err = compileList(value, path, key)
+		case "enum":
+			list, ok := value.([]interface{})
+			if !ok || len(list) == 0 {
+				return nil, schemaError(path, key, "must be a non-empty array")
+			}
+			s.Enum = list
+		case "const":
+			s.Const, s.HasConst = value, true
+		case "minLength":
+			s.MinLength, err = compileCount(value, path, key)
+		case "maxLength":
+			s.MaxLength, err = compileCount(value, path, key)
+		case "minItems":
+			s.MinItems, err = compileCount(value, path, key)
+		case "maxItems":
+			s.MaxItems, err = compileCount(value, path, key)
+		case "minProperties":
+			s.MinProperties, err = compileCount(value, path, key)
+		case "maxProperties":
+			s.MaxProperties, err = compileCount(value, path, key)
+		case "minimum":
+			s.Minimum, err = compileNumber(value, path, key)
+		case "maximum":
+			s.Maximum, err = compileNumber(value, path, key)
+		case "exclusiveMinimum":
+			s.ExclusiveMinimum, err = compileNumber(value, path, key)
+		case "exclusiveMaximum":
+			s.ExclusiveMaximum, err = compileNumber(value, path, key)
+		case "multipleOf":
+			s.MultipleOf, err = compileNumber(value, path, key)
+			if err == nil && *s.MultipleOf <= 0 {
+				err = schemaError(path, key, "must be greater than 0")
+			}
+		case "uniqueItems":
+			b, ok := value.(bool)
+			if !ok {
+				return nil, schemaError(path, key, "must be a boolean")
+			}
+			s.UniqueItems = b
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, schemaError(path, key, "must be a string")
+			}
+			if s.Pattern, err = regexp.Compile(pattern); err != nil {
+				return nil, schemaError(path, key, "is not a valid regular expression: "+err.Error())
+			}
+		case "format":
+			format, ok := value.(string)
+			if !ok || !contains(Formats, format) {
+				return nil, schemaError(path, key, "must be one of "+strings.Join(Formats, ", "))
+			}
+			s.Format = format
+		default:
+			if !annotations[key] {
+				return nil, schemaError(path, key, "is not a supported keyword")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// BE-IN - Internal backend only
+func compileTypes(value interface{}, path string) ([]string, error) {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, schemaError(path, "type", "must be a string or an array of strings")
+			}
+			types = append(types, name)
+		}
+	default:
+		return nil, schemaError(path, "type", "must be a string or an array of strings")
+	}
+	for _, name := range types {
+		if !contains(Types, name) {
+			return nil, schemaError(path, "type", fmt.Sprintf("%q is not one of %s", name, strings.Join(Types, ", ")))
+		}
+	}
+	return types, nil
+}
+
+// BE-IN - Internal backend only
+func compileList(value interface{}, path, key string) ([]*Schema, error) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, schemaError(path, key, "must be a non-empty array of schemas")
+	}
+	schemas := make([]*Schema, len(list))
+	for i, item := range list {
+		s, err := compile(item, fmt.Sprintf("%s[%d]", join(path, key), i))
+		if err != nil {
+			return nil, err
+		}
+		schemas[i] = s
+	}
+	return schemas, nil
+}
+
+// BE-IN - Internal backend only
+func compileStrings(value interface{}, path, key string) ([]string, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, schemaError(path, key, "must be an array of strings")
+	}
+	values := make([]string, len(list))
+	for i, item := range list {
+		if values[i], ok = item.(string); !ok {
+			return nil, schemaError(path, key, "must be an array of strings")
+		}
+	}
+	return values, nil
+}
+
+// BE-IN - Internal backend only
+func compileCount(value interface{}, path, key string) (*int, error) {
+	f, ok := value.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, schemaError(path, key, "must be a non-negative integer")
+	}
+	n := int(f)
+	return &n, nil
+}
+
+// BE-IN - Internal backend only
+func compileNumber(value interface{}, path, key string) (*float64, error) {
+	f, ok := value.(float64)
+	if !ok {
+		return nil, schemaError(path, key, "must be a number")
+	}
+	return &f, nil
+}
+
+// BE-IN - Internal backend only
+func schemaError(path, key, message string) error {
+	if where := join(path, key); where != "" {
+		return fmt.Errorf("schema %s: %s", where, message)
+	}
+	return fmt.Errorf("schema %s", message)
+}
+
+// BE-IN - Internal backend only
+// join appends a property name to a dotted path.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	if name == "" {
+		return path
+	}
+	return path + "." + name
+}
+
+// BE-IN - Internal backend only
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
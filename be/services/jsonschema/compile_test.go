@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Security metadata",
+		"type": "object",
+		"required": ["classification"],
+		"properties": {
+			"classification": {"enum": ["public", "internal", "confidential"]},
+			"owner": {"type": "string", "format": "email"},
+			"reviewers": {"type": "array", "items": {"type": "string"}, "maxItems": 3, "uniqueItems": true},
+			"cvss": {"type": "number", "minimum": 0, "maximum": 10, "multipleOf": 0.1}
+		},
+		"additionalProperties": false
+	}`))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if len(schema.Properties) != 4 || schema.AdditionalProperties == nil || *schema.Properties["reviewers"].MaxItems != 3 {
+		t.Errorf("compiled schema = %+v", schema)
+	}
+	if !schema.Accepts("object") || schema.Accepts("string") {
+		t.Error("Accepts does not follow type")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"invalid JSON", `{"type":`, "schema is not valid JSON"},
+		{"not an object", `[]`, "schema must be an object or a boolean"},
+		{"unknown keyword", `{"$ref": "#/defs/x"}`, "schema $ref: is not a supported keyword"},
+		{"unknown type", `{"type": "date"}`, `schema type: "date" is not one of string, number`},
+		{"type not a string", `{"type": [1]}`, "schema type: must be a string or an array of strings"},
+		{"nested property path", `{"properties": {"owner": {"properties": {"email": {"minLength": -1}}}}}`, "schema properties.owner.properties.email.minLength: must be a non-negative integer"},
+		{"fractional count", `{"maxItems": 1.5}`, "schema maxItems: must be a non-negative integer"},
+		{"combinator index", `{"anyOf": [{"type": "string"}, {"format": "phone"}]}`, "schema anyOf[1].format: must be one of date, date-time, email, uri, uuid"},
+		{"empty combinator", `{"oneOf": []}`, "schema oneOf: must be a non-empty array of schemas"},
+		{"empty enum", `{"enum": []}`, "schema enum: must be a non-empty array"},
+		{"invalid pattern", `{"pattern": "("}`, "schema pattern: is not a valid regular expression"},
+		{"zero multipleOf", `{"multipleOf": 0}`, "schema multipleOf: must be greater than 0"},
+		{"minimum not a number", `{"minimum": "1"}`, "schema minimum: must be a number"},
+		{"required not strings", `{"required": ["a", 1]}`, "schema required: must be an array of strings"},
+		{"uniqueItems not a boolean", `{"uniqueItems": "yes"}`, "schema uniqueItems: must be a boolean"},
+		{"items path", `{"items": {"additionalProperties": 1}}`, "schema items.additionalProperties: must be an object or a boolean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]byte(tt.schema))
+			if err == nil {
+				t.Fatal("Compile succeeded")
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Compile error = %q, want it to start with %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,362 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// BE-IN - Internal backend only
+// Error is a failed constraint. Path is the dotted location of the value
+// within the validated document, with array indexes in brackets, such as
+// owner.email or reviewers[1]; it is empty for the document itself.
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// BE-IN - Internal backend only
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// BE-IN - Internal backend only
+// Accepts reports whether the schema allows values of a JSON type at all.
+func (s *Schema) Accepts(typeName string) bool {
+	if s.always != nil {
+		return *s.always
+	}
+	return len(s.Types) == 0 || contains(s.Types, typeName) || (typeName == "integer" && contains(s.Types, "number"))
+}
+
+// BE-IN - Internal backend only
+// Validate checks a decoded JSON value and returns every failed
+// constraint, ordered by path. Go integer types are accepted as numbers.
+func (s *Schema) Validate(value interface{}) []Error {
+	// Score: [S8,P8,M8,T8,E9,L8]
+	// Details:
+	// - Security (S8): Linear in schema and value size, no recursion through $ref
+	// - Performance (P8): Single walk over the value
+	// - Memory (M8): Only the error list is allocated
+	// - Testing (T8): Pure function over schema and value
+	// - Error (E9): Every failure reported with its path
+	// - Load (L8): No shared state
+	// Tags: BE-module-medium
+
+	var errs []Error
+	s.validate(normalize(value), "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+// BE-IN - Internal backend only
+func (s *Schema) validate(value interface{}, path string, errs *[]Error) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.always != nil {
+		if !*s.always {
+			fail("is not allowed")
+		}
+		return
+	}
+
+	if len(s.Types) > 0 && !matchesType(s.Types, value) {
+		fail("must be %s", article(s.Types))
+		return
+	}
+	if s.Enum != nil && !containsValue(s.Enum, value) {
+		fail("must be one of %s", formatValues(s.Enum))
+	}
+	if s.HasConst && !reflect.DeepEqual(normalize(s.Const), value) {
+		fail("must be %s", formatValue(s.Const))
+	}
+
+	switch v := value.(type) {
+	case string:
+		s.validateString(v, fail)
+	case float64:
+		s.validateNumber(v, fail)
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		for i, item := range v {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if s.Items != nil {
+				s.Items.validate(item, itemPath, errs)
+			}
+			if s.UniqueItems {
+				for j := 0; j < i; j++ {
+					if reflect.DeepEqual(v[j], item) {
+						*errs = append(*errs, Error{Path: itemPath, Message: fmt.Sprintf("duplicates item %d", j)})
+						break
+					}
+				}
+			}
+		}
+	case map[string]interface{}:
+		s.validateObject(v, path, errs, fail)
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(value, path, errs)
+	}
+	if len(s.AnyOf) > 0 && countMatches(s.AnyOf, value) == 0 {
+		fail("does not match any of the allowed schemas")
+	}
+	if len(s.OneOf) > 0 {
+		if n := countMatches(s.OneOf, value); n != 1 {
+			fail("matches %d of the allowed schemas, exactly one is required", n)
+		}
+	}
+	if s.Not != nil && countMatches([]*Schema{s.Not}, value) == 1 {
+		fail("must not match the excluded schema")
+	}
+}
+
+// BE-IN - Internal backend only
+func (s *Schema) validateString(v string, fail func(string, ...interface{})) {
+	length := utf8.RuneCountInString(v)
+	if s.MinLength != nil && length < *s.MinLength {
+		fail("must be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("must be at most %d characters", *s.MaxLength)
+	}
+	if s.Pattern != nil && !s.Pattern.MatchString(v) {
+		fail("must match the pattern %s", s.Pattern)
+	}
+	if s.Format != "" && !validFormat(s.Format, v) {
+		fail("must be a valid %s", s.Format)
+	}
+}
+
+// BE-IN - Internal backend only
+func (s *Schema) validateNumber(v float64, fail func(string, ...interface{})) {
+	if s.Minimum != nil && v < *s.Minimum {
+		fail("must be at least %s", formatNumber(*s.Minimum))
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		fail("must be at most %s", formatNumber(*s.Maximum))
+	}
+	if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+		fail("must be greater than %s", formatNumber(*s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+		fail("must be less than %s", formatNumber(*s.ExclusiveMaximum))
+	}
+	if s.MultipleOf != nil {
+		q := v / *s.MultipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("must be a multiple of %s", formatNumber(*s.MultipleOf))
+		}
+	}
+}
+
+// BE-IN - Internal backend only
+func (s *Schema) validateObject(v map[string]interface{}, path string, errs *[]Error, fail func(string, ...interface{})) {
+	if s.MinProperties != nil && len(v) < *s.MinProperties {
+		fail("must have at least %d properties", *s.MinProperties)
+	}
+	if s.MaxProperties != nil && len(v) > *s.MaxProperties {
+		fail("must have at most %d properties", *s.MaxProperties)
+	}
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			*errs = append(*errs, Error{Path: join(path, name), Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if prop, ok := s.Properties[name]; ok {
+			prop.validate(v[name], join(path, name), errs)
+		} else if s.AdditionalProperties != nil {
+			s.AdditionalProperties.validate(v[name], join(path, name), errs)
+		}
+	}
+}
+
+// BE-IN - Internal backend only
+func countMatches(schemas []*Schema, value interface{}) int {
+	n := 0
+	for _, s := range schemas {
+		var errs []Error
+		s.validate(value, "", &errs)
+		if len(errs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// BE-IN - Internal backend only
+func matchesType(types []string, value interface{}) bool {
+	for _, t := range types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+func validFormat(format, value string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != ""
+	case "uuid":
+		_, err := uuid.Parse(value)
+		return err == nil && len(value) == 36
+	}
+	return true
+}
+
+// BE-IN - Internal backend only
+// normalize converts Go numbers to float64 recursively, so values built
+// in Go compare like decoded JSON.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case []string:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = item
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = normalize(item)
+		}
+		return out
+	}
+	return value
+}
+
+// BE-IN - Internal backend only
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(normalize(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+// article renders a type list for messages: "a string", "an integer or null".
+func article(types []string) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "null":
+			parts[i] = "null"
+		case "integer", "object", "array":
+			parts[i] = "an " + t
+		default:
+			parts[i] = "a " + t
+		}
+	}
+	return strings.Join(parts, " or ")
+}
+
+// BE-IN - Internal backend only
+func formatValues(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatValue(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BE-IN - Internal backend only
+func formatValue(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return formatNumber(f)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// BE-IN - Internal backend only
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,146 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func mustCompile(t *testing.T, schema string) *Schema {
+	t.Helper()
+	s, err := Compile([]byte(schema))
+	if err != nil {
+		t.Fatalf("Compile(%s): %v", schema, err)
+	}
+	return s
+}
+
+func decode(t *testing.T, value string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		t.Fatalf("decode %s: %v", value, err)
+	}
+	return v
+}
+
+func TestValidateKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		value  string
+		want   []Error
+	}{
+		{"type", `{"type": "string"}`, `1`, []Error{{"", "must be a string"}}},
+		{"type list", `{"type": ["integer", "null"]}`, `"x"`, []Error{{"", "must be an integer or null"}}},
+		{"integer", `{"type": "integer"}`, `1.5`, []Error{{"", "must be an integer"}}},
+		{"integer accepts whole numbers", `{"type": "integer"}`, `2.0`, nil},
+		{"enum", `{"enum": ["low", 2]}`, `"high"`, []Error{{"", `must be one of "low", 2`}}},
+		{"const", `{"const": {"a": 1}}`, `{"a": 2}`, []Error{{"", `must be {"a":1}`}}},
+		{"minLength counts runes", `{"minLength": 3}`, `"äö"`, []Error{{"", "must be at least 3 characters"}}},
+		{"maxLength", `{"maxLength": 2}`, `"abc"`, []Error{{"", "must be at most 2 characters"}}},
+		{"pattern", `{"pattern": "^[A-Z]+-[0-9]+$"}`, `"sec-1"`, []Error{{"", "must match the pattern ^[A-Z]+-[0-9]+$"}}},
+		{"pattern ignores other types", `{"pattern": "^a"}`, `1`, nil},
+		{"format date", `{"format": "date"}`, `"2026-02-30"`, []Error{{"", "must be a valid date"}}},
+		{"format date-time", `{"format": "date-time"}`, `"2026-02-01 10:00"`, []Error{{"", "must be a valid date-time"}}},
+		{"format email", `{"format": "email"}`, `"Alex <alex@example.com>"`, []Error{{"", "must be a valid email"}}},
+		{"format uri", `{"format": "uri"}`, `"example.com/x"`, []Error{{"", "must be a valid uri"}}},
+		{"format uuid", `{"format": "uuid"}`, `"123e4567e89b12d3a456426614174000"`, []Error{{"", "must be a valid uuid"}}},
+		{"minimum", `{"minimum": 0}`, `-1`, []Error{{"", "must be at least 0"}}},
+		{"maximum", `{"maximum": 10}`, `10.5`, []Error{{"", "must be at most 10"}}},
+		{"exclusiveMinimum", `{"exclusiveMinimum": 0}`, `0`, []Error{{"", "must be greater than 0"}}},
+		{"exclusiveMaximum", `{"exclusiveMaximum": 1}`, `1`, []Error{{"", "must be less than 1"}}},
+		{"multipleOf", `{"multipleOf": 0.1}`, `0.35`, []Error{{"", "must be a multiple of 0.1"}}},
+		{"multipleOf tolerates rounding", `{"multipleOf": 0.1}`, `0.3`, nil},
+		{"minItems", `{"minItems": 2}`, `[1]`, []Error{{"", "must have at least 2 items"}}},
+		{"maxItems", `{"maxItems": 1}`, `[1, 2]`, []Error{{"", "must have at most 1 items"}}},
+		{"uniqueItems", `{"uniqueItems": true}`, `["a", "b", "a"]`, []Error{{"[2]", "duplicates item 0"}}},
+		{"items", `{"items": {"type": "string"}}`, `["a", 1]`, []Error{{"[1]", "must be a string"}}},
+		{"required", `{"required": ["owner", "cvss"]}`, `{"owner": "x"}`, []Error{{"cvss", "is required"}}},
+		{"minProperties", `{"minProperties": 1}`, `{}`, []Error{{"", "must have at least 1 properties"}}},
+		{"maxProperties", `{"maxProperties": 1}`, `{"a": 1, "b": 2}`, []Error{{"", "must have at most 1 properties"}}},
+		{"additionalProperties false", `{"properties": {"a": {}}, "additionalProperties": false}`, `{"a": 1, "b": 2}`, []Error{{"b", "is not allowed"}}},
+		{"additionalProperties schema", `{"additionalProperties": {"type": "boolean"}}`, `{"flag": "yes"}`, []Error{{"flag", "must be a boolean"}}},
+		{"allOf", `{"allOf": [{"minLength": 2}, {"maxLength": 3}]}`, `"abcd"`, []Error{{"", "must be at most 3 characters"}}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"type": "null"}]}`, `1`, []Error{{"", "does not match any of the allowed schemas"}}},
+		{"oneOf none", `{"oneOf": [{"type": "string"}, {"type": "null"}]}`, `1`, []Error{{"", "matches 0 of the allowed schemas, exactly one is required"}}},
+		{"oneOf several", `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, `1`, []Error{{"", "matches 2 of the allowed schemas, exactly one is required"}}},
+		{"not", `{"not": {"const": "none"}}`, `"none"`, []Error{{"", "must not match the excluded schema"}}},
+		{"false schema", `false`, `{}`, []Error{{"", "is not allowed"}}},
+		{"true schema", `true`, `[1, "a", null]`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustCompile(t, tt.schema).Validate(decode(t, tt.value))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%s) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateErrorPaths(t *testing.T) {
+	schema := mustCompile(t, `{
+		"type": "object",
+		"required": ["classification", "owner"],
+		"properties": {
+			"classification": {"enum": ["public", "internal"]},
+			"owner": {
+				"type": "object",
+				"required": ["email"],
+				"properties": {"email": {"type": "string", "format": "email"}}
+			},
+			"reviewers": {
+				"type": "array",
+				"items": {"type": "object", "properties": {"name": {"minLength": 1}}}
+			}
+		},
+		"additionalProperties": false
+	}`)
+
+	got := schema.Validate(decode(t, `{
+		"owner": {"email": "not an address"},
+		"reviewers": [{"name": "Sam"}, {"name": ""}],
+		"severity": "high"
+	}`))
+	want := []Error{
+		{"classification", "is required"},
+		{"owner.email", "must be a valid email"},
+		{"reviewers[1].name", "must be at least 1 characters"},
+		{"severity", "is not allowed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate = %v, want %v", got, want)
+	}
+
+	if got := (Error{Path: "owner.email", Message: "must be a valid email"}).Error(); got != "owner.email: must be a valid email" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := (Error{Message: "must be an object"}).Error(); got != "must be an object" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestValidateGoValues(t *testing.T) {
+	schema := mustCompile(t, `{
+		"properties": {
+			"count": {"type": "integer", "maximum": 5},
+			"tags": {"type": "array", "items": {"enum": ["a", "b"]}},
+			"level": {"enum": [1, 2]}
+		}
+	}`)
+
+	// Metadata built in Go, rather than decoded, validates the same way
+	got := schema.Validate(map[string]interface{}{
+		"count": int64(7),
+		"tags":  []string{"a", "c"},
+		"level": 2,
+	})
+	want := []Error{
+		{"count", "must be at most 5"},
+		{"tags[1]", `must be one of "a", "b"`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,491 @@
+// Package loadtest parses load-test results from vegeta, k6 or a CSV of
+// latency samples into a common summary and suggests a load score for it
+// from service level objectives.
+package loadtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BE-IN - Internal backend only
+// Supported input formats
+const (
+	FormatVegeta        = "vegeta"
+	FormatVegetaResults = "vegeta-results"
+	FormatK6            = "k6"
+	FormatCSV           = "csv"
+)
+
+// BE-IN - Internal backend only
+// Summary is a load test reduced to the metrics the score is based on.
+// Latencies are in milliseconds and throughput counts successful
+// requests per second. Metrics the source does not report are zero and
+// listed in Missing.
+type Summary struct {
+	Format     string   `json:"format"`
+	Requests   int      `json:"requests"`
+	Errors     int      `json:"errors"`
+	ErrorRate  float64  `json:"error_rate"`
+	Duration   float64  `json:"duration_seconds"`
+	Throughput float64  `json:"throughput"`
+	MeanMs     float64  `json:"mean_ms"`
+	P50Ms      float64  `json:"p50_ms"`
+	P95Ms      float64  `json:"p95_ms"`
+	P99Ms      float64  `json:"p99_ms"`
+	MaxMs      float64  `json:"max_ms"`
+	Missing    []string `json:"missing,omitempty"`
+}
+
+// BE-IN - Internal backend only
+// Options complete inputs that lack information. Duration is used for
+// CSV samples without timestamps.
+type Options struct {
+	Format   string
+	Duration time.Duration
+}
+
+// BE-IN - Internal backend only
+// Parse reads load-test results. With an empty Format the format is
+// detected: a vegeta report -type=json object, vegeta encode -to json
+// results, a k6 summary (--summary-export or handleSummary data) or a
+// CSV of latency samples.
+func Parse(r io.Reader, opts Options) (*Summary, error) {
+	// Score: [S7,P7,M6,T7,E8,L7]
+	// Details:
+	// - Security (S7): Uploaded text decoded into fixed structures only
+	// - Performance (P7): Single pass, one sort for sample percentiles
+	// - Memory (M6): Latency samples held to compute percentiles
+	// - Testing (T7): Pure function over the uploaded text
+	// - Error (E8): Rows and lines named in parse errors
+	// - Load (L7): Bounded by the upload size limit
+	// Tags: BE-module-medium
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = detect(data)
+		if format == "" {
+			return nil, fmt.Errorf("unrecognized JSON, expected a vegeta report or results, or a k6 summary")
+		}
+	}
+
+	var summary *Summary
+	switch format {
+	case FormatVegeta:
+		summary, err = parseVegetaReport(data)
+	case FormatVegetaResults:
+		summary, err = parseVegetaResults(data)
+	case FormatK6:
+		summary, err = parseK6(data)
+	case FormatCSV:
+		summary, err = parseCSV(data, opts.Duration)
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected vegeta, vegeta-results, k6 or csv", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", format, err)
+	}
+
+	summary.Format = format
+	if summary.Requests > 0 && summary.ErrorRate == 0 {
+		summary.ErrorRate = float64(summary.Errors) / float64(summary.Requests)
+	}
+	summary.ErrorRate = round(summary.ErrorRate, 6)
+	summary.Throughput = round(summary.Throughput, 2)
+	summary.Duration = round(summary.Duration, 3)
+	for _, m := range []*float64{&summary.MeanMs, &summary.P50Ms, &summary.P95Ms, &summary.P99Ms, &summary.MaxMs} {
+		*m = round(*m, 3)
+	}
+	summary.Missing = missing(summary)
+	return summary, nil
+}
+
+// BE-IN - Internal backend only
+func detect(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		return FormatCSV
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(trimmed)).Decode(&probe); err != nil {
+		return FormatCSV
+	}
+	switch {
+	case probe["metrics"] != nil:
+		return FormatK6
+	case probe["latencies"] != nil:
+		return FormatVegeta
+	case probe["latency"] != nil:
+		return FormatVegetaResults
+	}
+	return ""
+}
+
+// BE-IN - Internal backend only
+type vegetaReport struct {
+	Latencies struct {
+		Mean float64 `json:"mean"`
+		P50  float64 `json:"50th"`
+		P95  float64 `json:"95th"`
+		P99  float64 `json:"99th"`
+		Max  float64 `json:"max"`
+	} `json:"latencies"`
+	Duration    float64        `json:"duration"`
+	Wait        float64        `json:"wait"`
+	Requests    int            `json:"requests"`
+	Throughput  float64        `json:"throughput"`
+	Success     float64        `json:"success"`
+	StatusCodes map[string]int `json:"status_codes"`
+}
+
+// BE-IN - Internal backend only
+// parseVegetaReport reads vegeta report -type=json. Durations are
+// nanoseconds.
+func parseVegetaReport(data []byte) (*Summary, error) {
+	var report vegetaReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid report: %v", err)
+	}
+	if report.Requests == 0 {
+		return nil, fmt.Errorf("report has no requests")
+	}
+
+	const ms = float64(time.Millisecond)
+	return &Summary{
+		Requests:   report.Requests,
+		Errors:     int(math.Round(float64(report.Requests) * (1 - report.Success))),
+		ErrorRate:  1 - report.Success,
+		Duration:   (report.Duration + report.Wait) / float64(time.Second),
+		Throughput: report.Throughput,
+		MeanMs:     report.Latencies.Mean / ms,
+		P50Ms:      report.Latencies.P50 / ms,
+		P95Ms:      report.Latencies.P95 / ms,
+		P99Ms:      report.Latencies.P99 / ms,
+		MaxMs:      report.Latencies.Max / ms,
+	}, nil
+}
+
+// BE-IN - Internal backend only
+type vegetaResult struct {
+	Timestamp time.Time `json:"timestamp"`
+	Latency   int64     `json:"latency"`
+	Code      int       `json:"code"`
+	Error     string    `json:"error"`
+}
+
+// BE-IN - Internal backend only
+// parseVegetaResults reads the results stream of vegeta encode -to json,
+// one JSON object per line.
+func parseVegetaResults(data []byte) (*Summary, error) {
+	var s samples
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var result vegetaResult
+		if err := json.Unmarshal(text, &result); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		failed := result.Error != "" || result.Code == 0 || result.Code >= 400
+		s.add(float64(result.Latency)/float64(time.Millisecond), failed, result.Timestamp)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return s.summary(0)
+}
+
+// BE-IN - Internal backend only
+// k6Metric accepts both the flat metrics of --summary-export and the
+// values object of handleSummary data. Trend times are milliseconds.
+type k6Metric struct {
+	Values map[string]float64 `json:"values"`
+	flat   map[string]float64
+}
+
+// BE-IN - Internal backend only
+func (m *k6Metric) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	m.flat = make(map[string]float64)
+	for key, value := range raw {
+		if key == "values" {
+			if err := json.Unmarshal(value, &m.Values); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+			continue
+		}
+		var f float64
+		if json.Unmarshal(value, &f) == nil {
+			m.flat[key] = f
+		}
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+func (m *k6Metric) get(key string) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	if v, ok := m.Values[key]; ok {
+		return v, true
+	}
+	v, ok := m.flat[key]
+	return v, ok
+}
+
+// BE-IN - Internal backend only
+type k6Summary struct {
+	Metrics map[string]*k6Metric `json:"metrics"`
+	State   struct {
+		TestRunDurationMs float64 `json:"testRunDurationMs"`
+	} `json:"state"`
+}
+
+// BE-IN - Internal backend only
+// parseK6 reads a k6 summary. p(99) is only present when k6 ran with a
+// summaryTrendStats option that includes it.
+func parseK6(data []byte) (*Summary, error) {
+	var summary k6Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("invalid summary: %v", err)
+	}
+	reqs := summary.Metrics["http_reqs"]
+	duration := summary.Metrics["http_req_duration"]
+	count, ok := reqs.get("count")
+	if !ok || count == 0 {
+		return nil, fmt.Errorf("summary has no http_reqs count")
+	}
+	if duration == nil {
+		return nil, fmt.Errorf("summary has no http_req_duration metric")
+	}
+
+	s := &Summary{Requests: int(count)}
+	s.MeanMs, _ = duration.get("avg")
+	s.P50Ms, _ = duration.get("med")
+	s.P95Ms, _ = duration.get("p(95)")
+	s.P99Ms, _ = duration.get("p(99)")
+	s.MaxMs, _ = duration.get("max")
+
+	// http_req_failed is a rate metric: passes counts failed requests
+	failed := summary.Metrics["http_req_failed"]
+	if rate, ok := failed.get("rate"); ok {
+		s.ErrorRate = rate
+	} else if value, ok := failed.get("value"); ok {
+		s.ErrorRate = value
+	}
+	if passes, ok := failed.get("passes"); ok {
+		s.Errors = int(passes)
+	} else {
+		s.Errors = int(math.Round(count * s.ErrorRate))
+	}
+
+	rate, _ := reqs.get("rate")
+	s.Throughput = rate * (1 - s.ErrorRate)
+	if summary.State.TestRunDurationMs > 0 {
+		s.Duration = summary.State.TestRunDurationMs / 1000
+	} else if rate > 0 {
+		s.Duration = count / rate
+	}
+	return s, nil
+}
+
+// BE-IN - Internal backend only
+// parseCSV reads latency samples with a header row. latency_ms is
+// required; status (an HTTP status, where 0 and codes from 400 up are
+// failures), error (failed when not empty) and timestamp (RFC 3339 or
+// Unix milliseconds) are optional. Without timestamps throughput needs
+// duration.
+func parseCSV(data []byte, duration time.Duration) (*Summary, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row: %v", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	latencyColumn, ok := columns["latency_ms"]
+	if !ok {
+		return nil, fmt.Errorf("header must include a latency_ms column")
+	}
+	statusColumn, hasStatus := columns["status"]
+	errorColumn, hasError := columns["error"]
+	timeColumn, hasTime := columns["timestamp"]
+
+	var s samples
+	row := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", row, err)
+		}
+		field := func(i int) string {
+			if i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		latency, err := strconv.ParseFloat(field(latencyColumn), 64)
+		if err != nil || latency < 0 {
+			return nil, fmt.Errorf("row %d: invalid latency_ms %q", row, field(latencyColumn))
+		}
+		failed := false
+		if hasStatus && field(statusColumn) != "" {
+			status, err := strconv.Atoi(field(statusColumn))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid status %q", row, field(statusColumn))
+			}
+			failed = status == 0 || status >= 400
+		}
+		if hasError && field(errorColumn) != "" {
+			failed = true
+		}
+		var at time.Time
+		if hasTime && field(timeColumn) != "" {
+			at, err = parseTimestamp(field(timeColumn))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %v", row, err)
+			}
+		}
+		s.add(latency, failed, at)
+	}
+	return s.summary(duration)
+}
+
+// BE-IN - Internal backend only
+func parseTimestamp(value string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q, expected RFC 3339 or Unix milliseconds", value)
+	}
+	return t, nil
+}
+
+// BE-IN - Internal backend only
+// samples accumulates individual requests.
+type samples struct {
+	latencies []float64
+	errors    int
+	first     time.Time
+	last      time.Time
+}
+
+// BE-IN - Internal backend only
+func (s *samples) add(latencyMs float64, failed bool, at time.Time) {
+	s.latencies = append(s.latencies, latencyMs)
+	if failed {
+		s.errors++
+	}
+	if at.IsZero() {
+		return
+	}
+	end := at.Add(time.Duration(latencyMs * float64(time.Millisecond)))
+	if s.first.IsZero() || at.Before(s.first) {
+		s.first = at
+	}
+	if end.After(s.last) {
+		s.last = end
+	}
+}
+
+// BE-IN - Internal backend only
+// summary computes percentiles by nearest rank. The span of the
+// timestamps gives the duration; fallback is used when there are none.
+func (s *samples) summary(fallback time.Duration) (*Summary, error) {
+	if len(s.latencies) == 0 {
+		return nil, fmt.Errorf("no samples found")
+	}
+	sorted := append([]float64(nil), s.latencies...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, l := range sorted {
+		sum += l
+	}
+	summary := &Summary{
+		Requests: len(sorted),
+		Errors:   s.errors,
+		MeanMs:   sum / float64(len(sorted)),
+		P50Ms:    percentile(sorted, 50),
+		P95Ms:    percentile(sorted, 95),
+		P99Ms:    percentile(sorted, 99),
+		MaxMs:    sorted[len(sorted)-1],
+	}
+
+	duration := fallback
+	if !s.first.IsZero() && s.last.After(s.first) {
+		duration = s.last.Sub(s.first)
+	}
+	if duration > 0 {
+		summary.Duration = duration.Seconds()
+		summary.Throughput = float64(summary.Requests-summary.Errors) / duration.Seconds()
+	}
+	return summary, nil
+}
+
+// BE-IN - Internal backend only
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// BE-IN - Internal backend only
+// missing names the metrics the source did not provide.
+func missing(s *Summary) []string {
+	var names []string
+	if s.P95Ms == 0 {
+		names = append(names, "p95")
+	}
+	if s.P99Ms == 0 {
+		names = append(names, "p99")
+	}
+	if s.Throughput == 0 && s.Errors < s.Requests {
+		names = append(names, "throughput")
+	}
+	return names
+}
+
+// BE-IN - Internal backend only
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,98 @@
+package loadtest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseFixture(t *testing.T, name string, opts Options) *Summary {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+	summary, err := Parse(f, opts)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return summary
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fixture string
+		opts    Options
+		want    Summary
+	}{
+		{"vegeta_report.json", Options{}, Summary{Format: FormatVegeta, Requests: 9000, Errors: 9, ErrorRate: 0.001, Duration: 30, Throughput: 299.7,
+			MeanMs: 42, P50Ms: 35, P95Ms: 120, P99Ms: 310, MaxMs: 900}},
+		{"vegeta_results.jsonl", Options{}, Summary{Format: FormatVegetaResults, Requests: 20, Errors: 2, ErrorRate: 0.1, Duration: 1.23, Throughput: 14.63,
+			MeanMs: 50.8, P50Ms: 16, P95Ms: 250, P99Ms: 480, MaxMs: 480}},
+		// The export has no p99; throughput counts successful requests
+		{"k6_export.json", Options{}, Summary{Format: FormatK6, Requests: 12000, Errors: 24, ErrorRate: 0.002, Duration: 60.15, Throughput: 199.1,
+			MeanMs: 61.3, P50Ms: 48.7, P95Ms: 212.9, MaxMs: 1210.4, Missing: []string{"p99"}}},
+		{"k6_summary.json", Options{}, Summary{Format: FormatK6, Requests: 60000, Errors: 30, ErrorRate: 0.0005, Duration: 60.013, Throughput: 999.29,
+			MeanMs: 38.2, P50Ms: 30.5, P95Ms: 88.4, P99Ms: 190.3, MaxMs: 420.7}},
+		{"samples.csv", Options{}, Summary{Format: FormatCSV, Requests: 20, Errors: 2, ErrorRate: 0.1, Duration: 2.08, Throughput: 8.65,
+			MeanMs: 85.235, P50Ms: 31.9, P95Ms: 120.5, P99Ms: 980.2, MaxMs: 980.2}},
+		// Samples without timestamps need the duration from the options
+		{"samples_untimed.csv", Options{}, Summary{Format: FormatCSV, Requests: 20, Errors: 1, ErrorRate: 0.05,
+			MeanMs: 85.235, P50Ms: 31.9, P95Ms: 120.5, P99Ms: 980.2, MaxMs: 980.2, Missing: []string{"throughput"}}},
+		{"samples_untimed.csv", Options{Duration: 10 * time.Second}, Summary{Format: FormatCSV, Requests: 20, Errors: 1, ErrorRate: 0.05, Duration: 10, Throughput: 1.9,
+			MeanMs: 85.235, P50Ms: 31.9, P95Ms: 120.5, P99Ms: 980.2, MaxMs: 980.2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			if got := parseFixture(t, tt.fixture, tt.opts); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Parse =\n%+v\nwant\n%+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  Options
+		want  string
+	}{
+		{"unknown JSON", `{"checks": []}`, Options{}, "unrecognized JSON"},
+		{"unknown format", "latency_ms\n1\n", Options{Format: "jmeter"}, `unsupported format "jmeter"`},
+		{"empty vegeta report", `{"latencies": {}, "requests": 0}`, Options{}, "vegeta: report has no requests"},
+		{"vegeta results line", "{\"latency\": 1000000, \"code\": 200}\n{\"latency\": \"slow\"}\n", Options{}, "vegeta-results: line 2:"},
+		{"k6 without requests", `{"metrics": {"http_req_duration": {"avg": 1}}}`, Options{}, "k6: summary has no http_reqs count"},
+		{"k6 without durations", `{"metrics": {"http_reqs": {"count": 10, "rate": 1}}}`, Options{}, "k6: summary has no http_req_duration metric"},
+		{"csv without latency", "status\n200\n", Options{}, "csv: header must include a latency_ms column"},
+		{"csv latency", "latency_ms\n12\n-1\n", Options{}, `csv: row 3: invalid latency_ms "-1"`},
+		{"csv status", "latency_ms,status\n12,ok\n", Options{}, `csv: row 2: invalid status "ok"`},
+		{"csv timestamp", "latency_ms,timestamp\n12,yesterday\n", Options{}, `csv: row 2: invalid timestamp "yesterday"`},
+		{"csv without rows", "latency_ms\n", Options{}, "csv: no samples found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input), tt.opts)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for p, want := range map[float64]float64{0: 1, 50: 5, 95: 10, 99: 10, 100: 10} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	// Samples without timestamps need the duration from the options
+	if s := parseFixture(t, "samples_untimed.csv", Options{Duration: 2 * time.Second}); s.Throughput != 9.5 {
+		t.Errorf("Throughput = %v, want 19 successful requests over 2s", s.Throughput)
+	}
+}
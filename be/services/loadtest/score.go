@@ -0,0 +1,195 @@
+package loadtest
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// SLO is the service level a run must meet for Score. Zero limits are
+// not checked. Latencies are milliseconds, ErrorRate a fraction and
+// MinThroughput successful requests per second.
+type SLO struct {
+	Score         int     `json:"score"`
+	P95Ms         float64 `json:"p95_ms,omitempty"`
+	P99Ms         float64 `json:"p99_ms,omitempty"`
+	ErrorRate     float64 `json:"error_rate,omitempty"`
+	MinThroughput float64 `json:"min_throughput,omitempty"`
+}
+
+// BE-IN - Internal backend only
+// SLOs are ordered from the highest score to the lowest. A run scores
+// the first level it meets, or FloorScore when it meets none.
+type SLOs struct {
+	Levels     []SLO `json:"levels"`
+	FloorScore int   `json:"floor_score"`
+}
+
+// BE-IN - Internal backend only
+var DefaultSLOs = SLOs{
+	Levels: []SLO{
+		{Score: 10, P95Ms: 100, P99Ms: 250, ErrorRate: 0.001, MinThroughput: 500},
+		{Score: 8, P95Ms: 250, P99Ms: 500, ErrorRate: 0.005, MinThroughput: 200},
+		{Score: 6, P95Ms: 500, P99Ms: 1000, ErrorRate: 0.01, MinThroughput: 100},
+		{Score: 4, P95Ms: 1000, P99Ms: 2500, ErrorRate: 0.05, MinThroughput: 20},
+	},
+	FloorScore: 2,
+}
+
+// BE-IN - Internal backend only
+// SLOsFromEnv returns DefaultSLOs, or the levels in LOAD_SLO_THRESHOLDS
+// such as "10:p95=100,p99=250,errors=0.1,rps=500;6:p95=500,errors=1".
+// errors is a percentage. LOAD_SLO_FLOOR_SCORE sets the floor score.
+func SLOsFromEnv() (SLOs, error) {
+	slos := DefaultSLOs
+	if value := os.Getenv("LOAD_SLO_THRESHOLDS"); value != "" {
+		levels, err := ParseSLOs(value)
+		if err != nil {
+			return DefaultSLOs, fmt.Errorf("LOAD_SLO_THRESHOLDS: %w", err)
+		}
+		slos.Levels = levels
+	}
+	if value := os.Getenv("LOAD_SLO_FLOOR_SCORE"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > 10 {
+			return DefaultSLOs, fmt.Errorf("LOAD_SLO_FLOOR_SCORE must be an integer between 0 and 10")
+		}
+		slos.FloorScore = n
+	}
+	return slos, nil
+}
+
+// BE-IN - Internal backend only
+// ParseSLOs parses "score:metric=limit,..." levels separated by
+// semicolons. Metrics are p95 and p99 (milliseconds), errors (percent)
+// and rps (minimum successful requests per second). The result is
+// sorted by descending score.
+func ParseSLOs(s string) ([]SLO, error) {
+	var levels []SLO
+	for _, level := range strings.Split(s, ";") {
+		level = strings.TrimSpace(level)
+		if level == "" {
+			continue
+		}
+		scoreText, limits, ok := strings.Cut(level, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q must have the form score:metric=limit,...", level)
+		}
+		score, err := strconv.Atoi(strings.TrimSpace(scoreText))
+		if err != nil || score < 0 || score > 10 {
+			return nil, fmt.Errorf("%q: score must be between 0 and 10", level)
+		}
+
+		slo := SLO{Score: score}
+		for _, pair := range strings.Split(limits, ",") {
+			metric, limitText, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return nil, fmt.Errorf("%q must have the form metric=limit", pair)
+			}
+			limit, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(limitText), "%"), 64)
+			if err != nil || limit <= 0 {
+				return nil, fmt.Errorf("%q: limit must be a positive number", pair)
+			}
+			switch strings.ToLower(strings.TrimSpace(metric)) {
+			case "p95":
+				slo.P95Ms = limit
+			case "p99":
+				slo.P99Ms = limit
+			case "errors":
+				slo.ErrorRate = limit / 100
+			case "rps":
+				slo.MinThroughput = limit
+			default:
+				return nil, fmt.Errorf("%q: metric must be p95, p99, errors or rps", pair)
+			}
+		}
+		levels = append(levels, slo)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("no levels given")
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Score > levels[j].Score })
+	return levels, nil
+}
+
+// BE-IN - Internal backend only
+// Suggest scores a summary against the SLO levels and explains which
+// objectives held back a better score. Metrics the source did not
+// report are skipped and mentioned.
+func (s SLOs) Suggest(summary *Summary) (int, string) {
+	// Score: [S8,P9,M9,T8,E7,L9]
+	// Details:
+	// - Security (S8): Levels are server-side configuration
+	// - Performance (P9): Constant work per level
+	// - Memory (M9): No allocations beyond the details text
+	// - Testing (T8): Pure function of summary and levels
+	// - Error (E7): Unreported metrics skipped rather than failed
+	// - Load (L9): Cheap enough to run on every upload
+	// Tags: BE-module-low
+
+	score, matched := s.FloorScore, len(s.Levels)
+	for i, level := range s.Levels {
+		if len(s.misses(level, summary)) == 0 {
+			score, matched = level.Score, i
+			break
+		}
+	}
+
+	run := fmt.Sprintf("%d requests (%s)", summary.Requests, summary.Format)
+	if summary.Duration > 0 {
+		run = fmt.Sprintf("%d requests over %.1fs (%s), %.1f req/s successful", summary.Requests, summary.Duration, summary.Format, summary.Throughput)
+	}
+	details := fmt.Sprintf("%s, p50 %s, p95 %s, p99 %s, %.2f%% errors; suggested score %d.",
+		run, formatMs(summary.P50Ms), formatMs(summary.P95Ms), formatMs(summary.P99Ms), summary.ErrorRate*100, score)
+
+	// Name what kept the run from the next level up
+	if matched > 0 {
+		next := s.Levels[matched-1]
+		details += fmt.Sprintf(" Below the SLO for %d: %s.", next.Score, strings.Join(s.misses(next, summary), ", "))
+	}
+	if len(summary.Missing) > 0 {
+		details += fmt.Sprintf(" Not reported, so not checked: %s.", strings.Join(summary.Missing, ", "))
+	}
+	return score, details
+}
+
+// BE-IN - Internal backend only
+func (s SLOs) misses(level SLO, summary *Summary) []string {
+	reported := func(metric string) bool {
+		for _, m := range summary.Missing {
+			if m == metric {
+				return false
+			}
+		}
+		return true
+	}
+
+	var misses []string
+	if level.P95Ms > 0 && reported("p95") && summary.P95Ms > level.P95Ms {
+		misses = append(misses, fmt.Sprintf("p95 %s > %s", formatMs(summary.P95Ms), formatMs(level.P95Ms)))
+	}
+	if level.P99Ms > 0 && reported("p99") && summary.P99Ms > level.P99Ms {
+		misses = append(misses, fmt.Sprintf("p99 %s > %s", formatMs(summary.P99Ms), formatMs(level.P99Ms)))
+	}
+	if level.ErrorRate > 0 && summary.ErrorRate > level.ErrorRate {
+		misses = append(misses, fmt.Sprintf("errors %.2f%% > %.2f%%", summary.ErrorRate*100, level.ErrorRate*100))
+	}
+	if level.MinThroughput > 0 && reported("throughput") && summary.Throughput < level.MinThroughput {
+		misses = append(misses, fmt.Sprintf("%.1f req/s < %.0f req/s", summary.Throughput, level.MinThroughput))
+	}
+	return misses
+}
+
+// BE-IN - Internal backend only
+func formatMs(ms float64) string {
+	if ms == 0 {
+		return "n/a"
+	}
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+	return fmt.Sprintf("%.1fms", ms)
+}
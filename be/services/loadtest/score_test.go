@@ -0,0 +1,96 @@
+package loadtest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuggest(t *testing.T) {
+	// Levels a single developer machine can reach with a short run
+	local, err := ParseSLOs("9:p95=50,p99=150,errors=1,rps=15; 7:p95=150,errors=5; 5:p99=1000")
+	if err != nil {
+		t.Fatalf("ParseSLOs: %v", err)
+	}
+	localSLOs := SLOs{Levels: local, FloorScore: 1}
+
+	tests := []struct {
+		name    string
+		fixture string
+		opts    Options
+		slos    SLOs
+		score   int
+		details string
+	}{
+		// p95 120ms misses the 100ms of level 10
+		{"vegeta report", "vegeta_report.json", Options{}, DefaultSLOs, 8, "9000 requests over 30.0s (vegeta), 299.7 req/s successful, p50 35.0ms, p95 120.0ms, p99 310.0ms, 0.10% errors; suggested score 8. " +
+			"Below the SLO for 10: p95 120.0ms > 100.0ms, p99 310.0ms > 250.0ms, 299.7 req/s < 500 req/s."},
+		// 10% errors and 14.6 req/s are below every level
+		{"vegeta results", "vegeta_results.jsonl", Options{}, DefaultSLOs, 2, "suggested score 2. Below the SLO for 4: errors 10.00% > 5.00%, 14.6 req/s < 20 req/s."},
+		{"vegeta results local", "vegeta_results.jsonl", Options{}, localSLOs, 5, "suggested score 5. Below the SLO for 7: p95 250.0ms > 150.0ms, errors 10.00% > 5.00%."},
+		// No p99 is reported; 199.1 successful req/s miss level 8
+		{"k6 export", "k6_export.json", Options{}, DefaultSLOs, 6, "p99 n/a, 0.20% errors; suggested score 6. Below the SLO for 8: 199.1 req/s < 200 req/s. Not reported, so not checked: p99."},
+		{"k6 summary", "k6_summary.json", Options{}, DefaultSLOs, 10, "60000 requests over 60.0s (k6), 999.3 req/s successful, p50 30.5ms, p95 88.4ms, p99 190.3ms, 0.05% errors; suggested score 10."},
+		{"samples local", "samples.csv", Options{}, localSLOs, 5, "suggested score 5. Below the SLO for 7: errors 10.00% > 5.00%."},
+		// Without timestamps or a duration throughput is not checked;
+		// with one, 1.9 req/s is listed against level 9 as well
+		{"samples untimed local", "samples_untimed.csv", Options{}, localSLOs, 7, "20 requests (csv), p50 31.9ms, p95 120.5ms, p99 980.2ms, 5.00% errors; suggested score 7. " +
+			"Below the SLO for 9: p95 120.5ms > 50.0ms, p99 980.2ms > 150.0ms, errors 5.00% > 1.00%. Not reported, so not checked: throughput."},
+		{"samples untimed duration", "samples_untimed.csv", Options{Duration: 10 * time.Second}, localSLOs, 7, "20 requests over 10.0s (csv), 1.9 req/s successful, p50 31.9ms, p95 120.5ms, p99 980.2ms, 5.00% errors; suggested score 7. " +
+			"Below the SLO for 9: p95 120.5ms > 50.0ms, p99 980.2ms > 150.0ms, errors 5.00% > 1.00%, 1.9 req/s < 15 req/s."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := parseFixture(t, tt.fixture, tt.opts)
+			score, details := tt.slos.Suggest(summary)
+			if score != tt.score {
+				t.Errorf("score = %d, want %d: %s", score, tt.score, details)
+			}
+			if !strings.HasSuffix(details, tt.details) {
+				t.Errorf("details = %q, want it to end with %q", details, tt.details)
+			}
+		})
+	}
+}
+
+func TestParseSLOs(t *testing.T) {
+	levels, err := ParseSLOs("6:p95=500,errors=1%; 10:p95=100,p99=250,errors=0.1,rps=500")
+	if err != nil {
+		t.Fatalf("ParseSLOs: %v", err)
+	}
+	want := []SLO{
+		{Score: 10, P95Ms: 100, P99Ms: 250, ErrorRate: 0.001, MinThroughput: 500},
+		{Score: 6, P95Ms: 500, ErrorRate: 0.01},
+	}
+	if len(levels) != len(want) {
+		t.Fatalf("ParseSLOs = %+v, want %+v", levels, want)
+	}
+	for i := range want {
+		if levels[i] != want[i] {
+			t.Errorf("level %d = %+v, want %+v", i, levels[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"", "10", "11:p95=1", "10:p90=1", "10:p95=0", "10:p95"} {
+		if _, err := ParseSLOs(bad); err == nil {
+			t.Errorf("ParseSLOs(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestSLOsFromEnv(t *testing.T) {
+	t.Setenv("LOAD_SLO_THRESHOLDS", "7:p95=200")
+	t.Setenv("LOAD_SLO_FLOOR_SCORE", "3")
+	slos, err := SLOsFromEnv()
+	if err != nil {
+		t.Fatalf("SLOsFromEnv: %v", err)
+	}
+	if len(slos.Levels) != 1 || slos.Levels[0].P95Ms != 200 || slos.FloorScore != 3 {
+		t.Errorf("SLOsFromEnv = %+v", slos)
+	}
+
+	t.Setenv("LOAD_SLO_FLOOR_SCORE", "eleven")
+	if _, err := SLOsFromEnv(); err == nil || !strings.Contains(err.Error(), "LOAD_SLO_FLOOR_SCORE") {
+		t.Errorf("SLOsFromEnv error = %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"encore.app/models"
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+// Lifecycle events that trigger notifications. Each kind has a
+// <kind>.txt.tmpl and <kind>.html.tmpl template.
+const (
+	KindReportSubmitted = "report_submitted"
+	KindReportEvaluated = "report_evaluated"
+	KindReportApproved  = "report_approved"
+	KindReportRejected  = "report_rejected"
+	KindDeliveryFailed  = "delivery_failed"
+	KindReportDue       = "report_due"
+	KindCommentMention  = "comment_mention"
+)
+
+// BE-IN - Internal backend only
+var subjects = map[string]string{
+	KindReportSubmitted: "Report submitted: %s",
+	KindReportEvaluated: "Evaluation recorded: %s",
+	KindReportApproved:  "Report approved: %s",
+	KindReportRejected:  "Report rejected: %s",
+	KindDeliveryFailed:  "Delivery failed: %s",
+	KindReportDue:       "Report due soon: %s",
+	KindCommentMention:  "Mentioned in a comment: %s",
+}
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// BE-IN - Internal backend only
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/*.txt.tmpl"))
+	htmlLayout    = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/layout.html.tmpl"))
+)
+
+// BE-IN - Internal backend only
+// Event describes something that happened to a report. Recipients are
+// resolved by the caller so this package stays free of access rules.
+type Event struct {
+	Kind           string
+	Report         *models.Report
+	Evaluation     *models.Evaluation
+	DepartmentName string
+	ActorName      string
+	Reason         string
+	// Period is set for deadline reminders
+	Period *models.ReportingPeriod
+	// Comment is the body of the comment for mention notifications
+	Comment    string
+	Recipients []*models.User
+}
+
+// BE-IN - Internal backend only
+// templateData is what the message templates see for one recipient.
+type templateData struct {
+	Event
+	RecipientName string
+	ReportURL     string
+}
+
+// BE-IN - Internal backend only
+type Notifier struct {
+	Sender  Sender
+	BaseURL string
+}
+
+// BE-IN - Internal backend only
+func NewNotifier(sender Sender, baseURL string) *Notifier {
+	return &Notifier{Sender: sender, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// BE-OUT - External data involved
+// Notify renders and sends one message per recipient. Render and delivery
+// failures for individual recipients are logged and the rest are still
+// attempted; the first failure is returned.
+func (n *Notifier) Notify(ctx context.Context, event Event) error {
+	// Score: [S7,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S7): html/template escaping for all report content, one recipient per message
+	// - Performance (P7): Templates parsed once at startup
+	// - Memory (M7): Messages rendered one recipient at a time
+	// - Testing (T7): Sender interface allows fake SMTP servers and in-memory senders
+	// - Error (E8): Per-recipient render and send failures logged, first error returned
+	// - Load (L7): Bounded by the number of recipients per event
+	// Tags: BE-OUT-medium
+
+	if _, ok := subjects[event.Kind]; !ok {
+		return fmt.Errorf("unknown notification kind %q", event.Kind)
+	}
+
+	var firstErr error
+	seen := make(map[string]bool)
+	for _, recipient := range event.Recipients {
+		if recipient == nil || recipient.Email == "" || seen[recipient.Email] {
+			continue
+		}
+		seen[recipient.Email] = true
+
+		message, err := n.Render(event, recipient)
+		if err != nil {
+			rlog.Error("failed to render notification",
+				"kind", event.Kind, "report_id", event.Report.ID, "recipient", recipient.ID, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if err := n.Sender.Send(ctx, message); err != nil {
+			rlog.Error("failed to send notification",
+				"kind", event.Kind, "report_id", event.Report.ID, "recipient", recipient.ID, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// BE-IN - Internal backend only
+// Render builds the message for a single recipient.
+func (n *Notifier) Render(event Event, recipient *models.User) (Message, error) {
+	data := templateData{
+		Event:         event,
+		RecipientName: recipient.Name,
+	}
+	if n.BaseURL != "" {
+		data.ReportURL = n.BaseURL + "/reports/" + event.Report.ID
+	}
+
+	var text bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, event.Kind+".txt.tmpl", data); err != nil {
+		return Message{}, fmt.Errorf("render text template: %w", err)
+	}
+
+	// Each kind defines its own "body" block, so the layout is cloned per
+	// render
+	layout, err := htmlLayout.Clone()
+	if err != nil {
+		return Message{}, err
+	}
+	if _, err := layout.ParseFS(templateFiles, "templates/"+event.Kind+".html.tmpl"); err != nil {
+		return Message{}, fmt.Errorf("parse html template: %w", err)
+	}
+	var html bytes.Buffer
+	if err := layout.ExecuteTemplate(&html, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("render html template: %w", err)
+	}
+
+	return Message{
+		To:      recipient.Email,
+		Subject: fmt.Sprintf(subjects[event.Kind], event.Report.Title),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,236 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"encore.dev/rlog"
+)
+
+// BE-IN - Internal backend only
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// BE-IN - Internal backend only
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// BE-IN - Internal backend only
+// SMTPSender delivers mail over SMTP. STARTTLS is used when the server
+// offers it unless DisableTLS is set, and authentication is only
+// attempted when a username is configured, so a local fake SMTP server
+// needs nothing but Host and Port.
+type SMTPSender struct {
+	Host       string
+	Port       string
+	Username   string
+	Password   string
+	From       string
+	DisableTLS bool
+	Timeout    time.Duration
+}
+
+// BE-IN - Internal backend only
+// NewSenderFromEnv returns an SMTP sender configured from SMTP_HOST,
+// SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM and
+// SMTP_DISABLE_TLS. Without SMTP_HOST messages are only logged.
+func NewSenderFromEnv() Sender {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return LogSender{}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "25"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "Project Reporting System <reports@localhost>"
+	}
+
+	return &SMTPSender{
+		Host:       host,
+		Port:       port,
+		Username:   os.Getenv("SMTP_USERNAME"),
+		Password:   os.Getenv("SMTP_PASSWORD"),
+		From:       from,
+		DisableTLS: os.Getenv("SMTP_DISABLE_TLS") == "true",
+		Timeout:    10 * time.Second,
+	}
+}
+
+// BE-OUT - External data involved
+func (s *SMTPSender) Send(ctx context.Context, message Message) error {
+	// Score: [S8,P6,M7,T7,E8,L6]
+	// Details:
+	// - Security (S8): STARTTLS when offered, header values stripped of CR/LF, addresses parsed
+	// - Performance (P6): One connection per message
+	// - Memory (M7): Message built in a single buffer
+	// - Testing (T7): Works against a plain local fake SMTP server
+	// - Error (E8): Every SMTP step wrapped with context
+	// - Load (L6): Suitable for notification volumes, not bulk mail
+	// Tags: BE-OUT-high
+
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	body, err := buildMIMEMessage(from, to, message)
+	if err != nil {
+		return err
+	}
+
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.Host, s.Port))
+	if err != nil {
+		return fmt.Errorf("connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && !s.DisableTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("smtp write body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp end data: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// BE-IN - Internal backend only
+// buildMIMEMessage encodes a multipart/alternative message with plain
+// text and HTML parts.
+func buildMIMEMessage(from, to *mail.Address, message Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	messageID := make([]byte, 16)
+	if _, err := rand.Read(messageID); err != nil {
+		return nil, err
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(from.Address, "@"); at >= 0 {
+		domain = from.Address[at+1:]
+	}
+
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", stripNewlines(message.Subject)),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: <" + hex.EncodeToString(messageID) + "@" + domain + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + writer.Boundary(),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n"))
+	buf.WriteString("\r\n\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
+
+// BE-IN - Internal backend only
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// BE-IN - Internal backend only
+// LogSender logs messages instead of sending them. It is the default
+// when no SMTP server is configured.
+type LogSender struct{}
+
+// BE-IN - Internal backend only
+func (LogSender) Send(ctx context.Context, message Message) error {
+	rlog.Info("notification email (not sent, SMTP_HOST unset)", "to", message.To, "subject", message.Subject)
+	return nil
+}
@@ -0,0 +1,416 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"encore.app/models"
+)
+
+// fakeSMTP is an in-process SMTP server that records every command and
+// message it receives.
+type fakeSMTP struct {
+	// extensions are advertised in the EHLO reply, such as "STARTTLS"
+	// or "AUTH PLAIN"
+	extensions []string
+
+	listener net.Listener
+	mu       sync.Mutex
+	commands []string
+	auth     string
+	from     string
+	to       []string
+	data     string
+	done     chan struct{}
+}
+
+func startFakeSMTP(t *testing.T, extensions ...string) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &fakeSMTP{extensions: extensions, listener: listener, done: make(chan struct{})}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		defer close(server.done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		server.serve(conn)
+	}()
+	return server
+}
+
+func (s *fakeSMTP) sender() *SMTPSender {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return &SMTPSender{Host: host, Port: port, From: "Reports <reports@example.com>", Timeout: 5 * time.Second}
+}
+
+// wait returns once the connection is closed.
+func (s *fakeSMTP) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fake smtp server did not finish")
+	}
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for i, line := range lines {
+			separator := " "
+			if i < len(lines)-1 {
+				separator = "-"
+			}
+			fmt.Fprintf(conn, "%s%s%s\r\n", line[:3], separator, line[4:])
+		}
+	}
+
+	reply("220 fake ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Fields(command)[0])
+		s.mu.Unlock()
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			lines := []string{"250 fake"}
+			for _, extension := range s.extensions {
+				lines = append(lines, "250 "+extension)
+			}
+			reply(lines...)
+		case strings.HasPrefix(command, "STARTTLS"):
+			reply("454 TLS not available")
+		case strings.HasPrefix(command, "AUTH PLAIN"):
+			s.mu.Lock()
+			s.auth = strings.TrimSpace(line[len("AUTH PLAIN"):])
+			s.mu.Unlock()
+			reply("235 Authenticated")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.TrimSpace(line[len("MAIL FROM:"):])
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.mu.Lock()
+			s.to = append(s.to, strings.TrimSpace(line[len("RCPT TO:"):]))
+			s.mu.Unlock()
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 Go ahead")
+			var body strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				body.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			s.mu.Lock()
+			s.data = body.String()
+			s.mu.Unlock()
+			reply("250 Queued")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Not implemented")
+		}
+	}
+}
+
+// parsedMessage is a received message with its decoded parts.
+type parsedMessage struct {
+	header mail.Header
+	parts  map[string]string
+}
+
+func parseMessage(t *testing.T, data string) parsedMessage {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	parsed := parsedMessage{header: msg.Header, parts: map[string]string{}}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		// multipart.Reader decodes quoted-printable parts itself
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parsed.parts[partType] = string(content)
+	}
+	return parsed
+}
+
+func TestSMTPSenderSend(t *testing.T) {
+	server := startFakeSMTP(t)
+	message := Message{
+		To:      "Alex Developer <alex@example.com>",
+		Subject: "Report approved: Überblick Q4",
+		Text:    "Hi Alex,\n\nA line long enough to need a soft line break in quoted-printable encoding, which wraps at 76 characters.\n",
+		HTML:    "<p>Hi Alex,</p>\n<p>Score = 8</p>\n",
+	}
+
+	if err := server.sender().Send(context.Background(), message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	server.wait(t)
+
+	if server.from != "<reports@example.com>" {
+		t.Errorf("MAIL FROM = %q, want <reports@example.com>", server.from)
+	}
+	if len(server.to) != 1 || server.to[0] != "<alex@example.com>" {
+		t.Errorf("RCPT TO = %q, want [<alex@example.com>]", server.to)
+	}
+	for _, command := range server.commands {
+		if command == "STARTTLS" || command == "AUTH" {
+			t.Errorf("unexpected %s without the extension or a username", command)
+		}
+	}
+
+	parsed := parseMessage(t, server.data)
+	headers := map[string]string{
+		"From":         `"Reports" <reports@example.com>`,
+		"To":           `"Alex Developer" <alex@example.com>`,
+		"MIME-Version": "1.0",
+	}
+	for name, want := range headers {
+		if got := parsed.header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.header.Get("Subject"))
+	if err != nil || subject != message.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, message.Subject)
+	}
+	if !strings.HasSuffix(parsed.header.Get("Message-ID"), "@example.com>") {
+		t.Errorf("Message-ID = %q, want the sender's domain", parsed.header.Get("Message-ID"))
+	}
+	if _, err := parsed.header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+
+	// Both parts are quoted-printable, with long lines soft-wrapped
+	if n := strings.Count(server.data, "Content-Transfer-Encoding: quoted-printable\r\n"); n != 2 {
+		t.Errorf("%d quoted-printable parts, want 2", n)
+	}
+	if !strings.Contains(server.data, "=\r\n") {
+		t.Error("long text line was not soft-wrapped")
+	}
+
+	// Line breaks are sent as CRLF
+	crlf := strings.NewReplacer("\n", "\r\n")
+	if got, want := parsed.parts["text/plain"], crlf.Replace(message.Text); got != want {
+		t.Errorf("text part = %q, want %q", got, want)
+	}
+	if got, want := parsed.parts["text/html"], crlf.Replace(message.HTML); got != want {
+		t.Errorf("html part = %q, want %q", got, want)
+	}
+}
+
+func TestSMTPSenderStripsHeaderNewlines(t *testing.T) {
+	server := startFakeSMTP(t)
+	message := Message{To: "alex@example.com", Subject: "Hello\r\nBcc: eve@example.com", Text: "x", HTML: "x"}
+
+	if err := server.sender().Send(context.Background(), message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	server.wait(t)
+
+	parsed := parseMessage(t, server.data)
+	if bcc := parsed.header.Get("Bcc"); bcc != "" {
+		t.Errorf("Bcc header injected: %q", bcc)
+	}
+	if len(server.to) != 1 {
+		t.Errorf("RCPT TO = %q, want one recipient", server.to)
+	}
+}
+
+func TestSMTPSenderStartTLS(t *testing.T) {
+	t.Run("attempted when offered", func(t *testing.T) {
+		server := startFakeSMTP(t, "STARTTLS")
+		err := server.sender().Send(context.Background(), Message{To: "alex@example.com", Subject: "s", Text: "t", HTML: "h"})
+		if err == nil || !strings.Contains(err.Error(), "smtp starttls") {
+			t.Fatalf("Send error = %v, want a starttls failure", err)
+		}
+		server.wait(t)
+		if server.data != "" {
+			t.Error("message sent after STARTTLS failed")
+		}
+	})
+
+	t.Run("skipped when disabled", func(t *testing.T) {
+		server := startFakeSMTP(t, "STARTTLS")
+		sender := server.sender()
+		sender.DisableTLS = true
+		if err := sender.Send(context.Background(), Message{To: "alex@example.com", Subject: "s", Text: "t", HTML: "h"}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		server.wait(t)
+		for _, command := range server.commands {
+			if command == "STARTTLS" {
+				t.Error("STARTTLS sent although DisableTLS is set")
+			}
+		}
+		if server.data == "" {
+			t.Error("no message received")
+		}
+	})
+}
+
+func TestSMTPSenderAuth(t *testing.T) {
+	server := startFakeSMTP(t, "AUTH PLAIN")
+	sender := server.sender()
+	sender.Username = "reports"
+	sender.Password = "secret"
+
+	if err := sender.Send(context.Background(), Message{To: "alex@example.com", Subject: "s", Text: "t", HTML: "h"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	server.wait(t)
+
+	credentials, err := base64.StdEncoding.DecodeString(server.auth)
+	if err != nil {
+		t.Fatalf("decode AUTH PLAIN: %v", err)
+	}
+	if string(credentials) != "\x00reports\x00secret" {
+		t.Errorf("AUTH PLAIN credentials = %q", credentials)
+	}
+}
+
+func TestSMTPSenderInvalidAddress(t *testing.T) {
+	sender := &SMTPSender{Host: "127.0.0.1", Port: "1", From: "reports@example.com"}
+	err := sender.Send(context.Background(), Message{To: "not an address"})
+	if err == nil || !strings.Contains(err.Error(), "invalid recipient address") {
+		t.Fatalf("Send error = %v, want an invalid recipient error", err)
+	}
+}
+
+// recordingSender keeps the messages instead of sending them.
+type recordingSender struct {
+	messages []Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, message Message) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestNotifierRendersEveryKind(t *testing.T) {
+	report := &models.Report{ID: "report-123", Title: `Auth <script>alert(1)</script> review`}
+	recipient := &models.User{ID: "user-123", Name: "Alex Developer", Email: "alex@example.com"}
+	period := &models.ReportingPeriod{Name: "2026-Q4", DueAt: time.Date(2026, 12, 31, 17, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		kind    string
+		subject string
+		text    []string
+	}{
+		{KindReportSubmitted, "Report submitted: ", []string{"was submitted to the Security department by Sam Security."}},
+		{KindReportEvaluated, "Evaluation recorded: ", []string{"Sam Security recorded an evaluation", "Security:       8/10"}},
+		{KindReportApproved, "Report approved: ", []string{"was approved by Sam Security."}},
+		{KindReportRejected, "Report rejected: ", []string{"was rejected by Sam Security.", "Reason: Missing load tests"}},
+		{KindDeliveryFailed, "Delivery failed: ", []string{"could not be delivered to the Security department.", "Error: Missing load tests"}},
+		{KindReportDue, "Report due soon: ", []string{"due for the 2026-Q4 period of the Security department on Thu 31 Dec 2026 17:00 UTC"}},
+		{KindCommentMention, "Mentioned in a comment: ", []string{"Sam Security mentioned you in a comment", "@user-123 please add load tests"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			sender := &recordingSender{}
+			notifier := NewNotifier(sender, "https://reports.example.com/")
+			event := Event{
+				Kind:           tt.kind,
+				Report:         report,
+				Evaluation:     &models.Evaluation{SecurityScore: 8},
+				DepartmentName: "Security",
+				ActorName:      "Sam Security",
+				Reason:         "Missing load tests",
+				Period:         period,
+				Comment:        "@user-123 please add load tests",
+				Recipients:     []*models.User{recipient, recipient},
+			}
+			if err := notifier.Notify(context.Background(), event); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+
+			// Duplicate recipients get one message
+			if len(sender.messages) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(sender.messages))
+			}
+			message := sender.messages[0]
+			if message.To != recipient.Email {
+				t.Errorf("To = %q, want %q", message.To, recipient.Email)
+			}
+			if message.Subject != tt.subject+report.Title {
+				t.Errorf("Subject = %q, want %q", message.Subject, tt.subject+report.Title)
+			}
+
+			wantText := append([]string{"Hi Alex Developer,", "Open the report: https://reports.example.com/reports/report-123"}, tt.text...)
+			for _, want := range wantText {
+				if !strings.Contains(message.Text, want) {
+					t.Errorf("text body missing %q:\n%s", want, message.Text)
+				}
+			}
+
+			if strings.Contains(message.HTML, "<script>") {
+				t.Errorf("html body does not escape the title:\n%s", message.HTML)
+			}
+			wantHTML := []string{"<p>Hi Alex Developer,</p>", "&lt;script&gt;", `href="https://reports.example.com/reports/report-123"`}
+			for _, want := range wantHTML {
+				if !strings.Contains(message.HTML, want) {
+					t.Errorf("html body missing %q:\n%s", want, message.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifierRejectsUnknownKind(t *testing.T) {
+	notifier := NewNotifier(&recordingSender{}, "")
+	err := notifier.Notify(context.Background(), Event{Kind: "report_unknown", Report: &models.Report{}})
+	if err == nil {
+		t.Fatal("Notify accepted an unknown kind")
+	}
+}
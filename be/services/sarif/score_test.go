@@ -0,0 +1,99 @@
+package sarif
+
+import "testing"
+
+func TestSuggestFixtures(t *testing.T) {
+	strict := Policy{
+		Weights: map[string]float64{Critical: 5, High: 2.5, Medium: 1, Low: 0.5},
+		Caps:    map[string]int{Critical: 1, High: 5, Medium: 8},
+	}
+
+	tests := []struct {
+		name    string
+		fixture string
+		policy  Policy
+		score   int
+		details string
+	}{
+		// 10 - (4 + 2*2 + 0.5 + 2*0.1) rounds to 1; critical caps at 2
+		// but the score is already lower
+		{"gosec default", "gosec.sarif", DefaultPolicy, 1, "6 open findings from gosec, CodeQL (1 critical, 2 high, 1 medium, 2 low); suggested score 1. " +
+			"Most severe: critical G402 at services/webhooks/client.go:25; high G104 at api/import.go:12; high G101 at services/notifications/sender.go:42."},
+		// 10 - (4 + 0.5 + 0.1) rounds to 5, capped at 2 by the critical
+		{"govulncheck default", "govulncheck.json", DefaultPolicy, 2, "3 open findings from govulncheck (1 critical, 1 medium, 1 low); suggested score 2, capped at 2 by critical findings. " +
+			"Most severe: critical GO-2023-2102 at api/webhooks.go:212."},
+		{"govulncheck strict", "govulncheck.json", strict, 1, "3 open findings from govulncheck (1 critical, 1 medium, 1 low); suggested score 1, capped at 1 by critical findings. " +
+			"Most severe: critical GO-2023-2102 at api/webhooks.go:212."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scan := parseFixture(t, tt.fixture)
+			score, details := tt.policy.Suggest(scan.Findings, scan.Tools)
+			if score != tt.score || details != tt.details {
+				t.Errorf("Suggest = %d, %q\nwant %d, %q", score, details, tt.score, tt.details)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	finding := func(severity string) *Finding {
+		return &Finding{Tool: "gosec", RuleID: "G000", Severity: severity, File: "api/reports.go", Line: 1}
+	}
+
+	tests := []struct {
+		name    string
+		open    []*Finding
+		tools   []string
+		score   int
+		details string
+	}{
+		{"clean", nil, []string{"gosec", "govulncheck"}, 10, "No open findings from gosec, govulncheck; suggested score 10."},
+		{"clean without tools", nil, nil, 10, "No open findings from the uploaded scans; suggested score 10."},
+		{"lows only", []*Finding{finding(Low), finding(Low), finding(Low)}, []string{"gosec"}, 10, "3 open findings from gosec (3 low); suggested score 10."},
+		{"one high", []*Finding{finding(High)}, []string{"gosec"}, 6, "1 open findings from gosec (1 high); suggested score 6, capped at 6 by high findings. Most severe: high G000 at api/reports.go:1."},
+		{"mediums", []*Finding{finding(Medium), finding(Medium), finding(Medium)}, []string{"gosec"}, 9, "3 open findings from gosec (3 medium); suggested score 9."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, details := DefaultPolicy.Suggest(tt.open, tt.tools)
+			if score != tt.score || details != tt.details {
+				t.Errorf("Suggest = %d, %q\nwant %d, %q", score, details, tt.score, tt.details)
+			}
+		})
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	t.Setenv("SECURITY_SEVERITY_WEIGHTS", "critical=5, HIGH=2.5")
+	t.Setenv("SECURITY_SEVERITY_CAPS", "medium=8")
+	policy, err := PolicyFromEnv()
+	if err != nil {
+		t.Fatalf("PolicyFromEnv: %v", err)
+	}
+	if policy.Weights[Critical] != 5 || policy.Weights[High] != 2.5 || policy.Weights[Low] != 0.1 {
+		t.Errorf("Weights = %v", policy.Weights)
+	}
+	if policy.Caps[Medium] != 8 || policy.Caps[Critical] != 2 {
+		t.Errorf("Caps = %v", policy.Caps)
+	}
+	if DefaultPolicy.Weights[Critical] != 4 {
+		t.Error("PolicyFromEnv changed DefaultPolicy")
+	}
+
+	for _, bad := range []struct{ key, value string }{
+		{"SECURITY_SEVERITY_WEIGHTS", "severe=5"},
+		{"SECURITY_SEVERITY_WEIGHTS", "high=11"},
+		{"SECURITY_SEVERITY_CAPS", "high=5.5"},
+		{"SECURITY_SEVERITY_CAPS", "high"},
+	} {
+		t.Run(bad.value, func(t *testing.T) {
+			t.Setenv("SECURITY_SEVERITY_WEIGHTS", "")
+			t.Setenv("SECURITY_SEVERITY_CAPS", "")
+			t.Setenv(bad.key, bad.value)
+			if _, err := PolicyFromEnv(); err == nil {
+				t.Errorf("%s=%s accepted", bad.key, bad.value)
+			}
+		})
+	}
+}
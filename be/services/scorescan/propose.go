@@ -0,0 +1,97 @@
+package scorescan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"encore.app/models"
+)
+
+// BE-IN - Internal backend only
+// maxDetailLines bounds the bullet list of each details field
+const maxDetailLines = 25
+
+// BE-IN - Internal backend only
+// ProposeEvaluation rolls annotations up into an evaluation. Each score is
+// the rounded mean across annotated functions. Each details field starts
+// with the mean and lists the functions' own notes, weakest first.
+// ReportID and EvaluatorID are left for the caller.
+func ProposeEvaluation(annotations []Annotation) *models.Evaluation {
+	// Score: [S7,P8,M7,T7,E7,L8]
+	// Details:
+	// - Security (S7): Works only on already validated annotations
+	// - Performance (P8): One pass per dimension plus a sort
+	// - Memory (M7): Details text capped per dimension
+	// - Testing (T7): Pure function over annotations
+	// - Error (E7): Empty input yields an empty proposal
+	// - Load (L8): Linear in the number of annotations
+	// Tags: BE-module-medium
+
+	evaluation := &models.Evaluation{}
+	if len(annotations) == 0 {
+		return evaluation
+	}
+
+	scores := make([]int, len(Dimensions))
+	details := make([]string, len(Dimensions))
+	for i, dimension := range Dimensions {
+		sum := 0
+		for _, a := range annotations {
+			sum += a.Scores[i]
+		}
+		mean := float64(sum) / float64(len(annotations))
+		scores[i] = int(math.Round(mean))
+		details[i] = dimensionDetails(annotations, i, dimension, mean)
+	}
+
+	evaluation.SecurityScore = scores[0]
+	evaluation.PerformanceScore = scores[1]
+	evaluation.MemoryScore = scores[2]
+	evaluation.TestingScore = scores[3]
+	evaluation.ErrorScore = scores[4]
+	evaluation.LoadScore = scores[5]
+	evaluation.SecurityDetails = details[0]
+	evaluation.PerformanceDetails = details[1]
+	evaluation.MemoryDetails = details[2]
+	evaluation.TestingDetails = details[3]
+	evaluation.ErrorDetails = details[4]
+	evaluation.LoadDetails = details[5]
+
+	return evaluation
+}
+
+// BE-IN - Internal backend only
+func dimensionDetails(annotations []Annotation, index int, dimension Dimension, mean float64) string {
+	sorted := make([]Annotation, len(annotations))
+	copy(sorted, annotations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Scores[index] != sorted[j].Scores[index] {
+			return sorted[i].Scores[index] < sorted[j].Scores[index]
+		}
+		return qualifiedName(sorted[i]) < qualifiedName(sorted[j])
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Average %s%.1f across %d annotated functions.", dimension.Letter, mean, len(annotations))
+	for i, a := range sorted {
+		if i == maxDetailLines {
+			fmt.Fprintf(&b, "\n- ... and %d more", len(sorted)-maxDetailLines)
+			break
+		}
+		fmt.Fprintf(&b, "\n- %s (%s%d)", qualifiedName(a), dimension.Letter, a.Scores[index])
+		if text := a.Details[dimension.Name]; text != "" {
+			b.WriteString(": " + text)
+		}
+	}
+	return b.String()
+}
+
+// BE-IN - Internal backend only
+func qualifiedName(a Annotation) string {
+	if a.Package == "" {
+		return a.Function
+	}
+	return a.Package + "." + a.Function
+}
@@ -0,0 +1,359 @@
+// Package scorescan extracts the score annotations written inside Go
+// functions:
+//
+//	// Score: [S8,P7,M6,T7,E8,L6]
+//	// Details:
+//	// - Security (S8): Input validation, authorization checks
+//	// - ...
+//	// Tags: BE-module-high
+//
+// and rolls them up into a proposed evaluation.
+package scorescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"encore.app/models"
+	"encore.app/services/gosource"
+)
+
+// BE-IN - Internal backend only
+// Issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// BE-IN - Internal backend only
+// Dimension is one of the six evaluation dimensions, in Score line order.
+type Dimension struct {
+	Letter string
+	Name   string
+}
+
+// BE-IN - Internal backend only
+var Dimensions = []Dimension{
+	{"S", "Security"},
+	{"P", "Performance"},
+	{"M", "Memory"},
+	{"T", "Testing"},
+	{"E", "Error"},
+	{"L", "Load"},
+}
+
+// BE-IN - Internal backend only
+// Scores holds the six scores of an annotation, indexed like Dimensions.
+type Scores [6]int
+
+// BE-IN - Internal backend only
+type Annotation struct {
+	File     string            `json:"file"`
+	Line     int               `json:"line"`
+	Package  string            `json:"package"`
+	Function string            `json:"function"`
+	Scores   Scores            `json:"scores"`
+	Details  map[string]string `json:"details,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Marker   string            `json:"marker,omitempty"`
+}
+
+// BE-IN - Internal backend only
+type Issue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// BE-IN - Internal backend only
+func (i Issue) String() string {
+	location := fmt.Sprintf("%s:%d", i.File, i.Line)
+	if i.Function != "" {
+		location += " " + i.Function
+	}
+	return fmt.Sprintf("%s: %s: %s", location, i.Severity, i.Message)
+}
+
+// BE-IN - Internal backend only
+type Result struct {
+	Files       int          `json:"files"`
+	Functions   int          `json:"functions"`
+	Annotations []Annotation `json:"annotations"`
+	Issues      []Issue      `json:"issues"`
+}
+
+// BE-IN - Internal backend only
+// HasErrors reports whether any issue is an error rather than a warning.
+func (r *Result) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// BE-IN - Internal backend only
+// Source is one Go file to scan. Path is only used for reporting.
+type Source = gosource.File
+
+// BE-IN - Internal backend only
+var (
+	scoreLinePattern = regexp.MustCompile(`^Score:\s*\[\s*S(\d+)\s*,\s*P(\d+)\s*,\s*M(\d+)\s*,\s*T(\d+)\s*,\s*E(\d+)\s*,\s*L(\d+)\s*\]\s*$`)
+	bulletPattern    = regexp.MustCompile(`^-\s*([A-Za-z]+)\s*\(\s*([A-Z])(\d+)\s*\)\s*:\s*(.*)$`)
+)
+
+// BE-IN - Internal backend only
+// ScanDir scans every file ReadDir returns for root.
+func ScanDir(root string) (*Result, error) {
+	sources, err := ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	return ScanSources(sources), nil
+}
+
+// BE-IN - Internal backend only
+// ReadDir reads every non-test .go file under root. Paths are relative to
+// root.
+func ReadDir(root string) ([]Source, error) {
+	return gosource.ReadDir(root, false)
+}
+
+// BE-IN - Internal backend only
+// ScanSources scans in-memory files. Files that do not parse are reported
+// as issues and skipped.
+func ScanSources(sources []Source) *Result {
+	// Score: [S8,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S8): Source is parsed, never compiled or executed
+	// - Performance (P7): One parse per file, comments matched by position
+	// - Memory (M7): ASTs released after each file
+	// - Testing (T7): Pure function over in-memory sources
+	// - Error (E8): Syntax problems reported per file and line instead of failing the scan
+	// - Load (L7): Linear in source size
+	// Tags: BE-module-medium
+
+	result := &Result{Annotations: []Annotation{}, Issues: []Issue{}}
+	fset := token.NewFileSet()
+
+	for _, source := range sources {
+		file, err := parser.ParseFile(fset, source.Path, source.Content, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			result.Issues = append(result.Issues, Issue{
+				File: source.Path, Line: 1, Severity: SeverityError, Message: "parse error: " + err.Error(),
+			})
+			continue
+		}
+		result.Files++
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			result.Functions++
+			scanFunc(fset, file, fn, source.Path, result)
+		}
+	}
+
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		if result.Issues[i].File != result.Issues[j].File {
+			return result.Issues[i].File < result.Issues[j].File
+		}
+		return result.Issues[i].Line < result.Issues[j].Line
+	})
+
+	return result
+}
+
+// BE-IN - Internal backend only
+// commentLine is one line of a // comment with its position.
+type commentLine struct {
+	text string
+	line int
+}
+
+// BE-IN - Internal backend only
+// scanFunc looks for Score blocks in the doc comment and body of fn.
+func scanFunc(fset *token.FileSet, file *ast.File, fn *ast.FuncDecl, path string, result *Result) {
+	name := gosource.FuncName(fn)
+
+	var groups [][]commentLine
+	for _, group := range file.Comments {
+		inDoc := fn.Doc != nil && group == fn.Doc
+		if !inDoc && (group.Pos() < fn.Pos() || group.End() > fn.End()) {
+			continue
+		}
+		var lines []commentLine
+		for _, c := range group.List {
+			if !strings.HasPrefix(c.Text, "//") {
+				continue
+			}
+			lines = append(lines, commentLine{
+				text: strings.TrimSpace(strings.TrimPrefix(c.Text, "//")),
+				line: fset.Position(c.Slash).Line,
+			})
+		}
+		groups = append(groups, lines)
+	}
+
+	found := false
+	for _, lines := range groups {
+		for i, l := range lines {
+			if !strings.HasPrefix(l.text, "Score:") {
+				continue
+			}
+			if found {
+				result.Issues = append(result.Issues, Issue{
+					File: path, Line: l.line, Function: name, Severity: SeverityWarning,
+					Message: "multiple Score annotations, only the first is used",
+				})
+				continue
+			}
+			found = true
+			annotation, issues := parseBlock(lines[i:], path, name)
+			result.Issues = append(result.Issues, issues...)
+			if annotation != nil {
+				annotation.Package = file.Name.Name
+				annotation.Marker = docMarker(fn)
+				result.Annotations = append(result.Annotations, *annotation)
+			}
+		}
+	}
+}
+
+// BE-IN - Internal backend only
+// docMarker returns the BE-IN/BE-OUT style marker in the doc comment of
+// fn, e.g. "BE-OUT", or "" when there is none.
+func docMarker(fn *ast.FuncDecl) string {
+	if fn.Doc == nil {
+		return ""
+	}
+	for _, c := range fn.Doc.List {
+		typ, scope, _, ok := models.ParseMarker(strings.TrimPrefix(c.Text, "//"))
+		if ok {
+			return typ + "-" + scope
+		}
+	}
+	return ""
+}
+
+// BE-IN - Internal backend only
+// parseBlock parses a Score block starting at lines[0]. The block ends at
+// the Tags line, the end of the comment group or the first line that is
+// not part of the format.
+func parseBlock(lines []commentLine, path, name string) (*Annotation, []Issue) {
+	var issues []Issue
+	issue := func(line int, severity, format string, args ...interface{}) {
+		issues = append(issues, Issue{
+			File: path, Line: line, Function: name, Severity: severity, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	first := lines[0]
+	match := scoreLinePattern.FindStringSubmatch(first.text)
+	if match == nil {
+		issue(first.line, SeverityError, "malformed Score line %q, expected Score: [S<n>,P<n>,M<n>,T<n>,E<n>,L<n>]", first.text)
+		return nil, issues
+	}
+
+	annotation := &Annotation{File: path, Line: first.line, Function: name, Details: make(map[string]string)}
+	valid := true
+	for i := range Dimensions {
+		score, _ := strconv.Atoi(match[i+1])
+		if score < 0 || score > 10 {
+			issue(first.line, SeverityError, "%s score %d is out of range 0-10", Dimensions[i].Name, score)
+			valid = false
+		}
+		annotation.Scores[i] = score
+	}
+
+	rest := lines[1:]
+	if len(rest) == 0 || rest[0].text != "Details:" {
+		issue(first.line, SeverityWarning, "missing Details: line after Score")
+	} else {
+		rest = rest[1:]
+	}
+
+	lastLine := first.line
+	sawTags := false
+	for _, l := range rest {
+		lastLine = l.line
+		if strings.HasPrefix(l.text, "Tags:") {
+			sawTags = true
+			tags := strings.FieldsFunc(strings.TrimPrefix(l.text, "Tags:"), func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			})
+			if len(tags) == 0 {
+				issue(l.line, SeverityWarning, "empty Tags line")
+			}
+			for _, tag := range tags {
+				parsed, err := models.ParseTag(tag)
+				if err != nil {
+					issue(l.line, SeverityError, "%s", err.Error())
+					continue
+				}
+				if parsed.String() != tag {
+					issue(l.line, SeverityWarning, "tag %q should be spelled %s", tag, parsed)
+				}
+				annotation.Tags = append(annotation.Tags, parsed.String())
+			}
+			break
+		}
+
+		bullet := bulletPattern.FindStringSubmatch(l.text)
+		if bullet == nil {
+			break
+		}
+		index := dimensionIndex(bullet[1])
+		if index < 0 {
+			issue(l.line, SeverityError, "unknown dimension %q", bullet[1])
+			continue
+		}
+		dimension := Dimensions[index]
+		if bullet[2] != dimension.Letter {
+			issue(l.line, SeverityError, "%s detail is labelled %s, expected %s", dimension.Name, bullet[2], dimension.Letter)
+		}
+		if score, _ := strconv.Atoi(bullet[3]); score != annotation.Scores[index] {
+			issue(l.line, SeverityError, "%s detail says %s%d but the Score line says %s%d",
+				dimension.Name, bullet[2], score, dimension.Letter, annotation.Scores[index])
+		}
+		if _, dup := annotation.Details[dimension.Name]; dup {
+			issue(l.line, SeverityWarning, "duplicate %s detail", dimension.Name)
+		}
+		annotation.Details[dimension.Name] = strings.TrimSpace(bullet[4])
+	}
+
+	for _, dimension := range Dimensions {
+		if _, ok := annotation.Details[dimension.Name]; !ok {
+			issue(first.line, SeverityWarning, "missing %s detail", dimension.Name)
+		}
+	}
+	if !sawTags {
+		issue(lastLine, SeverityWarning, "missing Tags line")
+	}
+
+	if !valid {
+		return nil, issues
+	}
+	return annotation, issues
+}
+
+// BE-IN - Internal backend only
+func dimensionIndex(name string) int {
+	for i, dimension := range Dimensions {
+		if strings.EqualFold(dimension.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
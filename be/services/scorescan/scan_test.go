@@ -0,0 +1,197 @@
+package scorescan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// block builds a function whose body starts with the given comment lines.
+func block(lines ...string) string {
+	return "package api\n\nfunc Handle() {\n\t// " + strings.Join(lines, "\n\t// ") + "\n}\n"
+}
+
+// fullDetails are the Details and Tags lines matching [S8,P7,M6,T7,E8,L6].
+var fullDetails = []string{
+	"Details:",
+	"- Security (S8): Input validation",
+	"- Performance (P7): Single lookup",
+	"- Memory (M6): Copies the report",
+	"- Testing (T7): Table tests",
+	"- Error (E8): Typed errors",
+	"- Load (L6): Locks per report",
+	"Tags: BE-module-high",
+}
+
+func withDetails(first string, replace map[int]string) []string {
+	lines := append([]string{first}, fullDetails...)
+	for i, line := range replace {
+		lines[i] = line
+	}
+	return lines
+}
+
+func TestScanSources(t *testing.T) {
+	src := `package api
+
+// BE-OUT - External data involved
+func (s *Store) Save() {
+	// Score: [S8,P7,M6,T7,E8,L6]
+	// Details:
+	// - Security (S8): Input validation
+	// - Performance (P7): Single lookup
+	// - Memory (M6): Copies the report
+	// - Testing (T7): Table tests
+	// - Error (E8): Typed errors
+	// - Load (L6): Locks per report
+	// Tags: BE-module-high, BE-DB-medium
+}
+
+func helper() {}
+`
+	result := ScanSources([]Source{{Path: "api/store.go", Content: src}})
+	if result.Files != 1 || result.Functions != 2 || len(result.Issues) != 0 {
+		t.Fatalf("result = %d files, %d functions, issues %v", result.Files, result.Functions, result.Issues)
+	}
+	want := Annotation{
+		File:     "api/store.go",
+		Line:     5,
+		Package:  "api",
+		Function: "(*Store).Save",
+		Scores:   Scores{8, 7, 6, 7, 8, 6},
+		Details: map[string]string{
+			"Security": "Input validation", "Performance": "Single lookup", "Memory": "Copies the report",
+			"Testing": "Table tests", "Error": "Typed errors", "Load": "Locks per report",
+		},
+		Tags:   []string{"BE-module-high", "BE-DB-medium"},
+		Marker: "BE-OUT",
+	}
+	if len(result.Annotations) != 1 || !reflect.DeepEqual(result.Annotations[0], want) {
+		t.Errorf("annotations = %+v, want %+v", result.Annotations, want)
+	}
+}
+
+func TestScanSourcesIssues(t *testing.T) {
+	const score = "Score: [S8,P7,M6,T7,E8,L6]"
+
+	tests := []struct {
+		name       string
+		src        string
+		annotation bool
+		issues     []string
+	}{
+		{
+			name:   "malformed score line",
+			src:    block("Score: [S8,P7,M6]", "Details:"),
+			issues: []string{`4: error: malformed Score line "Score: [S8,P7,M6]", expected Score: [S<n>,P<n>,M<n>,T<n>,E<n>,L<n>]`},
+		},
+		{
+			name:   "score out of range",
+			src:    block(withDetails("Score: [S11,P7,M6,T7,E8,L6]", map[int]string{2: "- Security (S11): Input validation"})...),
+			issues: []string{"4: error: Security score 11 is out of range 0-10"},
+		},
+		{
+			name:       "detail score differs from the Score line",
+			src:        block(withDetails(score, map[int]string{2: "- Security (S6): Input validation"})...),
+			annotation: true,
+			issues:     []string{"6: error: Security detail says S6 but the Score line says S8"},
+		},
+		{
+			name:       "detail labelled with the wrong letter",
+			src:        block(withDetails(score, map[int]string{3: "- Performance (M7): Single lookup"})...),
+			annotation: true,
+			issues:     []string{"7: error: Performance detail is labelled M, expected P"},
+		},
+		{
+			name:       "unknown dimension",
+			src:        block(withDetails(score, map[int]string{4: "- Storage (M6): Copies the report"})...),
+			annotation: true,
+			issues:     []string{"4: warning: missing Memory detail", `8: error: unknown dimension "Storage"`},
+		},
+		{
+			name:       "duplicate detail",
+			src:        block(withDetails(score, map[int]string{4: "- Performance (P7): Again"})...),
+			annotation: true,
+			issues:     []string{"4: warning: missing Memory detail", "8: warning: duplicate Performance detail"},
+		},
+		{
+			name:       "duplicate block",
+			src:        block(append(withDetails(score, nil), "Score: [S1,P1,M1,T1,E1,L1]")...),
+			annotation: true,
+			issues:     []string{"13: warning: multiple Score annotations, only the first is used"},
+		},
+		{
+			name:       "missing details and tags",
+			src:        block(score, "- Security (S8): Input validation"),
+			annotation: true,
+			issues: []string{
+				"4: warning: missing Details: line after Score",
+				"4: warning: missing Performance detail", "4: warning: missing Memory detail", "4: warning: missing Testing detail",
+				"4: warning: missing Error detail", "4: warning: missing Load detail",
+				"5: warning: missing Tags line",
+			},
+		},
+		{
+			name:       "tags",
+			src:        block(withDetails(score, map[int]string{8: "Tags: be-module-high, BE-nowhere-high"})...),
+			annotation: true,
+			issues:     []string{`12: warning: tag "be-module-high" should be spelled BE-module-high`, "12: error: "},
+		},
+		{
+			name:   "parse error",
+			src:    "package api\n\nfunc Handle() {\n",
+			issues: []string{"1: error: parse error: "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ScanSources([]Source{{Path: "api/handle.go", Content: tt.src}})
+			if got := len(result.Annotations) == 1; got != tt.annotation {
+				t.Errorf("annotation kept = %v, want %v", got, tt.annotation)
+			}
+			if len(result.Issues) != len(tt.issues) {
+				t.Fatalf("issues = %v, want %q", result.Issues, tt.issues)
+			}
+			for i, issue := range result.Issues {
+				if want := "api/handle.go:" + tt.issues[i]; !strings.HasPrefix(strings.Replace(issue.String(), " Handle", "", 1), want) {
+					t.Errorf("issue %d = %q, want %q", i, issue, want)
+				}
+			}
+			if result.HasErrors() != strings.Contains(strings.Join(tt.issues, "\n"), ": error: ") {
+				t.Errorf("HasErrors = %v for %v", result.HasErrors(), result.Issues)
+			}
+		})
+	}
+}
+
+func TestScanSourcesDocComment(t *testing.T) {
+	// A block in the doc comment counts like one in the body
+	src := "package api\n\n// Score: [S8,P7,M6,T7,E8,L6]\n// " + strings.Join(fullDetails, "\n// ") + "\nfunc Handle() {}\n"
+	result := ScanSources([]Source{{Path: "api/handle.go", Content: src}})
+	if len(result.Annotations) != 1 || len(result.Issues) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestProposeEvaluation(t *testing.T) {
+	annotations := []Annotation{
+		{Package: "api", Function: "Create", Scores: Scores{8, 7, 6, 7, 8, 6}, Details: map[string]string{"Security": "Validated"}},
+		{Package: "api", Function: "Delete", Scores: Scores{5, 7, 7, 6, 8, 7}},
+	}
+	evaluation := ProposeEvaluation(annotations)
+
+	// Means round half away from zero: 6.5 -> 7
+	got := []int{evaluation.SecurityScore, evaluation.PerformanceScore, evaluation.MemoryScore, evaluation.TestingScore, evaluation.ErrorScore, evaluation.LoadScore}
+	if want := []int{7, 7, 7, 7, 8, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scores = %v, want %v", got, want)
+	}
+	// Weakest first, with the function's own note
+	wantDetails := "Average S6.5 across 2 annotated functions.\n- api.Delete (S5)\n- api.Create (S8): Validated"
+	if evaluation.SecurityDetails != wantDetails {
+		t.Errorf("SecurityDetails = %q, want %q", evaluation.SecurityDetails, wantDetails)
+	}
+
+	if empty := ProposeEvaluation(nil); empty.SecurityScore != 0 || empty.SecurityDetails != "" {
+		t.Errorf("ProposeEvaluation(nil) = %+v, want an empty evaluation", empty)
+	}
+}
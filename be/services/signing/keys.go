@@ -0,0 +1,201 @@
+// Package signing signs report submission snapshots with Ed25519 and
+// verifies the resulting receipts.
+//
+// A snapshot is canonical JSON (object keys sorted, no insignificant
+// whitespace). Its digest is the hex SHA-256 of those bytes, and what is
+// signed, by the server and optionally by the author, is
+//
+//	report-submission-v1\n<hex digest>
+//
+// so a co-signature can be produced with any Ed25519 tool.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// BE-IN - Internal backend only
+// Algorithm names the signature scheme in receipts and published keys
+const Algorithm = "Ed25519"
+
+// BE-IN - Internal backend only
+var (
+	ErrNoSigningKey = errors.New("no signing key configured")
+	ErrInvalidKey   = errors.New("key must be a base64 Ed25519 seed or public key")
+)
+
+// BE-IN - Internal backend only
+// Key is a server key. Retired keys only verify: either an older seed
+// still configured, or a bare public key.
+type Key struct {
+	ID      string
+	Public  ed25519.PublicKey
+	private ed25519.PrivateKey
+	Active  bool
+}
+
+// BE-IN - Internal backend only
+// KeyRing holds the active signing key and the retired keys that
+// signatures made before a rotation are checked against.
+type KeyRing struct {
+	mu   sync.RWMutex
+	keys []*Key
+}
+
+// BE-IN - Internal backend only
+// KeyID derives the published identifier of a public key.
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
+
+// BE-IN - Internal backend only
+// NewKeyRing builds a ring from seeds, newest first, and public keys of
+// retired keys whose seed is no longer available. The first seed signs.
+func NewKeyRing(seeds [][]byte, retired []ed25519.PublicKey) (*KeyRing, error) {
+	r := &KeyRing{}
+	for i, seed := range seeds {
+		if len(seed) != ed25519.SeedSize {
+			return nil, ErrInvalidKey
+		}
+		private := ed25519.NewKeyFromSeed(seed)
+		public := private.Public().(ed25519.PublicKey)
+		r.keys = append(r.keys, &Key{ID: KeyID(public), Public: public, private: private, Active: i == 0})
+	}
+	for _, public := range retired {
+		if len(public) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+		r.keys = append(r.keys, &Key{ID: KeyID(public), Public: public})
+	}
+	return r, nil
+}
+
+// BE-IN - Internal backend only
+// KeyRingFromEnv reads SUBMISSION_SIGNING_KEYS, comma-separated base64
+// seeds newest first, and SUBMISSION_RETIRED_KEYS, comma-separated base64
+// public keys. Without seeds an ephemeral key is generated, so signatures
+// only verify until the process restarts.
+func KeyRingFromEnv() (*KeyRing, bool, error) {
+	seeds, err := decodeList(os.Getenv("SUBMISSION_SIGNING_KEYS"), ed25519.SeedSize)
+	if err != nil {
+		return nil, false, fmt.Errorf("SUBMISSION_SIGNING_KEYS: %w", err)
+	}
+	publics, err := decodeList(os.Getenv("SUBMISSION_RETIRED_KEYS"), ed25519.PublicKeySize)
+	if err != nil {
+		return nil, false, fmt.Errorf("SUBMISSION_RETIRED_KEYS: %w", err)
+	}
+	retired := make([]ed25519.PublicKey, len(publics))
+	for i, p := range publics {
+		retired[i] = p
+	}
+
+	if len(seeds) == 0 {
+		r, err := NewEphemeralKeyRing()
+		return r, true, err
+	}
+	r, err := NewKeyRing(seeds, retired)
+	return r, false, err
+}
+
+// BE-IN - Internal backend only
+// NewEphemeralKeyRing returns a ring with a freshly generated key.
+func NewEphemeralKeyRing() (*KeyRing, error) {
+	seed, err := NewSeed()
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyRing([][]byte{seed}, nil)
+}
+
+// BE-IN - Internal backend only
+// NewSeed returns a random Ed25519 seed. Rotation prepends a new seed to
+// SUBMISSION_SIGNING_KEYS; the older ones keep verifying.
+func NewSeed() ([]byte, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// BE-IN - Internal backend only
+// Sign signs a digest with the active key.
+func (r *KeyRing) Sign(digest string) (keyID, signature string, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, k := range r.keys {
+		if k.Active && k.private != nil {
+			return k.ID, base64.StdEncoding.EncodeToString(ed25519.Sign(k.private, Message(digest))), nil
+		}
+	}
+	return "", "", ErrNoSigningKey
+}
+
+// BE-IN - Internal backend only
+// Keys returns the published keys, the active one first.
+func (r *KeyRing) Keys() []Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]Key, len(r.keys))
+	for i, k := range r.keys {
+		keys[i] = Key{ID: k.ID, Public: k.Public, Active: k.Active}
+	}
+	return keys
+}
+
+// BE-IN - Internal backend only
+// PublicKeys maps key IDs to public keys, as Verify expects.
+func (r *KeyRing) PublicKeys() map[string]ed25519.PublicKey {
+	keys := make(map[string]ed25519.PublicKey)
+	for _, k := range r.Keys() {
+		keys[k.ID] = k.Public
+	}
+	return keys
+}
+
+// BE-IN - Internal backend only
+// ParsePublicKey decodes a base64 Ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(data) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+// BE-IN - Internal backend only
+// ParseSeed decodes a base64 Ed25519 seed into a private key.
+func ParseSeed(s string) (ed25519.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(data) != ed25519.SeedSize {
+		return nil, ErrInvalidKey
+	}
+	return ed25519.NewKeyFromSeed(data), nil
+}
+
+// BE-IN - Internal backend only
+func decodeList(value string, size int) ([][]byte, error) {
+	var out [][]byte
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(item)
+		if err != nil || len(data) != size {
+			return nil, ErrInvalidKey
+		}
+		out = append(out, data)
+	}
+	return out, nil
+}
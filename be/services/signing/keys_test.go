@@ -0,0 +1,143 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestNewKeyRing(t *testing.T) {
+	newest, older := testSeed(1), testSeed(2)
+	retired := ed25519.NewKeyFromSeed(testSeed(3)).Public().(ed25519.PublicKey)
+
+	ring, err := NewKeyRing([][]byte{newest, older}, []ed25519.PublicKey{retired})
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+
+	keys := ring.Keys()
+	if len(keys) != 3 {
+		t.Fatalf("got %d keys, want 3", len(keys))
+	}
+	wantIDs := []string{
+		KeyID(ed25519.NewKeyFromSeed(newest).Public().(ed25519.PublicKey)),
+		KeyID(ed25519.NewKeyFromSeed(older).Public().(ed25519.PublicKey)),
+		KeyID(retired),
+	}
+	for i, key := range keys {
+		if key.ID != wantIDs[i] {
+			t.Errorf("key %d ID = %s, want %s", i, key.ID, wantIDs[i])
+		}
+		if key.Active != (i == 0) {
+			t.Errorf("key %d Active = %v", i, key.Active)
+		}
+	}
+
+	keyID, signature, err := ring.Sign("digest")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if keyID != wantIDs[0] {
+		t.Errorf("signed with %s, want the newest key %s", keyID, wantIDs[0])
+	}
+	if !VerifySignature(keys[0].Public, "digest", signature) {
+		t.Error("signature does not verify with the active key")
+	}
+	if VerifySignature(keys[0].Public, "other digest", signature) {
+		t.Error("signature verifies for a different digest")
+	}
+}
+
+func TestNewKeyRingErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		seeds   [][]byte
+		retired []ed25519.PublicKey
+		wantErr error
+	}{
+		{"short seed", [][]byte{make([]byte, 16)}, nil, ErrInvalidKey},
+		{"short public key", [][]byte{testSeed(1)}, []ed25519.PublicKey{make([]byte, 16)}, ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeyRing(tt.seeds, tt.retired); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewKeyRing error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Retired keys alone can verify but not sign
+	ring, err := NewKeyRing(nil, []ed25519.PublicKey{ed25519.NewKeyFromSeed(testSeed(1)).Public().(ed25519.PublicKey)})
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+	if _, _, err := ring.Sign("digest"); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("Sign error = %v, want %v", err, ErrNoSigningKey)
+	}
+}
+
+func TestKeyRingFromEnv(t *testing.T) {
+	seed := base64.StdEncoding.EncodeToString(testSeed(1))
+	retired := base64.StdEncoding.EncodeToString(ed25519.NewKeyFromSeed(testSeed(2)).Public().(ed25519.PublicKey))
+
+	tests := []struct {
+		name          string
+		seeds         string
+		retired       string
+		wantKeys      int
+		wantEphemeral bool
+		wantErr       bool
+	}{
+		{name: "configured", seeds: seed, retired: retired, wantKeys: 2},
+		{name: "blank entries ignored", seeds: " , " + seed + ",", wantKeys: 1},
+		{name: "ephemeral without seeds", retired: retired, wantKeys: 1, wantEphemeral: true},
+		{name: "invalid seed", seeds: "not base64", wantErr: true},
+		{name: "invalid retired key", seeds: seed, retired: seed + "AAAA", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SUBMISSION_SIGNING_KEYS", tt.seeds)
+			t.Setenv("SUBMISSION_RETIRED_KEYS", tt.retired)
+
+			ring, ephemeral, err := KeyRingFromEnv()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidKey) {
+					t.Errorf("KeyRingFromEnv error = %v, want %v", err, ErrInvalidKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("KeyRingFromEnv: %v", err)
+			}
+			if ephemeral != tt.wantEphemeral {
+				t.Errorf("ephemeral = %v, want %v", ephemeral, tt.wantEphemeral)
+			}
+			if got := len(ring.Keys()); got != tt.wantKeys {
+				t.Errorf("got %d keys, want %d", got, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	seed := testSeed(1)
+	private, err := ParseSeed(base64.StdEncoding.EncodeToString(seed))
+	if err != nil {
+		t.Fatalf("ParseSeed: %v", err)
+	}
+	public, err := ParsePublicKey(" " + base64.StdEncoding.EncodeToString(private.Public().(ed25519.PublicKey)) + "\n")
+	if err != nil {
+		t.Fatalf("ParsePublicKey: %v", err)
+	}
+	if !public.Equal(ed25519.NewKeyFromSeed(seed).Public()) {
+		t.Error("parsed public key does not match the seed")
+	}
+
+	if _, err := ParseSeed(base64.StdEncoding.EncodeToString(seed[:16])); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParseSeed accepted a short seed: %v", err)
+	}
+	if _, err := ParsePublicKey("%%%"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParsePublicKey accepted invalid base64: %v", err)
+	}
+}
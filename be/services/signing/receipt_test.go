@@ -0,0 +1,241 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSeed(b byte) []byte {
+	return bytes.Repeat([]byte{b}, ed25519.SeedSize)
+}
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		ReportID:     "report-123",
+		Title:        "Quarterly security review",
+		Description:  "## Summary\nAuthentication module",
+		ProjectID:    "project-123",
+		AuthorID:     "user-123",
+		DepartmentID: "dept-123",
+		SubmittedAt:  time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC),
+		Metadata:     map[string]interface{}{"review_cycle": "quarterly", "classification": "internal"},
+		Tags:         []string{"auth", "q1"},
+		Evaluation:   &SnapshotEvaluation{SecurityScore: 8, PerformanceScore: 7, MemoryScore: 7, TestingScore: 6, ErrorScore: 8, LoadScore: 7},
+	}
+}
+
+func mustSign(t *testing.T, ring *KeyRing) *Receipt {
+	t.Helper()
+	receipt, err := ring.SignSnapshot(testSnapshot(), time.Date(2026, 3, 31, 12, 0, 1, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("SignSnapshot: %v", err)
+	}
+	return receipt
+}
+
+// cosign adds an author co-signature made with the seed's key
+func cosign(receipt *Receipt, seed []byte) *Receipt {
+	private := ed25519.NewKeyFromSeed(seed)
+	receipt.AuthorKeyID = KeyID(private.Public().(ed25519.PublicKey))
+	receipt.AuthorSignature = base64.StdEncoding.EncodeToString(ed25519.Sign(private, Message(receipt.Digest)))
+	return receipt
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"sorts keys", `{"b":1,"a":{"d":2,"c":3}}`, `{"a":{"c":3,"d":2},"b":1}`},
+		{"drops whitespace", "{ \"a\" : [ 1 , 2 ] }\n", `{"a":[1,2]}`},
+		{"keeps number literals", `{"a":1.50,"b":1e3}`, `{"a":1.50,"b":1e3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Canonicalize: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Canonicalize = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Canonicalize([]byte(`{"a":1} {"b":2}`)); err == nil {
+		t.Error("Canonicalize accepted trailing data")
+	}
+}
+
+func TestSignSnapshotIsDeterministic(t *testing.T) {
+	ring, err := NewKeyRing([][]byte{testSeed(1)}, nil)
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+	first, second := mustSign(t, ring), mustSign(t, ring)
+	if first.Digest != second.Digest || first.Signature != second.Signature {
+		t.Error("signing the same snapshot twice gave different receipts")
+	}
+	if !bytes.Contains(first.Snapshot, []byte(`"metadata":{"classification":"internal","review_cycle":"quarterly"}`)) {
+		t.Errorf("snapshot is not canonical: %s", first.Snapshot)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	oldSeed, newSeed, authorSeed := testSeed(1), testSeed(2), testSeed(3)
+	authorKey := ed25519.NewKeyFromSeed(authorSeed).Public().(ed25519.PublicKey)
+	otherKey := ed25519.NewKeyFromSeed(testSeed(4)).Public().(ed25519.PublicKey)
+	oldPublic := ed25519.NewKeyFromSeed(oldSeed).Public().(ed25519.PublicKey)
+
+	oldRing, err := NewKeyRing([][]byte{oldSeed}, nil)
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+	// After a rotation the old key is only published as a retired key
+	rotated, err := NewKeyRing([][]byte{newSeed}, []ed25519.PublicKey{oldPublic})
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+	unrelated, err := NewKeyRing([][]byte{testSeed(5)}, nil)
+	if err != nil {
+		t.Fatalf("NewKeyRing: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		receipt    func() *Receipt
+		serverKeys map[string]ed25519.PublicKey
+		authorKey  ed25519.PublicKey
+		want       Result
+		problems   []string
+	}{
+		{
+			name:       "round trip",
+			receipt:    func() *Receipt { return mustSign(t, rotated) },
+			serverKeys: rotated.PublicKeys(),
+			want:       Result{Valid: true, DigestValid: true, SignatureValid: true, KeyKnown: true},
+		},
+		{
+			name: "tampered snapshot",
+			receipt: func() *Receipt {
+				receipt := mustSign(t, rotated)
+				receipt.Snapshot = bytes.Replace(receipt.Snapshot, []byte(`"security_score":8`), []byte(`"security_score":10`), 1)
+				return receipt
+			},
+			serverKeys: rotated.PublicKeys(),
+			// The signature is checked against the recomputed digest
+			want:     Result{KeyKnown: true},
+			problems: []string{"snapshot does not match the digest", "server signature does not verify"},
+		},
+		{
+			name: "reformatted snapshot",
+			receipt: func() *Receipt {
+				receipt := mustSign(t, rotated)
+				var indented bytes.Buffer
+				if err := json.Indent(&indented, receipt.Snapshot, "", "  "); err != nil {
+					t.Fatalf("Indent: %v", err)
+				}
+				receipt.Snapshot = indented.Bytes()
+				return receipt
+			},
+			serverKeys: rotated.PublicKeys(),
+			want:       Result{Valid: true, DigestValid: true, SignatureValid: true, KeyKnown: true},
+		},
+		{
+			name:       "signed by a retired key",
+			receipt:    func() *Receipt { return mustSign(t, oldRing) },
+			serverKeys: rotated.PublicKeys(),
+			want:       Result{Valid: true, DigestValid: true, SignatureValid: true, KeyKnown: true},
+		},
+		{
+			name:       "unknown key id",
+			receipt:    func() *Receipt { return mustSign(t, unrelated) },
+			serverKeys: rotated.PublicKeys(),
+			want:       Result{DigestValid: true},
+			problems:   []string{"is not a published server key"},
+		},
+		{
+			name: "forged server signature",
+			receipt: func() *Receipt {
+				receipt := mustSign(t, rotated)
+				receipt.Signature = mustSign(t, unrelated).Signature
+				return receipt
+			},
+			serverKeys: rotated.PublicKeys(),
+			want:       Result{DigestValid: true, KeyKnown: true},
+			problems:   []string{"server signature does not verify"},
+		},
+		{
+			name:       "unsupported algorithm",
+			receipt:    func() *Receipt { r := mustSign(t, rotated); r.Algorithm = "RSA"; return r },
+			serverKeys: rotated.PublicKeys(),
+			problems:   []string{`unsupported algorithm "RSA"`},
+		},
+		{
+			name:       "valid author co-signature",
+			receipt:    func() *Receipt { return cosign(mustSign(t, rotated), authorSeed) },
+			serverKeys: rotated.PublicKeys(),
+			authorKey:  authorKey,
+			want:       Result{Valid: true, DigestValid: true, SignatureValid: true, KeyKnown: true, AuthorSigned: true, AuthorValid: true},
+		},
+		{
+			name: "invalid author co-signature",
+			receipt: func() *Receipt {
+				receipt := cosign(mustSign(t, rotated), authorSeed)
+				other := ed25519.Sign(ed25519.NewKeyFromSeed(authorSeed), Message("another digest"))
+				receipt.AuthorSignature = base64.StdEncoding.EncodeToString(other)
+				return receipt
+			},
+			serverKeys: rotated.PublicKeys(),
+			authorKey:  authorKey,
+			want:       Result{DigestValid: true, SignatureValid: true, KeyKnown: true, AuthorSigned: true},
+			problems:   []string{"author signature does not verify"},
+		},
+		{
+			name:       "author key does not match",
+			receipt:    func() *Receipt { return cosign(mustSign(t, rotated), authorSeed) },
+			serverKeys: rotated.PublicKeys(),
+			authorKey:  otherKey,
+			want:       Result{DigestValid: true, SignatureValid: true, KeyKnown: true, AuthorSigned: true},
+			problems:   []string{"author key does not match"},
+		},
+		{
+			name:       "author key unknown",
+			receipt:    func() *Receipt { return cosign(mustSign(t, rotated), authorSeed) },
+			serverKeys: rotated.PublicKeys(),
+			want:       Result{DigestValid: true, SignatureValid: true, KeyKnown: true, AuthorSigned: true},
+			problems:   []string{"is unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := tt.receipt()
+			got := Verify(receipt, tt.serverKeys, tt.authorKey)
+
+			want := tt.want
+			want.KeyID = receipt.KeyID
+			problems := got.Problems
+			got.Problems = nil
+			if got.Valid != want.Valid || got.DigestValid != want.DigestValid || got.SignatureValid != want.SignatureValid ||
+				got.KeyID != want.KeyID || got.KeyKnown != want.KeyKnown || got.AuthorSigned != want.AuthorSigned || got.AuthorValid != want.AuthorValid {
+				t.Errorf("Verify = %+v, want %+v", *got, want)
+			}
+
+			if len(problems) != len(tt.problems) {
+				t.Fatalf("problems = %q, want %q", problems, tt.problems)
+			}
+			for i, problem := range tt.problems {
+				if !strings.Contains(problems[i], problem) {
+					t.Errorf("problem %d = %q, want one containing %q", i, problems[i], problem)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package webhooks signs and sends outbound webhook deliveries.
+//
+// Every delivery is a JSON envelope POSTed to the subscriber's URL with an
+// X-Webhook-Signature header of the form
+//
+//	t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" keyed with the secret>
+//
+// Receivers recompute the HMAC with their secret and reject stale
+// timestamps to prevent replays.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// BE-IN - Internal backend only
+// Delivery headers
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderWebhook   = "X-Webhook-ID"
+)
+
+// BE-IN - Internal backend only
+var (
+	ErrInvalidURL       = errors.New("webhook url must be an absolute http or https url")
+	ErrPrivateAddress   = errors.New("webhook url resolves to a private or loopback address")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// BE-IN - Internal backend only
+// Envelope is the JSON body of every delivery.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Test      bool            `json:"test,omitempty"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// BE-IN - Internal backend only
+// Result describes one delivery attempt.
+type Result struct {
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// BE-IN - Internal backend only
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// BE-IN - Internal backend only
+// Sign returns the X-Webhook-Signature value for body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + signature(secret, t, body)
+}
+
+// BE-IN - Internal backend only
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BE-IN - Internal backend only
+// Verify checks a signature header against body, rejecting signatures
+// older than tolerance. It is what receivers are expected to implement.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := signature(secret, timestamp, body)
+	for _, s := range signatures {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// BE-IN - Internal backend only
+// ValidateURL checks that raw is an absolute http(s) URL. Private and
+// loopback destinations are refused when the client is built, not here,
+// because a hostname can resolve differently at delivery time.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+// BE-IN - Internal backend only
+// Client delivers webhooks.
+type Client struct {
+	HTTP *http.Client
+}
+
+// BE-IN - Internal backend only
+// NewClient returns a client with a short timeout that refuses to connect
+// to private, loopback and link-local addresses, so webhooks cannot be
+// used to reach internal services. Set WEBHOOK_ALLOW_PRIVATE=true to allow
+// them for local development.
+func NewClient() *Client {
+	allowPrivate := os.Getenv("WEBHOOK_ALLOW_PRIVATE") == "true"
+
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if allowPrivate {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+				ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	return &Client{
+		HTTP: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConns:        20,
+				IdleConnTimeout:     30 * time.Second,
+			},
+			// Redirects could point at internal addresses the dialer
+			// already refuses, but there is no reason to follow them
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// BE-OUT - External data involved
+// Deliver POSTs the envelope once. Any non-2xx response is an error.
+func (c *Client) Deliver(ctx context.Context, webhookID, targetURL, secret string, envelope *Envelope) Result {
+	// Score: [S8,P7,M7,T7,E8,L7]
+	// Details:
+	// - Security (S8): HMAC-signed bodies, private addresses refused, redirects not followed
+	// - Performance (P7): Shared transport with connection reuse
+	// - Memory (M7): Response bodies discarded after a bounded read
+	// - Testing (T7): Works against httptest servers with WEBHOOK_ALLOW_PRIVATE
+	// - Error (E8): Status and transport errors reported per attempt
+	// - Load (L7): Short timeouts keep slow receivers from piling up
+	// Tags: BE-OUT-high
+
+	start := time.Now()
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ProjectReporting-Webhooks/1.0")
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), body))
+	req.Header.Set(HeaderEvent, envelope.Type)
+	req.Header.Set(HeaderDelivery, envelope.ID)
+	req.Header.Set(HeaderWebhook, webhookID)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return Result{Duration: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	result := Result{StatusCode: resp.StatusCode, Duration: time.Since(start)}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result.Err = fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return result
+}